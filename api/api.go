@@ -12,8 +12,10 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/swagger"
 
+	"github.com/initia-labs/rollytics/api/cache"
 	"github.com/initia-labs/rollytics/api/docs"
 	"github.com/initia-labs/rollytics/api/handler"
+	"github.com/initia-labs/rollytics/api/ratelimit"
 	"github.com/initia-labs/rollytics/config"
 	"github.com/initia-labs/rollytics/metrics"
 	"github.com/initia-labs/rollytics/orm"
@@ -25,20 +27,36 @@ type Api struct {
 	cfg    *config.Config
 	logger *slog.Logger
 	db     *orm.Database
+	cache  *cache.Cache
 }
 
-func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *Api {
-	app := fiber.New(fiber.Config{
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) (*Api, error) {
+	fiberCfg := fiber.Config{
 		AppName:               "Rollytics API",
 		DisableStartupMessage: true,
 		ErrorHandler:          createErrorHandler(logger),
 		ReadBufferSize:        int(cfg.GetRecvBufferSize()), //nolint:gosec
-	})
+	}
+	// the client IP is only taken from the proxy header of the trusted proxies
+	if proxies := cfg.GetTrustedProxies(); len(proxies) > 0 {
+		fiberCfg.ProxyHeader = cfg.GetProxyHeader()
+		fiberCfg.EnableTrustedProxyCheck = true
+		fiberCfg.TrustedProxies = proxies
+		fiberCfg.EnableIPValidation = true
+	}
+	app := fiber.New(fiberCfg)
 
 	addCORS(app, cfg, logger)
 	addPanicRecoveryMiddleware(app, logger)
 	addMetricsMiddleware(app)
-	handler.Register(app, db, cfg, logger)
+	if err := addRateLimitMiddleware(app, cfg, db, logger); err != nil {
+		return nil, err
+	}
+	apiCache, err := cache.NewCache(cfg, db, logger)
+	if err != nil {
+		return nil, err
+	}
+	handler.Register(app, db, cfg, logger, apiCache)
 	setupSwagger(app, cfg)
 
 	return &Api{
@@ -46,7 +64,8 @@ func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *Api {
 		cfg:    cfg,
 		logger: logger,
 		db:     db,
-	}
+		cache:  apiCache,
+	}, nil
 }
 
 // createErrorHandler creates the error handler function for the fiber app
@@ -178,6 +197,22 @@ func addMetricsMiddleware(app *fiber.App) {
 	})
 }
 
+// addRateLimitMiddleware adds API key authentication and per-client rate limiting to the app
+func addRateLimitMiddleware(app *fiber.App, cfg *config.Config, db *orm.Database, logger *slog.Logger) error {
+	rateLimitCfg := cfg.GetRateLimitConfig()
+	if rateLimitCfg == nil || !rateLimitCfg.Enabled {
+		return nil
+	}
+
+	limiter, err := ratelimit.New(cfg, db, logger)
+	if err != nil {
+		return err
+	}
+
+	app.Use(limiter)
+	return nil
+}
+
 // handlePanicMetrics handles metrics tracking during panic recovery
 func handlePanicMetrics(start time.Time, c *fiber.Ctx, httpMetrics *metrics.HTTPMetrics) {
 	if r := recover(); r != nil {
@@ -333,5 +368,8 @@ func (a *Api) Start() error {
 }
 
 func (a *Api) Shutdown() error {
-	return a.app.Shutdown()
+	if err := a.app.Shutdown(); err != nil {
+		return err
+	}
+	return a.cache.Close()
 }
@@ -1,18 +1,59 @@
 package cache
 
 import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cache"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+const (
+	headerCache = "X-Cache"
+
+	// immutableExpiration is the server side TTL of immutable and final resources, clients may keep
+	// them longer
+	immutableExpiration = 10 * time.Second
 )
 
+// Cache holds the storage and the block height source shared by the cache middlewares of the routes.
+// A nil Cache keeps the entries in process memory and falls back to fixed TTLs for per block entries.
+type Cache struct {
+	storage fiber.Storage
+	heights func() int64
+	maxAge  time.Duration
+}
+
 // Config holds cache configuration
 type Config struct {
 	// Expiration time for the cache
 	Expiration time.Duration
 	// IncludeQueryParams determines if query parameters should be included in cache key
 	IncludeQueryParams bool
+	// PerBlock keys the entries by the latest indexed block height, so that a new block invalidates them.
+	// Expiration then only bounds the age of entries while no block is indexed.
+	// Responses carry a weak ETag of the block height, see withHeightETag.
+	PerBlock bool
+	// AppendOnly marks per block lists only ever appended at the head, whose final pages are served
+	// like Final resources, see isFinalPage
+	AppendOnly bool
+	// Immutable marks content addressed resources, which never change, see withStrongETag
+	Immutable bool
+	// Final marks resources addressed by position, such as a height, which do not change once indexed
+	// but may be pruned. Clients revalidate them after a bounded time, see withStrongETag.
+	Final bool
+	// Storage holds the entries, nil keeps them in process memory
+	Storage fiber.Storage
+	// Heights returns the latest indexed block height, PerBlock entries need it
+	Heights func() int64
 }
 
 // DefaultConfig returns a default cache configuration
@@ -23,6 +64,58 @@ func DefaultConfig() Config {
 	}
 }
 
+// NewCache creates the storage and the block height source shared by the cache middlewares of the
+// routes it is passed to
+func NewCache(cfg *config.Config, db *orm.Database, logger *slog.Logger) (*Cache, error) {
+	cacheCfg := cfg.GetAPICacheConfig()
+	c := &Cache{maxAge: cacheCfg.MaxAge}
+
+	if cacheCfg.Storage == config.APICacheStorageRedis {
+		redis, err := NewRedisStorage(RedisOptions{
+			Addr:     cacheCfg.RedisAddr,
+			Password: cacheCfg.RedisPassword,
+			DB:       cacheCfg.RedisDB,
+			Prefix:   fmt.Sprintf("rollytics:%s:cache:", cfg.GetChainId()),
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.storage = redis
+	}
+
+	tracker := NewHeightTracker(db.DB, cfg.GetChainId(), cacheCfg.HeightPollInterval, logger.With("component", "api-cache"))
+	c.heights = tracker.Height
+
+	logger.Info("api cache configured",
+		slog.String("storage", cacheCfg.Storage),
+		slog.Duration("max_age", cacheCfg.MaxAge),
+		slog.Duration("height_poll_interval", cacheCfg.HeightPollInterval))
+	return c, nil
+}
+
+// Close releases the shared storage
+func (c *Cache) Close() error {
+	if c == nil || c.storage == nil {
+		return nil
+	}
+	return c.storage.Close()
+}
+
+// config returns the default configuration of the middlewares sharing the cache
+func (c *Cache) config() Config {
+	cfg := DefaultConfig()
+	if c != nil {
+		cfg.Storage = c.storage
+		cfg.Heights = c.heights
+	}
+	return cfg
+}
+
+// perBlock reports whether the cache has a block height source for per block entries
+func (c *Cache) perBlock() bool {
+	return c != nil && c.heights != nil
+}
+
 // New creates a new cache middleware with the given configuration
 func New(cfg Config) fiber.Handler {
 	// fallback to default
@@ -31,7 +124,11 @@ func New(cfg Config) fiber.Handler {
 	}
 
 	cacheConfig := cache.Config{
-		Expiration: cfg.Expiration,
+		Expiration:  cfg.Expiration,
+		CacheHeader: headerCache,
+		Storage:     cfg.Storage,
+		// Streamed exports are neither cacheable nor told apart by the key (Accept header)
+		Next: common.IsExportRequest,
 	}
 
 	// If query parameters should be included, use custom KeyGenerator
@@ -50,12 +147,98 @@ func New(cfg Config) fiber.Handler {
 		}
 	}
 
-	return cache.New(cacheConfig)
+	// without a block height source per block entries fall back to the expiration
+	cfg.PerBlock = cfg.PerBlock && cfg.Heights != nil
+
+	if cfg.PerBlock {
+		keyGenerator := cacheConfig.KeyGenerator
+		if keyGenerator == nil {
+			keyGenerator = func(c *fiber.Ctx) string { return c.Path() }
+		}
+		cacheConfig.KeyGenerator = func(c *fiber.Ctx) string {
+			return strconv.FormatInt(requestHeight(c, cfg.Heights), 10) + "|" + keyGenerator(c)
+		}
+	}
+
+	handler := cache.New(cacheConfig)
+	lookup := func(c *fiber.Ctx) error {
+		err := handler(c)
+		trackCacheResult(c)
+		return err
+	}
+
+	if !cfg.Immutable && !cfg.Final && !cfg.PerBlock {
+		return lookup
+	}
+	return func(c *fiber.Ctx) error {
+		switch {
+		case common.IsExportRequest(c):
+			return c.Next()
+		case cfg.Immutable:
+			return withStrongETag(c, lookup, cacheControlImmutable)
+		case cfg.Final || (cfg.AppendOnly && isFinalPage(c)):
+			return withStrongETag(c, lookup, cacheControlFinal)
+		default:
+			return withHeightETag(c, lookup, cfg.Heights)
+		}
+	}
 }
 
 // WithExpiration creates a cache middleware with custom expiration time
-func WithExpiration(expiration time.Duration) fiber.Handler {
-	cfg := DefaultConfig()
+func (c *Cache) WithExpiration(expiration time.Duration) fiber.Handler {
+	cfg := c.config()
 	cfg.Expiration = expiration
 	return New(cfg)
 }
+
+// PerBlock creates a cache middleware whose entries live until the next indexed block.
+// Without a block height source it falls back to the default expiration.
+func (c *Cache) PerBlock() fiber.Handler {
+	cfg := c.config()
+	if c.perBlock() {
+		cfg.Expiration = c.maxAge
+		cfg.PerBlock = true
+	}
+	return New(cfg)
+}
+
+// AppendOnly creates a per block cache middleware for lists only ever appended at the head,
+// whose final pages are served as final.
+func (c *Cache) AppendOnly() fiber.Handler {
+	cfg := c.config()
+	if c.perBlock() {
+		cfg.Expiration = c.maxAge
+		cfg.PerBlock = true
+		cfg.AppendOnly = true
+	}
+	return New(cfg)
+}
+
+// Immutable creates a cache middleware for content addressed resources, which never change.
+// Missing resources are errors, which are neither cached nor tagged.
+func (c *Cache) Immutable() fiber.Handler {
+	cfg := c.config()
+	cfg.Expiration = immutableExpiration
+	cfg.Immutable = true
+	return New(cfg)
+}
+
+// Final creates a cache middleware for resources addressed by position which do not change once
+// indexed, but may be pruned. Missing resources are errors, which are neither cached nor tagged.
+func (c *Cache) Final() fiber.Handler {
+	cfg := c.config()
+	cfg.Expiration = immutableExpiration
+	cfg.Final = true
+	return New(cfg)
+}
+
+// trackCacheResult counts the cache lookup of the request by its route pattern
+func trackCacheResult(c *fiber.Ctx) {
+	result := c.GetRespHeader(headerCache)
+	m := metrics.GetMetrics()
+	if result == "" || m == nil {
+		return
+	}
+	// the header value aliases the response buffer, which is reused by later requests
+	m.HTTPMetrics().CacheRequests.WithLabelValues(c.Route().Path, strings.Clone(result)).Inc()
+}
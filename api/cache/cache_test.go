@@ -40,7 +40,7 @@ func TestCacheExpiration(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			app := fiber.New()
-			app.Get("/test", cache.WithExpiration(tc.expiration), func(c *fiber.Ctx) error {
+			app.Get("/test", (*cache.Cache)(nil).WithExpiration(tc.expiration), func(c *fiber.Ctx) error {
 				return c.SendString(time.Now().String())
 			})
 
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+)
+
+func conditionalGet(t *testing.T, app *fiber.App, target, ifNoneMatch string) (int, string, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodGet, target, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderETag), resp.Header.Get(fiber.HeaderCacheControl)
+}
+
+// heightCache returns a cache in process memory whose block height source reads height
+func heightCache(height *int64) *Cache {
+	return &Cache{heights: func() int64 { return *height }, maxAge: config.DefaultAPICacheMaxAge}
+}
+
+func TestImmutableETag(t *testing.T) {
+	app := fiber.New()
+	app.Get("/txs/:tx_hash", (*Cache)(nil).Immutable(), func(c *fiber.Ctx) error {
+		if c.Params("tx_hash") == "missing" {
+			return fiber.NewError(fiber.StatusNotFound, "tx not found")
+		}
+		return c.SendString("tx " + c.Params("tx_hash"))
+	})
+
+	status, etag, cacheControl := conditionalGet(t, app, "/txs/abc", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, cacheControlImmutable, cacheControl)
+
+	status, _, _ = conditionalGet(t, app, "/txs/abc", `"other", `+etag)
+	assert.Equal(t, fiber.StatusNotModified, status)
+
+	status, otherETag, _ := conditionalGet(t, app, "/txs/def", etag)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.NotEqual(t, etag, otherETag)
+
+	status, etag, cacheControl = conditionalGet(t, app, "/txs/missing", "")
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.Empty(t, etag)
+	assert.Empty(t, cacheControl)
+}
+
+func TestFinalETag(t *testing.T) {
+	app := fiber.New()
+	app.Get("/blocks/:height", (*Cache)(nil).Final(), func(c *fiber.Ctx) error {
+		return c.SendString("block " + c.Params("height"))
+	})
+
+	// kept for a bounded time, then revalidated against the strong ETag
+	status, etag, cacheControl := conditionalGet(t, app, "/blocks/10", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, cacheControlFinal, cacheControl)
+	assert.NotContains(t, cacheControl, "immutable")
+
+	status, _, cacheControl = conditionalGet(t, app, "/blocks/10", etag)
+	assert.Equal(t, fiber.StatusNotModified, status)
+	assert.Equal(t, cacheControlFinal, cacheControl)
+}
+
+func TestHeightETag(t *testing.T) {
+	var height int64 = 100
+	store := heightCache(&height)
+
+	calls := 0
+	app := fiber.New()
+	app.Get("/txs", store.AppendOnly(), func(c *fiber.Ctx) error {
+		calls++
+		return c.SendString("txs")
+	})
+
+	status, etag, cacheControl := conditionalGet(t, app, "/txs", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, `W/"100"`, etag)
+	assert.Equal(t, cacheControlRevalidate, cacheControl)
+
+	// revalidation at the same height skips the handler and the cache
+	status, _, _ = conditionalGet(t, app, "/txs", etag)
+	assert.Equal(t, fiber.StatusNotModified, status)
+	assert.Equal(t, 1, calls)
+
+	height = 101
+	status, etag, _ = conditionalGet(t, app, "/txs", etag)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, `W/"101"`, etag)
+	assert.Equal(t, 2, calls)
+
+	// pages behind a descending cursor are final
+	status, etag, cacheControl = conditionalGet(t, app, "/txs?pagination.key=MTA=&pagination.count_total=false", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, cacheControlFinal, cacheControl)
+	status, _, _ = conditionalGet(t, app, "/txs?pagination.key=MTA=&pagination.count_total=false", etag)
+	assert.Equal(t, fiber.StatusNotModified, status)
+
+	// unless they count the total or go towards the head
+	_, etag, _ = conditionalGet(t, app, "/txs?pagination.key=MTA=", "")
+	assert.Equal(t, `W/"101"`, etag)
+	_, etag, _ = conditionalGet(t, app, "/txs?pagination.key=MTA=&pagination.count_total=false&pagination.reverse=false", "")
+	assert.Equal(t, `W/"101"`, etag)
+
+	// exports are streamed and not tagged
+	status, etag, _ = conditionalGet(t, app, "/txs?format=csv", `W/"101"`)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Empty(t, etag)
+}
+
+func TestMatchETag(t *testing.T) {
+	assert.False(t, matchETag("", `"a"`))
+	assert.True(t, matchETag(`"a"`, `"a"`))
+	assert.True(t, matchETag(`W/"a"`, `"a"`))
+	assert.True(t, matchETag(`"b", W/"a"`, `W/"a"`))
+	assert.True(t, matchETag("*", `"a"`))
+	assert.False(t, matchETag(`"b"`, `"a"`))
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// HeightTracker follows the latest indexed block height. It is read on every per block
+// cache lookup, so the height is reloaded in the background once the poll interval has
+// elapsed and lookups never wait for the database.
+type HeightTracker struct {
+	db       *gorm.DB
+	chainId  string
+	interval time.Duration
+	logger   *slog.Logger
+
+	height     atomic.Int64
+	loadedAt   atomic.Int64
+	refreshing atomic.Bool
+}
+
+// NewHeightTracker creates a tracker and loads the current height
+func NewHeightTracker(db *gorm.DB, chainId string, interval time.Duration, logger *slog.Logger) *HeightTracker {
+	t := &HeightTracker{
+		db:       db,
+		chainId:  chainId,
+		interval: interval,
+		logger:   logger,
+	}
+	t.refresh()
+	return t
+}
+
+// Height returns the latest known block height
+func (t *HeightTracker) Height() int64 {
+	if time.Since(time.Unix(0, t.loadedAt.Load())) >= t.interval && t.refreshing.CompareAndSwap(false, true) {
+		go func() {
+			defer t.refreshing.Store(false)
+			t.refresh()
+		}()
+	}
+	return t.height.Load()
+}
+
+func (t *HeightTracker) refresh() {
+	defer t.loadedAt.Store(time.Now().UnixNano())
+
+	var height int64
+	if err := t.db.Model(&types.CollectedBlock{}).
+		Select("height").
+		Where("chain_id = ?", t.chainId).
+		Order("height DESC").
+		Limit(1).
+		Scan(&height).Error; err != nil {
+		// keep the previous height, entries then expire by their max age
+		t.logger.Error("failed to load latest block height", "error", err)
+		return
+	}
+
+	t.height.Store(height)
+}
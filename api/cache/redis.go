@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultRedisPoolSize = 16
+	defaultRedisTimeout  = time.Second
+	redisScanCount       = 1000
+)
+
+// RedisOptions configures a RedisStorage
+type RedisOptions struct {
+	Addr     string
+	Password string
+	DB       int
+	// Prefix namespaces the keys, so that several chains can share a server
+	Prefix   string
+	PoolSize int
+	Timeout  time.Duration
+}
+
+// RedisStorage is a fiber.Storage backed by a Redis server, keeping its keys under a prefix
+type RedisStorage struct {
+	client  *redis.Client
+	prefix  string
+	timeout time.Duration
+}
+
+// NewRedisStorage connects to the server and checks it answers
+func NewRedisStorage(opts RedisOptions) (*RedisStorage, error) {
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = defaultRedisPoolSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultRedisTimeout
+	}
+
+	s := &RedisStorage{
+		client: redis.NewClient(&redis.Options{
+			Addr:         opts.Addr,
+			Password:     opts.Password,
+			DB:           opts.DB,
+			PoolSize:     opts.PoolSize,
+			DialTimeout:  opts.Timeout,
+			ReadTimeout:  opts.Timeout,
+			WriteTimeout: opts.Timeout,
+		}),
+		prefix:  opts.Prefix,
+		timeout: opts.Timeout,
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+	if err := s.client.Ping(ctx).Err(); err != nil {
+		_ = s.client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", opts.Addr, err)
+	}
+	return s, nil
+}
+
+// Get returns the value of the key, or nil if it does not exist
+func (s *RedisStorage) Get(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := s.context()
+	defer cancel()
+
+	val, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return val, err
+}
+
+// Set stores the value of the key, a zero expiration keeps it until deleted
+func (s *RedisStorage) Set(key string, val []byte, exp time.Duration) error {
+	if len(key) == 0 || len(val) == 0 {
+		return nil
+	}
+	ctx, cancel := s.context()
+	defer cancel()
+
+	return s.client.Set(ctx, s.prefix+key, val, exp).Err()
+}
+
+// Delete removes the key
+func (s *RedisStorage) Delete(key string) error {
+	if len(key) == 0 {
+		return nil
+	}
+	ctx, cancel := s.context()
+	defer cancel()
+
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
+
+// Reset removes every key under the prefix
+func (s *RedisStorage) Reset() error {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, s.prefix+"*", redisScanCount).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := s.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// Close closes the connections
+func (s *RedisStorage) Close() error {
+	return s.client.Close()
+}
+
+func (s *RedisStorage) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.timeout)
+}
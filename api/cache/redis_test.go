@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/metrics"
+)
+
+func TestRedisStorage(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+
+	_, err := NewRedisStorage(RedisOptions{Addr: server.Addr(), Password: "wrong"})
+	require.Error(t, err)
+
+	s, err := NewRedisStorage(RedisOptions{Addr: server.Addr(), Password: "secret", DB: 1, Prefix: "test:"})
+	require.NoError(t, err)
+	defer s.Close()
+
+	val, err := s.Get("missing")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	require.NoError(t, s.Set("key", []byte("value\r\nwith crlf"), 0))
+	val, err = s.Get("key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value\r\nwith crlf"), val)
+
+	require.NoError(t, s.Set("short", []byte("value"), 10*time.Millisecond))
+	server.FastForward(20 * time.Millisecond)
+	val, err = s.Get("short")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	require.NoError(t, s.Delete("key"))
+	val, err = s.Get("key")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	// reset only clears the keys under the prefix
+	server.Select(1)
+	require.NoError(t, server.Set("other:key", "value"))
+	require.NoError(t, s.Set("a", []byte("1"), 0))
+	require.NoError(t, s.Set("b", []byte("2"), 0))
+	require.NoError(t, s.Reset())
+	assert.Equal(t, []string{"other:key"}, server.DB(1).Keys())
+}
+
+func TestPerBlockCache(t *testing.T) {
+	metrics.Init("test-chain")
+
+	server := miniredis.RunT(t)
+	redis, err := NewRedisStorage(RedisOptions{Addr: server.Addr(), Prefix: "test:"})
+	require.NoError(t, err)
+	defer redis.Close()
+
+	var height int64 = 100
+	store := heightCache(&height)
+	store.storage = redis
+
+	// two replicas sharing the storage
+	calls := 0
+	newReplica := func() *fiber.App {
+		app := fiber.New()
+		app.Get("/txs", store.PerBlock(), func(c *fiber.Ctx) error {
+			calls++
+			return c.SendString("txs at " + strconv.FormatInt(height, 10))
+		})
+		return app
+	}
+	replicaA, replicaB := newReplica(), newReplica()
+
+	get := func(app *fiber.App) (string, string) {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/txs?pagination.limit=10", nil), -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.Header.Get(headerCache), string(body)
+	}
+
+	result, body := get(replicaA)
+	assert.Equal(t, "miss", result)
+	assert.Equal(t, "txs at 100", body)
+
+	result, body = get(replicaB)
+	assert.Equal(t, "hit", result)
+	assert.Equal(t, "txs at 100", body)
+	assert.Equal(t, 1, calls)
+
+	// a new block invalidates the entry on every replica
+	height = 101
+	result, body = get(replicaB)
+	assert.Equal(t, "miss", result)
+	assert.Equal(t, "txs at 101", body)
+	result, _ = get(replicaA)
+	assert.Equal(t, "hit", result)
+	assert.Equal(t, 2, calls)
+
+	cacheRequests := metrics.GetMetrics().HTTPMetrics().CacheRequests
+	assert.Equal(t, 2.0, testutil.ToFloat64(cacheRequests.WithLabelValues("/txs", "hit")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(cacheRequests.WithLabelValues("/txs", "miss")))
+}
@@ -68,11 +68,69 @@ const docTemplate = `{
                         "description": "Reverse order, default is true. if set to true, the results will be ordered in descending order",
                         "name": "pagination.reverse",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed proposer validator info (moniker, operator address), default is true",
+                        "name": "include_validator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or after this time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or before this time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
             }
         },
+        "/indexer/block/v1/blocks/batch": {
+            "post": {
+                "description": "Get up to 100 blocks by their heights in a single call. Results keep the request order and misses are marked with found=false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Block"
+                ],
+                "summary": "Get blocks by heights",
+                "parameters": [
+                    {
+                        "description": "Block heights",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/block.BlocksBatchRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed proposer validator info (moniker, operator address), default is true",
+                        "name": "include_validator",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/block.BlocksBatchResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/indexer/block/v1/blocks/{height}": {
             "get": {
                 "description": "Get a specific block by its height",
@@ -93,11 +151,60 @@ const docTemplate = `{
                         "name": "height",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed proposer validator info (moniker, operator address), default is true",
+                        "name": "include_validator",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
             }
         },
+        "/indexer/block/v1/proposers": {
+            "get": {
+                "description": "Get the number of blocks proposed per validator of the current set over a height window, with missed block estimates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Block"
+                ],
+                "summary": "Get proposer statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Start height (inclusive), default is to_height - 999",
+                        "name": "from_height",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End height (inclusive), default is the latest indexed height",
+                        "name": "to_height",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed proposer validator info (moniker, operator address), default is true",
+                        "name": "include_validator",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/block.ProposersResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/indexer/nft/v1/collections": {
             "get": {
                 "description": "Get NFT collections",
@@ -309,6 +416,40 @@ const docTemplate = `{
                 }
             }
         },
+        "/indexer/nft/v1/tokens/batch": {
+            "post": {
+                "description": "Get up to 100 NFT tokens by collection address and token ID in a single call. Results keep the request order and misses are marked with found=false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NFT"
+                ],
+                "summary": "Get NFT tokens by collection and token ID pairs",
+                "parameters": [
+                    {
+                        "description": "Collection address and token ID pairs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/nft.NftsBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/nft.NftsBatchResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/indexer/nft/v1/tokens/by_account/{account}": {
             "get": {
                 "description": "Get NFT tokens owned by a specific account",
@@ -530,6 +671,35 @@ const docTemplate = `{
                         "description": "Reverse order default is true if set to true, the results will be ordered in descending order",
                         "name": "pagination.reverse",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or after this time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or before this time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default",
+                        "name": "columns",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
@@ -585,6 +755,23 @@ const docTemplate = `{
                         "description": "Reverse order default is true if set to true, the results will be ordered in descending order",
                         "name": "pagination.reverse",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream all holders instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default",
+                        "name": "columns",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -597,6 +784,38 @@ const docTemplate = `{
                 }
             }
         },
+        "/indexer/search/v1": {
+            "get": {
+                "description": "Classify the input as a height, tx hash, EVM tx hash, account, collection address, collection name or denom and return the matching records with links to their canonical endpoints",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search input",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/search.SearchResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/indexer/tx/v1/evm-internal-txs": {
             "get": {
                 "description": "Get a list of EVM internal transactions with pagination",
@@ -640,6 +859,35 @@ const docTemplate = `{
                         "description": "Reverse order default is true if set to true, the results will be ordered in descending order",
                         "name": "pagination.reverse",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or after this time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or before this time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default",
+                        "name": "columns",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
@@ -695,6 +943,35 @@ const docTemplate = `{
                         "description": "Reverse order default is true if set to true, the results will be ordered in descending order",
                         "name": "pagination.reverse",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or after this time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or before this time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default",
+                        "name": "columns",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
@@ -853,6 +1130,62 @@ const docTemplate = `{
                         "description": "Reverse order default is true if set to true, the results will be ordered in descending order",
                         "name": "pagination.reverse",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or after this time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or before this time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default",
+                        "name": "columns",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/indexer/tx/v1/evm-txs/batch": {
+            "post": {
+                "description": "Get up to 100 EVM transactions by their hashes in a single call. Results keep the request order and misses are marked with found=false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "EVM Tx"
+                ],
+                "summary": "Get EVM transactions by hashes",
+                "parameters": [
+                    {
+                        "description": "EVM transaction hashes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tx.TxHashesRequest"
+                        }
                     }
                 ],
                 "responses": {}
@@ -914,6 +1247,35 @@ const docTemplate = `{
                         "description": "Filter by signer accounts, default is false",
                         "name": "is_signer",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or after this time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or before this time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default",
+                        "name": "columns",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
@@ -1053,19 +1415,111 @@ const docTemplate = `{
                         "description": "Message types to filter (comma-separated or multiple params)",
                         "name": "msgs",
                         "in": "query"
-                    }
-                ],
-                "responses": {}
-            }
-        },
-        "/indexer/tx/v1/txs/by_account/{account}": {
-            "get": {
-                "description": "Get transactions associated with a specific account",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                    },
+                    {
+                        "enum": [
+                            "success",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Filter by execution result",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter failed transactions by error codespace, not allowed with status=success",
+                        "name": "codespace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the contract called by a message (bech32 or hex address)",
+                        "name": "msg.contract",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"0x1::dex::swap\"",
+                        "description": "Filter by the function called by a message, a name, an evm selector or a move function id",
+                        "name": "msg.function",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"0x1::coin::transfer\"",
+                        "description": "Filter by the move entry function called, \u003caddress\u003e::\u003cmodule\u003e::\u003cfunction\u003e, or script for the scripts",
+                        "name": "move_function",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or after this time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or before this time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default",
+                        "name": "columns",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/indexer/tx/v1/txs/batch": {
+            "post": {
+                "description": "Get up to 100 transactions by their hashes in a single call. Results keep the request order and misses are marked with found=false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tx"
+                ],
+                "summary": "Get transactions by hashes",
+                "parameters": [
+                    {
+                        "description": "Transaction hashes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tx.TxHashesRequest"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/indexer/tx/v1/txs/by_account/{account}": {
+            "get": {
+                "description": "Get transactions associated with a specific account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
                     "Tx"
@@ -1125,6 +1579,64 @@ const docTemplate = `{
                         "description": "Message types to filter (comma-separated or multiple params)",
                         "name": "msgs",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "success",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Filter by execution result",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter failed transactions by error codespace, not allowed with status=success",
+                        "name": "codespace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the contract called by a message (bech32 or hex address)",
+                        "name": "msg.contract",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"0x1::dex::swap\"",
+                        "description": "Filter by the function called by a message, a name, an evm selector or a move function id",
+                        "name": "msg.function",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or after this time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only include records at or before this time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default",
+                        "name": "columns",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
@@ -1191,11 +1703,120 @@ const docTemplate = `{
                         "description": "Message types to filter (comma-separated or multiple params)",
                         "name": "msgs",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "success",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Filter by execution result",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter failed transactions by error codespace, not allowed with status=success",
+                        "name": "codespace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by the contract called by a message (bech32 or hex address)",
+                        "name": "msg.contract",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"0x1::dex::swap\"",
+                        "description": "Filter by the function called by a message, a name, an evm selector or a move function id",
+                        "name": "msg.function",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
             }
         },
+        "/indexer/tx/v1/txs/failures": {
+            "get": {
+                "description": "Aggregate failed transactions by codespace and code over a height window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tx"
+                ],
+                "summary": "Get transaction failure statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Start height (inclusive), default is to_height - 999",
+                        "name": "from_height",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End height (inclusive), default is the latest indexed height",
+                        "name": "to_height",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tx.TxFailuresResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexer/tx/v1/txs/move_functions": {
+            "get": {
+                "description": "Count the txs calling each move entry function, or a script, per UTC day over a time window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tx"
+                ],
+                "summary": "Get the top called move functions per day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the window (RFC3339), default is 7 days before to_time",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the window (RFC3339), default is now",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of functions per day, default is 10, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tx.MoveFunctionStatsResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/indexer/tx/v1/txs/{tx_hash}": {
             "get": {
                 "description": "Get a specific transaction by its hash",
@@ -1221,9 +1842,9 @@ const docTemplate = `{
                 "responses": {}
             }
         },
-        "/status": {
+        "/indexer/webhook/v1/subscriptions": {
             "get": {
-                "description": "Get current indexer status including chain ID and latest block height",
+                "description": "Get the webhook subscriptions with their delivery status",
                 "consumes": [
                     "application/json"
                 ],
@@ -1231,222 +1852,1064 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "App"
+                    "Webhook"
+                ],
+                "summary": "Get webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "account_tx",
+                            "collection_mint",
+                            "rich_list_top"
+                        ],
+                        "type": "string",
+                        "description": "Event of the subscriptions",
+                        "name": "event",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "pagination.offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit, default is 100",
+                        "name": "pagination.limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total, default is true",
+                        "name": "pagination.count_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse order default is true if set to true, the results will be ordered in descending order",
+                        "name": "pagination.reverse",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Status check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/status.StatusResponse"
+                            "$ref": "#/definitions/webhook.SubscriptionsResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe a URL to the txs of an account, the mints of a collection or the top holders of a denom. The HMAC secret signing the payloads is only returned here",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhook.CreateSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.CreateSubscriptionResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "/indexer/webhook/v1/subscriptions/{id}": {
+            "get": {
+                "description": "Get a webhook subscription with its delivery status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subscription id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.SubscriptionResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a webhook subscription along with its dead letters and pending retries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subscription id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.SubscriptionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/indexer/webhook/v1/subscriptions/{id}/dead_letters": {
+            "get": {
+                "description": "Get the notifications of a subscription which failed every delivery attempt",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get the dead letters of a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subscription id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "pagination.offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit, default is 100",
+                        "name": "pagination.limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count total, default is true",
+                        "name": "pagination.count_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reverse order default is true if set to true, the results will be ordered in descending order",
+                        "name": "pagination.reverse",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.DeadLettersResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "description": "Get current indexer status including chain ID and latest block height",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "App"
+                ],
+                "summary": "Status check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/status.StatusResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "block.Block": {
+            "type": "object",
+            "properties": {
+                "block_time": {
+                    "type": "string",
+                    "x-order:3": true
+                },
+                "chain_id": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "gas_used": {
+                    "type": "string",
+                    "x-order:5": true
+                },
+                "gas_wanted": {
+                    "type": "string",
+                    "x-order:6": true
+                },
+                "hash": {
+                    "type": "string",
+                    "x-order:2": true
+                },
+                "height": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "proposer": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/block.Proposer"
+                        }
+                    ],
+                    "x-order:9": true
+                },
+                "timestamp": {
+                    "type": "string",
+                    "x-order:4": true
+                },
+                "total_fee": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/block.Fee"
+                    },
+                    "x-order:8": true
+                },
+                "tx_count": {
+                    "type": "string",
+                    "x-order:7": true
+                }
+            }
+        },
+        "block.BlockBatchEntry": {
+            "type": "object",
+            "properties": {
+                "block": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/block.Block"
+                        }
+                    ],
+                    "x-order:2": true
+                },
+                "found": {
+                    "type": "boolean",
+                    "x-order:1": true
+                },
+                "height": {
+                    "type": "string",
+                    "x-order:0": true
+                }
+            }
+        },
+        "block.BlocksBatchRequest": {
+            "type": "object",
+            "properties": {
+                "heights": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "block.BlocksBatchResponse": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/block.BlockBatchEntry"
+                    }
+                }
+            }
+        },
+        "block.Fee": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "denom": {
+                    "type": "string",
+                    "x-order:0": true
+                }
+            }
+        },
+        "block.Proposer": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "x-order:3": true
+                },
+                "identity": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "moniker": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "operator_address": {
+                    "type": "string",
+                    "x-order:2": true
+                }
+            }
+        },
+        "block.ProposerStats": {
+            "type": "object",
+            "properties": {
+                "block_count": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "expected_blocks": {
+                    "type": "string",
+                    "x-order:3": true
+                },
+                "missed_blocks": {
+                    "type": "string",
+                    "x-order:4": true
+                },
+                "proposer": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/block.Proposer"
+                        }
+                    ],
+                    "x-order:0": true
+                },
+                "share": {
+                    "type": "string",
+                    "x-order:2": true
+                }
+            }
+        },
+        "block.ProposersResponse": {
+            "type": "object",
+            "properties": {
+                "from_height": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "proposers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/block.ProposerStats"
+                    },
+                    "x-order:3": true
+                },
+                "to_height": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "total_blocks": {
+                    "type": "string",
+                    "x-order:2": true
+                }
+            }
+        },
         "common.PaginationResponse": {
             "type": "object",
             "properties": {
-                "next_key": {
+                "next_key": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "previous_key": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "total": {
+                    "type": "string",
+                    "x-order:2": true
+                }
+            }
+        },
+        "nft.Collection": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/nft.CollectionDetail"
+                        }
+                    ],
+                    "x-order:1": true
+                },
+                "height": {
+                    "type": "integer",
+                    "x-order:2": true
+                },
+                "object_addr": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "timestamp": {
+                    "type": "string",
+                    "x-order:3": true
+                }
+            }
+        },
+        "nft.CollectionDetail": {
+            "type": "object",
+            "properties": {
+                "creator": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "name": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "nfts": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/nft.NftHandle"
+                        }
+                    ],
+                    "x-order:4": true
+                },
+                "origin_name": {
+                    "type": "string",
+                    "x-order:2": true
+                }
+            }
+        },
+        "nft.CollectionResponse": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "$ref": "#/definitions/nft.Collection"
+                }
+            }
+        },
+        "nft.CollectionsResponse": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/nft.Collection"
+                    },
+                    "x-order:0": true
+                },
+                "pagination": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/common.PaginationResponse"
+                        }
+                    ],
+                    "x-order:1": true
+                }
+            }
+        },
+        "nft.Nft": {
+            "type": "object",
+            "properties": {
+                "collection_addr": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "collection_name": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "collection_origin_name": {
+                    "type": "string",
+                    "x-order:2": true
+                },
+                "height": {
+                    "type": "integer",
+                    "x-order:6": true
+                },
+                "nft": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/nft.NftDetails"
+                        }
+                    ],
+                    "x-order:5": true
+                },
+                "object_addr": {
+                    "description": "only used in Move",
+                    "type": "string",
+                    "x-order:3": true
+                },
+                "owner": {
+                    "type": "string",
+                    "x-order:4": true
+                },
+                "timestamp": {
+                    "type": "string",
+                    "x-order:7": true
+                }
+            }
+        },
+        "nft.NftBatchEntry": {
+            "type": "object",
+            "properties": {
+                "collection_addr": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "found": {
+                    "type": "boolean",
+                    "x-order:2": true
+                },
+                "token": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/nft.Nft"
+                        }
+                    ],
+                    "x-order:3": true
+                },
+                "token_id": {
+                    "type": "string",
+                    "x-order:1": true
+                }
+            }
+        },
+        "nft.NftDetails": {
+            "type": "object",
+            "properties": {
+                "token_id": {
+                    "type": "string",
+                    "x-order:2": true
+                },
+                "uri": {
+                    "type": "string",
+                    "x-order:3": true
+                }
+            }
+        },
+        "nft.NftHandle": {
+            "type": "object",
+            "properties": {
+                "handle": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "length": {
+                    "type": "integer",
+                    "x-order:1": true
+                }
+            }
+        },
+        "nft.NftKey": {
+            "type": "object",
+            "properties": {
+                "collection_addr": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "nft.NftsBatchRequest": {
+            "type": "object",
+            "properties": {
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/nft.NftKey"
+                    }
+                }
+            }
+        },
+        "nft.NftsBatchResponse": {
+            "type": "object",
+            "properties": {
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/nft.NftBatchEntry"
+                    }
+                }
+            }
+        },
+        "nft.NftsResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/common.PaginationResponse"
+                        }
+                    ],
+                    "x-order:1": true
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/nft.Nft"
+                    },
+                    "x-order:0": true
+                }
+            }
+        },
+        "richlist.TokenHolder": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "string"
+                }
+            }
+        },
+        "richlist.TokenHoldersResponse": {
+            "type": "object",
+            "properties": {
+                "holders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/richlist.TokenHolder"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/common.PaginationResponse"
+                }
+            }
+        },
+        "search.HitType": {
+            "type": "string",
+            "enum": [
+                "block",
+                "tx",
+                "evm_tx",
+                "account",
+                "nft_collection",
+                "denom"
+            ],
+            "x-enum-varnames": [
+                "HitTypeBlock",
+                "HitTypeTx",
+                "HitTypeEvmTx",
+                "HitTypeAccount",
+                "HitTypeNftCollection",
+                "HitTypeDenom"
+            ]
+        },
+        "search.SearchHit": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "x-order:1": true
+                },
+                "link": {
+                    "type": "string",
+                    "x-order:3": true
+                },
+                "name": {
+                    "type": "string",
+                    "x-order:2": true
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/search.HitType"
+                        }
+                    ],
+                    "x-order:0": true
+                }
+            }
+        },
+        "search.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/search.SearchHit"
+                    },
+                    "x-order:1": true
+                },
+                "query": {
                     "type": "string",
+                    "x-order:0": true
+                }
+            }
+        },
+        "status.ExtensionStatus": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer",
                     "x-order:1": true
                 },
-                "previous_key": {
+                "last_error": {
+                    "type": "string",
+                    "x-order:7": true
+                },
+                "meta": {
+                    "type": "object",
+                    "x-order:4": true
+                },
+                "name": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "restarts": {
+                    "type": "integer",
+                    "x-order:6": true
+                },
+                "sequence": {
+                    "type": "integer",
+                    "x-order:2": true
+                },
+                "state": {
                     "type": "string",
-                    "x-order:0": true
+                    "enum": [
+                        "waiting",
+                        "running",
+                        "restarting",
+                        "paused",
+                        "degraded",
+                        "completed",
+                        "stopped"
+                    ],
+                    "x-order:5": true
                 },
-                "total": {
+                "updated_at": {
                     "type": "string",
-                    "x-order:2": true
+                    "x-order:3": true
                 }
             }
         },
-        "nft.Collection": {
+        "status.StatusResponse": {
             "type": "object",
             "properties": {
-                "collection": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/nft.CollectionDetail"
-                        }
-                    ],
+                "chain_id": {
+                    "type": "string",
+                    "x-order:2": true
+                },
+                "commit_hash": {
+                    "type": "string",
                     "x-order:1": true
                 },
+                "event_sink_height": {
+                    "type": "integer",
+                    "x-order:14": true
+                },
+                "evm_ret_cleanup_height": {
+                    "type": "integer",
+                    "x-order:6": true
+                },
+                "extensions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/status.ExtensionStatus"
+                    },
+                    "x-order:15": true
+                },
+                "extensions_role": {
+                    "type": "string",
+                    "x-order:13": true
+                },
                 "height": {
                     "type": "integer",
-                    "x-order:2": true
+                    "x-order:3": true
                 },
-                "object_addr": {
+                "indexer_role": {
                     "type": "string",
-                    "x-order:0": true
+                    "x-order:12": true
                 },
-                "timestamp": {
+                "internal_tx_height": {
+                    "type": "integer",
+                    "x-order:4": true
+                },
+                "prune_deleted": {
+                    "type": "integer",
+                    "x-order:11": true
+                },
+                "pruned_height": {
+                    "type": "integer",
+                    "x-order:10": true
+                },
+                "rich_list_height": {
+                    "type": "integer",
+                    "x-order:5": true
+                },
+                "tx_account_cleanup_deleted": {
+                    "type": "integer",
+                    "x-order:8": true
+                },
+                "tx_account_cleanup_inserted": {
+                    "type": "integer",
+                    "x-order:9": true
+                },
+                "tx_account_cleanup_sequence": {
+                    "type": "integer",
+                    "x-order:7": true
+                },
+                "version": {
                     "type": "string",
-                    "x-order:3": true
+                    "x-order:0": true
                 }
             }
         },
-        "nft.CollectionDetail": {
+        "tx.MoveFunctionCount": {
             "type": "object",
             "properties": {
-                "creator": {
-                    "type": "string",
-                    "x-order:0": true
-                },
-                "name": {
+                "count": {
                     "type": "string",
                     "x-order:1": true
                 },
-                "nfts": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/nft.NftHandle"
-                        }
-                    ],
-                    "x-order:4": true
-                },
-                "origin_name": {
+                "function": {
                     "type": "string",
-                    "x-order:2": true
+                    "x-order:0": true
                 }
             }
         },
-        "nft.CollectionResponse": {
+        "tx.MoveFunctionDayStats": {
             "type": "object",
             "properties": {
-                "collection": {
-                    "$ref": "#/definitions/nft.Collection"
+                "date": {
+                    "type": "string",
+                    "x-order:0": true
+                },
+                "functions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tx.MoveFunctionCount"
+                    },
+                    "x-order:1": true
                 }
             }
         },
-        "nft.CollectionsResponse": {
+        "tx.MoveFunctionStatsResponse": {
             "type": "object",
             "properties": {
-                "collections": {
+                "days": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/nft.Collection"
+                        "$ref": "#/definitions/tx.MoveFunctionDayStats"
                     },
+                    "x-order:2": true
+                },
+                "from_time": {
+                    "type": "string",
                     "x-order:0": true
                 },
-                "pagination": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/common.PaginationResponse"
-                        }
-                    ],
+                "to_time": {
+                    "type": "string",
                     "x-order:1": true
                 }
             }
         },
-        "nft.Nft": {
+        "tx.TxFailureStats": {
             "type": "object",
             "properties": {
-                "collection_addr": {
-                    "type": "string",
-                    "x-order:0": true
+                "code": {
+                    "type": "integer",
+                    "x-order:1": true
                 },
-                "collection_name": {
+                "codespace": {
                     "type": "string",
-                    "x-order:1": true
+                    "x-order:0": true
                 },
-                "collection_origin_name": {
+                "count": {
                     "type": "string",
                     "x-order:2": true
                 },
-                "height": {
-                    "type": "integer",
-                    "x-order:6": true
-                },
-                "nft": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/nft.NftDetails"
-                        }
-                    ],
-                    "x-order:5": true
-                },
-                "object_addr": {
-                    "description": "only used in Move",
+                "last_height": {
                     "type": "string",
                     "x-order:3": true
                 },
-                "owner": {
+                "last_txhash": {
                     "type": "string",
                     "x-order:4": true
-                },
-                "timestamp": {
-                    "type": "string",
-                    "x-order:7": true
                 }
             }
         },
-        "nft.NftDetails": {
+        "tx.TxFailuresResponse": {
             "type": "object",
             "properties": {
-                "token_id": {
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tx.TxFailureStats"
+                    },
+                    "x-order:3": true
+                },
+                "from_height": {
                     "type": "string",
-                    "x-order:2": true
+                    "x-order:0": true
                 },
-                "uri": {
+                "to_height": {
                     "type": "string",
-                    "x-order:3": true
+                    "x-order:1": true
+                },
+                "total_failed": {
+                    "type": "string",
+                    "x-order:2": true
                 }
             }
         },
-        "nft.NftHandle": {
+        "tx.TxHashesRequest": {
             "type": "object",
             "properties": {
-                "handle": {
+                "tx_hashes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "webhook.CreateSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "event": {
                     "type": "string",
-                    "x-order:0": true
+                    "enum": [
+                        "account_tx",
+                        "collection_mint",
+                        "rich_list_top"
+                    ]
                 },
-                "length": {
-                    "type": "integer",
-                    "x-order:1": true
+                "target": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "nft.NftsResponse": {
+        "webhook.CreateSubscriptionResponse": {
             "type": "object",
             "properties": {
-                "pagination": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/common.PaginationResponse"
-                        }
-                    ],
-                    "x-order:1": true
+                "secret": {
+                    "type": "string"
                 },
-                "tokens": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/nft.Nft"
-                    },
-                    "x-order:0": true
+                "subscription": {
+                    "$ref": "#/definitions/webhook.Subscription"
                 }
             }
         },
-        "richlist.TokenHolder": {
+        "webhook.DeadLetter": {
             "type": "object",
             "properties": {
-                "account": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "amount": {
+                "delivery_id": {
+                    "type": "string"
+                },
+                "error": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status_code": {
+                    "type": "integer"
                 }
             }
         },
-        "richlist.TokenHoldersResponse": {
+        "webhook.DeadLettersResponse": {
             "type": "object",
             "properties": {
-                "holders": {
+                "dead_letters": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/richlist.TokenHolder"
+                        "$ref": "#/definitions/webhook.DeadLetter"
                     }
                 },
                 "pagination": {
@@ -1454,48 +2917,68 @@ const docTemplate = `{
                 }
             }
         },
-        "status.StatusResponse": {
+        "webhook.DeliveryStatus": {
             "type": "object",
             "properties": {
-                "chain_id": {
-                    "type": "string",
-                    "x-order:2": true
+                "delivered": {
+                    "type": "string"
                 },
-                "commit_hash": {
-                    "type": "string",
-                    "x-order:1": true
+                "failed": {
+                    "type": "string"
                 },
-                "evm_ret_cleanup_height": {
-                    "type": "integer",
-                    "x-order:6": true
+                "last_delivery_at": {
+                    "type": "string"
                 },
-                "height": {
-                    "type": "integer",
-                    "x-order:3": true
+                "last_error": {
+                    "type": "string"
                 },
-                "internal_tx_height": {
-                    "type": "integer",
-                    "x-order:4": true
+                "last_status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "webhook.Subscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "rich_list_height": {
-                    "type": "integer",
-                    "x-order:5": true
+                "delivery": {
+                    "$ref": "#/definitions/webhook.DeliveryStatus"
                 },
-                "tx_account_cleanup_deleted": {
-                    "type": "integer",
-                    "x-order:8": true
+                "event": {
+                    "type": "string"
                 },
-                "tx_account_cleanup_inserted": {
-                    "type": "integer",
-                    "x-order:9": true
+                "id": {
+                    "type": "string"
                 },
-                "tx_account_cleanup_sequence": {
-                    "type": "integer",
-                    "x-order:7": true
+                "target": {
+                    "type": "string"
                 },
-                "version": {
-                    "type": "string",
-                    "x-order:0": true
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhook.SubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "subscription": {
+                    "$ref": "#/definitions/webhook.Subscription"
+                }
+            }
+        },
+        "webhook.SubscriptionsResponse": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/common.PaginationResponse"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webhook.Subscription"
+                    }
                 }
             }
         }
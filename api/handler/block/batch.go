@@ -0,0 +1,62 @@
+package block
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+// GetBlocksBatch handles POST /block/v1/blocks/batch
+// @Summary Get blocks by heights
+// @Description Get up to 100 blocks by their heights in a single call. Results keep the request order and misses are marked with found=false
+// @Tags Block
+// @Accept json
+// @Produce json
+// @Param request body BlocksBatchRequest true "Block heights"
+// @Param include_validator query bool false "Embed proposer validator info (moniker, operator address), default is true" default is true
+// @Success 200 {object} BlocksBatchResponse
+// @Router /indexer/block/v1/blocks/batch [post]
+func (h *BlockHandler) GetBlocksBatch(c *fiber.Ctx) error {
+	var req BlocksBatchRequest
+	if err := common.ParseBatchRequest(c, &req, "heights", func() int { return len(req.Heights) }); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	for _, height := range req.Heights {
+		if height < 1 {
+			return fiber.NewError(fiber.StatusBadRequest, types.NewInvalidValueError("heights", fmt.Sprintf("%d", height), "must be a positive integer").Error())
+		}
+	}
+	includeValidator := c.QueryBool("include_validator", true)
+
+	var blocks []types.CollectedBlock
+	if err := h.buildBaseBlockQuery().
+		Where("height IN ?", req.Heights).
+		Find(&blocks).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get blocks", err).Error())
+	}
+
+	blocksRes, err := ToBlocksResponse(c.UserContext(), blocks, h.querier, includeValidator)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	found := make(map[int64]*Block, len(blocks))
+	for idx := range blocks {
+		found[blocks[idx].Height] = &blocksRes[idx]
+	}
+
+	entries := make([]BlockBatchEntry, 0, len(req.Heights))
+	for _, height := range req.Heights {
+		block, ok := found[height]
+		entries = append(entries, BlockBatchEntry{
+			Height: fmt.Sprintf("%d", height),
+			Found:  ok,
+			Block:  block,
+		})
+	}
+
+	return c.JSON(BlocksBatchResponse{Blocks: entries})
+}
@@ -20,12 +20,25 @@ import (
 // @Param pagination.offset query int false "Pagination offset"
 // @Param pagination.limit query int false "Pagination limit, default is 100"
 // @Param pagination.reverse query bool false "Reverse order, default is true. if set to true, the results will be ordered in descending order"
+// @Param include_validator query bool false "Embed proposer validator info (moniker, operator address), default is true" default is true
+// @Param from_time query string false "Only include records at or after this time (RFC3339)"
+// @Param to_time query string false "Only include records at or before this time (RFC3339)"
 // @Router /indexer/block/v1/blocks [get]
 func (h *BlockHandler) GetBlocks(c *fiber.Ctx) error {
 	pagination, err := common.ParsePagination(c, common.CursorTypeHeight)
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	includeValidator := c.QueryBool("include_validator", true)
+
+	pagination.Range, err = h.GetHeightRange(h.GetDatabase().DB, timeRange)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
 
 	var lastBlock types.CollectedBlock
 	if err := h.buildBaseBlockQuery().
@@ -34,7 +47,7 @@ func (h *BlockHandler) GetBlocks(c *fiber.Ctx) error {
 		First(&lastBlock).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
-	total := lastBlock.Height
+	total := countBlocksInRange(lastBlock.Height, pagination.Range)
 
 	var blocks []types.CollectedBlock
 	query := h.buildBaseBlockQuery()
@@ -43,7 +56,7 @@ func (h *BlockHandler) GetBlocks(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	blocksRes, err := ToBlocksResponse(c.UserContext(), blocks, h.querier)
+	blocksRes, err := ToBlocksResponse(c.UserContext(), blocks, h.querier, includeValidator)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -66,24 +79,33 @@ func (h *BlockHandler) GetBlocks(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param height path string true "Block height"
+// @Param include_validator query bool false "Embed proposer validator info (moniker, operator address), default is true" default is true
 // @Router /indexer/block/v1/blocks/{height} [get]
 func (h *BlockHandler) GetBlockByHeight(c *fiber.Ctx) error {
 	height, err := common.GetHeightParam(c)
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	includeValidator := c.QueryBool("include_validator", true)
 
 	var block types.CollectedBlock
 	if err := h.buildBaseBlockQuery().
 		Where("height = ?", height).
 		First(&block).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get block", err).Error())
+		}
+		archived, err := h.FindArchivedBlock(height)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, types.NewInternalError("get archived block", err).Error())
+		}
+		if archived == nil {
 			return fiber.NewError(fiber.StatusNotFound, types.NewNotFoundError("block").Error())
 		}
-		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get block", err).Error())
+		block = *archived
 	}
 
-	blockRes, err := ToBlockResponse(c.UserContext(), block, h.querier)
+	blockRes, err := ToBlockResponse(c.UserContext(), block, h.querier, includeValidator)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -125,6 +147,22 @@ func (h *BlockHandler) GetAvgBlockTime(c *fiber.Ctx) error {
 	})
 }
 
+// countBlocksInRange counts the blocks up to the latest height within the optional height range
+func countBlocksInRange(latestHeight int64, hr *common.KeyRange) int64 {
+	if hr == nil {
+		return latestHeight
+	}
+	if hr.Empty {
+		return 0
+	}
+
+	from, to := max(hr.From, 1), latestHeight
+	if hr.To != 0 {
+		to = min(hr.To, latestHeight)
+	}
+	return max(0, to-from+1)
+}
+
 func (h *BlockHandler) buildBaseBlockQuery() *gorm.DB {
 	return h.GetDatabase().
 		Model(&types.CollectedBlock{}).
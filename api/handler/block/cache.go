@@ -3,11 +3,16 @@ package block
 import (
 	"context"
 
+	"golang.org/x/sync/singleflight"
+
 	"github.com/initia-labs/rollytics/types"
 	"github.com/initia-labs/rollytics/util/cache"
 	"github.com/initia-labs/rollytics/util/querier"
 )
 
+// validatorSetGroup lets concurrent requests share a fetch of the validator set
+var validatorSetGroup singleflight.Group
+
 func getValidator(ctx context.Context, querier *querier.Querier, validatorAddr string) (*types.ValidatorResponse, error) {
 	cached, ok := cache.GetValidatorCache(validatorAddr)
 	if ok {
@@ -22,3 +27,24 @@ func getValidator(ctx context.Context, querier *querier.Querier, validatorAddr s
 
 	return validator, nil
 }
+
+func getValidatorSet(ctx context.Context, querier *querier.Querier) ([]types.Validator, error) {
+	cached, ok := cache.GetValidatorSetCache()
+	if ok {
+		return cached, nil
+	}
+	validators, err, _ := validatorSetGroup.Do("validators", func() (any, error) {
+		validators, err := querier.GetValidators(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.SetValidatorSetCache(validators)
+
+		return validators, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return validators.([]types.Validator), nil
+}
@@ -16,10 +16,11 @@ var _ common.HandlerRegistrar = (*BlockHandler)(nil)
 type BlockHandler struct {
 	*common.BaseHandler
 	querier *querier.Querier
+	cache   *cache.Cache
 }
 
-func NewBlockHandler(base *common.BaseHandler, cfg *config.Config) *BlockHandler {
-	return &BlockHandler{BaseHandler: base, querier: querier.NewQuerier(cfg.GetChainConfig())}
+func NewBlockHandler(base *common.BaseHandler, cfg *config.Config, apiCache *cache.Cache) *BlockHandler {
+	return &BlockHandler{BaseHandler: base, querier: querier.NewQuerier(cfg.GetChainConfig()), cache: apiCache}
 }
 
 func (h *BlockHandler) Register(router fiber.Router) {
@@ -27,7 +28,9 @@ func (h *BlockHandler) Register(router fiber.Router) {
 	// initValidatorCache(h.GetConfig())
 	blocks := router.Group("indexer/block/v1")
 
-	blocks.Get("/blocks", cache.WithExpiration(time.Second), h.GetBlocks)
-	blocks.Get("/blocks/:height", cache.WithExpiration(10*time.Second), h.GetBlockByHeight)
-	blocks.Get("/avg_blocktime", cache.WithExpiration(10*time.Second), h.GetAvgBlockTime)
+	blocks.Get("/blocks", h.cache.AppendOnly(), h.GetBlocks)
+	blocks.Get("/blocks/:height", h.cache.Final(), h.GetBlockByHeight)
+	blocks.Post("/blocks/batch", h.GetBlocksBatch)
+	blocks.Get("/avg_blocktime", h.cache.WithExpiration(10*time.Second), h.GetAvgBlockTime)
+	blocks.Get("/proposers", h.cache.WithExpiration(10*time.Second), h.GetProposers)
 }
@@ -0,0 +1,222 @@
+package block
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+const (
+	DefaultProposerWindow = 1000
+	MaxProposerWindow     = 100000
+
+	ed25519PubKeyType   = "/cosmos.crypto.ed25519.PubKey"
+	secp256k1PubKeyType = "/cosmos.crypto.secp256k1.PubKey"
+)
+
+type proposerCount struct {
+	Proposer   string
+	BlockCount int64
+}
+
+// GetProposers handles GET /block/v1/proposers
+// @Summary Get proposer statistics
+// @Description Get the number of blocks proposed per validator of the current set over a height window, with missed block estimates
+// @Tags Block
+// @Accept json
+// @Produce json
+// @Param from_height query int false "Start height (inclusive), default is to_height - 999"
+// @Param to_height query int false "End height (inclusive), default is the latest indexed height"
+// @Param include_validator query bool false "Embed proposer validator info (moniker, operator address), default is true" default is true
+// @Success 200 {object} ProposersResponse
+// @Router /indexer/block/v1/proposers [get]
+func (h *BlockHandler) GetProposers(c *fiber.Ctx) error {
+	fromHeight, err := common.GetHeightQuery(c, "from_height")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	toHeight, err := common.GetHeightQuery(c, "to_height")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	includeValidator := c.QueryBool("include_validator", true)
+
+	if toHeight == 0 {
+		var lastBlock types.CollectedBlock
+		if err := h.buildBaseBlockQuery().
+			Order("height DESC").
+			Limit(1).
+			First(&lastBlock).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		toHeight = lastBlock.Height
+	}
+	fromHeight, toHeight, err = common.ResolveHeightRange(fromHeight, toHeight, DefaultProposerWindow, MaxProposerWindow)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if toHeight == 0 {
+		return c.JSON(ProposersResponse{
+			FromHeight:  "0",
+			ToHeight:    "0",
+			TotalBlocks: "0",
+			Proposers:   []ProposerStats{},
+		})
+	}
+
+	var counts []proposerCount
+	if err := h.buildBaseBlockQuery().
+		Select("proposer, COUNT(*) AS block_count").
+		Where("height >= ? AND height <= ?", fromHeight, toHeight).
+		Group("proposer").
+		Scan(&counts).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get proposers", err).Error())
+	}
+
+	set := h.validatorSet(c.UserContext())
+	stats, total := computeProposerStats(counts, slices.Collect(maps.Keys(set)))
+	if includeValidator {
+		for idx := range stats {
+			stats[idx].Proposer = h.resolveProposer(c.UserContext(), stats[idx].Proposer.Address, set)
+		}
+	}
+
+	return c.JSON(ProposersResponse{
+		FromHeight:  fmt.Sprintf("%d", fromHeight),
+		ToHeight:    fmt.Sprintf("%d", toHeight),
+		TotalBlocks: fmt.Sprintf("%d", total),
+		Proposers:   stats,
+	})
+}
+
+// computeProposerStats aggregates per-proposer block counts into statistics ordered by block count.
+// The counts are left joined with the validators of the current set, so that validators which did not
+// propose in the window are listed with no blocks. Missed blocks are estimated against an equal share
+// of the window for every listed proposer, which matches the round-robin proposer selection of an
+// equally weighted validator set.
+func computeProposerStats(counts []proposerCount, validators []string) ([]ProposerStats, int64) {
+	observed := make(map[string]struct{}, len(counts))
+	for _, count := range counts {
+		observed[count.Proposer] = struct{}{}
+	}
+	for _, validator := range validators {
+		if _, ok := observed[validator]; !ok {
+			observed[validator] = struct{}{}
+			counts = append(counts, proposerCount{Proposer: validator})
+		}
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].BlockCount != counts[j].BlockCount {
+			return counts[i].BlockCount > counts[j].BlockCount
+		}
+		return counts[i].Proposer < counts[j].Proposer
+	})
+
+	var total int64
+	for _, count := range counts {
+		total += count.BlockCount
+	}
+
+	stats := make([]ProposerStats, 0, len(counts))
+	if total == 0 {
+		return stats, 0
+	}
+
+	expected := int64(math.Round(float64(total) / float64(len(counts))))
+	for _, count := range counts {
+		stats = append(stats, ProposerStats{
+			Proposer:       Proposer{Address: count.Proposer},
+			BlockCount:     fmt.Sprintf("%d", count.BlockCount),
+			Share:          fmt.Sprintf("%.4f", float64(count.BlockCount)/float64(total)),
+			ExpectedBlocks: fmt.Sprintf("%d", expected),
+			MissedBlocks:   fmt.Sprintf("%d", max(0, expected-count.BlockCount)),
+		})
+	}
+
+	return stats, total
+}
+
+// validatorSet returns the validators of the current set by proposer address. The statistics
+// only miss the validators without blocks when the set cannot be fetched, so failures are logged.
+func (h *BlockHandler) validatorSet(ctx context.Context) map[string]types.Validator {
+	validators, err := getValidatorSet(ctx, h.querier)
+	if err != nil {
+		h.GetLogger().Warn("failed to fetch validator set", slog.Any("error", err))
+		return nil
+	}
+
+	set := make(map[string]types.Validator, len(validators))
+	for _, validator := range validators {
+		addr, err := proposerAddress(validator.ConsensusPubkey)
+		if err != nil {
+			h.GetLogger().Warn("failed to derive proposer address", slog.String("validator", validator.OperatorAddress), slog.Any("error", err))
+			continue
+		}
+		set[addr] = validator
+	}
+	return set
+}
+
+// proposerAddress returns the address blocks record as proposer for a consensus key
+func proposerAddress(pubkey types.ConsensusPubkey) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(pubkey.Key)
+	if err != nil {
+		return "", types.NewInvalidValueError("consensus_pubkey", pubkey.Key, "must be base64 encoded")
+	}
+
+	var pk cryptotypes.PubKey
+	switch pubkey.Type {
+	case ed25519PubKeyType:
+		if len(key) != ed25519.PubKeySize {
+			return "", types.NewInvalidValueError("consensus_pubkey", pubkey.Key, "invalid ed25519 key size")
+		}
+		pk = &ed25519.PubKey{Key: key}
+	case secp256k1PubKeyType:
+		if len(key) != secp256k1.PubKeySize {
+			return "", types.NewInvalidValueError("consensus_pubkey", pubkey.Key, "invalid secp256k1 key size")
+		}
+		pk = &secp256k1.PubKey{Key: key}
+	default:
+		return "", types.NewInvalidValueError("consensus_pubkey", pubkey.Type, "unsupported key type")
+	}
+	return sdk.ValAddress(pk.Address()).String(), nil
+}
+
+// resolveProposer returns the validator info of a proposer, from the current set when it is part
+// of it. Validators that already left the set can no longer be resolved, so lookup failures only
+// leave the info empty.
+func (h *BlockHandler) resolveProposer(ctx context.Context, addr string, set map[string]types.Validator) Proposer {
+	proposer := Proposer{Address: addr}
+	if validator, ok := set[addr]; ok {
+		proposer.Moniker = validator.Moniker
+		proposer.OperatorAddress = validator.OperatorAddress
+		return proposer
+	}
+
+	validator, err := getValidator(ctx, h.querier, addr)
+	if err != nil {
+		h.GetLogger().Warn("failed to resolve proposer", slog.String("proposer", addr), slog.Any("error", err))
+		return proposer
+	}
+
+	proposer.Moniker = validator.Validator.Moniker
+	proposer.OperatorAddress = validator.Validator.OperatorAddress
+	return proposer
+}
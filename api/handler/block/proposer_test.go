@@ -0,0 +1,106 @@
+package block
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/cache"
+)
+
+func TestComputeProposerStats(t *testing.T) {
+	t.Run("empty window", func(t *testing.T) {
+		stats, total := computeProposerStats(nil, nil)
+		assert.Empty(t, stats)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("single proposer", func(t *testing.T) {
+		stats, total := computeProposerStats([]proposerCount{
+			{Proposer: "initvaloper1a", BlockCount: 100},
+		}, nil)
+		assert.Equal(t, int64(100), total)
+		assert.Len(t, stats, 1)
+		assert.Equal(t, "initvaloper1a", stats[0].Proposer.Address)
+		assert.Equal(t, "100", stats[0].BlockCount)
+		assert.Equal(t, "1.0000", stats[0].Share)
+		assert.Equal(t, "100", stats[0].ExpectedBlocks)
+		assert.Equal(t, "0", stats[0].MissedBlocks)
+	})
+
+	t.Run("ordered by block count with missed estimates", func(t *testing.T) {
+		stats, total := computeProposerStats([]proposerCount{
+			{Proposer: "initvaloper1c", BlockCount: 20},
+			{Proposer: "initvaloper1a", BlockCount: 50},
+			{Proposer: "initvaloper1b", BlockCount: 20},
+			{Proposer: "initvaloper1d", BlockCount: 10},
+		}, nil)
+		assert.Equal(t, int64(100), total)
+		assert.Len(t, stats, 4)
+
+		// ties are broken by proposer address
+		assert.Equal(t, "initvaloper1a", stats[0].Proposer.Address)
+		assert.Equal(t, "initvaloper1b", stats[1].Proposer.Address)
+		assert.Equal(t, "initvaloper1c", stats[2].Proposer.Address)
+		assert.Equal(t, "initvaloper1d", stats[3].Proposer.Address)
+
+		assert.Equal(t, "0.5000", stats[0].Share)
+		assert.Equal(t, "25", stats[0].ExpectedBlocks)
+		assert.Equal(t, "0", stats[0].MissedBlocks)
+		assert.Equal(t, "5", stats[1].MissedBlocks)
+		assert.Equal(t, "15", stats[3].MissedBlocks)
+	})
+	t.Run("validators without blocks", func(t *testing.T) {
+		stats, total := computeProposerStats([]proposerCount{
+			{Proposer: "initvaloper1a", BlockCount: 60},
+			{Proposer: "initvaloper1b", BlockCount: 40},
+		}, []string{"initvaloper1c", "initvaloper1a", "initvaloper1b", "initvaloper1d"})
+		assert.Equal(t, int64(100), total)
+		assert.Len(t, stats, 4)
+
+		// the validators of the set which did not propose are listed with no blocks
+		assert.Equal(t, "initvaloper1c", stats[2].Proposer.Address)
+		assert.Equal(t, "initvaloper1d", stats[3].Proposer.Address)
+		for _, stat := range stats[2:] {
+			assert.Equal(t, "0", stat.BlockCount)
+			assert.Equal(t, "0.0000", stat.Share)
+			assert.Equal(t, "25", stat.ExpectedBlocks)
+			assert.Equal(t, "25", stat.MissedBlocks)
+		}
+		assert.Equal(t, "0", stats[0].MissedBlocks)
+		assert.Equal(t, "0", stats[1].MissedBlocks)
+	})
+}
+
+func TestProposerAddress(t *testing.T) {
+	pubKey := ed25519.GenPrivKey().PubKey()
+
+	addr, err := proposerAddress(types.ConsensusPubkey{
+		Type: ed25519PubKeyType,
+		Key:  base64.StdEncoding.EncodeToString(pubKey.Bytes()),
+	})
+	require.NoError(t, err)
+	// blocks record the proposer as the validator address of the consensus address
+	assert.Equal(t, sdk.ValAddress(pubKey.Address()).String(), addr)
+
+	_, err = proposerAddress(types.ConsensusPubkey{Type: ed25519PubKeyType, Key: base64.StdEncoding.EncodeToString([]byte("short"))})
+	assert.Error(t, err)
+	_, err = proposerAddress(types.ConsensusPubkey{Type: "/unknown.PubKey", Key: base64.StdEncoding.EncodeToString(pubKey.Bytes())})
+	assert.Error(t, err)
+}
+
+func TestGetValidatorSet_Cached(t *testing.T) {
+	cache.SetValidatorSetCache([]types.Validator{{Moniker: "validator", OperatorAddress: "initvaloper1a"}})
+
+	// the set is served from the cache without querying the node
+	validators, err := getValidatorSet(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, validators, 1)
+	assert.Equal(t, "validator", validators[0].Moniker)
+}
@@ -48,12 +48,28 @@ type Proposer struct {
 	Moniker         string `json:"moniker" extensions:"x-order:0"`
 	Identity        string `json:"identity" extensions:"x-order:1"`
 	OperatorAddress string `json:"operator_address" extensions:"x-order:2"`
+	Address         string `json:"address" extensions:"x-order:3"`
 }
 
-func ToBlocksResponse(ctx context.Context, cbs []types.CollectedBlock, querier *querier.Querier) ([]Block, error) {
+type ProposersResponse struct {
+	FromHeight  string          `json:"from_height" extensions:"x-order:0"`
+	ToHeight    string          `json:"to_height" extensions:"x-order:1"`
+	TotalBlocks string          `json:"total_blocks" extensions:"x-order:2"`
+	Proposers   []ProposerStats `json:"proposers" extensions:"x-order:3"`
+}
+
+type ProposerStats struct {
+	Proposer       Proposer `json:"proposer" extensions:"x-order:0"`
+	BlockCount     string   `json:"block_count" extensions:"x-order:1"`
+	Share          string   `json:"share" extensions:"x-order:2"`
+	ExpectedBlocks string   `json:"expected_blocks" extensions:"x-order:3"`
+	MissedBlocks   string   `json:"missed_blocks" extensions:"x-order:4"`
+}
+
+func ToBlocksResponse(ctx context.Context, cbs []types.CollectedBlock, querier *querier.Querier, includeValidator bool) ([]Block, error) {
 	blocks := make([]Block, 0, len(cbs))
 	for _, cb := range cbs {
-		block, err := ToBlockResponse(ctx, cb, querier)
+		block, err := ToBlockResponse(ctx, cb, querier, includeValidator)
 		if err != nil {
 			return nil, err
 		}
@@ -62,15 +78,23 @@ func ToBlocksResponse(ctx context.Context, cbs []types.CollectedBlock, querier *
 	return blocks, nil
 }
 
-func ToBlockResponse(ctx context.Context, cb types.CollectedBlock, querier *querier.Querier) (block Block, err error) {
+// ToBlockResponse converts a collected block into the API response. Validator info
+// (moniker, operator address) is only resolved when includeValidator is set, since
+// it may require a remote query for validators that are not cached yet.
+func ToBlockResponse(ctx context.Context, cb types.CollectedBlock, querier *querier.Querier, includeValidator bool) (block Block, err error) {
 	var fees []Fee
 	if err := json.Unmarshal(cb.TotalFee, &fees); err != nil {
 		return block, err
 	}
 
-	validatorResponse, err := getValidator(ctx, querier, cb.Proposer)
-	if err != nil {
-		return block, err
+	proposer := Proposer{Address: cb.Proposer}
+	if includeValidator {
+		validatorResponse, err := getValidator(ctx, querier, cb.Proposer)
+		if err != nil {
+			return block, err
+		}
+		proposer.Moniker = validatorResponse.Validator.Moniker
+		proposer.OperatorAddress = validatorResponse.Validator.OperatorAddress
 	}
 
 	return Block{
@@ -83,9 +107,20 @@ func ToBlockResponse(ctx context.Context, cb types.CollectedBlock, querier *quer
 		GasWanted: fmt.Sprintf("%d", cb.GasWanted),
 		TxCount:   fmt.Sprintf("%d", cb.TxCount),
 		TotalFee:  fees,
-		Proposer: Proposer{
-			Moniker:         validatorResponse.Validator.Moniker,
-			OperatorAddress: validatorResponse.Validator.OperatorAddress,
-		},
+		Proposer:  proposer,
 	}, nil
 }
+
+type BlocksBatchRequest struct {
+	Heights []int64 `json:"heights"`
+}
+
+type BlockBatchEntry struct {
+	Height string `json:"height" extensions:"x-order:0"`
+	Found  bool   `json:"found" extensions:"x-order:1"`
+	Block  *Block `json:"block" extensions:"x-order:2"`
+}
+
+type BlocksBatchResponse struct {
+	Blocks []BlockBatchEntry `json:"blocks"`
+}
@@ -5,24 +5,29 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/initia-labs/rollytics/api/cache"
 	"github.com/initia-labs/rollytics/api/handler/block"
 	"github.com/initia-labs/rollytics/api/handler/nft"
 	"github.com/initia-labs/rollytics/api/handler/richlist"
+	"github.com/initia-labs/rollytics/api/handler/search"
 	"github.com/initia-labs/rollytics/api/handler/tx"
+	"github.com/initia-labs/rollytics/api/handler/webhook"
 	"github.com/initia-labs/rollytics/config"
 	"github.com/initia-labs/rollytics/orm"
 	"github.com/initia-labs/rollytics/util/common-handler/common"
 	"github.com/initia-labs/rollytics/util/common-handler/status"
 )
 
-func Register(router fiber.Router, db *orm.Database, cfg *config.Config, logger *slog.Logger) {
+func Register(router fiber.Router, db *orm.Database, cfg *config.Config, logger *slog.Logger, apiCache *cache.Cache) {
 	base := common.NewBaseHandler(db, cfg, logger)
 	handlers := []common.HandlerRegistrar{
-		status.NewStatusHandler(base),
-		block.NewBlockHandler(base, cfg),
-		tx.NewTxHandler(base),
-		nft.NewNftHandler(base),
-		richlist.NewRichListHandler(base, cfg),
+		status.NewStatusHandler(base, apiCache),
+		block.NewBlockHandler(base, cfg, apiCache),
+		tx.NewTxHandler(base, apiCache),
+		nft.NewNftHandler(base, apiCache),
+		richlist.NewRichListHandler(base, cfg, apiCache),
+		search.NewSearchHandler(base, apiCache),
+		webhook.NewWebhookHandler(base, cfg),
 	}
 
 	for _, handler := range handlers {
@@ -0,0 +1,81 @@
+package nft
+
+import (
+	"database/sql"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+// GetTokensBatch handles POST /nft/v1/tokens/batch
+// @Summary Get NFT tokens by collection and token ID pairs
+// @Description Get up to 100 NFT tokens by collection address and token ID in a single call. Results keep the request order and misses are marked with found=false
+// @Tags NFT
+// @Accept json
+// @Produce json
+// @Param request body NftsBatchRequest true "Collection address and token ID pairs"
+// @Success 200 {object} NftsBatchResponse
+// @Router /indexer/nft/v1/tokens/batch [post]
+func (h *NftHandler) GetTokensBatch(c *fiber.Ctx) error {
+	var req NftsBatchRequest
+	if err := common.ParseBatchRequest(c, &req, "tokens", func() int { return len(req.Tokens) }); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	keys := make([]string, 0, len(req.Tokens))
+	pairs := make([][]any, 0, len(req.Tokens))
+	for _, token := range req.Tokens {
+		collectionAddr, err := common.NormalizeCollectionAddr(token.CollectionAddr)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, types.NewInvalidValueError("collection_addr", token.CollectionAddr, "invalid address format").Error())
+		}
+		keys = append(keys, nftBatchKey(collectionAddr, token.TokenId))
+		pairs = append(pairs, []any{collectionAddr, token.TokenId})
+	}
+
+	// Use read-only transaction for better performance
+	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
+	defer tx.Rollback()
+
+	var nfts []types.CollectedNft
+	if err := tx.Model(&types.CollectedNft{}).
+		Where("(collection_addr, token_id) IN ?", pairs).
+		Find(&nfts).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get nfts", err).Error())
+	}
+
+	ownerAccounts, err := h.getNftOwnerIdMap(tx, nfts)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	nftsRes, err := ToNftsResponse(h.GetDatabase(), nfts, ownerAccounts)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	found := make(map[string]*Nft, len(nfts))
+	for idx := range nfts {
+		found[nftBatchKey(nfts[idx].CollectionAddr, nfts[idx].TokenId)] = &nftsRes[idx]
+	}
+
+	entries := make([]NftBatchEntry, 0, len(req.Tokens))
+	for idx, token := range req.Tokens {
+		nft, ok := found[keys[idx]]
+		entries = append(entries, NftBatchEntry{
+			CollectionAddr: token.CollectionAddr,
+			TokenId:        token.TokenId,
+			Found:          ok,
+			Token:          nft,
+		})
+	}
+
+	return c.JSON(NftsBatchResponse{Tokens: entries})
+}
+
+func nftBatchKey(collectionAddr []byte, tokenId string) string {
+	return util.BytesToHex(collectionAddr) + "/" + tokenId
+}
@@ -11,12 +11,13 @@ import (
 
 type NftHandler struct {
 	*common.BaseHandler
+	cache *cache.Cache
 }
 
 var _ common.HandlerRegistrar = (*NftHandler)(nil)
 
-func NewNftHandler(base *common.BaseHandler) *NftHandler {
-	return &NftHandler{BaseHandler: base}
+func NewNftHandler(base *common.BaseHandler, apiCache *cache.Cache) *NftHandler {
+	return &NftHandler{BaseHandler: base, cache: apiCache}
 }
 
 func (h *NftHandler) Register(router fiber.Router) {
@@ -26,17 +27,18 @@ func (h *NftHandler) Register(router fiber.Router) {
 	nfts := router.Group("indexer/nft/v1")
 	// Collections routes
 	collections := nfts.Group("/collections")
-	collections.Get("/", cache.WithExpiration(time.Second), h.GetCollections)
-	collections.Get("/by_account/:account", cache.WithExpiration(time.Second), h.GetCollectionsByAccount)
-	collections.Get("/by_name/:name", cache.WithExpiration(time.Second), h.GetCollectionsByName)
-	collections.Get("/:collection_addr", cache.WithExpiration(10*time.Second), h.GetCollectionByCollectionAddr)
+	collections.Get("/", h.cache.PerBlock(), h.GetCollections)
+	collections.Get("/by_account/:account", h.cache.PerBlock(), h.GetCollectionsByAccount)
+	collections.Get("/by_name/:name", h.cache.PerBlock(), h.GetCollectionsByName)
+	collections.Get("/:collection_addr", h.cache.WithExpiration(10*time.Second), h.GetCollectionByCollectionAddr)
 
 	// Tokens(NFT) routes
 	tokens := nfts.Group("/tokens")
-	tokens.Get("/by_account/:account", cache.WithExpiration(time.Second), h.GetTokensByAccount)
-	tokens.Get("/by_collection/:collection_addr", cache.WithExpiration(time.Second), h.GetTokensByCollectionAddr)
+	tokens.Get("/by_account/:account", h.cache.PerBlock(), h.GetTokensByAccount)
+	tokens.Get("/by_collection/:collection_addr", h.cache.PerBlock(), h.GetTokensByCollectionAddr)
+	tokens.Post("/batch", h.GetTokensBatch)
 
 	// NFT transaction routes
 	txs := nfts.Group("/txs")
-	txs.Get("/:collection_addr/:token_id", cache.WithExpiration(time.Second), h.GetNftTxs)
+	txs.Get("/:collection_addr/:token_id", h.cache.AppendOnly(), h.GetNftTxs)
 }
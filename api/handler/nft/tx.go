@@ -27,6 +27,10 @@ import (
 // @Param pagination.limit query int false "Pagination limit, default is 100" default is 100
 // @Param pagination.count_total query bool false "Count total, default is true" default is true
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
+// @Param from_time query string false "Only include records at or after this time (RFC3339)"
+// @Param to_time query string false "Only include records at or before this time (RFC3339)"
+// @Param format query string false "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)" Enums(json, csv, ndjson)
+// @Param columns query string false "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default"
 // @Router /indexer/nft/v1/txs/{collection_addr}/{token_id} [get]
 func (h *NftHandler) GetNftTxs(c *fiber.Ctx) error {
 	collectionAddr, err := common.GetCollectionAddrParam(c, h.GetChainConfig())
@@ -41,12 +45,23 @@ func (h *NftHandler) GetNftTxs(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	format, err := common.ParseExportFormat(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	var nft types.CollectedNft
 	if err := h.GetDatabase().
 		Where("collection_addr = ? AND token_id = ?", collectionAddr, tokenId).
 		First(&nft).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if format != "" {
+				return tx.ExportTxs(c, h.BaseHandler, format, pagination, tx.EmptyQuery(&types.CollectedTx{}))
+			}
 			return c.JSON(tx.TxsResponse{
 				Txs:        []types.Tx{},
 				Pagination: pagination.ToResponse(0, false),
@@ -55,7 +70,12 @@ func (h *NftHandler) GetNftTxs(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	query := h.GetDatabase().Model(&types.CollectedTx{}).Order(pagination.OrderBy("sequence"))
+	pagination.Range, err = h.GetSequenceRange(h.GetDatabase().DB, timeRange, types.CollectedTx{}.TableName())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	var sequenceSubQuery *gorm.DB
 
 	switch h.GetVmType() {
 	case types.MoveVM:
@@ -65,17 +85,19 @@ func (h *NftHandler) GetNftTxs(c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 		}
 		if len(accountIds) == 0 {
+			if format != "" {
+				return tx.ExportTxs(c, h.BaseHandler, format, pagination, tx.EmptyQuery(&types.CollectedTx{}))
+			}
 			return c.JSON(tx.TxsResponse{
 				Txs:        []types.Tx{},
 				Pagination: pagination.ToResponse(0, false),
 			})
 		}
 
-		sequenceSubQuery := h.GetDatabase().
+		sequenceSubQuery = h.GetDatabase().
 			Model(&types.CollectedTxAccount{}).
 			Select("sequence").
 			Where("account_id = ?", accountIds[0])
-		query = query.Where("sequence IN (?)", sequenceSubQuery)
 
 	case types.WasmVM, types.EVM:
 		nftKey := cache.NftKey{
@@ -87,24 +109,38 @@ func (h *NftHandler) GetNftTxs(c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 		}
 		if len(nftIds) == 0 {
+			if format != "" {
+				return tx.ExportTxs(c, h.BaseHandler, format, pagination, tx.EmptyQuery(&types.CollectedTx{}))
+			}
 			return c.JSON(tx.TxsResponse{
 				Txs:        []types.Tx{},
 				Pagination: pagination.ToResponse(0, false),
 			})
 		}
 
-		sequenceSubQuery := h.GetDatabase().
+		sequenceSubQuery = h.GetDatabase().
 			Model(&types.CollectedTxNft{}).
 			Select("sequence").
 			Where("nft_id IN ?", nftIds)
-		query = query.Where("sequence IN (?)", sequenceSubQuery)
 	}
 
+	if format != "" {
+		return tx.ExportTxs(c, h.BaseHandler, format, pagination, func(db *gorm.DB, pagination *common.Pagination) (*gorm.DB, error) {
+			return pagination.ApplySequence(db.Model(&types.CollectedTx{}).Where("sequence IN (?)", sequenceSubQuery)), nil
+		})
+	}
+
+	query := h.GetDatabase().
+		Model(&types.CollectedTx{}).
+		Where("sequence IN (?)", sequenceSubQuery).
+		Order(pagination.OrderBy("sequence"))
+
 	// Use optimized COUNT - always has filters (account_ids or nft_ids)
 	var strategy types.CollectedTx
 	hasFilters := true // always has account_ids or nft_ids filter
 	var total int64
-	total, err = common.GetOptimizedCount(query, strategy, hasFilters, pagination.CountTotal)
+	countQuery := pagination.ApplyRange(query.Session(&gorm.Session{}), "sequence")
+	total, err = common.GetOptimizedCount(countQuery, strategy, hasFilters, pagination.CountTotal)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
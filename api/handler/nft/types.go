@@ -117,3 +117,24 @@ func ToNftsResponse(db *orm.Database, nfts []types.CollectedNft, ownerAccounts m
 	}
 	return nftResponses, nil
 }
+
+// Nft batch
+type NftKey struct {
+	CollectionAddr string `json:"collection_addr"`
+	TokenId        string `json:"token_id"`
+}
+
+type NftsBatchRequest struct {
+	Tokens []NftKey `json:"tokens"`
+}
+
+type NftBatchEntry struct {
+	CollectionAddr string `json:"collection_addr" extensions:"x-order:0"`
+	TokenId        string `json:"token_id" extensions:"x-order:1"`
+	Found          bool   `json:"found" extensions:"x-order:2"`
+	Token          *Nft   `json:"token" extensions:"x-order:3"`
+}
+
+type NftsBatchResponse struct {
+	Tokens []NftBatchEntry `json:"tokens"`
+}
@@ -15,19 +15,21 @@ type RichListHandler struct {
 	*common.BaseHandler
 	cfg     *config.Config
 	querier *querier.Querier
+	cache   *cache.Cache
 }
 
 var _ common.HandlerRegistrar = (*RichListHandler)(nil)
 
-func NewRichListHandler(base *common.BaseHandler, cfg *config.Config) *RichListHandler {
+func NewRichListHandler(base *common.BaseHandler, cfg *config.Config, apiCache *cache.Cache) *RichListHandler {
 	return &RichListHandler{
 		BaseHandler: base,
 		cfg:         cfg,
 		querier:     querier.NewQuerier(cfg.GetChainConfig()),
+		cache:       apiCache,
 	}
 }
 
 func (h *RichListHandler) Register(router fiber.Router) {
 	richlist := router.Group("indexer/richlist/v1")
-	richlist.Get("/:denom", cache.WithExpiration(10*time.Second), h.GetTokenHolders)
+	richlist.Get("/:denom", h.cache.WithExpiration(10*time.Second), h.GetTokenHolders)
 }
@@ -2,10 +2,12 @@ package richlist
 
 import (
 	"database/sql"
+	"errors"
 	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 
 	"github.com/initia-labs/rollytics/types"
 	"github.com/initia-labs/rollytics/util"
@@ -24,6 +26,8 @@ import (
 // @Param pagination.limit query int false "Pagination limit, default is 100" default is 100
 // @Param pagination.count_total query bool false "Count total, default is true" default is true
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
+// @Param format query string false "Stream all holders instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)" Enums(json, csv, ndjson)
+// @Param columns query string false "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default"
 // @Success 200 {object} TokenHoldersResponse
 // @Router /indexer/richlist/v1/{denom} [get]
 func (h *RichListHandler) GetTokenHolders(c *fiber.Ctx) error {
@@ -46,11 +50,58 @@ func (h *RichListHandler) GetTokenHolders(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	format, err := common.ParseExportFormat(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if format != "" {
+		return common.StreamExport(c, h.BaseHandler, format, pagination, common.Export[TokenHolder]{
+			Name:    "holders",
+			Columns: tokenHolderExportColumns,
+			Fetch: func(tx *gorm.DB, pagination *common.Pagination) ([]TokenHolder, any, error) {
+				holders, err := h.getTokenHolders(tx, denom, pagination)
+				return holders, nil, err
+			},
+		})
+	}
 
 	// Start read-only transaction
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
+	holders, err := h.getTokenHolders(tx, denom, pagination)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	// Count total if requested
+	var total int64
+	if pagination.CountTotal {
+		countQuery := tx.Model(&types.CollectedRichList{}).
+			Where("denom = ?", denom)
+		if err := countQuery.Count(&total).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to count total holders")
+		}
+	}
+
+	// Build pagination response
+	hasMore := len(holders) == pagination.Limit
+	paginationResp := pagination.ToResponse(total, hasMore)
+
+	return c.JSON(TokenHoldersResponse{
+		Holders:    holders,
+		Pagination: paginationResp,
+	})
+}
+
+var tokenHolderExportColumns = []common.ExportColumn[TokenHolder]{
+	{Name: "account", Value: func(holder TokenHolder) any { return holder.Account }},
+	{Name: "amount", Value: func(holder TokenHolder) any { return holder.Amount }},
+}
+
+// getTokenHolders fetches a page of holders of the denom ordered by amount
+func (h *RichListHandler) getTokenHolders(tx *gorm.DB, denom string, pagination *common.Pagination) ([]TokenHolder, error) {
 	// Query rich list ordered by amount with pagination
 	var richListRecords []types.CollectedRichList
 	if err := tx.Model(&types.CollectedRichList{}).
@@ -59,7 +110,7 @@ func (h *RichListHandler) GetTokenHolders(c *fiber.Ctx) error {
 		Limit(pagination.Limit).
 		Offset(pagination.Offset).
 		Find(&richListRecords).Error; err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch token holders")
+		return nil, errors.New("failed to fetch token holders")
 	}
 
 	// Extract unique account IDs
@@ -75,7 +126,7 @@ func (h *RichListHandler) GetTokenHolders(c *fiber.Ctx) error {
 			Select("id, account").
 			Where("id IN ?", accountIds).
 			Find(&accounts).Error; err != nil {
-			return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch account addresses")
+			return nil, errors.New("failed to fetch account addresses")
 		}
 	}
 
@@ -98,22 +149,5 @@ func (h *RichListHandler) GetTokenHolders(c *fiber.Ctx) error {
 		}
 	}
 
-	// Count total if requested
-	var total int64
-	if pagination.CountTotal {
-		countQuery := tx.Model(&types.CollectedRichList{}).
-			Where("denom = ?", denom)
-		if err := countQuery.Count(&total).Error; err != nil {
-			return fiber.NewError(fiber.StatusInternalServerError, "failed to count total holders")
-		}
-	}
-
-	// Build pagination response
-	hasMore := len(richListRecords) == pagination.Limit
-	paginationResp := pagination.ToResponse(total, hasMore)
-
-	return c.JSON(TokenHoldersResponse{
-		Holders:    holders,
-		Pagination: paginationResp,
-	})
+	return holders, nil
 }
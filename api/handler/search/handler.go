@@ -0,0 +1,24 @@
+package search
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/initia-labs/rollytics/api/cache"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+type SearchHandler struct {
+	*common.BaseHandler
+	cache *cache.Cache
+}
+
+var _ common.HandlerRegistrar = (*SearchHandler)(nil)
+
+func NewSearchHandler(base *common.BaseHandler, apiCache *cache.Cache) *SearchHandler {
+	return &SearchHandler{BaseHandler: base, cache: apiCache}
+}
+
+func (h *SearchHandler) Register(router fiber.Router) {
+	search := router.Group("indexer/search/v1")
+	search.Get("/", h.cache.PerBlock(), h.Search)
+}
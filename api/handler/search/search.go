@@ -0,0 +1,217 @@
+package search
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	collectortx "github.com/initia-labs/rollytics/indexer/collector/tx"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+)
+
+const (
+	MaxQueryLength     = 256
+	MaxCollectionsHits = 10
+)
+
+var (
+	regexHeight  = regexp.MustCompile(`^[0-9]+$`)
+	regexTxHash  = regexp.MustCompile(`^(?:0x)?[a-fA-F0-9]{64}$`)
+	regexBech32  = regexp.MustCompile(collectortx.InitBech32Regex)
+	regexHexAddr = regexp.MustCompile(collectortx.InitHexRegex)
+)
+
+// classification describes what kinds of keys a search input may be. An input can be
+// several kinds at once, e.g. 0x-prefixed 32 bytes hex is both a tx hash and a Move address.
+type classification struct {
+	Height  int64
+	TxHash  []byte
+	Address sdk.AccAddress
+	Text    bool
+}
+
+func classify(q string) classification {
+	var c classification
+
+	if regexHeight.MatchString(q) {
+		if height, err := strconv.ParseInt(q, 10, 64); err == nil && height > 0 {
+			c.Height = height
+		}
+		return c
+	}
+
+	if regexTxHash.MatchString(q) {
+		if hash, err := util.HexToBytes(q); err == nil {
+			c.TxHash = hash
+		}
+	}
+
+	if regexBech32.MatchString(q) || regexHexAddr.MatchString(q) {
+		if addr, err := util.AccAddressFromString(q); err == nil {
+			c.Address = addr
+		}
+	}
+
+	c.Text = c.TxHash == nil && c.Address == nil
+	return c
+}
+
+// Search handles GET /search/v1
+// @Summary Search
+// @Description Classify the input as a height, tx hash, EVM tx hash, account, collection address, collection name or denom and return the matching records with links to their canonical endpoints
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search input"
+// @Success 200 {object} SearchResponse
+// @Router /indexer/search/v1 [get]
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		return fiber.NewError(fiber.StatusBadRequest, types.NewValidationError("q", "must not be empty").Error())
+	}
+	if len(q) > MaxQueryLength {
+		return fiber.NewError(fiber.StatusBadRequest, types.NewValidationError("q", fmt.Sprintf("must not be longer than %d characters", MaxQueryLength)).Error())
+	}
+
+	// Use read-only transaction for better performance
+	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
+	defer tx.Rollback()
+
+	hits, err := h.search(tx, q, classify(q))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("search", err).Error())
+	}
+
+	return c.JSON(SearchResponse{
+		Query: q,
+		Hits:  hits,
+	})
+}
+
+func (h *SearchHandler) search(tx *gorm.DB, q string, class classification) ([]SearchHit, error) {
+	hits := make([]SearchHit, 0)
+
+	if class.Height > 0 {
+		found, err := exists(tx, tx.Model(&types.CollectedBlock{}).Where("chain_id = ? AND height = ?", h.GetChainId(), class.Height))
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			hits = append(hits, SearchHit{
+				Type: HitTypeBlock,
+				Key:  fmt.Sprintf("%d", class.Height),
+				Link: fmt.Sprintf("/indexer/block/v1/blocks/%d", class.Height),
+			})
+		}
+		return hits, nil
+	}
+
+	if class.TxHash != nil {
+		found, err := exists(tx, tx.Model(&types.CollectedTx{}).Where("hash = ?", class.TxHash))
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			hash := strings.ToUpper(util.BytesToHex(class.TxHash))
+			hits = append(hits, SearchHit{Type: HitTypeTx, Key: hash, Link: "/indexer/tx/v1/txs/" + hash})
+		}
+
+		if h.GetVmType() == types.EVM {
+			// evm_tx is looked up instead of evm_tx_hash_dict, since the dictionary
+			// is only populated when the internal tx extension is enabled
+			found, err := exists(tx, tx.Model(&types.CollectedEvmTx{}).Where("hash = ?", class.TxHash))
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				hash := util.BytesToHexWithPrefix(class.TxHash)
+				hits = append(hits, SearchHit{Type: HitTypeEvmTx, Key: hash, Link: "/indexer/tx/v1/evm-txs/" + hash})
+			}
+		}
+	}
+
+	if class.Address != nil {
+		accountIds, err := h.GetAccountIds([]string{class.Address.String()})
+		if err != nil {
+			return nil, err
+		}
+		if len(accountIds) > 0 {
+			addr := class.Address.String()
+			hits = append(hits, SearchHit{Type: HitTypeAccount, Key: addr, Link: "/indexer/tx/v1/txs/by_account/" + addr})
+		}
+
+		var collections []types.CollectedNftCollection
+		if err := tx.Model(&types.CollectedNftCollection{}).
+			Select("addr, name").
+			Where("addr = ?", class.Address.Bytes()).
+			Limit(1).
+			Find(&collections).Error; err != nil {
+			return nil, err
+		}
+		hits = appendCollectionHits(hits, collections)
+	}
+
+	if class.Text {
+		var collections []types.CollectedNftCollection
+		if err := tx.Model(&types.CollectedNftCollection{}).
+			Select("addr, name").
+			Where("name LIKE ?", escapeLike(q)+"%").
+			Order("name").
+			Limit(MaxCollectionsHits).
+			Find(&collections).Error; err != nil {
+			return nil, err
+		}
+		hits = appendCollectionHits(hits, collections)
+	}
+
+	denom := searchDenom(q, class)
+	found, err := exists(tx, tx.Model(&types.CollectedRichList{}).Where("denom = ?", denom))
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		hits = append(hits, SearchHit{Type: HitTypeDenom, Key: denom, Link: "/indexer/richlist/v1/" + url.PathEscape(denom)})
+	}
+
+	return hits, nil
+}
+
+// searchDenom returns the denom probed for the input. Denoms are case sensitive, only the hex
+// addresses and hashes, such as evm contract denoms, are stored in lower case.
+func searchDenom(q string, class classification) string {
+	if class.TxHash != nil || (class.Address != nil && regexHexAddr.MatchString(q)) {
+		return strings.ToLower(q)
+	}
+	return q
+}
+
+func appendCollectionHits(hits []SearchHit, collections []types.CollectedNftCollection) []SearchHit {
+	for _, collection := range collections {
+		addr := util.BytesToHexWithPrefixIfPresent(collection.Addr)
+		hits = append(hits, SearchHit{
+			Type: HitTypeNftCollection,
+			Key:  addr,
+			Name: collection.Name,
+			Link: "/indexer/nft/v1/collections/" + addr,
+		})
+	}
+	return hits
+}
+
+func exists(tx *gorm.DB, query *gorm.DB) (found bool, err error) {
+	err = tx.Raw("SELECT EXISTS (?)", query.Select("1")).Scan(&found).Error
+	return found, err
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/initia-labs/rollytics/config"
+)
+
+func init() {
+	config.InitializeSDKConfig("init")
+}
+
+func TestClassify(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	account := sdk.AccAddress(make([]byte, 20)).String()
+
+	tests := []struct {
+		name        string
+		q           string
+		height      int64
+		txHash      bool
+		address     bool
+		text        bool
+		addressSize int
+	}{
+		{name: "height", q: "12345", height: 12345},
+		{name: "zero height", q: "0"},
+		{name: "cosmos tx hash", q: strings.ToUpper(hash), txHash: true},
+		{name: "evm tx hash is also a move address", q: "0x" + hash, txHash: true, address: true, addressSize: 32},
+		{name: "bech32 account", q: account, address: true, addressSize: 20},
+		{name: "short hex address", q: "0x1", address: true, addressSize: 20},
+		{name: "collection name", q: "Initia Punks", text: true},
+		{name: "denom", q: "uinit", text: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class := classify(tt.q)
+			assert.Equal(t, tt.height, class.Height)
+			assert.Equal(t, tt.txHash, class.TxHash != nil)
+			assert.Equal(t, tt.address, class.Address != nil)
+			assert.Equal(t, tt.text, class.Text)
+			if tt.address {
+				assert.Len(t, class.Address.Bytes(), tt.addressSize)
+			}
+		})
+	}
+}
+
+func TestSearchDenom(t *testing.T) {
+	tests := []struct {
+		q     string
+		denom string
+	}{
+		{q: "uinit", denom: "uinit"},
+		{q: "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2", denom: "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"},
+		{q: "factory/init1abc/uTOKEN", denom: "factory/init1abc/uTOKEN"},
+		{q: "0xAbCdEf0000000000000000000000000000000001", denom: "0xabcdef0000000000000000000000000000000001"},
+		{q: "0x" + strings.Repeat("AB", 32), denom: "0x" + strings.Repeat("ab", 32)},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.denom, searchDenom(tt.q, classify(tt.q)), tt.q)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_off\\`, escapeLike(`100%_off\`))
+}
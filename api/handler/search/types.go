@@ -0,0 +1,24 @@
+package search
+
+type HitType string
+
+const (
+	HitTypeBlock         HitType = "block"
+	HitTypeTx            HitType = "tx"
+	HitTypeEvmTx         HitType = "evm_tx"
+	HitTypeAccount       HitType = "account"
+	HitTypeNftCollection HitType = "nft_collection"
+	HitTypeDenom         HitType = "denom"
+)
+
+type SearchHit struct {
+	Type HitType `json:"type" extensions:"x-order:0"`
+	Key  string  `json:"key" extensions:"x-order:1"`
+	Name string  `json:"name,omitempty" extensions:"x-order:2"`
+	Link string  `json:"link" extensions:"x-order:3"`
+}
+
+type SearchResponse struct {
+	Query string      `json:"query" extensions:"x-order:0"`
+	Hits  []SearchHit `json:"hits" extensions:"x-order:1"`
+}
@@ -0,0 +1,125 @@
+package tx
+
+import (
+	"database/sql"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+// GetTxsBatch handles POST /tx/v1/txs/batch
+// @Summary Get transactions by hashes
+// @Description Get up to 100 transactions by their hashes in a single call. Results keep the request order and misses are marked with found=false
+// @Tags Tx
+// @Accept json
+// @Produce json
+// @Param request body TxHashesRequest true "Transaction hashes"
+// @Router /indexer/tx/v1/txs/batch [post]
+func (h *TxHandler) GetTxsBatch(c *fiber.Ctx) error {
+	req, hashes, err := parseTxHashesRequest(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	// Use read-only transaction for better performance
+	dbTx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
+	defer dbTx.Rollback()
+
+	var txs []types.CollectedTx
+	if err := dbTx.Model(&types.CollectedTx{}).
+		Where("hash IN ?", hashes).
+		Find(&txs).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get transactions", err).Error())
+	}
+
+	txsRes, err := ToTxsResponse(txs)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	found := make(map[string]*types.Tx, len(txs))
+	for idx := range txs {
+		found[string(txs[idx].Hash)] = &txsRes[idx]
+	}
+
+	entries := make([]TxBatchEntry, 0, len(hashes))
+	for idx, hash := range hashes {
+		tx, ok := found[string(hash)]
+		entries = append(entries, TxBatchEntry{
+			TxHash: req.TxHashes[idx],
+			Found:  ok,
+			Tx:     tx,
+		})
+	}
+
+	return c.JSON(TxsBatchResponse{Txs: entries})
+}
+
+// GetEvmTxsBatch handles POST /tx/v1/evm-txs/batch
+// @Summary Get EVM transactions by hashes
+// @Description Get up to 100 EVM transactions by their hashes in a single call. Results keep the request order and misses are marked with found=false
+// @Tags EVM Tx
+// @Accept json
+// @Produce json
+// @Param request body TxHashesRequest true "EVM transaction hashes"
+// @Router /indexer/tx/v1/evm-txs/batch [post]
+func (h *TxHandler) GetEvmTxsBatch(c *fiber.Ctx) error {
+	req, hashes, err := parseTxHashesRequest(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	// Use read-only transaction for better performance
+	dbTx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
+	defer dbTx.Rollback()
+
+	var txs []types.CollectedEvmTx
+	if err := dbTx.Model(&types.CollectedEvmTx{}).
+		Where("hash IN ?", hashes).
+		Find(&txs).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get evm transactions", err).Error())
+	}
+
+	txsRes, err := ToEvmTxsResponse(txs)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	found := make(map[string]*types.EvmTx, len(txs))
+	for idx := range txs {
+		found[string(txs[idx].Hash)] = &txsRes[idx]
+	}
+
+	entries := make([]EvmTxBatchEntry, 0, len(hashes))
+	for idx, hash := range hashes {
+		tx, ok := found[string(hash)]
+		entries = append(entries, EvmTxBatchEntry{
+			TxHash: req.TxHashes[idx],
+			Found:  ok,
+			Tx:     tx,
+		})
+	}
+
+	return c.JSON(EvmTxsBatchResponse{Txs: entries})
+}
+
+// parseTxHashesRequest parses a batch request body and decodes its hashes, keeping the request order
+func parseTxHashesRequest(c *fiber.Ctx) (req TxHashesRequest, hashes [][]byte, err error) {
+	if err := common.ParseBatchRequest(c, &req, "tx_hashes", func() int { return len(req.TxHashes) }); err != nil {
+		return req, nil, err
+	}
+
+	hashes = make([][]byte, 0, len(req.TxHashes))
+	for _, hash := range req.TxHashes {
+		hashBytes, err := util.HexToBytes(hash)
+		if err != nil || len(hashBytes) == 0 {
+			return req, nil, types.NewInvalidValueError("tx_hashes", hash, "invalid hash format")
+		}
+		hashes = append(hashes, hashBytes)
+	}
+
+	return req, hashes, nil
+}
@@ -0,0 +1,87 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+func postBatch(t *testing.T, handler fiber.Handler, body string) (int, []byte) {
+	t.Helper()
+
+	app := fiber.New()
+	app.Post("/batch", handler)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/batch", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, respBody
+}
+
+func TestGetTxsBatch_KeepsOrderAndMarksMisses(t *testing.T) {
+	handler, mock := newTxHandlerWithMockDB(t)
+
+	row := sqlmock.NewRows([]string{"hash", "height", "sequence", "signer_id", "data"}).
+		AddRow([]byte{0xbb}, int64(2), int64(2), int64(0), legacyTxPayload("BB")).
+		AddRow([]byte{0xaa}, int64(1), int64(1), int64(0), legacyTxPayload("AA"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "tx" WHERE hash IN \(\$1,\$2,\$3\)`).
+		WithArgs([]byte{0xaa}, []byte{0xcc}, []byte{0xbb}).
+		WillReturnRows(row)
+	mock.ExpectRollback()
+
+	status, body := postBatch(t, handler.GetTxsBatch, `{"tx_hashes":["AA","0xcc","bb"]}`)
+	require.Equal(t, fiber.StatusOK, status)
+
+	var res TxsBatchResponse
+	require.NoError(t, json.Unmarshal(body, &res))
+	require.Len(t, res.Txs, 3)
+
+	require.Equal(t, "AA", res.Txs[0].TxHash)
+	require.True(t, res.Txs[0].Found)
+	require.Equal(t, "AA", res.Txs[0].Tx.TxHash)
+
+	require.Equal(t, "0xcc", res.Txs[1].TxHash)
+	require.False(t, res.Txs[1].Found)
+	require.Nil(t, res.Txs[1].Tx)
+
+	require.True(t, res.Txs[2].Found)
+	require.Equal(t, "BB", res.Txs[2].Tx.TxHash)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTxsBatch_InvalidRequest(t *testing.T) {
+	handler, _ := newTxHandlerWithMockDB(t)
+
+	tooMany := make([]string, common.MaxBatchSize+1)
+	for idx := range tooMany {
+		tooMany[idx] = fmt.Sprintf("%q", "AA")
+	}
+
+	for name, body := range map[string]string{
+		"empty":        `{"tx_hashes":[]}`,
+		"malformed":    `{"tx_hashes":`,
+		"invalid hash": `{"tx_hashes":["zz"]}`,
+		"too many":     `{"tx_hashes":[` + strings.Join(tooMany, ",") + `]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			status, _ := postBatch(t, handler.GetTxsBatch, body)
+			require.Equal(t, fiber.StatusBadRequest, status)
+		})
+	}
+}
@@ -156,7 +156,7 @@ func setupSQLiteBenchmarkHandler(tb testing.TB, txCount int, useEdges bool) (*Tx
 	cfg.SetRichListConfig(&config.RichListConfig{Enabled: true})
 
 	base := common.NewBaseHandler(&orm.Database{DB: gdb}, cfg, logger)
-	handler := NewTxHandler(base)
+	handler := NewTxHandler(base, nil)
 
 	return handler, cleanup, primaryAccount.hex
 }
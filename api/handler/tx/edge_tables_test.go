@@ -12,6 +12,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
 	"github.com/initia-labs/rollytics/config"
 	dbconfig "github.com/initia-labs/rollytics/orm/config"
@@ -28,6 +29,7 @@ func init() {
 		NftCacheSize:              1024,
 		MsgTypeCacheSize:          256,
 		TypeTagCacheSize:          256,
+		MoveFunctionCacheSize:     256,
 		MoveDenomCacheSize:        1024,
 		EvmTxHashCacheSize:        1024,
 		EvmDenomContractCacheSize: 1024,
@@ -50,7 +52,7 @@ func newTxHandlerWithMockDB(tb testing.TB) (*TxHandler, sqlmock.Sqlmock) {
 	cfg.SetRichListConfig(&config.RichListConfig{Enabled: true})
 
 	base := common.NewBaseHandler(db, cfg, logger)
-	handler := NewTxHandler(base)
+	handler := NewTxHandler(base, nil)
 
 	return handler, mock
 }
@@ -423,6 +425,14 @@ func (tc byAccountTestCase) requestPath() string {
 func setupAccountExpectations(t *testing.T, mock sqlmock.Sqlmock, tc byAccountTestCase, accBytes []byte) {
 	t.Helper()
 
+	expectAccountQueries(t, mock, tc, accBytes)
+	mock.ExpectRollback()
+}
+
+// expectAccountQueries expects the queries of a by account request up to the txs of the page
+func expectAccountQueries(t *testing.T, mock sqlmock.Sqlmock, tc byAccountTestCase, accBytes []byte) {
+	t.Helper()
+
 	mock.ExpectBegin()
 	mock.ExpectQuery(`SELECT \* FROM "account_dict" WHERE account IN`).
 		WithArgs(sqlmock.AnyArg()).
@@ -434,15 +444,13 @@ func setupAccountExpectations(t *testing.T, mock sqlmock.Sqlmock, tc byAccountTe
 	// Add transaction expectations for GetCountWithTimeout
 	mock.ExpectExec(`SAVEPOINT sp`).WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec(`SET LOCAL statement_timeout = '5s'`).WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectQuery(`SELECT COUNT\(DISTINCT\("sequence"\)\) FROM "` + tc.edgeTable + `" WHERE account_id = \$1`).
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT\(("` + tc.edgeTable + `"\.)?"sequence"\)\) FROM "` + tc.edgeTable + `" WHERE (` + tc.edgeTable + `\.)?account_id = \$1`).
 		WithArgs(tc.accountID).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 	mock.ExpectExec(`RESET statement_timeout`).WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectQuery(`SELECT \* FROM "`+tc.table+`" WHERE sequence IN \(SELECT`).
 		WithArgs(tc.accountID, sqlmock.AnyArg()).
 		WillReturnRows(row)
-
-	mock.ExpectRollback()
 }
 
 func legacyTxPayload(hash string) []byte {
@@ -501,7 +509,7 @@ func TestBuildEdgeQueryForGetTxs(t *testing.T) {
 	mock.ExpectCommit() // GORM transaction commit
 
 	// Call the function
-	query, total, err := buildEdgeQueryForGetTxs(handler.BaseHandler.GetDatabase().DB, msgTypeIds, pagination)
+	query, total, err := buildEdgeQueryForGetTxs(handler.BaseHandler.GetDatabase().DB, msgTypeIds, nil, TxStatusFilter{}, TxMsgFilter{}, pagination)
 
 	// Verify results
 	req.NoError(err)
@@ -531,7 +539,7 @@ func TestBuildEdgeQueryForGetTxs_NoFilter(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(expectedTotal))
 
 	// Call the function
-	query, total, err := buildEdgeQueryForGetTxs(handler.BaseHandler.GetDatabase().DB, msgTypeIds, pagination)
+	query, total, err := buildEdgeQueryForGetTxs(handler.BaseHandler.GetDatabase().DB, msgTypeIds, nil, TxStatusFilter{}, TxMsgFilter{}, pagination)
 
 	// Verify results
 	req.NoError(err)
@@ -541,3 +549,201 @@ func TestBuildEdgeQueryForGetTxs_NoFilter(t *testing.T) {
 	// Verify all expectations were met
 	req.NoError(mock.ExpectationsWereMet())
 }
+
+func TestBuildTxEdgeQuery_MsgTypeJoin(t *testing.T) {
+	handler, _ := newTxHandlerWithMockDB(t)
+	db := handler.GetDatabase().DB
+	pagination := &common.Pagination{
+		Limit:       10,
+		Order:       common.OrderDesc,
+		CursorType:  common.CursorTypeSequence,
+		CursorValue: map[string]any{"sequence": int64(15)},
+		Range:       &common.KeyRange{From: 10, To: 20},
+	}
+
+	query, _, err := buildTxEdgeQuery(db, 5, false, []int64{1}, TxStatusFilter{}, TxMsgFilter{}, pagination)
+	require.NoError(t, err)
+
+	// tx_msg_types has a sequence too, so the range and the cursor are qualified by the edge table
+	stmt := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var txs []types.CollectedTx
+		return query.Session(&gorm.Session{DryRun: true}).Find(&txs)
+	})
+	require.Equal(t, `SELECT * FROM "tx" WHERE sequence IN (SELECT DISTINCT tx_accounts.sequence FROM "tx_accounts" `+
+		`JOIN tx_msg_types ON tx_msg_types.sequence = tx_accounts.sequence WHERE tx_accounts.account_id = 5 `+
+		`AND tx_msg_types.msg_type_id = ANY('{1}') AND tx_accounts.sequence >= 10 AND tx_accounts.sequence <= 20 `+
+		`AND tx_accounts.sequence < 15 ORDER BY tx_accounts.sequence DESC LIMIT 10) ORDER BY sequence DESC`, stmt)
+}
+
+func TestGetTxs_StatusFilter(t *testing.T) {
+	handler, mock := newTxHandlerWithMockDB(t)
+
+	const (
+		route    = "/indexer/tx/v1/txs?status=failed&codespace=sdk"
+		height   = int64(77)
+		sequence = int64(9)
+		hash     = "0xFAILED"
+	)
+
+	row := sqlmock.NewRows([]string{"hash", "height", "sequence", "signer_id", "code", "codespace", "data"}).
+		AddRow([]byte(hash), height, sequence, int64(0), int64(5), "sdk", legacyTxPayload(hash))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT sp`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SET LOCAL statement_timeout = '5s'`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COUNT\("sequence"\) FROM "tx" WHERE code <> 0 AND codespace = \$1`).
+		WithArgs("sdk").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(`RESET statement_timeout`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "tx" WHERE sequence IN \(SELECT "sequence" FROM "tx" WHERE code <> 0 AND codespace = \$1 ORDER BY sequence DESC LIMIT \$2\)`).
+		WithArgs("sdk", int64(common.DefaultLimit)).
+		WillReturnRows(row)
+	mock.ExpectRollback()
+
+	app := fiber.New()
+	app.Get("/indexer/tx/v1/txs", handler.GetTxs)
+
+	req := httptest.NewRequest(fiber.MethodGet, route, nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTxs_InvalidStatus(t *testing.T) {
+	handler, _ := newTxHandlerWithMockDB(t)
+
+	app := fiber.New()
+	app.Get("/indexer/tx/v1/txs", handler.GetTxs)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/indexer/tx/v1/txs?status=pending", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGetTxs_CodespaceWithSuccessStatus(t *testing.T) {
+	handler, _ := newTxHandlerWithMockDB(t)
+
+	app := fiber.New()
+	app.Get("/indexer/tx/v1/txs", handler.GetTxs)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/indexer/tx/v1/txs?status=success&codespace=sdk", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestParseTxMsgFilter(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected TxMsgFilter
+		wantErr  bool
+	}{
+		{query: "", expected: TxMsgFilter{}},
+		{query: "msg.function=swap", expected: TxMsgFilter{Function: "swap"}},
+		{query: "msg.function=0xA9059CBB", expected: TxMsgFilter{Function: "0xa9059cbb"}},
+		{query: "msg.function=0x0001::dex::swap", expected: TxMsgFilter{Module: "0x1::dex", Function: "swap"}},
+		{query: "msg.contract=0xabcd", expected: TxMsgFilter{Contract: append(make([]byte, 18), 0xab, 0xcd)}},
+		{query: "msg.function=0x1::swap", wantErr: true},
+		{query: "msg.function=0x1::dex::", wantErr: true},
+		{query: "msg.contract=0xzz", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			app := fiber.New()
+			var (
+				filter TxMsgFilter
+				err    error
+			)
+			app.Get("/", func(c *fiber.Ctx) error {
+				filter, err = parseTxMsgFilter(c)
+				return nil
+			})
+
+			_, testErr := app.Test(httptest.NewRequest(fiber.MethodGet, "/?"+tc.query, nil), -1)
+			require.NoError(t, testErr)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, filter)
+		})
+	}
+}
+
+func TestGetTxs_MsgFunctionFilter(t *testing.T) {
+	handler, mock := newTxHandlerWithMockDB(t)
+
+	const (
+		height   = int64(88)
+		sequence = int64(12)
+		hash     = "0xSWAP"
+	)
+
+	row := sqlmock.NewRows([]string{"hash", "height", "sequence", "signer_id", "code", "codespace", "data"}).
+		AddRow([]byte(hash), height, sequence, int64(0), int64(0), "", legacyTxPayload(hash))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT sp`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SET LOCAL statement_timeout = '5s'`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT\("sequence"\)\) FROM "tx_msg" WHERE tx_msg.function = \$1 AND tx_msg.module = \$2`).
+		WithArgs("swap", "0x1::dex").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(`RESET statement_timeout`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "tx" WHERE sequence IN \(SELECT DISTINCT "sequence" FROM "tx_msg" WHERE tx_msg.function = \$1 AND tx_msg.module = \$2 ORDER BY sequence DESC LIMIT \$3\)`).
+		WithArgs("swap", "0x1::dex", int64(common.DefaultLimit)).
+		WillReturnRows(row)
+	mock.ExpectRollback()
+
+	app := fiber.New()
+	app.Get("/indexer/tx/v1/txs", handler.GetTxs)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/indexer/tx/v1/txs?msg.function=0x1::dex::swap", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTxs_MoveFunctionFilter(t *testing.T) {
+	handler, mock := newTxHandlerWithMockDB(t)
+
+	const (
+		height   = int64(91)
+		sequence = int64(14)
+		hash     = "0xCOIN"
+	)
+
+	row := sqlmock.NewRows([]string{"hash", "height", "sequence", "signer_id", "code", "codespace", "data"}).
+		AddRow([]byte(hash), height, sequence, int64(0), int64(0), "", legacyTxPayload(hash))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "move_function_dict" WHERE function IN \(\$1\)`).
+		WithArgs("0x1::coin::transfer").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "function"}).AddRow(int64(3), "0x1::coin::transfer"))
+	mock.ExpectExec(`SAVEPOINT sp`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SET LOCAL statement_timeout = '5s'`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT\("sequence"\)\) FROM "tx_move_calls" WHERE function_id = ANY\(\$1\)`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(`RESET statement_timeout`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "tx" WHERE sequence IN \(SELECT DISTINCT "sequence" FROM "tx_move_calls" WHERE function_id = ANY\(\$1\) ORDER BY sequence DESC LIMIT \$2\)`).
+		WithArgs(sqlmock.AnyArg(), int64(common.DefaultLimit)).
+		WillReturnRows(row)
+	mock.ExpectRollback()
+
+	app := fiber.New()
+	app.Get("/indexer/tx/v1/txs", handler.GetTxs)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/indexer/tx/v1/txs?move_function=0x0001::coin::transfer", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
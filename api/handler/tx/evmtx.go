@@ -23,21 +23,44 @@ import (
 // @Param pagination.limit query int false "Pagination limit, default is 100" default is 100
 // @Param pagination.count_total query bool false "Count total, default is true" default is true
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
+// @Param from_time query string false "Only include records at or after this time (RFC3339)"
+// @Param to_time query string false "Only include records at or before this time (RFC3339)"
+// @Param format query string false "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)" Enums(json, csv, ndjson)
+// @Param columns query string false "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default"
 // @Router /indexer/tx/v1/evm-txs [get]
 func (h *TxHandler) GetEvmTxs(c *fiber.Ctx) error {
 	pagination, err := common.ParsePagination(c, common.CursorTypeSequence)
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	format, err := common.ParseExportFormat(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	// Use read-only transaction for better performance
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
-	// Use optimized COUNT - no filters
-	query := tx.Model(&types.CollectedEvmTx{})
+	pagination.Range, err = h.GetSequenceRange(tx, timeRange, types.CollectedEvmTx{}.TableName())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	if format != "" {
+		return h.exportEvmTxs(c, format, pagination, func(tx *gorm.DB, pagination *common.Pagination) (*gorm.DB, error) {
+			return pagination.ApplySequence(tx.Model(&types.CollectedEvmTx{})), nil
+		})
+	}
+
+	// Use optimized COUNT - no filters unless bounded by a time range
+	query := pagination.ApplyRange(tx.Model(&types.CollectedEvmTx{}), "sequence")
 	var strategy types.CollectedEvmTx
-	hasFilters := false // no filters in basic GetEvmTxs
+	hasFilters := pagination.HasRange()
 	var total int64
 	total, err = common.GetOptimizedCount(query, strategy, hasFilters, pagination.CountTotal)
 	if err != nil {
@@ -60,9 +83,15 @@ func (h *TxHandler) GetEvmTxs(c *fiber.Ctx) error {
 		lastRecord = txs[len(txs)-1]
 	}
 
+	prunedHeight, err := h.ListPrunedHeight(tx)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(EvmTxsResponse{
-		Txs:        txsRes,
-		Pagination: pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		Txs:          txsRes,
+		Pagination:   pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		PrunedHeight: prunedHeight,
 	})
 }
 
@@ -79,6 +108,10 @@ func (h *TxHandler) GetEvmTxs(c *fiber.Ctx) error {
 // @Param pagination.count_total query bool false "Count total, default is true" default is true
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
 // @Param is_signer query bool false "Filter by signer accounts, default is false" default is false
+// @Param from_time query string false "Only include records at or after this time (RFC3339)"
+// @Param to_time query string false "Only include records at or before this time (RFC3339)"
+// @Param format query string false "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)" Enums(json, csv, ndjson)
+// @Param columns query string false "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default"
 // @Router /indexer/tx/v1/evm-txs/by_account/{account} [get]
 func (h *TxHandler) GetEvmTxsByAccount(c *fiber.Ctx) error {
 	account, err := common.GetAccountParam(c)
@@ -90,23 +123,46 @@ func (h *TxHandler) GetEvmTxsByAccount(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	format, err := common.ParseExportFormat(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	// Use read-only transaction for better performance
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
+	pagination.Range, err = h.GetSequenceRange(tx, timeRange, types.CollectedEvmTx{}.TableName())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
 	accountIds, err := h.GetAccountIds([]string{account})
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
 	if len(accountIds) == 0 {
+		if format != "" {
+			return h.exportEvmTxs(c, format, pagination, EmptyQuery(&types.CollectedEvmTx{}))
+		}
 		return c.JSON(EvmTxsResponse{
 			Txs:        []types.EvmTx{},
 			Pagination: pagination.ToResponse(0, false),
 		})
 	}
 
+	if format != "" {
+		return h.exportEvmTxs(c, format, pagination, func(tx *gorm.DB, pagination *common.Pagination) (*gorm.DB, error) {
+			query, _, err := buildEvmTxEdgeQuery(tx, accountIds[0], isSigner, pagination)
+			return query, err
+		})
+	}
+
 	query, total, err := buildEvmTxEdgeQuery(tx, accountIds[0], isSigner, pagination)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
@@ -127,9 +183,15 @@ func (h *TxHandler) GetEvmTxsByAccount(c *fiber.Ctx) error {
 		lastRecord = txs[len(txs)-1]
 	}
 
+	prunedHeight, err := h.ListPrunedHeight(tx)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(EvmTxsResponse{
-		Txs:        txsRes,
-		Pagination: pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		Txs:          txsRes,
+		Pagination:   pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		PrunedHeight: prunedHeight,
 	})
 }
 
@@ -144,7 +206,7 @@ func buildEvmTxEdgeQuery(tx *gorm.DB, accountID int64, isSigner bool, pagination
 	}
 
 	sequenceQuery = sequenceQuery.Distinct("sequence")
-	countQuery := sequenceQuery.Session(&gorm.Session{})
+	countQuery := pagination.ApplyRange(sequenceQuery.Session(&gorm.Session{}), "sequence")
 
 	total, err := common.GetCountWithTimeout(countQuery, pagination.CountTotal)
 	if err != nil {
@@ -188,6 +250,10 @@ func (h *TxHandler) GetEvmTxsByHeight(c *fiber.Ctx) error {
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
+	if err := h.CheckNotPruned(tx, height, "evm txs"); err != nil {
+		return err
+	}
+
 	query := tx.Model(&types.CollectedEvmTx{}).Where("height = ?", height)
 
 	// Use optimized COUNT - always has filters (height)
@@ -248,10 +314,20 @@ func (h *TxHandler) GetEvmTxByHash(c *fiber.Ctx) error {
 	if err := dbTx.Model(&types.CollectedEvmTx{}).
 		Where("hash = ?", hashBytes).
 		First(&tx).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		archived, err := h.FindArchivedEvmTx(hashBytes)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		if archived == nil {
+			if err := h.CheckMissingNotPruned(dbTx, "evm transaction"); err != nil {
+				return err
+			}
 			return fiber.NewError(fiber.StatusNotFound, "tx not found")
 		}
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		tx = *archived
 	}
 
 	txRes, err := ToEvmTxResponse(tx)
@@ -0,0 +1,138 @@
+package tx
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+// TxQueryBuilder builds the paginated tx query of a list endpoint, so that exports reuse the endpoint filters
+type TxQueryBuilder func(tx *gorm.DB, pagination *common.Pagination) (*gorm.DB, error)
+
+var txExportColumns = []common.ExportColumn[types.Tx]{
+	{Name: "txhash", Value: func(tx types.Tx) any { return tx.TxHash }},
+	{Name: "height", Value: func(tx types.Tx) any { return tx.Height }},
+	{Name: "timestamp", Value: func(tx types.Tx) any { return tx.Timestamp }},
+	{Name: "code", Value: func(tx types.Tx) any { return tx.Code }},
+	{Name: "codespace", Value: func(tx types.Tx) any { return tx.Codespace }},
+	{Name: "gas_wanted", Value: func(tx types.Tx) any { return tx.GasWanted }},
+	{Name: "gas_used", Value: func(tx types.Tx) any { return tx.GasUsed }},
+	{Name: "info", Value: func(tx types.Tx) any { return tx.Info }},
+	{Name: "raw_log", Value: func(tx types.Tx) any { return tx.RawLog }},
+}
+
+var evmTxExportColumns = []common.ExportColumn[types.EvmTx]{
+	{Name: "transactionHash", Value: func(tx types.EvmTx) any { return tx.TxHash }},
+	{Name: "blockNumber", Value: func(tx types.EvmTx) any { return tx.BlockNumber }},
+	{Name: "blockHash", Value: func(tx types.EvmTx) any { return tx.BlockHash }},
+	{Name: "transactionIndex", Value: func(tx types.EvmTx) any { return tx.TxIndex }},
+	{Name: "from", Value: func(tx types.EvmTx) any { return tx.From }},
+	{Name: "to", Value: func(tx types.EvmTx) any { return tx.To }},
+	{Name: "contractAddress", Value: func(tx types.EvmTx) any { return tx.ContractAddress }},
+	{Name: "status", Value: func(tx types.EvmTx) any { return tx.Status }},
+	{Name: "type", Value: func(tx types.EvmTx) any { return tx.Type }},
+	{Name: "gasUsed", Value: func(tx types.EvmTx) any { return tx.GasUsed }},
+	{Name: "cumulativeGasUsed", Value: func(tx types.EvmTx) any { return tx.CumulativeGasUsed }},
+	{Name: "effectiveGasPrice", Value: func(tx types.EvmTx) any { return tx.EffectiveGasPrice }},
+}
+
+var evmInternalTxExportColumns = []common.ExportColumn[EvmInternalTxResponse]{
+	{Name: "height", Value: func(tx EvmInternalTxResponse) any { return tx.Height }},
+	{Name: "hash", Value: func(tx EvmInternalTxResponse) any { return tx.Hash }},
+	{Name: "parent_index", Value: func(tx EvmInternalTxResponse) any { return tx.ParentIndex }},
+	{Name: "index", Value: func(tx EvmInternalTxResponse) any { return tx.Index }},
+	{Name: "type", Value: func(tx EvmInternalTxResponse) any { return tx.Type }},
+	{Name: "from", Value: func(tx EvmInternalTxResponse) any { return tx.From }},
+	{Name: "to", Value: func(tx EvmInternalTxResponse) any { return tx.To }},
+	{Name: "value", Value: func(tx EvmInternalTxResponse) any { return tx.Value }},
+	{Name: "gas", Value: func(tx EvmInternalTxResponse) any { return tx.Gas }},
+	{Name: "gasUsed", Value: func(tx EvmInternalTxResponse) any { return tx.GasUsed }},
+	{Name: "input", Value: func(tx EvmInternalTxResponse) any { return tx.Input }},
+	{Name: "output", Value: func(tx EvmInternalTxResponse) any { return tx.Output }},
+}
+
+// ExportTxs streams the txs matched by the query builder in the given format
+func ExportTxs(c *fiber.Ctx, h *common.BaseHandler, format common.ExportFormat, pagination *common.Pagination, build TxQueryBuilder) error {
+	return common.StreamExport(c, h, format, pagination, common.Export[types.Tx]{
+		Name:    "txs",
+		Columns: txExportColumns,
+		Fetch: func(tx *gorm.DB, pagination *common.Pagination) ([]types.Tx, any, error) {
+			query, err := build(tx, pagination)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			var txs []types.CollectedTx
+			if err := query.Find(&txs).Error; err != nil {
+				return nil, nil, err
+			}
+
+			txsRes, err := ToTxsResponse(txs)
+			if err != nil {
+				return nil, nil, err
+			}
+			return txsRes, common.LastRecord(txs), nil
+		},
+	})
+}
+
+func (h *TxHandler) exportEvmTxs(c *fiber.Ctx, format common.ExportFormat, pagination *common.Pagination, build TxQueryBuilder) error {
+	return common.StreamExport(c, h.BaseHandler, format, pagination, common.Export[types.EvmTx]{
+		Name:    "evm-txs",
+		Columns: evmTxExportColumns,
+		Fetch: func(tx *gorm.DB, pagination *common.Pagination) ([]types.EvmTx, any, error) {
+			query, err := build(tx, pagination)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			var txs []types.CollectedEvmTx
+			if err := query.Find(&txs).Error; err != nil {
+				return nil, nil, err
+			}
+
+			txsRes, err := ToEvmTxsResponse(txs)
+			if err != nil {
+				return nil, nil, err
+			}
+			return txsRes, common.LastRecord(txs), nil
+		},
+	})
+}
+
+func (h *TxHandler) exportEvmInternalTxs(c *fiber.Ctx, format common.ExportFormat, pagination *common.Pagination, build TxQueryBuilder) error {
+	return common.StreamExport(c, h.BaseHandler, format, pagination, common.Export[EvmInternalTxResponse]{
+		Name:    "evm-internal-txs",
+		Columns: evmInternalTxExportColumns,
+		Fetch: func(tx *gorm.DB, pagination *common.Pagination) ([]EvmInternalTxResponse, any, error) {
+			query, err := build(tx, pagination)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			var txs []types.CollectedEvmInternalTx
+			if err := query.Find(&txs).Error; err != nil {
+				return nil, nil, err
+			}
+
+			accounts, err := h.getAccounts(tx, txs)
+			if err != nil {
+				return nil, nil, err
+			}
+			hashes, err := h.getHashes(tx, txs)
+			if err != nil {
+				return nil, nil, err
+			}
+			return ToEvmInternalTxsResponse(txs, accounts, hashes), common.LastRecord(txs), nil
+		},
+	})
+}
+
+// EmptyQuery builds a query matching no row of the given model, for exports of unknown accounts
+func EmptyQuery(model any) TxQueryBuilder {
+	return func(tx *gorm.DB, _ *common.Pagination) (*gorm.DB, error) {
+		return tx.Model(model).Where("FALSE"), nil
+	}
+}
@@ -0,0 +1,56 @@
+package tx
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+func TestGetTxs_ExportReusesFilters(t *testing.T) {
+	handler, mock := newTxHandlerWithMockDB(t)
+
+	const hash = "0xFAILED"
+
+	// the request transaction only parses filters, rows are read by the stream in its own transaction
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "tx" WHERE sequence IN \(SELECT "sequence" FROM "tx" WHERE code <> 0 AND codespace = \$1 ORDER BY sequence DESC LIMIT \$2\)`).
+		WithArgs("sdk", int64(common.MaxLimit)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash", "height", "sequence", "signer_id", "code", "codespace", "data"}).
+			AddRow([]byte(hash), int64(100), int64(9), int64(0), int64(5), "sdk", legacyTxPayload(hash)))
+	mock.ExpectRollback()
+
+	app := fiber.New()
+	app.Get("/indexer/tx/v1/txs", handler.GetTxs)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/indexer/tx/v1/txs?status=failed&codespace=sdk&columns=txhash,height", nil)
+	req.Header.Set(fiber.HeaderAccept, common.MIMETextCSV)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "txhash,height\n"+hash+",100\n", string(body))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTxs_InvalidExportFormat(t *testing.T) {
+	handler, _ := newTxHandlerWithMockDB(t)
+
+	app := fiber.New()
+	app.Get("/indexer/tx/v1/txs", handler.GetTxs)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/indexer/tx/v1/txs?format=xml", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,110 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+const (
+	DefaultFailureWindow = 1000
+	MaxFailureWindow     = 100000
+)
+
+type txFailureCount struct {
+	Codespace  string
+	Code       uint32
+	TxCount    int64
+	LastHeight int64
+	LastTxHash []byte
+}
+
+// GetTxFailures handles GET /tx/v1/txs/failures
+// @Summary Get transaction failure statistics
+// @Description Aggregate failed transactions by codespace and code over a height window
+// @Tags Tx
+// @Accept json
+// @Produce json
+// @Param from_height query int false "Start height (inclusive), default is to_height - 999"
+// @Param to_height query int false "End height (inclusive), default is the latest indexed height"
+// @Success 200 {object} TxFailuresResponse
+// @Router /indexer/tx/v1/txs/failures [get]
+func (h *TxHandler) GetTxFailures(c *fiber.Ctx) error {
+	fromHeight, err := common.GetHeightQuery(c, "from_height")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	toHeight, err := common.GetHeightQuery(c, "to_height")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if toHeight == 0 {
+		var lastBlock types.CollectedBlock
+		if err := h.GetDatabase().
+			Model(&types.CollectedBlock{}).
+			Where("chain_id = ?", h.GetChainId()).
+			Order("height DESC").
+			Limit(1).
+			First(&lastBlock).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		toHeight = lastBlock.Height
+	}
+
+	fromHeight, toHeight, err = common.ResolveHeightRange(fromHeight, toHeight, DefaultFailureWindow, MaxFailureWindow)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if toHeight == 0 {
+		return c.JSON(TxFailuresResponse{
+			FromHeight:  "0",
+			ToHeight:    "0",
+			TotalFailed: "0",
+			Failures:    []TxFailureStats{},
+		})
+	}
+
+	var counts []txFailureCount
+	if err := h.GetDatabase().
+		Model(&types.CollectedTx{}).
+		Select("codespace, code, COUNT(*) AS tx_count, MAX(height) AS last_height, (ARRAY_AGG(hash ORDER BY sequence DESC))[1] AS last_tx_hash").
+		Where("height >= ? AND height <= ?", fromHeight, toHeight).
+		Where("code <> 0").
+		Group("codespace, code").
+		Order("tx_count DESC, codespace, code").
+		Scan(&counts).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get tx failures", err).Error())
+	}
+
+	failures, total := toTxFailureStats(counts)
+	return c.JSON(TxFailuresResponse{
+		FromHeight:  fmt.Sprintf("%d", fromHeight),
+		ToHeight:    fmt.Sprintf("%d", toHeight),
+		TotalFailed: fmt.Sprintf("%d", total),
+		Failures:    failures,
+	})
+}
+
+func toTxFailureStats(counts []txFailureCount) ([]TxFailureStats, int64) {
+	var total int64
+	failures := make([]TxFailureStats, 0, len(counts))
+	for _, count := range counts {
+		total += count.TxCount
+		failures = append(failures, TxFailureStats{
+			Codespace:  count.Codespace,
+			Code:       count.Code,
+			Count:      fmt.Sprintf("%d", count.TxCount),
+			LastHeight: fmt.Sprintf("%d", count.LastHeight),
+			LastTxHash: strings.ToUpper(util.BytesToHex(count.LastTxHash)),
+		})
+	}
+	return failures, total
+}
@@ -0,0 +1,31 @@
+package tx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToTxFailureStats(t *testing.T) {
+	t.Run("no failures", func(t *testing.T) {
+		failures, total := toTxFailureStats(nil)
+		assert.Empty(t, failures)
+		assert.NotNil(t, failures)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("aggregated failures", func(t *testing.T) {
+		failures, total := toTxFailureStats([]txFailureCount{
+			{Codespace: "sdk", Code: 5, TxCount: 12, LastHeight: 120, LastTxHash: []byte{0xab, 0xcd}},
+			{Codespace: "move", Code: 2, TxCount: 3, LastHeight: 99, LastTxHash: []byte{0x01}},
+		})
+		assert.Equal(t, int64(15), total)
+		assert.Len(t, failures, 2)
+		assert.Equal(t, "sdk", failures[0].Codespace)
+		assert.Equal(t, uint32(5), failures[0].Code)
+		assert.Equal(t, "12", failures[0].Count)
+		assert.Equal(t, "120", failures[0].LastHeight)
+		assert.Equal(t, "ABCD", failures[0].LastTxHash)
+		assert.Equal(t, "move", failures[1].Codespace)
+	})
+}
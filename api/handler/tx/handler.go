@@ -12,38 +12,47 @@ import (
 
 type TxHandler struct {
 	*common.BaseHandler
+	cache *cache.Cache
 }
 
 var _ common.HandlerRegistrar = (*TxHandler)(nil)
 
-func NewTxHandler(base *common.BaseHandler) *TxHandler {
-	return &TxHandler{BaseHandler: base}
+func NewTxHandler(base *common.BaseHandler, apiCache *cache.Cache) *TxHandler {
+	return &TxHandler{BaseHandler: base, cache: apiCache}
 }
 
 func (h *TxHandler) Register(router fiber.Router) {
 	txs := router.Group("indexer/tx/v1")
 
-	txs.Get("/txs", cache.WithExpiration(time.Second), h.GetTxs)
-	txs.Get("/txs/by_account/:account", cache.WithExpiration(time.Second), h.GetTxsByAccount)
-	txs.Get("/txs/by_height/:height", cache.WithExpiration(time.Second), h.GetTxsByHeight)
-	txs.Get("/txs/:tx_hash", cache.WithExpiration(10*time.Second), h.GetTxByHash)
+	txs.Get("/txs", h.cache.AppendOnly(), h.GetTxs)
+	txs.Get("/txs/by_account/:account", h.cache.PerBlock(), h.GetTxsByAccount)
+	txs.Get("/txs/by_height/:height", h.cache.PerBlock(), h.GetTxsByHeight)
+	txs.Get("/txs/failures", h.cache.WithExpiration(10*time.Second), h.GetTxFailures)
+	if h.GetChainConfig().VmType == types.MoveVM {
+		txs.Get("/txs/move_functions", h.cache.WithExpiration(10*time.Second), h.GetMoveFunctionStats)
+	} else {
+		txs.Get("/txs/move_functions", h.NotFound)
+	}
+	txs.Get("/txs/:tx_hash", h.cache.Immutable(), h.GetTxByHash)
+	txs.Post("/txs/batch", h.GetTxsBatch)
 
 	evmTxs := txs.Group("/evm-txs")
 	if h.GetChainConfig().VmType == types.EVM {
-		evmTxs.Get("", cache.WithExpiration(time.Second), h.GetEvmTxs)
-		evmTxs.Get("/by_account/:account", cache.WithExpiration(time.Second), h.GetEvmTxsByAccount)
-		evmTxs.Get("/by_height/:height", cache.WithExpiration(time.Second), h.GetEvmTxsByHeight)
-		evmTxs.Get("/:tx_hash", cache.WithExpiration(10*time.Second), h.GetEvmTxByHash)
+		evmTxs.Get("", h.cache.AppendOnly(), h.GetEvmTxs)
+		evmTxs.Get("/by_account/:account", h.cache.PerBlock(), h.GetEvmTxsByAccount)
+		evmTxs.Get("/by_height/:height", h.cache.PerBlock(), h.GetEvmTxsByHeight)
+		evmTxs.Get("/:tx_hash", h.cache.Immutable(), h.GetEvmTxByHash)
+		evmTxs.Post("/batch", h.GetEvmTxsBatch)
 	} else {
 		evmTxs.All("/*", h.NotFound)
 	}
 
 	itxs := txs.Group("/evm-internal-txs")
 	if h.GetChainConfig().VmType == types.EVM && h.GetConfig().GetInternalTxConfig().Enabled {
-		itxs.Get("", cache.WithExpiration(time.Second), h.GetEvmInternalTxs)
-		itxs.Get("/by_height/:height", cache.WithExpiration(time.Second), h.GetEvmInternalTxsByHeight)
-		itxs.Get("/:tx_hash", cache.WithExpiration(10*time.Second), h.GetEvmInternalTxsByHash)
-		itxs.Get("/by_account/:account", cache.WithExpiration(time.Second), h.GetEvmInternalTxsByAccount)
+		itxs.Get("", h.cache.AppendOnly(), h.GetEvmInternalTxs)
+		itxs.Get("/by_height/:height", h.cache.PerBlock(), h.GetEvmInternalTxsByHeight)
+		itxs.Get("/:tx_hash", h.cache.WithExpiration(10*time.Second), h.GetEvmInternalTxsByHash)
+		itxs.Get("/by_account/:account", h.cache.PerBlock(), h.GetEvmInternalTxsByAccount)
 	} else {
 		itxs.All("/*", h.NotFound)
 	}
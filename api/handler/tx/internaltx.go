@@ -23,21 +23,44 @@ import (
 // @Param pagination.limit query int false "Pagination limit, default is 100" default is 100
 // @Param pagination.count_total query bool false "Count total, default is true" default is true
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
+// @Param from_time query string false "Only include records at or after this time (RFC3339)"
+// @Param to_time query string false "Only include records at or before this time (RFC3339)"
+// @Param format query string false "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)" Enums(json, csv, ndjson)
+// @Param columns query string false "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default"
 // @Router /indexer/tx/v1/evm-internal-txs [get]
 func (h *TxHandler) GetEvmInternalTxs(c *fiber.Ctx) error {
 	pagination, err := common.ParsePagination(c, common.CursorTypeSequence)
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	format, err := common.ParseExportFormat(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	// Use read-only transaction for better performance
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
-	// Use optimized COUNT - no filters
-	query := tx.Model(&types.CollectedEvmInternalTx{})
+	pagination.Range, err = h.GetSequenceRange(tx, timeRange, types.CollectedEvmInternalTx{}.TableName())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	if format != "" {
+		return h.exportEvmInternalTxs(c, format, pagination, func(tx *gorm.DB, pagination *common.Pagination) (*gorm.DB, error) {
+			return pagination.ApplySequence(tx.Model(&types.CollectedEvmInternalTx{})), nil
+		})
+	}
+
+	// Use optimized COUNT - no filters unless bounded by a time range
+	query := pagination.ApplyRange(tx.Model(&types.CollectedEvmInternalTx{}), "sequence")
 	var strategy types.CollectedEvmInternalTx
-	hasFilters := false // no filters in basic GetEvmInternalTxs
+	hasFilters := pagination.HasRange()
 	var total int64
 	total, err = common.GetOptimizedCount(query, strategy, hasFilters, pagination.CountTotal)
 	if err != nil {
@@ -68,9 +91,15 @@ func (h *TxHandler) GetEvmInternalTxs(c *fiber.Ctx) error {
 		lastRecord = txs[len(txs)-1]
 	}
 
+	prunedHeight, err := h.ListPrunedHeight(tx)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(EvmInternalTxsResponse{
-		Txs:        txsRes,
-		Pagination: pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		Txs:          txsRes,
+		Pagination:   pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		PrunedHeight: prunedHeight,
 	})
 }
 
@@ -86,6 +115,10 @@ func (h *TxHandler) GetEvmInternalTxs(c *fiber.Ctx) error {
 // @Param pagination.limit query int false "Pagination limit, default is 100" default is 100
 // @Param pagination.count_total query bool false "Count total, default is true" default is true
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
+// @Param from_time query string false "Only include records at or after this time (RFC3339)"
+// @Param to_time query string false "Only include records at or before this time (RFC3339)"
+// @Param format query string false "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)" Enums(json, csv, ndjson)
+// @Param columns query string false "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default"
 // @Router /indexer/tx/v1/evm-internal-txs/by_account/{account} [get]
 func (h *TxHandler) GetEvmInternalTxsByAccount(c *fiber.Ctx) error {
 	account, err := common.GetAccountParam(c)
@@ -96,22 +129,45 @@ func (h *TxHandler) GetEvmInternalTxsByAccount(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	format, err := common.ParseExportFormat(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	// Use read-only transaction for better performance
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
+	pagination.Range, err = h.GetSequenceRange(tx, timeRange, types.CollectedEvmInternalTx{}.TableName())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
 	accountIds, err := h.GetAccountIds([]string{account})
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 	if len(accountIds) == 0 {
+		if format != "" {
+			return h.exportEvmInternalTxs(c, format, pagination, EmptyQuery(&types.CollectedEvmInternalTx{}))
+		}
 		return c.JSON(EvmInternalTxsResponse{
 			Txs:        []EvmInternalTxResponse{},
 			Pagination: pagination.ToResponse(0, false),
 		})
 	}
 
+	if format != "" {
+		return h.exportEvmInternalTxs(c, format, pagination, func(tx *gorm.DB, pagination *common.Pagination) (*gorm.DB, error) {
+			query, _, err := buildEvmInternalTxEdgeQuery(tx, accountIds[0], pagination)
+			return query, err
+		})
+	}
+
 	query, total, err := buildEvmInternalTxEdgeQuery(tx, accountIds[0], pagination)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
@@ -140,9 +196,15 @@ func (h *TxHandler) GetEvmInternalTxsByAccount(c *fiber.Ctx) error {
 		lastRecord = txs[len(txs)-1]
 	}
 
+	prunedHeight, err := h.ListPrunedHeight(tx)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(EvmInternalTxsResponse{
-		Txs:        txsRes,
-		Pagination: pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		Txs:          txsRes,
+		Pagination:   pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		PrunedHeight: prunedHeight,
 	})
 }
 
@@ -153,7 +215,7 @@ func buildEvmInternalTxEdgeQuery(tx *gorm.DB, accountID int64, pagination *commo
 		Where("account_id = ?", accountID)
 
 	sequenceQuery = sequenceQuery.Distinct("sequence")
-	countQuery := sequenceQuery.Session(&gorm.Session{})
+	countQuery := pagination.ApplyRange(sequenceQuery.Session(&gorm.Session{}), "sequence")
 
 	total, err := common.GetCountWithTimeout(countQuery, pagination.CountTotal)
 	if err != nil {
@@ -197,6 +259,10 @@ func (h *TxHandler) GetEvmInternalTxsByHeight(c *fiber.Ctx) error {
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
+	if err := h.CheckNotPruned(tx, height, "evm internal txs"); err != nil {
+		return err
+	}
+
 	query := tx.Model(&types.CollectedEvmInternalTx{}).Where("height = ?", height)
 
 	// Use optimized COUNT - always has filters (height)
@@ -314,8 +380,14 @@ func (h *TxHandler) GetEvmInternalTxsByHash(c *fiber.Ctx) error {
 		lastRecord = txs[len(txs)-1]
 	}
 
+	prunedHeight, err := h.ListPrunedHeight(tx)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(EvmInternalTxsResponse{
-		Txs:        txsRes,
-		Pagination: pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		Txs:          txsRes,
+		Pagination:   pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		PrunedHeight: prunedHeight,
 	})
 }
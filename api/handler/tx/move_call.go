@@ -0,0 +1,149 @@
+package tx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+const (
+	DefaultMoveFunctionDays  = 7
+	MaxMoveFunctionDays      = 31
+	DefaultMoveFunctionLimit = 10
+	MaxMoveFunctionLimit     = 100
+)
+
+type moveFunctionCount struct {
+	Day       time.Time
+	Function  string
+	CallCount int64
+}
+
+// parseMoveFunction normalizes a move function <address>::<module>::<function>, or script for the
+// scripts, to the form of the move_function_dict entries
+func parseMoveFunction(value string) (string, error) {
+	if value == "" || value == types.MoveScriptFunction {
+		return value, nil
+	}
+
+	parts := strings.Split(value, "::")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", types.NewInvalidValueError("move_function", value, "must be <address>::<module>::<function> or "+types.MoveScriptFunction)
+	}
+	module, err := util.MoveModuleId(parts[0], parts[1])
+	if err != nil {
+		return "", types.NewInvalidValueError("move_function", value, "invalid module address")
+	}
+	return module + "::" + parts[2], nil
+}
+
+// GetMoveFunctionStats handles GET /tx/v1/txs/move_functions
+// @Summary Get the top called move functions per day
+// @Description Count the txs calling each move entry function, or a script, per UTC day over a time window
+// @Tags Tx
+// @Accept json
+// @Produce json
+// @Param from_time query string false "Start of the window (RFC3339), default is 7 days before to_time"
+// @Param to_time query string false "End of the window (RFC3339), default is now"
+// @Param limit query int false "Number of functions per day, default is 10, max 100"
+// @Success 200 {object} MoveFunctionStatsResponse
+// @Router /indexer/tx/v1/txs/move_functions [get]
+func (h *TxHandler) GetMoveFunctionStats(c *fiber.Ctx) error {
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	limit, err := parseMoveFunctionLimit(c.Query("limit"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if timeRange.To.IsZero() {
+		timeRange.To = time.Now().UTC()
+	}
+	if timeRange.From.IsZero() {
+		timeRange.From = timeRange.To.AddDate(0, 0, -DefaultMoveFunctionDays)
+	}
+	if timeRange.From.After(timeRange.To) {
+		return fiber.NewError(fiber.StatusBadRequest, types.NewInvalidValueError("from_time", timeRange.From.Format(time.RFC3339), "must not be after to_time").Error())
+	}
+	if timeRange.To.Sub(timeRange.From) > MaxMoveFunctionDays*24*time.Hour {
+		return fiber.NewError(fiber.StatusBadRequest, types.NewInvalidValueError("from_time", timeRange.From.Format(time.RFC3339), fmt.Sprintf("window must not exceed %d days", MaxMoveFunctionDays)).Error())
+	}
+
+	res := MoveFunctionStatsResponse{
+		FromTime: timeRange.From.Format(time.RFC3339),
+		ToTime:   timeRange.To.Format(time.RFC3339),
+		Days:     []MoveFunctionDayStats{},
+	}
+
+	db := h.GetDatabase().DB
+	seqRange, err := h.GetSequenceRange(db, timeRange, types.CollectedTx{}.TableName())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if seqRange.Empty {
+		return c.JSON(res)
+	}
+
+	// both bounds of the window are set, so are the ones of the sequence range
+	var counts []moveFunctionCount
+	if err := db.Raw(`WITH counts AS (
+			SELECT (b.timestamp AT TIME ZONE 'UTC')::date AS day, c.function_id, COUNT(*) AS call_count
+			FROM tx_move_calls AS c
+			JOIN tx AS t ON t.sequence = c.sequence
+			JOIN block AS b ON b.chain_id = ? AND b.height = t.height
+			WHERE c.sequence BETWEEN ? AND ?
+			GROUP BY 1, 2
+		), ranked AS (
+			SELECT day, function_id, call_count, ROW_NUMBER() OVER (PARTITION BY day ORDER BY call_count DESC, function_id) AS rank
+			FROM counts
+		)
+		SELECT ranked.day, d.function, ranked.call_count
+		FROM ranked
+		JOIN move_function_dict AS d ON d.id = ranked.function_id
+		WHERE ranked.rank <= ?
+		ORDER BY ranked.day, ranked.call_count DESC, d.function`,
+		h.GetChainId(), seqRange.From, seqRange.To, limit).
+		Scan(&counts).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get move function stats", err).Error())
+	}
+
+	res.Days = toMoveFunctionDayStats(counts)
+	return c.JSON(res)
+}
+
+func parseMoveFunctionLimit(value string) (int, error) {
+	if value == "" {
+		return DefaultMoveFunctionLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxMoveFunctionLimit {
+		return 0, types.NewInvalidValueError("limit", value, fmt.Sprintf("must be between 1 and %d", MaxMoveFunctionLimit))
+	}
+	return limit, nil
+}
+
+// toMoveFunctionDayStats groups the counts, ordered by day, into the stats of each day
+func toMoveFunctionDayStats(counts []moveFunctionCount) []MoveFunctionDayStats {
+	days := make([]MoveFunctionDayStats, 0)
+	for _, count := range counts {
+		date := count.Day.UTC().Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, MoveFunctionDayStats{Date: date, Functions: []MoveFunctionCount{}})
+		}
+		day := &days[len(days)-1]
+		day.Functions = append(day.Functions, MoveFunctionCount{
+			Function: count.Function,
+			Count:    fmt.Sprintf("%d", count.CallCount),
+		})
+	}
+	return days
+}
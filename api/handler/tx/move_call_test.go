@@ -0,0 +1,52 @@
+package tx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+func TestParseMoveFunction(t *testing.T) {
+	for value, expected := range map[string]string{
+		"":                         "",
+		"script":                   types.MoveScriptFunction,
+		"0x1::coin::transfer":      "0x1::coin::transfer",
+		"0x0001::coin::transfer":   "0x1::coin::transfer",
+		"0xABCD::dex::swap_script": "0xabcd::dex::swap_script",
+	} {
+		function, err := parseMoveFunction(value)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, function, value)
+	}
+
+	for _, value := range []string{"transfer", "0x1::coin", "0x1::coin::", "::coin::transfer", "0x1::coin::transfer::x", "0xzz::coin::transfer"} {
+		_, err := parseMoveFunction(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestToMoveFunctionDayStats(t *testing.T) {
+	day1 := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	days := toMoveFunctionDayStats([]moveFunctionCount{
+		{Day: day1, Function: "0x1::coin::transfer", CallCount: 30},
+		{Day: day1, Function: "script", CallCount: 2},
+		{Day: day2, Function: "0x1::dex::swap", CallCount: 7},
+	})
+
+	require.Equal(t, []MoveFunctionDayStats{
+		{Date: "2026-10-16", Functions: []MoveFunctionCount{
+			{Function: "0x1::coin::transfer", Count: "30"},
+			{Function: "script", Count: "2"},
+		}},
+		{Date: "2026-10-17", Functions: []MoveFunctionCount{
+			{Function: "0x1::dex::swap", Count: "7"},
+		}},
+	}, days)
+
+	require.Empty(t, toMoveFunctionDayStats(nil))
+}
@@ -0,0 +1,163 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+// testDSNEnv names the postgres database the partition pruning tests run against, they are
+// skipped without it
+const testDSNEnv = "ROLLYTICS_TEST_DB_DSN"
+
+// planNode is a node of the json output of EXPLAIN
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	IndexCond    string     `json:"Index Cond"`
+	ActualLoops  int64      `json:"Actual Loops"`
+	Plans        []planNode `json:"Plans"`
+}
+
+// setupPartitionedDB creates tx and tx_msg_types partitioned by sequence in partitions of 100
+// sequences, with 10 txs per height and the msg type 1 or 2 by the parity of the sequence
+func setupPartitionedDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// the search path is set on the only connection
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	schema := fmt.Sprintf("pruning_test_%d", os.Getpid())
+	require.NoError(t, db.Exec("DROP SCHEMA IF EXISTS "+schema+" CASCADE").Error)
+	require.NoError(t, db.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() { db.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE") })
+
+	for _, stmt := range []string{
+		"SET search_path TO " + schema,
+		`CREATE TABLE tx (hash bytea, height bigint, sequence bigint NOT NULL, signer_id bigint,
+			code bigint NOT NULL DEFAULT 0, codespace text NOT NULL DEFAULT '', data jsonb,
+			PRIMARY KEY (hash, height, sequence)) PARTITION BY RANGE (sequence)`,
+		"CREATE INDEX tx_height ON tx (height)",
+		"CREATE INDEX tx_sequence_desc ON tx (sequence DESC)",
+		`CREATE TABLE tx_msg_types (msg_type_id bigint, sequence bigint,
+			PRIMARY KEY (msg_type_id, sequence)) PARTITION BY RANGE (sequence)`,
+		"CREATE INDEX tx_msg_types_sequence ON tx_msg_types (sequence)",
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	for p := 0; p < 3; p++ {
+		for _, table := range []string{"tx", "tx_msg_types"} {
+			require.NoError(t, db.Exec(fmt.Sprintf("CREATE TABLE %[1]s_p%[2]d PARTITION OF %[1]s FOR VALUES FROM (%[3]d) TO (%[4]d)",
+				table, p, p*100, (p+1)*100)).Error)
+		}
+	}
+	require.NoError(t, db.Exec(`INSERT INTO tx (hash, height, sequence, signer_id, data)
+		SELECT sha256(s::text::bytea), s / 10 + 1, s, 1, '{}' FROM generate_series(1, 299) AS s`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO tx_msg_types (msg_type_id, sequence)
+		SELECT s % 2 + 1, s FROM generate_series(1, 299) AS s`).Error)
+	require.NoError(t, db.Exec("ANALYZE tx, tx_msg_types").Error)
+
+	// the tables are tiny, so the planner is steered to the plans it chooses for large ones
+	for _, setting := range []string{"enable_seqscan", "enable_bitmapscan", "enable_hashjoin", "enable_mergejoin"} {
+		require.NoError(t, db.Exec("SET "+setting+" = off").Error)
+	}
+	return db
+}
+
+// explain runs the query with EXPLAIN ANALYZE and returns the root of its plan
+func explain(t *testing.T, db *gorm.DB, build func(tx *gorm.DB) *gorm.DB) planNode {
+	stmt := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var txs []types.CollectedTx
+		return build(tx).Find(&txs)
+	})
+
+	var out string
+	require.NoError(t, db.Raw("EXPLAIN (ANALYZE, FORMAT JSON) "+stmt).Row().Scan(&out))
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &plans))
+	require.Len(t, plans, 1)
+	return plans[0].Plan
+}
+
+// scannedPartitions returns the partitions of the table scanned in the plan with the loops they were
+// executed, restricted to the index scans whose condition contains cond
+func scannedPartitions(node planNode, table, cond string, loops map[string]int64) map[string]int64 {
+	if loops == nil {
+		loops = make(map[string]int64)
+	}
+	if strings.HasPrefix(node.RelationName, table+"_p") && strings.Contains(node.IndexCond, cond) {
+		loops[node.RelationName] += node.ActualLoops
+	}
+	for _, child := range node.Plans {
+		scannedPartitions(child, table, cond, loops)
+	}
+	return loops
+}
+
+func executed(loops map[string]int64) []string {
+	var partitions []string
+	for partition, n := range loops {
+		if n > 0 {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions
+}
+
+func TestPartitionPruning_ByHeight(t *testing.T) {
+	db := setupPartitionedDB(t)
+	pagination := &common.Pagination{Limit: 10, Order: common.OrderDesc, CursorType: common.CursorTypeSequence}
+
+	// the sequences 240-249 of the height are in tx_p2, the lookups of the other partitions are pruned
+	plan := explain(t, db, func(tx *gorm.DB) *gorm.DB {
+		query, _, err := buildEdgeQueryForGetTxsByHeight(tx, 25, nil, TxStatusFilter{}, TxMsgFilter{}, pagination)
+		require.NoError(t, err)
+		return query
+	})
+	assert.ElementsMatch(t, []string{"tx_p2"}, executed(scannedPartitions(plan, "tx", "sequence", nil)))
+}
+
+func TestPartitionPruning_List(t *testing.T) {
+	db := setupPartitionedDB(t)
+	pagination := &common.Pagination{
+		Limit:       10,
+		Order:       common.OrderDesc,
+		CursorType:  common.CursorTypeSequence,
+		CursorValue: map[string]any{"sequence": int64(150)},
+	}
+
+	plan := explain(t, db, func(tx *gorm.DB) *gorm.DB {
+		query, _, err := buildEdgeQueryForGetTxs(tx, []int64{1}, nil, TxStatusFilter{}, TxMsgFilter{}, pagination)
+		require.NoError(t, err)
+		return query
+	})
+
+	// the partitions above the cursor are pruned from the plan
+	msgTypeScans := scannedPartitions(plan, "tx_msg_types", "", nil)
+	assert.Contains(t, msgTypeScans, "tx_msg_types_p1")
+	assert.NotContains(t, msgTypeScans, "tx_msg_types_p2")
+
+	// the page of sequences 130-149 is in tx_p1, the lookups of the other partitions are pruned
+	assert.ElementsMatch(t, []string{"tx_p1"}, executed(scannedPartitions(plan, "tx", "sequence", nil)))
+}
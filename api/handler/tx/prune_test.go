@@ -0,0 +1,151 @@
+package tx
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+)
+
+func TestGetTxsByHeight_Pruned(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   string
+		path    string
+		handler func(*TxHandler) fiber.Handler
+	}{
+		{"txs", "/txs/by_height/:height", "/txs/by_height/50", func(h *TxHandler) fiber.Handler { return h.GetTxsByHeight }},
+		{"evm txs", "/evm-txs/by_height/:height", "/evm-txs/by_height/50", func(h *TxHandler) fiber.Handler { return h.GetEvmTxsByHeight }},
+		{"evm internal txs", "/evm-internal-txs/by_height/:height", "/evm-internal-txs/by_height/50", func(h *TxHandler) fiber.Handler { return h.GetEvmInternalTxsByHeight }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mock := newTxHandlerWithMockDB(t)
+			handler.GetConfig().SetPruneConfig(&config.PruneConfig{KeepBlocks: 100, BatchSize: 10})
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "prune_status" ORDER BY "prune_status"."pruned_height" LIMIT $1`)).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"pruned_height", "deleted_records"}).AddRow(50, 1234))
+			mock.ExpectRollback()
+
+			app := fiber.New()
+			app.Get(tc.route, tc.handler(handler))
+
+			req := httptest.NewRequest("GET", tc.path, nil)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusGone, resp.StatusCode)
+			require.Contains(t, string(body), "[PRUNED] "+tc.name+" pruned: only heights from 51 are retained")
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func expectPruneStatus(mock sqlmock.Sqlmock, prunedHeight int64) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "prune_status" ORDER BY "prune_status"."pruned_height" LIMIT $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"pruned_height", "deleted_records"}).AddRow(prunedHeight, 1234))
+}
+
+func TestGetTxByHash_Pruned(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    string
+		table    string
+		resource string
+		handler  func(*TxHandler) fiber.Handler
+	}{
+		{"tx", "/txs/:tx_hash", "tx", "transaction", func(h *TxHandler) fiber.Handler { return h.GetTxByHash }},
+		{"evm tx", "/evm-txs/:tx_hash", "evm_tx", "evm transaction", func(h *TxHandler) fiber.Handler { return h.GetEvmTxByHash }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, pruned := range []bool{false, true} {
+				handler, mock := newTxHandlerWithMockDB(t)
+				if pruned {
+					handler.GetConfig().SetPruneConfig(&config.PruneConfig{KeepBlocks: 100, BatchSize: 10})
+				}
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "` + tc.table + `" WHERE hash = $1`)).
+					WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+				if pruned {
+					expectPruneStatus(mock, 50)
+				}
+				mock.ExpectRollback()
+
+				app := fiber.New()
+				app.Get(tc.route, tc.handler(handler))
+
+				resp, err := app.Test(httptest.NewRequest("GET", strings.Replace(tc.route, ":tx_hash", "0xabcd", 1), nil), -1)
+				require.NoError(t, err)
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				resp.Body.Close()
+
+				// a missing tx may have been deleted once the pruning mode ran
+				if pruned {
+					require.Equal(t, fiber.StatusGone, resp.StatusCode)
+					require.Contains(t, string(body), "[PRUNED] "+tc.resource+" pruned: only heights from 51 are retained")
+				} else {
+					require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+				}
+				require.NoError(t, mock.ExpectationsWereMet())
+			}
+		})
+	}
+}
+
+func TestGetTxsByAccount_PrunedHeight(t *testing.T) {
+	tc := byAccountTestCase{
+		route:      "/indexer/tx/v1/txs/by_account/:account",
+		table:      "tx",
+		edgeTable:  types.CollectedTxAccount{}.TableName(),
+		payload:    legacyTxPayload,
+		hash:       "0xBB",
+		accountHex: "0x5",
+		accountID:  5,
+		height:     110,
+		sequence:   20,
+	}
+	handler, mock := newTxHandlerWithMockDB(t)
+	handler.GetConfig().SetPruneConfig(&config.PruneConfig{KeepBlocks: 100, BatchSize: 10})
+	accBytes, err := util.AccAddressFromString(tc.accountHex)
+	require.NoError(t, err)
+
+	expectAccountQueries(t, mock, tc, accBytes)
+	expectPruneStatus(mock, 50)
+	mock.ExpectRollback()
+
+	app := fiber.New()
+	app.Get(tc.route, handler.GetTxsByAccount)
+
+	resp, err := app.Test(httptest.NewRequest("GET", tc.requestPath(), nil), -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// the list reports the heights missing from it
+	var res TxsResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	require.Len(t, res.Txs, 1)
+	require.Equal(t, int64(50), res.PrunedHeight)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,33 +1,195 @@
 package tx
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 
 	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
 	"github.com/initia-labs/rollytics/util/common-handler/common"
 )
 
-func buildTxEdgeQuery(tx *gorm.DB, accountID int64, isSigner bool, msgTypeIds []int64, pagination *common.Pagination) (*gorm.DB, int64, error) {
+const (
+	TxStatusSuccess = "success"
+	TxStatusFailed  = "failed"
+)
+
+// TxStatusFilter narrows tx queries down by execution result
+type TxStatusFilter struct {
+	Status    string
+	Codespace string
+}
+
+func parseTxStatusFilter(c *fiber.Ctx) (TxStatusFilter, error) {
+	filter := TxStatusFilter{
+		Status:    c.Query("status"),
+		Codespace: c.Query("codespace"),
+	}
+
+	switch filter.Status {
+	case "", TxStatusSuccess, TxStatusFailed:
+	default:
+		return filter, types.NewInvalidValueError("status", filter.Status, fmt.Sprintf("must be one of %s, %s", TxStatusSuccess, TxStatusFailed))
+	}
+	// only failed txs have a codespace, so no successful tx would match
+	if filter.Status == TxStatusSuccess && filter.Codespace != "" {
+		return filter, types.NewInvalidValueError("codespace", filter.Codespace, fmt.Sprintf("cannot be combined with status %s", TxStatusSuccess))
+	}
+
+	return filter, nil
+}
+
+func (f TxStatusFilter) IsEmpty() bool {
+	return f.Status == "" && f.Codespace == ""
+}
+
+// apply adds the filter conditions on the columns of the tx table, qualified by txTable when given
+func (f TxStatusFilter) apply(query *gorm.DB, txTable string) *gorm.DB {
+	column := func(name string) string {
+		if txTable == "" {
+			return name
+		}
+		return txTable + "." + name
+	}
+
+	// only failed txs have a codespace, so a codespace filter implies code <> 0 (hits the partial indexes)
+	if f.Status == TxStatusSuccess {
+		query = query.Where(column("code") + " = 0")
+	}
+	if f.Status == TxStatusFailed || f.Codespace != "" {
+		query = query.Where(column("code") + " <> 0")
+	}
+	if f.Codespace != "" {
+		query = query.Where(column("codespace")+" = ?", f.Codespace)
+	}
+
+	return query
+}
+
+// applyToEdge restricts an edge table query to the sequences of txs matching the filter
+func (f TxStatusFilter) applyToEdge(tx *gorm.DB, query *gorm.DB, edgeTable string) *gorm.DB {
+	if f.IsEmpty() {
+		return query
+	}
+
+	txTable := types.CollectedTx{}.TableName()
+	subQuery := f.apply(
+		tx.Session(&gorm.Session{NewDB: true}).
+			Table(txTable).
+			Select("1").
+			Where(txTable+".sequence = "+edgeTable+".sequence"),
+		txTable,
+	)
+
+	return query.Where("EXISTS (?)", subQuery)
+}
+
+// TxMsgFilter narrows tx queries down by the decoded fields of their msgs
+type TxMsgFilter struct {
+	Contract []byte
+	Module   string
+	Function string
+}
+
+// parseTxMsgFilter parses msg.contract, a bech32 or hex address, and msg.function, a function name
+// or a move function id <address>::<module>::<function>
+func parseTxMsgFilter(c *fiber.Ctx) (TxMsgFilter, error) {
+	var filter TxMsgFilter
+
+	if contract := c.Query("msg.contract"); contract != "" {
+		addr, err := util.AccAddressFromString(contract)
+		if err != nil {
+			return filter, types.NewInvalidValueError("msg.contract", contract, "must be a bech32 or hex address")
+		}
+		filter.Contract = addr
+	}
+
+	function := c.Query("msg.function")
+	if idx := strings.LastIndex(function, "::"); idx >= 0 {
+		addr, name, ok := strings.Cut(function[:idx], "::")
+		if !ok || name == "" || idx+2 == len(function) {
+			return filter, types.NewInvalidValueError("msg.function", function, "must be a function name or <address>::<module>::<function>")
+		}
+		module, err := util.MoveModuleId(addr, name)
+		if err != nil {
+			return filter, types.NewInvalidValueError("msg.function", function, "invalid module address")
+		}
+		filter.Module = module
+		function = function[idx+2:]
+	} else if strings.HasPrefix(function, "0x") {
+		// evm selectors are stored in lower case
+		function = strings.ToLower(function)
+	}
+	filter.Function = function
+
+	return filter, nil
+}
+
+func (f TxMsgFilter) IsEmpty() bool {
+	return f.Contract == nil && f.Function == ""
+}
+
+// apply adds the filter conditions on the columns of the tx_msg table
+func (f TxMsgFilter) apply(query *gorm.DB) *gorm.DB {
+	table := types.CollectedTxMsg{}.TableName()
+
+	if f.Contract != nil {
+		query = query.Where(table+".contract = ?", f.Contract)
+	}
+	if f.Function != "" {
+		query = query.Where(table+".function = ?", f.Function)
+	}
+	if f.Module != "" {
+		query = query.Where(table+".module = ?", f.Module)
+	}
+
+	return query
+}
+
+// applyToEdge restricts an edge table query to the sequences of txs with a msg matching the filter
+func (f TxMsgFilter) applyToEdge(tx *gorm.DB, query *gorm.DB, edgeTable string) *gorm.DB {
+	if f.IsEmpty() {
+		return query
+	}
+
+	msgTable := types.CollectedTxMsg{}.TableName()
+	subQuery := f.apply(
+		tx.Session(&gorm.Session{NewDB: true}).
+			Table(msgTable).
+			Select("1").
+			Where(msgTable + ".sequence = " + edgeTable + ".sequence"),
+	)
+
+	return query.Where("EXISTS (?)", subQuery)
+}
+
+func buildTxEdgeQuery(tx *gorm.DB, accountID int64, isSigner bool, msgTypeIds []int64, status TxStatusFilter, msg TxMsgFilter, pagination *common.Pagination) (*gorm.DB, int64, error) {
+	// the columns are qualified, since the msg type join has a sequence too
+	at := types.CollectedTxAccount{}.TableName()
 	sequenceQuery := tx.
 		Model(&types.CollectedTxAccount{}).
-		Select("sequence").
-		Where("account_id = ?", accountID)
+		Select(at+".sequence").
+		Where(at+".account_id = ?", accountID)
 
 	if isSigner {
-		sequenceQuery = sequenceQuery.Where("signer")
+		sequenceQuery = sequenceQuery.Where(at + ".signer")
 	}
 
 	if len(msgTypeIds) > 0 {
-		at := types.CollectedTxAccount{}.TableName()
 		mtt := types.CollectedTxMsgType{}.TableName()
 		sequenceQuery = sequenceQuery.
 			Joins("JOIN "+mtt+" ON "+mtt+".sequence = "+at+".sequence").
 			Where(mtt+".msg_type_id = ANY(?)", pq.Array(msgTypeIds))
 	}
 
-	sequenceQuery = sequenceQuery.Distinct("sequence")
-	countQuery := sequenceQuery.Session(&gorm.Session{})
+	sequenceQuery = status.applyToEdge(tx, sequenceQuery, at)
+	sequenceQuery = msg.applyToEdge(tx, sequenceQuery, at)
+	sequenceQuery = sequenceQuery.Distinct(at + ".sequence")
+	countQuery := pagination.ApplyRange(sequenceQuery.Session(&gorm.Session{}), at+".sequence")
 
 	total, err := common.GetCountWithTimeout(countQuery, pagination.CountTotal)
 	if err != nil {
@@ -35,7 +197,7 @@ func buildTxEdgeQuery(tx *gorm.DB, accountID int64, isSigner bool, msgTypeIds []
 	}
 
 	// apply pagination to the sequence query
-	sequenceQuery = pagination.ApplySequence(sequenceQuery)
+	sequenceQuery = pagination.ApplySequenceOf(sequenceQuery, at+".sequence")
 
 	query := tx.Model(&types.CollectedTx{}).
 		Where("sequence IN (?)", sequenceQuery).
@@ -44,17 +206,57 @@ func buildTxEdgeQuery(tx *gorm.DB, accountID int64, isSigner bool, msgTypeIds []
 	return query, total, nil
 }
 
-func buildSequenceQueryWithMsgTypeFilter(tx *gorm.DB, msgTypeIds []int64) *gorm.DB {
+// applyMoveFunctionsToEdge restricts an edge table query to the sequences of txs calling one of the move functions
+func applyMoveFunctionsToEdge(tx *gorm.DB, query *gorm.DB, edgeTable string, moveFunctionIds []int64) *gorm.DB {
+	if len(moveFunctionIds) == 0 {
+		return query
+	}
+
+	callTable := types.CollectedTxMoveCall{}.TableName()
+	subQuery := tx.Session(&gorm.Session{NewDB: true}).
+		Table(callTable).
+		Select("1").
+		Where(callTable+".sequence = "+edgeTable+".sequence").
+		Where(callTable+".function_id = ANY(?)", pq.Array(moveFunctionIds))
+
+	return query.Where("EXISTS (?)", subQuery)
+}
+
+func buildSequenceQueryWithMsgTypeFilter(tx *gorm.DB, msgTypeIds, moveFunctionIds []int64, status TxStatusFilter, msg TxMsgFilter) *gorm.DB {
+	// Without msg_type filter, move function filter is served directly by the tx_move_calls table
+	if len(msgTypeIds) == 0 && len(moveFunctionIds) > 0 {
+		callTable := types.CollectedTxMoveCall{}.TableName()
+		query := tx.Model(&types.CollectedTxMoveCall{}).Where("function_id = ANY(?)", pq.Array(moveFunctionIds))
+		query = status.applyToEdge(tx, query, callTable)
+		query = msg.applyToEdge(tx, query, callTable)
+		return query.Distinct("sequence")
+	}
+
+	// Without msg_type filter, msg filter is served directly by the tx_msg table (uses its indexes)
+	if len(msgTypeIds) == 0 && !msg.IsEmpty() {
+		query := msg.apply(tx.Model(&types.CollectedTxMsg{}))
+		query = status.applyToEdge(tx, query, types.CollectedTxMsg{}.TableName())
+		return query.Distinct("sequence")
+	}
+
+	// Without msg_type filter, status filter is served directly by the tx table (uses partial indexes)
+	if len(msgTypeIds) == 0 && !status.IsEmpty() {
+		return status.apply(tx.Model(&types.CollectedTx{}), "").Select("sequence")
+	}
+
 	query := tx.Model(&types.CollectedTxMsgType{})
 
 	if len(msgTypeIds) > 0 {
 		query = query.Where("msg_type_id = ANY(?)", pq.Array(msgTypeIds))
 	}
 
+	query = status.applyToEdge(tx, query, types.CollectedTxMsgType{}.TableName())
+	query = msg.applyToEdge(tx, query, types.CollectedTxMsgType{}.TableName())
+	query = applyMoveFunctionsToEdge(tx, query, types.CollectedTxMsgType{}.TableName(), moveFunctionIds)
 	return query.Distinct("sequence")
 }
 
-func buildSequenceQueryWithHeightAndMsgTypeFilter(tx *gorm.DB, height int64, msgTypeIds []int64) *gorm.DB {
+func buildSequenceQueryWithHeightAndMsgTypeFilter(tx *gorm.DB, height int64, msgTypeIds []int64, status TxStatusFilter, msg TxMsgFilter) *gorm.DB {
 	txTable := types.CollectedTx{}.TableName()
 	mttTable := types.CollectedTxMsgType{}.TableName()
 
@@ -71,13 +273,15 @@ func buildSequenceQueryWithHeightAndMsgTypeFilter(tx *gorm.DB, height int64, msg
 			Where(mttTable+".msg_type_id = ANY(?)", pq.Array(msgTypeIds))
 	}
 
+	query = status.apply(query, txTable)
+	query = msg.applyToEdge(tx, query, txTable)
 	return query.Distinct(txTable + ".sequence")
 }
 
-func buildEdgeQueryForGetTxs(tx *gorm.DB, msgTypeIds []int64, pagination *common.Pagination) (*gorm.DB, int64, error) {
-	sequenceQuery := buildSequenceQueryWithMsgTypeFilter(tx, msgTypeIds)
+func buildEdgeQueryForGetTxs(tx *gorm.DB, msgTypeIds, moveFunctionIds []int64, status TxStatusFilter, msg TxMsgFilter, pagination *common.Pagination) (*gorm.DB, int64, error) {
+	sequenceQuery := buildSequenceQueryWithMsgTypeFilter(tx, msgTypeIds, moveFunctionIds, status, msg)
 
-	hasFilters := len(msgTypeIds) > 0
+	hasFilters := len(msgTypeIds) > 0 || len(moveFunctionIds) > 0 || !status.IsEmpty() || !msg.IsEmpty() || pagination.HasRange()
 
 	var total int64
 	var err error
@@ -89,7 +293,7 @@ func buildEdgeQueryForGetTxs(tx *gorm.DB, msgTypeIds []int64, pagination *common
 			pagination.CountTotal,
 		)
 	} else {
-		countQuery := buildSequenceQueryWithMsgTypeFilter(tx, msgTypeIds)
+		countQuery := pagination.ApplyRange(buildSequenceQueryWithMsgTypeFilter(tx, msgTypeIds, moveFunctionIds, status, msg), "sequence")
 		total, err = common.GetCountWithTimeout(countQuery, pagination.CountTotal)
 	}
 
@@ -106,10 +310,10 @@ func buildEdgeQueryForGetTxs(tx *gorm.DB, msgTypeIds []int64, pagination *common
 	return query, total, nil
 }
 
-func buildEdgeQueryForGetTxsByHeight(tx *gorm.DB, height int64, msgTypeIds []int64, pagination *common.Pagination) (*gorm.DB, int64, error) {
-	sequenceQuery := buildSequenceQueryWithHeightAndMsgTypeFilter(tx, height, msgTypeIds)
+func buildEdgeQueryForGetTxsByHeight(tx *gorm.DB, height int64, msgTypeIds []int64, status TxStatusFilter, msg TxMsgFilter, pagination *common.Pagination) (*gorm.DB, int64, error) {
+	sequenceQuery := buildSequenceQueryWithHeightAndMsgTypeFilter(tx, height, msgTypeIds, status, msg)
 
-	hasFilters := len(msgTypeIds) > 0
+	hasFilters := len(msgTypeIds) > 0 || !status.IsEmpty() || !msg.IsEmpty()
 
 	var total int64
 	var err error
@@ -122,7 +326,7 @@ func buildEdgeQueryForGetTxsByHeight(tx *gorm.DB, height int64, msgTypeIds []int
 			pagination.CountTotal,
 		)
 	} else {
-		countQuery := buildSequenceQueryWithHeightAndMsgTypeFilter(tx, height, msgTypeIds)
+		countQuery := buildSequenceQueryWithHeightAndMsgTypeFilter(tx, height, msgTypeIds, status, msg)
 		total, err = common.GetCountWithTimeout(countQuery, pagination.CountTotal)
 	}
 
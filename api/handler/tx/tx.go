@@ -24,18 +24,52 @@ import (
 // @Param pagination.count_total query bool false "Count total, default is true" default is true
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
 // @Param msgs query []string false "Message types to filter (comma-separated or multiple params)" collectionFormat(multi) example("cosmos.bank.v1beta1.MsgSend,initia.move.v1.MsgExecute")
+// @Param status query string false "Filter by execution result" Enums(success, failed)
+// @Param codespace query string false "Filter failed transactions by error codespace, not allowed with status=success"
+// @Param msg.contract query string false "Filter by the contract called by a message (bech32 or hex address)"
+// @Param msg.function query string false "Filter by the function called by a message, a name, an evm selector or a move function id" example("0x1::dex::swap")
+// @Param move_function query string false "Filter by the move entry function called, <address>::<module>::<function>, or script for the scripts" example("0x1::coin::transfer")
+// @Param from_time query string false "Only include records at or after this time (RFC3339)"
+// @Param to_time query string false "Only include records at or before this time (RFC3339)"
+// @Param format query string false "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)" Enums(json, csv, ndjson)
+// @Param columns query string false "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default"
 // @Router /indexer/tx/v1/txs [get]
 func (h *TxHandler) GetTxs(c *fiber.Ctx) error {
 	msgs := common.GetMsgsQuery(c)
+	status, err := parseTxStatusFilter(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	msgFilter, err := parseTxMsgFilter(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	moveFunction, err := parseMoveFunction(c.Query("move_function"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 	pagination, err := common.ParsePagination(c, common.CursorTypeSequence)
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	format, err := common.ParseExportFormat(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	// Use read-only transaction for better performance
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
+	pagination.Range, err = h.GetSequenceRange(tx, timeRange, types.CollectedTx{}.TableName())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
 	var msgTypeIds []int64
 
 	if len(msgs) > 0 {
@@ -45,7 +79,32 @@ func (h *TxHandler) GetTxs(c *fiber.Ctx) error {
 		}
 	}
 
-	query, total, err := buildEdgeQueryForGetTxs(tx, msgTypeIds, pagination)
+	var moveFunctionIds []int64
+	if moveFunction != "" {
+		moveFunctionIds, err = h.GetMoveFunctionIds([]string{moveFunction})
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		// a function never called matches no tx
+		if len(moveFunctionIds) == 0 {
+			if format != "" {
+				return ExportTxs(c, h.BaseHandler, format, pagination, EmptyQuery(&types.CollectedTx{}))
+			}
+			return c.JSON(TxsResponse{
+				Txs:        []types.Tx{},
+				Pagination: pagination.ToResponse(0, false),
+			})
+		}
+	}
+
+	if format != "" {
+		return ExportTxs(c, h.BaseHandler, format, pagination, func(tx *gorm.DB, pagination *common.Pagination) (*gorm.DB, error) {
+			query, _, err := buildEdgeQueryForGetTxs(tx, msgTypeIds, moveFunctionIds, status, msgFilter, pagination)
+			return query, err
+		})
+	}
+
+	query, total, err := buildEdgeQueryForGetTxs(tx, msgTypeIds, moveFunctionIds, status, msgFilter, pagination)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -65,9 +124,15 @@ func (h *TxHandler) GetTxs(c *fiber.Ctx) error {
 		lastRecord = txs[len(txs)-1]
 	}
 
+	prunedHeight, err := h.ListPrunedHeight(tx)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(TxsResponse{
-		Txs:        txsRes,
-		Pagination: pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		Txs:          txsRes,
+		Pagination:   pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		PrunedHeight: prunedHeight,
 	})
 }
 
@@ -85,6 +150,14 @@ func (h *TxHandler) GetTxs(c *fiber.Ctx) error {
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
 // @Param is_signer query bool false "Filter by signer accounts, default is false" default is false
 // @Param msgs query []string false "Message types to filter (comma-separated or multiple params)" collectionFormat(multi) example("cosmos.bank.v1beta1.MsgSend,initia.move.v1.MsgExecute")
+// @Param status query string false "Filter by execution result" Enums(success, failed)
+// @Param codespace query string false "Filter failed transactions by error codespace, not allowed with status=success"
+// @Param msg.contract query string false "Filter by the contract called by a message (bech32 or hex address)"
+// @Param msg.function query string false "Filter by the function called by a message, a name, an evm selector or a move function id" example("0x1::dex::swap")
+// @Param from_time query string false "Only include records at or after this time (RFC3339)"
+// @Param to_time query string false "Only include records at or before this time (RFC3339)"
+// @Param format query string false "Stream all matching records instead of a page, also selected by the Accept header (text/csv, application/x-ndjson)" Enums(json, csv, ndjson)
+// @Param columns query string false "Comma-separated columns of the export, all (csv) or the full record (ndjson) by default"
 // @Router /indexer/tx/v1/txs/by_account/{account} [get]
 func (h *TxHandler) GetTxsByAccount(c *fiber.Ctx) error {
 	account, err := common.GetAccountParam(c)
@@ -92,21 +165,45 @@ func (h *TxHandler) GetTxsByAccount(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 	msgs := common.GetMsgsQuery(c)
+	status, err := parseTxStatusFilter(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	msgFilter, err := parseTxMsgFilter(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 	isSigner := c.Query("is_signer", "false") == "true"
 	pagination, err := common.ParsePagination(c, common.CursorTypeSequence)
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	timeRange, err := common.ParseTimeRange(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	format, err := common.ParseExportFormat(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	// Use read-only transaction for better performance
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
+	pagination.Range, err = h.GetSequenceRange(tx, timeRange, types.CollectedTx{}.TableName())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
 	accountIds, err := h.GetAccountIds([]string{account})
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 	if len(accountIds) == 0 {
+		if format != "" {
+			return ExportTxs(c, h.BaseHandler, format, pagination, EmptyQuery(&types.CollectedTx{}))
+		}
 		return c.JSON(TxsResponse{
 			Txs:        []types.Tx{},
 			Pagination: pagination.ToResponse(0, false),
@@ -121,7 +218,14 @@ func (h *TxHandler) GetTxsByAccount(c *fiber.Ctx) error {
 		}
 	}
 
-	query, total, err := buildTxEdgeQuery(tx, accountIds[0], isSigner, msgTypeIds, pagination)
+	if format != "" {
+		return ExportTxs(c, h.BaseHandler, format, pagination, func(tx *gorm.DB, pagination *common.Pagination) (*gorm.DB, error) {
+			query, _, err := buildTxEdgeQuery(tx, accountIds[0], isSigner, msgTypeIds, status, msgFilter, pagination)
+			return query, err
+		})
+	}
+
+	query, total, err := buildTxEdgeQuery(tx, accountIds[0], isSigner, msgTypeIds, status, msgFilter, pagination)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -141,9 +245,15 @@ func (h *TxHandler) GetTxsByAccount(c *fiber.Ctx) error {
 		lastRecord = txs[len(txs)-1]
 	}
 
+	prunedHeight, err := h.ListPrunedHeight(tx)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(TxsResponse{
-		Txs:        txsRes,
-		Pagination: pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		Txs:          txsRes,
+		Pagination:   pagination.ToResponseWithLastRecord(total, len(txsRes) == pagination.Limit, lastRecord),
+		PrunedHeight: prunedHeight,
 	})
 }
 
@@ -160,6 +270,10 @@ func (h *TxHandler) GetTxsByAccount(c *fiber.Ctx) error {
 // @Param pagination.count_total query bool false "Count total, default is true" default is true
 // @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
 // @Param msgs query []string false "Message types to filter (comma-separated or multiple params)" collectionFormat(multi) example("cosmos.bank.v1beta1.MsgSend,initia.move.v1.MsgExecute")
+// @Param status query string false "Filter by execution result" Enums(success, failed)
+// @Param codespace query string false "Filter failed transactions by error codespace, not allowed with status=success"
+// @Param msg.contract query string false "Filter by the contract called by a message (bech32 or hex address)"
+// @Param msg.function query string false "Filter by the function called by a message, a name, an evm selector or a move function id" example("0x1::dex::swap")
 // @Router /indexer/tx/v1/txs/by_height/{height} [get]
 func (h *TxHandler) GetTxsByHeight(c *fiber.Ctx) error {
 	height, err := common.GetHeightParam(c)
@@ -167,6 +281,14 @@ func (h *TxHandler) GetTxsByHeight(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 	msgs := common.GetMsgsQuery(c)
+	status, err := parseTxStatusFilter(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	msgFilter, err := parseTxMsgFilter(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 	pagination, err := common.ParsePagination(c, common.CursorTypeSequence)
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -176,6 +298,10 @@ func (h *TxHandler) GetTxsByHeight(c *fiber.Ctx) error {
 	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
 	defer tx.Rollback()
 
+	if err := h.CheckNotPruned(tx, height, "txs"); err != nil {
+		return err
+	}
+
 	var msgTypeIds []int64
 
 	if len(msgs) > 0 {
@@ -185,7 +311,7 @@ func (h *TxHandler) GetTxsByHeight(c *fiber.Ctx) error {
 		}
 	}
 
-	query, total, err := buildEdgeQueryForGetTxsByHeight(tx, height, msgTypeIds, pagination)
+	query, total, err := buildEdgeQueryForGetTxsByHeight(tx, height, msgTypeIds, status, msgFilter, pagination)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -238,10 +364,20 @@ func (h *TxHandler) GetTxByHash(c *fiber.Ctx) error {
 	if err := dbTx.Model(&types.CollectedTx{}).
 		Where("hash = ?", hashBytes).
 		First(&tx).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get transaction", err).Error())
+		}
+		archived, err := h.FindArchivedTx(hashBytes)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, types.NewInternalError("get archived transaction", err).Error())
+		}
+		if archived == nil {
+			if err := h.CheckMissingNotPruned(dbTx, "transaction"); err != nil {
+				return err
+			}
 			return fiber.NewError(fiber.StatusNotFound, types.NewNotFoundError("transaction").Error())
 		}
-		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get transaction", err).Error())
+		tx = *archived
 	}
 
 	txRes, err := ToTxResponse(tx)
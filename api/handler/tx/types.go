@@ -20,6 +20,8 @@ type TxResponse struct {
 type TxsResponse struct {
 	Txs        []types.Tx                `json:"txs" extensions:"x-order:0"`
 	Pagination common.PaginationResponse `json:"pagination" extensions:"x-order:1"`
+	// PrunedHeight is set once the pruning mode deleted the txs up to it, which are missing from the list
+	PrunedHeight int64 `json:"pruned_height,omitempty" extensions:"x-order:2"`
 }
 
 func ToTxsResponse(ctxs []types.CollectedTx) ([]types.Tx, error) {
@@ -54,6 +56,8 @@ type EvmTxResponse struct {
 type EvmTxsResponse struct {
 	Txs        []types.EvmTx             `json:"txs" extensions:"x-order:0"`
 	Pagination common.PaginationResponse `json:"pagination" extensions:"x-order:1"`
+	// PrunedHeight is set once the pruning mode deleted the txs up to it, which are missing from the list
+	PrunedHeight int64 `json:"pruned_height,omitempty" extensions:"x-order:2"`
 }
 
 func ToEvmTxsResponse(ctxs []types.CollectedEvmTx) ([]types.EvmTx, error) {
@@ -94,6 +98,8 @@ type EvmInternalTxResponse struct {
 type EvmInternalTxsResponse struct {
 	Txs        []EvmInternalTxResponse   `json:"internal_txs" extensions:"x-order:0"`
 	Pagination common.PaginationResponse `json:"pagination" extensions:"x-order:1"`
+	// PrunedHeight is set once the pruning mode deleted the txs up to it, which are missing from the list
+	PrunedHeight int64 `json:"pruned_height,omitempty" extensions:"x-order:2"`
 }
 
 func ToEvmInternalTxsResponse(citxs []types.CollectedEvmInternalTx, accounts map[int64][]byte, txhashes map[int64][]byte) []EvmInternalTxResponse {
@@ -131,3 +137,60 @@ func ToEvmInternalTxResponse(eitx *types.CollectedEvmInternalTx, accounts map[in
 		Output:      util.BytesToHexWithPrefix(eitx.Output),
 	}
 }
+
+// Tx Failures
+type TxFailuresResponse struct {
+	FromHeight  string           `json:"from_height" extensions:"x-order:0"`
+	ToHeight    string           `json:"to_height" extensions:"x-order:1"`
+	TotalFailed string           `json:"total_failed" extensions:"x-order:2"`
+	Failures    []TxFailureStats `json:"failures" extensions:"x-order:3"`
+}
+
+type TxFailureStats struct {
+	Codespace  string `json:"codespace" extensions:"x-order:0"`
+	Code       uint32 `json:"code" extensions:"x-order:1"`
+	Count      string `json:"count" extensions:"x-order:2"`
+	LastHeight string `json:"last_height" extensions:"x-order:3"`
+	LastTxHash string `json:"last_txhash" extensions:"x-order:4"`
+}
+
+type MoveFunctionStatsResponse struct {
+	FromTime string                 `json:"from_time" extensions:"x-order:0"`
+	ToTime   string                 `json:"to_time" extensions:"x-order:1"`
+	Days     []MoveFunctionDayStats `json:"days" extensions:"x-order:2"`
+}
+
+type MoveFunctionDayStats struct {
+	Date      string              `json:"date" extensions:"x-order:0"`
+	Functions []MoveFunctionCount `json:"functions" extensions:"x-order:1"`
+}
+
+type MoveFunctionCount struct {
+	Function string `json:"function" extensions:"x-order:0"`
+	Count    string `json:"count" extensions:"x-order:1"`
+}
+
+// Batch
+type TxHashesRequest struct {
+	TxHashes []string `json:"tx_hashes"`
+}
+
+type TxBatchEntry struct {
+	TxHash string    `json:"tx_hash" extensions:"x-order:0"`
+	Found  bool      `json:"found" extensions:"x-order:1"`
+	Tx     *types.Tx `json:"tx" extensions:"x-order:2"`
+}
+
+type TxsBatchResponse struct {
+	Txs []TxBatchEntry `json:"txs"`
+}
+
+type EvmTxBatchEntry struct {
+	TxHash string       `json:"tx_hash" extensions:"x-order:0"`
+	Found  bool         `json:"found" extensions:"x-order:1"`
+	Tx     *types.EvmTx `json:"tx" extensions:"x-order:2"`
+}
+
+type EvmTxsBatchResponse struct {
+	Txs []EvmTxBatchEntry `json:"txs"`
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+	"github.com/initia-labs/rollytics/util/querier"
+)
+
+// HeaderAdminToken carries the admin token of the subscription management API
+const HeaderAdminToken = "X-Admin-Token"
+
+type WebhookHandler struct {
+	*common.BaseHandler
+	cfg     *config.Config
+	querier *querier.Querier
+}
+
+var _ common.HandlerRegistrar = (*WebhookHandler)(nil)
+
+func NewWebhookHandler(base *common.BaseHandler, cfg *config.Config) *WebhookHandler {
+	return &WebhookHandler{
+		BaseHandler: base,
+		cfg:         cfg,
+		querier:     querier.NewQuerier(cfg.GetChainConfig()),
+	}
+}
+
+// Register adds the subscription management routes when webhooks are enabled
+func (h *WebhookHandler) Register(router fiber.Router) {
+	if !h.cfg.WebhookEnabled() {
+		return
+	}
+
+	subscriptions := router.Group("indexer/webhook/v1/subscriptions", h.requireAdminToken)
+	subscriptions.Post("/", h.CreateSubscription)
+	subscriptions.Get("/", h.GetSubscriptions)
+	subscriptions.Get("/:id", h.GetSubscription)
+	subscriptions.Delete("/:id", h.DeleteSubscription)
+	subscriptions.Get("/:id/dead_letters", h.GetDeadLetters)
+}
+
+func (h *WebhookHandler) requireAdminToken(c *fiber.Ctx) error {
+	token := c.Get(HeaderAdminToken)
+	expected := h.cfg.GetWebhookConfig().AdminToken
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid admin token")
+	}
+	return c.Next()
+}
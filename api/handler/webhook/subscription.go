@@ -0,0 +1,275 @@
+package webhook
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+	"github.com/initia-labs/rollytics/util/webhook"
+)
+
+// CreateSubscription handles POST /indexer/webhook/v1/subscriptions
+// @Summary Create a webhook subscription
+// @Description Subscribe a URL to the txs of an account, the mints of a collection or the top holders of a denom. The HMAC secret signing the payloads is only returned here
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Webhook admin token"
+// @Param request body CreateSubscriptionRequest true "Subscription"
+// @Success 200 {object} CreateSubscriptionResponse
+// @Router /indexer/webhook/v1/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(c *fiber.Ctx) error {
+	var req CreateSubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, types.NewBadRequestError("invalid request body: "+err.Error()).Error())
+	}
+	if err := webhook.ValidateUrl(c.Context(), req.Url, h.cfg.GetWebhookConfig().AllowPrivateUrls); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	target, err := webhook.NormalizeTarget(h.GetVmType(), req.Event, req.Target)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if req.Event == webhook.EventRichListTop && h.GetVmType() == types.EVM {
+		if target, err = h.querier.GetEvmContractByDenom(c.Context(), target); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	sub := types.CollectedWebhookSubscription{
+		Event:     req.Event,
+		Target:    target,
+		Url:       req.Url,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := h.GetDatabase().Create(&sub).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("create webhook subscription", err).Error())
+	}
+
+	return c.JSON(CreateSubscriptionResponse{
+		Subscription: ToSubscriptionResponse(sub),
+		Secret:       secret,
+	})
+}
+
+// GetSubscriptions handles GET /indexer/webhook/v1/subscriptions
+// @Summary Get webhook subscriptions
+// @Description Get the webhook subscriptions with their delivery status
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Webhook admin token"
+// @Param event query string false "Event of the subscriptions" Enums(account_tx, collection_mint, rich_list_top)
+// @Param pagination.offset query int false "Pagination offset"
+// @Param pagination.limit query int false "Pagination limit, default is 100" default is 100
+// @Param pagination.count_total query bool false "Count total, default is true" default is true
+// @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
+// @Success 200 {object} SubscriptionsResponse
+// @Router /indexer/webhook/v1/subscriptions [get]
+func (h *WebhookHandler) GetSubscriptions(c *fiber.Ctx) error {
+	pagination, err := common.ParsePagination(c, common.CursorTypeOffset)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
+	defer tx.Rollback()
+
+	query := tx.Model(&types.CollectedWebhookSubscription{})
+	if event := c.Query("event"); event != "" {
+		if !webhook.IsEvent(event) {
+			return fiber.NewError(fiber.StatusBadRequest, types.NewInvalidValueError("event", event, "must be account_tx, collection_mint or rich_list_top").Error())
+		}
+		query = query.Where("event = ?", event)
+	}
+
+	var total int64
+	if pagination.CountTotal {
+		if err := query.Count(&total).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("count webhook subscriptions", err).Error())
+		}
+	}
+
+	var subs []types.CollectedWebhookSubscription
+	if err := query.
+		Order(pagination.OrderBy("id")).
+		Limit(pagination.Limit).
+		Offset(pagination.Offset).
+		Find(&subs).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get webhook subscriptions", err).Error())
+	}
+
+	subsRes := make([]Subscription, 0, len(subs))
+	for _, sub := range subs {
+		subsRes = append(subsRes, ToSubscriptionResponse(sub))
+	}
+
+	return c.JSON(SubscriptionsResponse{
+		Subscriptions: subsRes,
+		Pagination:    pagination.ToResponse(total, len(subs) == pagination.Limit),
+	})
+}
+
+// GetSubscription handles GET /indexer/webhook/v1/subscriptions/:id
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription with its delivery status
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Webhook admin token"
+// @Param id path int true "Subscription id"
+// @Success 200 {object} SubscriptionResponse
+// @Router /indexer/webhook/v1/subscriptions/{id} [get]
+func (h *WebhookHandler) GetSubscription(c *fiber.Ctx) error {
+	id, err := getIdParam(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
+	defer tx.Rollback()
+
+	sub, err := getSubscription(tx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(SubscriptionResponse{Subscription: ToSubscriptionResponse(*sub)})
+}
+
+// DeleteSubscription handles DELETE /indexer/webhook/v1/subscriptions/:id
+// @Summary Delete a webhook subscription
+// @Description Delete a webhook subscription along with its dead letters and pending retries
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Webhook admin token"
+// @Param id path int true "Subscription id"
+// @Success 200 {object} SubscriptionResponse
+// @Router /indexer/webhook/v1/subscriptions/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *fiber.Ctx) error {
+	id, err := getIdParam(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var sub *types.CollectedWebhookSubscription
+	if err := h.GetDatabase().Transaction(func(tx *gorm.DB) error {
+		if sub, err = getSubscription(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Where("subscription_id = ?", id).Delete(&types.CollectedWebhookDeadLetter{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("subscription_id = ?", id).Delete(&types.CollectedWebhookRetry{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&types.CollectedWebhookSubscription{}, id).Error
+	}); err != nil {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			return fiberErr
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("delete webhook subscription", err).Error())
+	}
+
+	return c.JSON(SubscriptionResponse{Subscription: ToSubscriptionResponse(*sub)})
+}
+
+// GetDeadLetters handles GET /indexer/webhook/v1/subscriptions/:id/dead_letters
+// @Summary Get the dead letters of a webhook subscription
+// @Description Get the notifications of a subscription which failed every delivery attempt
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Webhook admin token"
+// @Param id path int true "Subscription id"
+// @Param pagination.offset query int false "Pagination offset"
+// @Param pagination.limit query int false "Pagination limit, default is 100" default is 100
+// @Param pagination.count_total query bool false "Count total, default is true" default is true
+// @Param pagination.reverse query bool false "Reverse order default is true if set to true, the results will be ordered in descending order"
+// @Success 200 {object} DeadLettersResponse
+// @Router /indexer/webhook/v1/subscriptions/{id}/dead_letters [get]
+func (h *WebhookHandler) GetDeadLetters(c *fiber.Ctx) error {
+	id, err := getIdParam(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	pagination, err := common.ParsePagination(c, common.CursorTypeOffset)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	tx := h.GetDatabase().Begin(&sql.TxOptions{ReadOnly: true})
+	defer tx.Rollback()
+
+	if _, err := getSubscription(tx, id); err != nil {
+		return err
+	}
+
+	query := tx.Model(&types.CollectedWebhookDeadLetter{}).Where("subscription_id = ?", id)
+	var total int64
+	if pagination.CountTotal {
+		if err := query.Count(&total).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("count webhook dead letters", err).Error())
+		}
+	}
+
+	var letters []types.CollectedWebhookDeadLetter
+	if err := query.
+		Order(pagination.OrderBy("id")).
+		Limit(pagination.Limit).
+		Offset(pagination.Offset).
+		Find(&letters).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get webhook dead letters", err).Error())
+	}
+
+	lettersRes := make([]DeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		lettersRes = append(lettersRes, ToDeadLetterResponse(letter))
+	}
+
+	return c.JSON(DeadLettersResponse{
+		DeadLetters: lettersRes,
+		Pagination:  pagination.ToResponse(total, len(letters) == pagination.Limit),
+	})
+}
+
+func getIdParam(c *fiber.Ctx) (int64, error) {
+	value, err := common.GetParams(c, "id")
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 1 {
+		return 0, types.NewInvalidValueError("id", value, "must be a positive integer")
+	}
+	return id, nil
+}
+
+func getSubscription(tx *gorm.DB, id int64) (*types.CollectedWebhookSubscription, error) {
+	var sub types.CollectedWebhookSubscription
+	if err := tx.Where("id = ?", id).First(&sub).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, types.NewNotFoundError("webhook subscription").Error())
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get webhook subscription", err).Error())
+	}
+	return &sub, nil
+}
+
+func formatInt(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
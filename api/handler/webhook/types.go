@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+type CreateSubscriptionRequest struct {
+	Event  string `json:"event" enums:"account_tx,collection_mint,rich_list_top"`
+	Target string `json:"target"`
+	Url    string `json:"url"`
+}
+
+// CreateSubscriptionResponse holds the HMAC secret of the subscription, which is only returned once
+type CreateSubscriptionResponse struct {
+	Subscription Subscription `json:"subscription"`
+	Secret       string       `json:"secret"`
+}
+
+type SubscriptionResponse struct {
+	Subscription Subscription `json:"subscription"`
+}
+
+type SubscriptionsResponse struct {
+	Subscriptions []Subscription            `json:"subscriptions"`
+	Pagination    common.PaginationResponse `json:"pagination"`
+}
+
+type Subscription struct {
+	Id        string         `json:"id"`
+	Event     string         `json:"event"`
+	Target    string         `json:"target"`
+	Url       string         `json:"url"`
+	CreatedAt string         `json:"created_at"`
+	Delivery  DeliveryStatus `json:"delivery"`
+}
+
+type DeliveryStatus struct {
+	Delivered      string `json:"delivered"`
+	Failed         string `json:"failed"`
+	LastDeliveryAt string `json:"last_delivery_at,omitempty"`
+	LastStatusCode int    `json:"last_status_code,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+}
+
+type DeadLettersResponse struct {
+	DeadLetters []DeadLetter              `json:"dead_letters"`
+	Pagination  common.PaginationResponse `json:"pagination"`
+}
+
+type DeadLetter struct {
+	Id         string          `json:"id"`
+	DeliveryId string          `json:"delivery_id"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+	Attempts   int             `json:"attempts"`
+	StatusCode int             `json:"status_code,omitempty"`
+	Error      string          `json:"error"`
+	CreatedAt  string          `json:"created_at"`
+}
+
+func ToSubscriptionResponse(sub types.CollectedWebhookSubscription) Subscription {
+	res := Subscription{
+		Id:        formatInt(sub.Id),
+		Event:     sub.Event,
+		Target:    sub.Target,
+		Url:       sub.Url,
+		CreatedAt: sub.CreatedAt.Format(time.RFC3339),
+		Delivery: DeliveryStatus{
+			Delivered:      formatInt(sub.DeliveredCount),
+			Failed:         formatInt(sub.FailedCount),
+			LastStatusCode: sub.LastStatusCode,
+			LastError:      sub.LastError,
+		},
+	}
+	if sub.LastDeliveryAt != nil {
+		res.Delivery.LastDeliveryAt = sub.LastDeliveryAt.Format(time.RFC3339)
+	}
+	return res
+}
+
+func ToDeadLetterResponse(letter types.CollectedWebhookDeadLetter) DeadLetter {
+	return DeadLetter{
+		Id:         formatInt(letter.Id),
+		DeliveryId: letter.DeliveryId,
+		Payload:    letter.Payload,
+		Attempts:   letter.Attempts,
+		StatusCode: letter.StatusCode,
+		Error:      letter.Error,
+		CreatedAt:  letter.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Tier is the token bucket configuration of a client: Rate tokens are refilled per second, up to Burst
+type Tier struct {
+	Rate  float64
+	Burst int
+}
+
+type tokenBucket struct {
+	mtx    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(tier Tier, now time.Time) *tokenBucket {
+	return &tokenBucket{tokens: float64(tier.Burst), last: now}
+}
+
+// take consumes cost tokens when available. Otherwise nothing is consumed and it returns
+// how long the client has to wait until enough tokens are refilled.
+// A cost above the burst is capped to the burst, so that every request can eventually pass.
+func (b *tokenBucket) take(tier Tier, cost int, now time.Time) (ok bool, remaining int, retryAfter time.Duration) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	burst := float64(tier.Burst)
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*tier.Rate)
+		b.last = now
+	}
+
+	need := math.Min(float64(cost), burst)
+	if b.tokens >= need {
+		b.tokens -= need
+		return true, int(b.tokens), 0
+	}
+
+	wait := (need - b.tokens) / tier.Rate
+	return false, int(b.tokens), time.Duration(wait * float64(time.Second))
+}
@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// APIKey is a known API key and the tier its requests are limited to
+type APIKey struct {
+	Name string
+	Tier Tier
+}
+
+// fileKey is an entry of the static API keys file. A zero rate or burst falls back to the default key tier.
+type fileKey struct {
+	Name  string  `json:"name"`
+	Key   string  `json:"key"`
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// KeyStore resolves API keys from a static file and, optionally, from the api_key table.
+// Keys are indexed by their SHA-256 hash. Table keys are reloaded in the background once
+// the refresh interval has elapsed, so lookups never wait for the database.
+type KeyStore struct {
+	defaultTier Tier
+	fileKeys    map[[sha256.Size]byte]*APIKey
+
+	db         *gorm.DB
+	refresh    time.Duration
+	keys       atomic.Pointer[map[[sha256.Size]byte]*APIKey]
+	loadedAt   atomic.Int64
+	refreshing atomic.Bool
+	mtx        sync.Mutex
+	logger     *slog.Logger
+}
+
+// NewKeyStore loads the static keys file, when given, and the api_key table, when db is not nil
+func NewKeyStore(path string, db *gorm.DB, refresh time.Duration, defaultTier Tier, logger *slog.Logger) (*KeyStore, error) {
+	s := &KeyStore{
+		defaultTier: defaultTier,
+		fileKeys:    make(map[[sha256.Size]byte]*APIKey),
+		db:          db,
+		refresh:     refresh,
+		logger:      logger,
+	}
+
+	if path != "" {
+		if err := s.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Lookup returns the API key matching the given raw key
+func (s *KeyStore) Lookup(key string) (*APIKey, bool) {
+	s.maybeRefresh()
+
+	apiKey, ok := (*s.keys.Load())[sha256.Sum256([]byte(key))]
+	return apiKey, ok
+}
+
+func (s *KeyStore) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read API keys file: %w", err)
+	}
+
+	var entries []fileKey
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse API keys file: %w", err)
+	}
+
+	for idx, entry := range entries {
+		if entry.Name == "" || entry.Key == "" {
+			return fmt.Errorf("API keys file entry %d must have a name and a key", idx)
+		}
+		s.fileKeys[sha256.Sum256([]byte(entry.Key))] = &APIKey{
+			Name: entry.Name,
+			Tier: s.tier(entry.Rate, entry.Burst),
+		}
+	}
+
+	return nil
+}
+
+// reload rebuilds the key index from the static keys and the api_key table
+func (s *KeyStore) reload() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	keys := make(map[[sha256.Size]byte]*APIKey, len(s.fileKeys))
+	for hash, apiKey := range s.fileKeys {
+		keys[hash] = apiKey
+	}
+
+	if s.db != nil {
+		var rows []types.CollectedApiKey
+		if err := s.db.Model(&types.CollectedApiKey{}).Where("NOT revoked").Find(&rows).Error; err != nil {
+			return types.NewDatabaseError("load api keys", err)
+		}
+		for _, row := range rows {
+			if len(row.KeyHash) != sha256.Size {
+				s.logger.Warn("skipping api key with invalid hash", "name", row.Name)
+				continue
+			}
+			keys[[sha256.Size]byte(row.KeyHash)] = &APIKey{
+				Name: row.Name,
+				Tier: s.tier(row.Rate, int(row.Burst)),
+			}
+		}
+	}
+
+	s.keys.Store(&keys)
+	s.loadedAt.Store(time.Now().UnixNano())
+	return nil
+}
+
+func (s *KeyStore) maybeRefresh() {
+	if s.db == nil || time.Since(time.Unix(0, s.loadedAt.Load())) < s.refresh {
+		return
+	}
+	if !s.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer s.refreshing.Store(false)
+		if err := s.reload(); err != nil {
+			// keep serving the previous keys until the next attempt
+			s.logger.Error("failed to refresh api keys", "error", err)
+			s.loadedAt.Store(time.Now().UnixNano())
+		}
+	}()
+}
+
+func (s *KeyStore) tier(rate float64, burst int) Tier {
+	tier := s.defaultTier
+	if rate > 0 {
+		tier.Rate = rate
+	}
+	if burst > 0 {
+		tier.Burst = burst
+	}
+	return tier
+}
@@ -0,0 +1,204 @@
+package ratelimit
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/cache"
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/orm"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+	AnonymousKey = "anonymous"
+
+	// maxBuckets bounds the number of tracked clients, the least recently seen ones are dropped first
+	maxBuckets = 100_000
+)
+
+var (
+	errAPIKeyRequired = errors.New("API key required")
+	errInvalidAPIKey  = errors.New("invalid API key")
+)
+
+type Limiter struct {
+	cfg       *config.RateLimitConfig
+	keys      *KeyStore
+	anonymous Tier
+	// proxies are the trusted reverse proxies, whose proxyHeader carries the client IP
+	proxies     []netip.Prefix
+	proxyHeader string
+
+	mtx     sync.Mutex
+	buckets *cache.Cache[string, *tokenBucket]
+	now     func() time.Time
+}
+
+// New creates the API key and rate limit middleware. Requests carrying an API key, in the X-API-Key header
+// or as a bearer token, are limited by the tier of the key. Other requests share the anonymous tier per client IP.
+func New(cfg *config.Config, db *orm.Database, logger *slog.Logger) (fiber.Handler, error) {
+	limiter, err := newLimiter(cfg.GetRateLimitConfig(), db, logger)
+	if err != nil {
+		return nil, err
+	}
+	limiter.proxies = parseProxies(cfg.GetTrustedProxies())
+	limiter.proxyHeader = cfg.GetProxyHeader()
+	return limiter.handle, nil
+}
+
+// parseProxies parses the validated addresses and CIDR ranges of the trusted proxies
+func parseProxies(proxies []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(proxy); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return prefixes
+}
+
+func newLimiter(cfg *config.RateLimitConfig, db *orm.Database, logger *slog.Logger) (*Limiter, error) {
+	var keysDB *gorm.DB
+	if cfg.KeysFromDB {
+		keysDB = db.DB
+	}
+
+	keys, err := NewKeyStore(
+		cfg.KeysFile,
+		keysDB,
+		cfg.RefreshInterval,
+		Tier{Rate: cfg.KeyRate, Burst: cfg.KeyBurst},
+		logger.With("component", "api-keys"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Limiter{
+		cfg:       cfg,
+		keys:      keys,
+		anonymous: Tier{Rate: cfg.AnonymousRate, Burst: cfg.AnonymousBurst},
+		buckets:   cache.New[string, *tokenBucket](maxBuckets),
+		now:       time.Now,
+	}, nil
+}
+
+func (l *Limiter) handle(c *fiber.Ctx) error {
+	httpMetrics := metrics.GetMetrics().HTTPMetrics()
+
+	name, bucketKey, tier, err := l.identify(c)
+	if err != nil {
+		httpMetrics.APIKeyRequests.WithLabelValues(name, "unauthorized").Inc()
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	}
+
+	cost := l.cost(c)
+	ok, remaining, retryAfter := l.bucket(bucketKey, tier).take(tier, cost, l.now())
+
+	c.Set("X-RateLimit-Limit", strconv.Itoa(tier.Burst))
+	c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+	if !ok {
+		httpMetrics.APIKeyRequests.WithLabelValues(name, "limited").Inc()
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds())))))
+		return fiber.NewError(fiber.StatusTooManyRequests, "rate limit exceeded")
+	}
+
+	httpMetrics.APIKeyRequests.WithLabelValues(name, "allowed").Inc()
+	httpMetrics.APIKeyCost.WithLabelValues(name).Add(float64(cost))
+	return c.Next()
+}
+
+// identify resolves the client of the request into its metrics name, bucket key and tier
+func (l *Limiter) identify(c *fiber.Ctx) (name, bucketKey string, tier Tier, err error) {
+	key := c.Get(HeaderAPIKey)
+	if key == "" {
+		if auth := c.Get(fiber.HeaderAuthorization); strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+		}
+	}
+
+	if key == "" {
+		if l.anonymous.Rate <= 0 {
+			return AnonymousKey, "", tier, errAPIKeyRequired
+		}
+		return AnonymousKey, "ip:" + l.clientIP(c), l.anonymous, nil
+	}
+
+	apiKey, ok := l.keys.Lookup(key)
+	if !ok {
+		return "invalid", "", tier, errInvalidAPIKey
+	}
+	return apiKey.Name, "key:" + apiKey.Name, apiKey.Tier, nil
+}
+
+// cost weighs the request by the work it asks for. Counting totals, which lists do unless told
+// otherwise (see common.ParsePagination), offset pagination and name searches are charged the
+// expensive cost, everything else costs a single token.
+func (l *Limiter) cost(c *fiber.Ctx) int {
+	if c.QueryBool("pagination.count_total", true) ||
+		c.QueryInt("pagination.offset", 0) > 0 ||
+		strings.Contains(c.Path(), "/by_name/") {
+		return l.cfg.ExpensiveCost
+	}
+	return 1
+}
+
+func (l *Limiter) bucket(key string, tier Tier) *tokenBucket {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	bucket, ok := l.buckets.Get(key)
+	if !ok {
+		bucket = newTokenBucket(tier, l.now())
+		l.buckets.Set(key, bucket)
+	}
+	return bucket
+}
+
+// clientIP returns the address of the client. The proxy header is only read when the peer is a trusted
+// proxy, and from the right, skipping the trusted proxies, since the entries left of the first other
+// address are supplied by the client.
+func (l *Limiter) clientIP(c *fiber.Ctx) string {
+	remote := c.Context().RemoteIP()
+	client, ok := netip.AddrFromSlice(remote)
+	if !ok || !l.trusted(client.Unmap()) {
+		return remote.String()
+	}
+
+	entries := strings.Split(c.Get(l.proxyHeader), ",")
+	for i := len(entries) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(entries[i]))
+		if err != nil {
+			break
+		}
+		client = addr
+		if !l.trusted(addr.Unmap()) {
+			break
+		}
+	}
+	return client.Unmap().String()
+}
+
+func (l *Limiter) trusted(addr netip.Addr) bool {
+	for _, proxy := range l.proxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
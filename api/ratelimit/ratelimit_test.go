@@ -0,0 +1,212 @@
+package ratelimit
+
+import (
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/metrics"
+)
+
+func init() {
+	metrics.Init("test-chain")
+}
+
+func TestTokenBucket(t *testing.T) {
+	tier := Tier{Rate: 2, Burst: 4}
+	now := time.Unix(0, 0)
+	bucket := newTokenBucket(tier, now)
+
+	ok, remaining, _ := bucket.take(tier, 3, now)
+	assert.True(t, ok)
+	assert.Equal(t, 1, remaining)
+
+	ok, _, retryAfter := bucket.take(tier, 3, now)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// refilled after waiting
+	ok, _, _ = bucket.take(tier, 3, now.Add(time.Second))
+	assert.True(t, ok)
+
+	// a cost above the burst is capped to the burst
+	ok, remaining, _ = bucket.take(tier, 10, now.Add(10*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 0, remaining)
+}
+
+func newTestLimiter(t *testing.T, cfg *config.RateLimitConfig, proxies ...string) (*fiber.App, *time.Time) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	limiter, err := newLimiter(cfg, nil, logger)
+	require.NoError(t, err)
+	limiter.proxies = parseProxies(proxies)
+	limiter.proxyHeader = config.DefaultProxyHeader
+
+	now := time.Unix(0, 0)
+	limiter.now = func() time.Time { return now }
+
+	app := fiber.New()
+	app.Use(limiter.handle)
+	app.Get("/*", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	return app, &now
+}
+
+func writeKeysFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func request(t *testing.T, app *fiber.App, target string, headers map[string]string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodGet, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderRetryAfter)
+}
+
+// cheapPath is a list request which does not count totals
+const cheapPath = "/indexer/tx/v1/txs?pagination.count_total=false"
+
+func TestLimiter(t *testing.T) {
+	cfg := &config.RateLimitConfig{
+		Enabled:        true,
+		AnonymousRate:  1,
+		AnonymousBurst: 2,
+		KeyRate:        10,
+		KeyBurst:       10,
+		ExpensiveCost:  5,
+		KeysFile:       writeKeysFile(t, `[{"name": "explorer", "key": "secret"}, {"name": "partner", "key": "other", "rate": 1, "burst": 1}]`),
+	}
+
+	t.Run("anonymous tier", func(t *testing.T) {
+		app, now := newTestLimiter(t, cfg)
+
+		for range 2 {
+			status, _ := request(t, app, cheapPath, nil)
+			assert.Equal(t, fiber.StatusOK, status)
+		}
+		status, retryAfter := request(t, app, cheapPath, nil)
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+		assert.Equal(t, "1", retryAfter)
+
+		*now = now.Add(time.Second)
+		status, _ = request(t, app, cheapPath, nil)
+		assert.Equal(t, fiber.StatusOK, status)
+	})
+
+	t.Run("api key tiers", func(t *testing.T) {
+		app, _ := newTestLimiter(t, cfg)
+
+		// default key tier through either header
+		for range 5 {
+			status, _ := request(t, app, cheapPath, map[string]string{HeaderAPIKey: "secret"})
+			assert.Equal(t, fiber.StatusOK, status)
+			status, _ = request(t, app, cheapPath, map[string]string{fiber.HeaderAuthorization: "Bearer secret"})
+			assert.Equal(t, fiber.StatusOK, status)
+		}
+		status, _ := request(t, app, cheapPath, map[string]string{HeaderAPIKey: "secret"})
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+
+		// keys have their own buckets
+		status, _ = request(t, app, cheapPath, map[string]string{HeaderAPIKey: "other"})
+		assert.Equal(t, fiber.StatusOK, status)
+		status, _ = request(t, app, cheapPath, map[string]string{HeaderAPIKey: "other"})
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		app, _ := newTestLimiter(t, cfg)
+
+		status, _ := request(t, app, cheapPath, map[string]string{HeaderAPIKey: "unknown"})
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+	})
+
+	t.Run("expensive requests", func(t *testing.T) {
+		app, _ := newTestLimiter(t, cfg)
+
+		for range 2 {
+			status, _ := request(t, app, "/indexer/tx/v1/txs?pagination.count_total=true", map[string]string{HeaderAPIKey: "secret"})
+			assert.Equal(t, fiber.StatusOK, status)
+		}
+		status, retryAfter := request(t, app, "/indexer/nft/v1/collections/by_name/foo", map[string]string{HeaderAPIKey: "secret"})
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+		assert.Equal(t, "1", retryAfter)
+
+		// lists count totals unless told otherwise
+		app, _ = newTestLimiter(t, cfg)
+		for range 2 {
+			status, _ := request(t, app, "/indexer/tx/v1/txs", map[string]string{HeaderAPIKey: "secret"})
+			assert.Equal(t, fiber.StatusOK, status)
+		}
+		status, _ = request(t, app, cheapPath, map[string]string{HeaderAPIKey: "secret"})
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+	})
+
+	t.Run("proxy header", func(t *testing.T) {
+		forwarded := func(value string) map[string]string {
+			return map[string]string{fiber.HeaderXForwardedFor: value}
+		}
+
+		// the header of an untrusted peer is ignored
+		app, _ := newTestLimiter(t, cfg)
+		for range 2 {
+			status, _ := request(t, app, cheapPath, forwarded("203.0.113.1"))
+			assert.Equal(t, fiber.StatusOK, status)
+		}
+		status, _ := request(t, app, cheapPath, forwarded("203.0.113.2"))
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+
+		// behind the trusted proxies, the clients have their own buckets
+		app, _ = newTestLimiter(t, cfg, "0.0.0.0", "10.0.0.0/8")
+		for range 2 {
+			status, _ := request(t, app, cheapPath, forwarded("203.0.113.1, 10.0.0.1"))
+			assert.Equal(t, fiber.StatusOK, status)
+		}
+		status, _ = request(t, app, cheapPath, forwarded("203.0.113.1"))
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+		status, _ = request(t, app, cheapPath, forwarded("203.0.113.2"))
+		assert.Equal(t, fiber.StatusOK, status)
+
+		// the entries the client prepends are not trusted
+		status, _ = request(t, app, cheapPath, forwarded("198.51.100.1, 203.0.113.1"))
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+	})
+
+	t.Run("anonymous access disabled", func(t *testing.T) {
+		noAnonymous := *cfg
+		noAnonymous.AnonymousRate = 0
+		app, _ := newTestLimiter(t, &noAnonymous)
+
+		status, _ := request(t, app, cheapPath, nil)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+		status, _ = request(t, app, cheapPath, map[string]string{HeaderAPIKey: "secret"})
+		assert.Equal(t, fiber.StatusOK, status)
+	})
+}
+
+func TestNewKeyStore_InvalidFile(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	_, err := NewKeyStore(writeKeysFile(t, `{"name": "x"}`), nil, time.Minute, Tier{Rate: 1, Burst: 1}, logger)
+	assert.Error(t, err)
+
+	_, err = NewKeyStore(writeKeysFile(t, `[{"name": "x"}]`), nil, time.Minute, Tier{Rate: 1, Burst: 1}, logger)
+	assert.Error(t, err)
+}
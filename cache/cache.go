@@ -25,3 +25,8 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 func (c *Cache[K, V]) Set(key K, value V) {
 	c.cache.Add(key, value)
 }
+
+// Purge removes every entry
+func (c *Cache[K, V]) Purge() {
+	c.cache.Purge()
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/api/handler/admin"
+)
+
+// adminClient calls the admin API of a running indexer
+type adminClient struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func adminCmd() *cobra.Command {
+	c := &adminClient{client: &http.Client{}}
+	cmd := &cobra.Command{
+		Use:   "admin [command]",
+		Short: "Control a running rollytics indexer",
+		Long: `
+Control a running rollytics indexer through its admin API.
+
+The indexer serves the admin API on its port (INDEXER_PORT) when INDEXER_ADMIN_TOKEN is set.
+The token is read from --token or INDEXER_ADMIN_TOKEN.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if c.token == "" {
+				c.token = os.Getenv("INDEXER_ADMIN_TOKEN")
+			}
+			if c.token == "" {
+				return errors.New("admin token is required, set --token or INDEXER_ADMIN_TOKEN")
+			}
+			return nil
+		},
+	}
+
+	port := os.Getenv("INDEXER_PORT")
+	if port == "" {
+		port = config.DefaultIndexerAPIPort
+	}
+	cmd.PersistentFlags().StringVar(&c.url, "url", "http://localhost:"+port, "URL of the indexer API")
+	cmd.PersistentFlags().StringVar(&c.token, "token", "", "Admin token of the indexer (default INDEXER_ADMIN_TOKEN)")
+
+	cmd.AddCommand(
+		c.actionCmd("status", "Show the pipeline, scraper and extension state", http.MethodGet, "/admin/status"),
+		c.actionCmd("pause", "Pause scraping, the blocks in flight are still collected", http.MethodPost, "/admin/pause"),
+		c.actionCmd("resume", "Resume scraping after a pause or a drain", http.MethodPost, "/admin/resume"),
+		c.drainCmd(),
+		c.extensionCmd(),
+	)
+
+	return cmd
+}
+
+func (c *adminClient) actionCmd(use, short, method, path string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.call(cmd, method, path, nil)
+		},
+	}
+}
+
+func (c *adminClient) drainCmd() *cobra.Command {
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "drain",
+		Short: "Pause scraping and wait until every block in flight is collected",
+		Long: `
+Pause scraping and wait until every block in flight is collected, e.g. before a deploy.
+
+The command fails when the indexer did not drain within the timeout. Scraping stays paused
+until resumed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "/admin/drain"
+			if timeout > 0 {
+				path += "?timeout=" + url.QueryEscape(timeout.String())
+				// leave the indexer time to answer after its own timeout
+				c.client.Timeout = timeout + 10*time.Second
+			}
+
+			var res admin.DrainResponse
+			if err := c.call(cmd, http.MethodPost, path, &res); err != nil {
+				return err
+			}
+			if !res.Drained {
+				return errors.New("indexer did not drain within the timeout")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Drain timeout (default INDEXER_ADMIN_DRAIN_TIMEOUT of the indexer)")
+	return cmd
+}
+
+func (c *adminClient) extensionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extension [command]",
+		Short: "Pause or resume an extension",
+	}
+	for _, action := range []string{"pause", "resume"} {
+		cmd.AddCommand(&cobra.Command{
+			Use:   action + " <name>",
+			Short: strings.ToUpper(action[:1]) + action[1:] + " the extension",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				path := "/admin/extensions/" + url.PathEscape(args[0]) + "/" + action
+				return c.call(cmd, http.MethodPost, path, nil)
+			},
+		})
+	}
+	return cmd
+}
+
+// call sends the request and prints the indented response, decoding it into out when set
+func (c *adminClient) call(cmd *cobra.Command, method, path string, out any) error {
+	req, err := http.NewRequestWithContext(cmd.Context(), method, strings.TrimSuffix(c.url, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(admin.HeaderAdminToken, c.token)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s: %s", method, path, res.Status, strings.TrimSpace(string(body)))
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), indented.String())
+
+	if out != nil {
+		return json.Unmarshal(body, out)
+	}
+	return nil
+}
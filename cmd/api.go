@@ -64,7 +64,10 @@ You can configure database, chain, logging, and server options via environment v
 			// Start DB stats collection
 			metrics.StartDBStatsUpdater(db, logger)
 
-			server := api.New(cfg, logger, db)
+			server, err := api.New(cfg, logger, db)
+			if err != nil {
+				return err
+			}
 
 			// graceful shutdown
 			sigChan := make(chan os.Signal, 1)
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/backfill"
+	"github.com/initia-labs/rollytics/log"
+	"github.com/initia-labs/rollytics/metrics"
+)
+
+// DefaultBackfillWorkers is the default number of shards indexed in parallel
+const DefaultBackfillWorkers = 4
+
+func backfillCmd() *cobra.Command {
+	var (
+		from    int64
+		to      int64
+		workers int
+	)
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Index a height range with parallel workers",
+		Long: `
+Index a height range with parallel workers, e.g. the history of a chain before starting the indexer.
+
+The range is split into one shard per worker. Each worker indexes the blocks and txs of its shard
+into staging tables of its own schema (backfill_<shard>), and the shards are merged into the public
+tables in order, assigning the final tx sequences during the merge. The range must start right after
+the last indexed block, and the indexer must be stopped until the backfill completes; it then
+resumes after the range.
+
+An interrupted backfill resumes when run again with the same range: merged shards are skipped and
+the others continue from their last staged block.
+
+The nft tables depend on the order of the blocks, so each shard replays the nft collect over its
+blocks in order while it is merged.
+
+You can configure database, chain, and logging options via environment variables.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetConfig()
+			if err != nil {
+				return err
+			}
+
+			logger := log.NewLogger(cfg)
+			initializeUtilities(cfg)
+			metrics.Init(cfg.GetChainId())
+
+			db, err := initializeDatabase(cfg, logger)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			if err := handleMigrations(ctx, db, logger); err != nil {
+				return err
+			}
+
+			b, err := backfill.New(cfg, logger, db, from, to, workers)
+			if err != nil {
+				return err
+			}
+			return b.Run(ctx)
+		},
+	}
+
+	cmd.Flags().Int64Var(&from, "from", 0, "First height of the range")
+	cmd.Flags().Int64Var(&to, "to", 0, "Last height of the range")
+	cmd.Flags().IntVar(&workers, "workers", DefaultBackfillWorkers, "Number of shards indexed in parallel")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
@@ -74,14 +74,18 @@ func runIndexer(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	indexerAPI := initializeAPIServer(cfg, logger, db)
+	idxer, err := indexer.New(cfg, logger, db)
+	if err != nil {
+		return err
+	}
+
+	indexerAPI := initializeAPIServer(cfg, logger, db, idxer)
 
 	setupGracefulShutdown(ctx, cancel, logger, indexerAPI, metricsServer)
 
 	metrics.StartDBStatsUpdater(db, logger)
 	defer metrics.StopDBStatsUpdater()
 
-	idxer := indexer.New(cfg, logger, db)
 	return idxer.Run(ctx)
 }
 
@@ -163,8 +167,8 @@ func handleMigrations(ctx context.Context, db *orm.Database, logger *slog.Logger
 	return nil
 }
 
-func initializeAPIServer(cfg *config.Config, logger *slog.Logger, db *orm.Database) *indexerapi.Api {
-	indexerAPI := indexerapi.New(cfg, logger, db)
+func initializeAPIServer(cfg *config.Config, logger *slog.Logger, db *orm.Database, idxer *indexer.Indexer) *indexerapi.Api {
+	indexerAPI := indexerapi.New(cfg, logger, db, idxer)
 
 	go func() {
 		if err := indexerAPI.Start(); err != nil {
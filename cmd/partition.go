@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/log"
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/orm/partition"
+)
+
+func partitionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "partition [table...]",
+		Short: "Convert tx and edge tables to range partitions by sequence",
+		Long: `
+Convert tx and edge tables to range partitions by sequence.
+
+The existing rows are kept as the <table>_legacy partition. The conversion validates their
+sequence bound and builds the new primary key online, then swaps the tables in a short
+metadata-only transaction, so the indexer and the API can keep running.
+
+Without arguments, every partitionable table is converted: ` + tableNames() + `.
+Afterwards the indexer creates partitions ahead of the head (PARTITION_SIZE, PARTITION_AHEAD).
+
+You can configure database options via environment variables.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.GetConfig()
+			if err != nil {
+				return err
+			}
+
+			tables := partition.Tables
+			if len(args) > 0 {
+				tables = nil
+				for _, name := range args {
+					table, ok := partition.LookupTable(name)
+					if !ok {
+						return fmt.Errorf("table %s cannot be partitioned, expected one of: %s", name, tableNames())
+					}
+					tables = append(tables, table)
+				}
+			}
+
+			logger := log.NewLogger(cfg)
+			metrics.Init(cfg.GetChainId())
+
+			db, err := initializeDatabase(cfg, logger)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			partitionCfg := cfg.GetPartitionConfig()
+			for _, table := range tables {
+				if err := partition.Convert(ctx, db.DB, table, partitionCfg.Size, partitionCfg.Ahead, logger); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func tableNames() string {
+	var names string
+	for i, table := range partition.Tables {
+		if i > 0 {
+			names += ", "
+		}
+		names += table.Name
+	}
+	return names
+}
@@ -45,6 +45,9 @@ comprehensive data collection and API services for blockchain networks.`,
 	cmd.AddCommand(indexerCmd())
 	cmd.AddCommand(apiCmd())
 	cmd.AddCommand(migrateCmd())
+	cmd.AddCommand(partitionCmd())
+	cmd.AddCommand(adminCmd())
+	cmd.AddCommand(backfillCmd())
 
 	return cmd
 }
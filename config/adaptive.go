@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// AdaptiveConfig configures the AIMD controller of the indexer, which adjusts the fast sync
+// concurrency from the scrape latency and the RPC errors, and the inflight window from the collect
+// duration. Both grow additively while healthy and are halved on congestion, within their bounds;
+// MAX_CONCURRENT_REQUESTS is the upper bound of the concurrency.
+// Env vars:
+// - ADAPTIVE_CONCURRENCY_ENABLED (bool)
+// - ADAPTIVE_MIN_CONCURRENCY (int; lower bound of the concurrent scrapes)
+// - ADAPTIVE_MIN_INFLIGHT_BLOCKS (int; lower bound of the inflight window)
+// - ADAPTIVE_MAX_INFLIGHT_BLOCKS (int; upper bound of the inflight window)
+// - ADAPTIVE_TARGET_SCRAPE_LATENCY (duration; average scrape latency above which the concurrency is halved)
+// - ADAPTIVE_TARGET_COLLECT_DURATION (duration; average collect duration above which the window is halved)
+// - ADAPTIVE_MAX_ERROR_RATE (float; share of failed scrapes or failing RPC endpoints above which the concurrency is halved)
+// - ADAPTIVE_INTERVAL (duration; how often the limits are adjusted)
+type AdaptiveConfig struct {
+	Enabled               bool          `json:"enabled"`
+	MinConcurrency        int           `json:"min_concurrency"`
+	MinInflightBlocks     int           `json:"min_inflight_blocks"`
+	MaxInflightBlocks     int           `json:"max_inflight_blocks"`
+	TargetScrapeLatency   time.Duration `json:"target_scrape_latency"`
+	TargetCollectDuration time.Duration `json:"target_collect_duration"`
+	MaxErrorRate          float64       `json:"max_error_rate"`
+	Interval              time.Duration `json:"interval"`
+}
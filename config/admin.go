@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// AdminConfig configures the admin API of the indexer, served on the indexer port to pause, resume
+// and drain the pipeline and its extensions. The routes are only served when a token is set.
+// Env vars:
+// - INDEXER_ADMIN_TOKEN (string; enables the admin API, sent as the X-Admin-Token header)
+// - INDEXER_ADMIN_DRAIN_TIMEOUT (duration; default wait of a drain for the in-flight blocks)
+type AdminConfig struct {
+	Token        string        `json:"-"`
+	DrainTimeout time.Duration `json:"drain_timeout"`
+}
@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+const (
+	APICacheStorageMemory = "memory"
+	APICacheStorageRedis  = "redis"
+)
+
+// APICacheConfig configures the response cache of the API.
+// Cached list responses are keyed by the latest indexed block height, so they are invalidated by new blocks
+// rather than by fixed TTLs. A Redis storage lets the replicas behind a load balancer share one cache.
+// Env vars:
+// - API_CACHE_STORAGE (memory|redis)
+// - API_CACHE_REDIS_ADDR (host:port)
+// - API_CACHE_REDIS_PASSWORD
+// - API_CACHE_REDIS_DB (int)
+// - API_CACHE_MAX_AGE (duration; upper bound of entries invalidated by block height)
+// - API_CACHE_HEIGHT_POLL_INTERVAL (duration; how often the latest block height is read)
+type APICacheConfig struct {
+	Storage            string        `json:"storage"`
+	RedisAddr          string        `json:"redis_addr"`
+	RedisPassword      string        `json:"-"`
+	RedisDB            int           `json:"redis_db"`
+	MaxAge             time.Duration `json:"max_age"`
+	HeightPollInterval time.Duration `json:"height_poll_interval"`
+}
@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+// ArchiveConfig configures the cold archive, which exports old height ranges of the block and tx
+// tables to Parquet files under Path. The archive is disabled unless Path is set.
+// Env vars:
+// - ARCHIVE_PATH (directory on a local or mounted volume)
+// - ARCHIVE_RANGE_SIZE (int; heights per archive file)
+// - ARCHIVE_KEEP_BLOCKS (int; latest blocks never archived)
+// - ARCHIVE_DROP (bool; delete the archived rows from the database)
+// - ARCHIVE_INTERVAL (duration)
+type ArchiveConfig struct {
+	Path       string        `json:"path"`
+	RangeSize  int64         `json:"range_size"`
+	KeepBlocks int64         `json:"keep_blocks"`
+	Drop       bool          `json:"drop"`
+	Interval   time.Duration `json:"interval"`
+}
+
+// Enabled reports whether an archive path is configured
+func (c ArchiveConfig) Enabled() bool {
+	return c.Path != ""
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
@@ -32,6 +33,7 @@ const (
 	DefaultIndexerAPIPort = "8081"
 	DefaultMetricsPort    = "9090"
 	DefaultRecvBufferSize = "16384" // 16k
+	DefaultProxyHeader    = "X-Forwarded-For"
 	MinPortNumber         = 1
 	MaxPortNumber         = 65535
 
@@ -40,6 +42,10 @@ const (
 	DefaultDBIdleConns = 2 // GORM default
 	DefaultDBBatchSize = 100
 
+	// Read replica settings
+	DefaultDBReadMaxLag           = 10
+	DefaultDBReadLagCheckInterval = time.Second
+
 	// Cache settings
 	DefaultCacheSize = 1000
 	DefaultCacheTTL  = 10 * time.Minute
@@ -49,6 +55,7 @@ const (
 	DefaultNftCacheSize              = 40960
 	DefaultMsgTypeCacheSize          = 1024
 	DefaultTypeTagCacheSize          = 1024
+	DefaultMoveFunctionCacheSize     = 1024
 	DefaultMoveDenomCacheSize        = 10240
 	DefaultEvmTxHashCacheSize        = 40960
 	DefaultEvmDenomContractCacheSize = 10240
@@ -61,12 +68,81 @@ const (
 	// Concurrent request settings
 	DefaultMaxConcurrentRequests = 50
 	MaxAllowedConcurrentRequests = 1000
+	DefaultCollectBatchSize      = 1
+	MaxAllowedCollectBatchSize   = 1000
 
 	// Internal TX settings
 	DefaultInternalTxPollInterval = 5 * time.Second
 	DefaultInternalTxBatchSize    = 10
 	DefaultInternalTxQueueSize    = 100 // Default queue size
 
+	// Export settings
+	DefaultExportMaxRows = 100_000
+
+	// Rate limit settings
+	DefaultRateLimitAnonymousRate  = 5.0
+	DefaultRateLimitAnonymousBurst = 20
+	DefaultRateLimitKeyRate        = 50.0
+	DefaultRateLimitKeyBurst       = 200
+	DefaultRateLimitExpensiveCost  = 5
+	DefaultAPIKeysRefreshInterval  = time.Minute
+
+	// API cache settings
+	DefaultAPICacheMaxAge             = time.Minute
+	DefaultAPICacheHeightPollInterval = 250 * time.Millisecond
+
+	// Partition settings
+	DefaultPartitionSize          = 10_000_000
+	DefaultPartitionAhead         = 2
+	DefaultPartitionCheckInterval = time.Minute
+
+	// Prune settings
+	DefaultPruneBatchSize = 100
+	DefaultPruneInterval  = time.Minute
+
+	// Archive settings
+	DefaultArchiveRangeSize  = 10_000
+	DefaultArchiveKeepBlocks = 1_000_000
+	DefaultArchiveInterval   = time.Minute
+
+	// Leader election settings
+	DefaultLeaderCheckInterval = 5 * time.Second
+
+	// Event sink settings
+	DefaultEventSinkTopicPrefix  = "rollytics"
+	DefaultEventSinkBatchSize    = 500
+	DefaultEventSinkPollInterval = time.Second
+
+	// Extension settings
+	DefaultExtensionDependencyPollInterval = 5 * time.Second
+	DefaultExtensionRestartInitialBackoff  = time.Second
+	DefaultExtensionRestartMaxBackoff      = 5 * time.Minute
+	DefaultExtensionMaxRestarts            = 10
+
+	// Adaptive concurrency settings
+	DefaultAdaptiveMinConcurrency        = 1
+	DefaultAdaptiveMinInflightBlocks     = 20
+	DefaultAdaptiveMaxInflightBlocks     = 400
+	DefaultAdaptiveTargetScrapeLatency   = 2 * time.Second
+	DefaultAdaptiveTargetCollectDuration = time.Second
+	DefaultAdaptiveMaxErrorRate          = 0.05
+	DefaultAdaptiveInterval              = 5 * time.Second
+
+	// Admin settings
+	DefaultAdminDrainTimeout = time.Minute
+
+	// Initial sync settings
+	DefaultInitialSyncThreshold = 100_000
+
+	// Webhook settings
+	DefaultWebhookBatchSize      = 100
+	DefaultWebhookPollInterval   = time.Second
+	DefaultWebhookTimeout        = 10 * time.Second
+	DefaultWebhookMaxAttempts    = 5
+	DefaultWebhookInitialBackoff = time.Second
+	DefaultWebhookMaxBackoff     = time.Minute
+	DefaultWebhookConcurrency    = 8
+
 	// Metrics settings
 	DefaultMetricsPath = "/metrics"
 
@@ -108,6 +184,7 @@ type CacheConfig struct {
 	NftCacheSize              int `json:"nft_cache_size"`
 	MsgTypeCacheSize          int `json:"msg_type_cache_size"`
 	TypeTagCacheSize          int `json:"type_tag_cache_size"`
+	MoveFunctionCacheSize     int `json:"move_function_cache_size"`
 	MoveDenomCacheSize        int `json:"move_denom_cache_size"`
 	EvmTxHashCacheSize        int `json:"evm_tx_hash_cache_size"`
 	EvmDenomContractCacheSize int `json:"evm_denom_contract_cache_size"`
@@ -131,6 +208,8 @@ func SetBuildInfo(v, commit string) {
 type Config struct {
 	listenPort             string
 	recvBufSize            uint
+	trustedProxies         []string // for api only
+	proxyHeader            string   // for api only
 	indexerListenPort      string
 	dbConfig               *dbconfig.Config
 	chainConfig            *ChainConfig
@@ -139,6 +218,7 @@ type Config struct {
 	coolingDuration        time.Duration // for indexer only
 	queryTimeout           time.Duration // for indexer only
 	maxConcurrentRequests  int           // for indexer only
+	collectBatchSize       int           // for indexer only
 	cacheSize              int
 	cacheTTL               time.Duration // for api only
 	pollingInterval        time.Duration // for api only
@@ -146,6 +226,19 @@ type Config struct {
 	richListConfig         *RichListConfig
 	evmRetCleanupConfig    *EvmRetCleanupConfig
 	txAccountCleanupConfig *TxAccountCleanupConfig
+	exportConfig           *ExportConfig
+	rateLimitConfig        *RateLimitConfig
+	apiCacheConfig         *APICacheConfig
+	partitionConfig        *PartitionConfig
+	pruneConfig            *PruneConfig
+	archiveConfig          *ArchiveConfig
+	leaderConfig           *LeaderConfig
+	eventSinkConfig        *EventSinkConfig
+	webhookConfig          *WebhookConfig
+	extensionsConfig       *ExtensionsConfig
+	adminConfig            *AdminConfig
+	adaptiveConfig         *AdaptiveConfig
+	initialSyncConfig      *InitialSyncConfig
 	metricsConfig          *MetricsConfig
 	cacheConfig            *CacheConfig
 	sentryConfig           *SentryConfig
@@ -159,16 +252,22 @@ type Config struct {
 func setDefaults() {
 	viper.SetDefault("PORT", DefaultAPIPort)
 	viper.SetDefault("RECV_BUFFER_SIZE", DefaultRecvBufferSize)
+	viper.SetDefault("TRUSTED_PROXIES", "")
+	viper.SetDefault("PROXY_HEADER", DefaultProxyHeader)
 	viper.SetDefault("INDEXER_PORT", DefaultIndexerAPIPort)
 	viper.SetDefault("DB_AUTO_MIGRATE", false)
 	viper.SetDefault("DB_BATCH_SIZE", DefaultDBBatchSize)
 	viper.SetDefault("DB_MAX_CONNS", DefaultDBMaxConns)
 	viper.SetDefault("DB_IDLE_CONNS", DefaultDBIdleConns)
 	viper.SetDefault("DB_MIGRATION_DIR", "orm/migrations")
+	viper.SetDefault("DB_READ_DSN", "")
+	viper.SetDefault("DB_READ_MAX_LAG", DefaultDBReadMaxLag)
+	viper.SetDefault("DB_READ_LAG_CHECK_INTERVAL", DefaultDBReadLagCheckInterval)
 	viper.SetDefault("ACCOUNT_ADDRESS_PREFIX", DefaultAccountAddressPrefix)
 	viper.SetDefault("COOLING_DURATION", DefaultCoolingDuration)
 	viper.SetDefault("QUERY_TIMEOUT", DefaultQueryTimeout)
 	viper.SetDefault("MAX_CONCURRENT_REQUESTS", DefaultMaxConcurrentRequests)
+	viper.SetDefault("COLLECT_BATCH_SIZE", DefaultCollectBatchSize)
 	viper.SetDefault("LOG_LEVEL", "warn")
 	viper.SetDefault("LOG_FORMAT", "json")
 	viper.SetDefault("CACHE_SIZE", DefaultCacheSize)
@@ -179,6 +278,69 @@ func setDefaults() {
 	viper.SetDefault("INTERNAL_TX_QUEUE_SIZE", DefaultInternalTxQueueSize)
 	viper.SetDefault("RICH_LIST", true)
 	viper.SetDefault("TX_ACCOUNT_CLEANUP", true)
+	viper.SetDefault("EXPORT_MAX_ROWS", DefaultExportMaxRows)
+
+	viper.SetDefault("RATE_LIMIT_ENABLED", false)
+	viper.SetDefault("RATE_LIMIT_ANONYMOUS_RATE", DefaultRateLimitAnonymousRate)
+	viper.SetDefault("RATE_LIMIT_ANONYMOUS_BURST", DefaultRateLimitAnonymousBurst)
+	viper.SetDefault("RATE_LIMIT_KEY_RATE", DefaultRateLimitKeyRate)
+	viper.SetDefault("RATE_LIMIT_KEY_BURST", DefaultRateLimitKeyBurst)
+	viper.SetDefault("RATE_LIMIT_EXPENSIVE_COST", DefaultRateLimitExpensiveCost)
+	viper.SetDefault("API_KEYS_FILE", "")
+	viper.SetDefault("API_KEYS_DB", false)
+	viper.SetDefault("API_KEYS_REFRESH_INTERVAL", DefaultAPIKeysRefreshInterval)
+	viper.SetDefault("API_CACHE_STORAGE", APICacheStorageMemory)
+	viper.SetDefault("API_CACHE_REDIS_ADDR", "")
+	viper.SetDefault("API_CACHE_REDIS_PASSWORD", "")
+	viper.SetDefault("API_CACHE_REDIS_DB", 0)
+	viper.SetDefault("API_CACHE_MAX_AGE", DefaultAPICacheMaxAge)
+	viper.SetDefault("API_CACHE_HEIGHT_POLL_INTERVAL", DefaultAPICacheHeightPollInterval)
+	viper.SetDefault("PARTITION_SIZE", DefaultPartitionSize)
+	viper.SetDefault("PARTITION_AHEAD", DefaultPartitionAhead)
+	viper.SetDefault("PARTITION_CHECK_INTERVAL", DefaultPartitionCheckInterval)
+	viper.SetDefault("PRUNE_KEEP_BLOCKS", 0)
+	viper.SetDefault("PRUNE_KEEP_DURATION", 0)
+	viper.SetDefault("PRUNE_BATCH_SIZE", DefaultPruneBatchSize)
+	viper.SetDefault("PRUNE_INTERVAL", DefaultPruneInterval)
+	viper.SetDefault("ARCHIVE_PATH", "")
+	viper.SetDefault("ARCHIVE_RANGE_SIZE", DefaultArchiveRangeSize)
+	viper.SetDefault("ARCHIVE_KEEP_BLOCKS", DefaultArchiveKeepBlocks)
+	viper.SetDefault("ARCHIVE_DROP", false)
+	viper.SetDefault("ARCHIVE_INTERVAL", DefaultArchiveInterval)
+	viper.SetDefault("LEADER_ELECTION_ENABLED", false)
+	viper.SetDefault("LEADER_CHECK_INTERVAL", DefaultLeaderCheckInterval)
+	viper.SetDefault("EVENT_SINK_BROKER", "")
+	viper.SetDefault("EVENT_SINK_URLS", "")
+	viper.SetDefault("EVENT_SINK_TOPIC_PREFIX", DefaultEventSinkTopicPrefix)
+	viper.SetDefault("EVENT_SINK_AUTO_CREATE_TOPICS", false)
+	viper.SetDefault("EVENT_SINK_BATCH_SIZE", DefaultEventSinkBatchSize)
+	viper.SetDefault("EVENT_SINK_POLL_INTERVAL", DefaultEventSinkPollInterval)
+	viper.SetDefault("WEBHOOK_ENABLED", false)
+	viper.SetDefault("WEBHOOK_ADMIN_TOKEN", "")
+	viper.SetDefault("EXTENSIONS", "")
+	viper.SetDefault("EXTENSION_DEPENDENCY_POLL_INTERVAL", DefaultExtensionDependencyPollInterval)
+	viper.SetDefault("EXTENSION_RESTART_INITIAL_BACKOFF", DefaultExtensionRestartInitialBackoff)
+	viper.SetDefault("EXTENSION_RESTART_MAX_BACKOFF", DefaultExtensionRestartMaxBackoff)
+	viper.SetDefault("EXTENSION_MAX_RESTARTS", DefaultExtensionMaxRestarts)
+	viper.SetDefault("ADAPTIVE_MIN_CONCURRENCY", DefaultAdaptiveMinConcurrency)
+	viper.SetDefault("ADAPTIVE_MIN_INFLIGHT_BLOCKS", DefaultAdaptiveMinInflightBlocks)
+	viper.SetDefault("ADAPTIVE_MAX_INFLIGHT_BLOCKS", DefaultAdaptiveMaxInflightBlocks)
+	viper.SetDefault("ADAPTIVE_TARGET_SCRAPE_LATENCY", DefaultAdaptiveTargetScrapeLatency)
+	viper.SetDefault("ADAPTIVE_TARGET_COLLECT_DURATION", DefaultAdaptiveTargetCollectDuration)
+	viper.SetDefault("ADAPTIVE_MAX_ERROR_RATE", DefaultAdaptiveMaxErrorRate)
+	viper.SetDefault("ADAPTIVE_INTERVAL", DefaultAdaptiveInterval)
+	viper.SetDefault("INDEXER_ADMIN_DRAIN_TIMEOUT", DefaultAdminDrainTimeout)
+	viper.SetDefault("INITIAL_SYNC_ENABLED", false)
+	viper.SetDefault("INITIAL_SYNC_THRESHOLD", DefaultInitialSyncThreshold)
+	viper.SetDefault("INITIAL_SYNC_DEFER_INDEXES", false)
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", DefaultWebhookBatchSize)
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", DefaultWebhookPollInterval)
+	viper.SetDefault("WEBHOOK_TIMEOUT", DefaultWebhookTimeout)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", DefaultWebhookMaxAttempts)
+	viper.SetDefault("WEBHOOK_INITIAL_BACKOFF", DefaultWebhookInitialBackoff)
+	viper.SetDefault("WEBHOOK_MAX_BACKOFF", DefaultWebhookMaxBackoff)
+	viper.SetDefault("WEBHOOK_CONCURRENCY", DefaultWebhookConcurrency)
+	viper.SetDefault("WEBHOOK_ALLOW_PRIVATE_URLS", false)
 	viper.SetDefault("METRICS_ENABLED", false)
 	viper.SetDefault("METRICS_PATH", DefaultMetricsPath)
 	viper.SetDefault("METRICS_PORT", DefaultMetricsPort)
@@ -203,6 +365,7 @@ func setDefaults() {
 	viper.SetDefault("NFT_CACHE_SIZE", DefaultNftCacheSize)
 	viper.SetDefault("MSG_TYPE_CACHE_SIZE", DefaultMsgTypeCacheSize)
 	viper.SetDefault("TYPE_TAG_CACHE_SIZE", DefaultTypeTagCacheSize)
+	viper.SetDefault("MOVE_FUNCTION_CACHE_SIZE", DefaultMoveFunctionCacheSize)
 	viper.SetDefault("MOVE_DENOM_CACHE_SIZE", DefaultMoveDenomCacheSize)
 	viper.SetDefault("EVM_TX_HASH_CACHE_SIZE", DefaultEvmTxHashCacheSize)
 	viper.SetDefault("EVM_DENOM_CONTRACT_CACHE_SIZE", DefaultEvmDenomContractCacheSize)
@@ -262,6 +425,10 @@ func loadConfig() (*Config, error) {
 		IdleConns:    viper.GetInt("DB_IDLE_CONNS"),
 		BatchSize:    viper.GetInt("DB_BATCH_SIZE"),
 		MigrationDir: viper.GetString("DB_MIGRATION_DIR"),
+
+		ReadDSNs:             splitAndTrim(viper.GetString("DB_READ_DSN")),
+		ReadMaxLag:           viper.GetInt64("DB_READ_MAX_LAG"),
+		ReadLagCheckInterval: viper.GetDuration("DB_READ_LAG_CHECK_INTERVAL"),
 	}
 
 	cc := &ChainConfig{
@@ -276,6 +443,8 @@ func loadConfig() (*Config, error) {
 	config := &Config{
 		listenPort:            viper.GetString("PORT"),
 		recvBufSize:           viper.GetUint("RECV_BUFFER_SIZE"),
+		trustedProxies:        splitAndTrim(viper.GetString("TRUSTED_PROXIES")),
+		proxyHeader:           viper.GetString("PROXY_HEADER"),
 		indexerListenPort:     viper.GetString("INDEXER_PORT"),
 		dbConfig:              dc,
 		chainConfig:           cc,
@@ -284,6 +453,7 @@ func loadConfig() (*Config, error) {
 		coolingDuration:       viper.GetDuration("COOLING_DURATION"),
 		queryTimeout:          viper.GetDuration("QUERY_TIMEOUT"),
 		maxConcurrentRequests: viper.GetInt("MAX_CONCURRENT_REQUESTS"),
+		collectBatchSize:      viper.GetInt("COLLECT_BATCH_SIZE"),
 		cacheSize:             viper.GetInt("CACHE_SIZE"),
 		cacheTTL:              viper.GetDuration("CACHE_TTL"),
 		pollingInterval:       viper.GetDuration("POLLING_INTERVAL"),
@@ -302,6 +472,96 @@ func loadConfig() (*Config, error) {
 		txAccountCleanupConfig: &TxAccountCleanupConfig{
 			Enabled: viper.GetBool("TX_ACCOUNT_CLEANUP"),
 		},
+		exportConfig: &ExportConfig{
+			MaxRows: viper.GetInt("EXPORT_MAX_ROWS"),
+		},
+		rateLimitConfig: &RateLimitConfig{
+			Enabled:         viper.GetBool("RATE_LIMIT_ENABLED"),
+			AnonymousRate:   viper.GetFloat64("RATE_LIMIT_ANONYMOUS_RATE"),
+			AnonymousBurst:  viper.GetInt("RATE_LIMIT_ANONYMOUS_BURST"),
+			KeyRate:         viper.GetFloat64("RATE_LIMIT_KEY_RATE"),
+			KeyBurst:        viper.GetInt("RATE_LIMIT_KEY_BURST"),
+			ExpensiveCost:   viper.GetInt("RATE_LIMIT_EXPENSIVE_COST"),
+			KeysFile:        viper.GetString("API_KEYS_FILE"),
+			KeysFromDB:      viper.GetBool("API_KEYS_DB"),
+			RefreshInterval: viper.GetDuration("API_KEYS_REFRESH_INTERVAL"),
+		},
+		apiCacheConfig: &APICacheConfig{
+			Storage:            strings.ToLower(viper.GetString("API_CACHE_STORAGE")),
+			RedisAddr:          viper.GetString("API_CACHE_REDIS_ADDR"),
+			RedisPassword:      viper.GetString("API_CACHE_REDIS_PASSWORD"),
+			RedisDB:            viper.GetInt("API_CACHE_REDIS_DB"),
+			MaxAge:             viper.GetDuration("API_CACHE_MAX_AGE"),
+			HeightPollInterval: viper.GetDuration("API_CACHE_HEIGHT_POLL_INTERVAL"),
+		},
+		partitionConfig: &PartitionConfig{
+			Size:          viper.GetInt64("PARTITION_SIZE"),
+			Ahead:         viper.GetInt("PARTITION_AHEAD"),
+			CheckInterval: viper.GetDuration("PARTITION_CHECK_INTERVAL"),
+		},
+		pruneConfig: &PruneConfig{
+			KeepBlocks:   viper.GetInt64("PRUNE_KEEP_BLOCKS"),
+			KeepDuration: viper.GetDuration("PRUNE_KEEP_DURATION"),
+			BatchSize:    viper.GetInt64("PRUNE_BATCH_SIZE"),
+			Interval:     viper.GetDuration("PRUNE_INTERVAL"),
+		},
+		archiveConfig: &ArchiveConfig{
+			Path:       viper.GetString("ARCHIVE_PATH"),
+			RangeSize:  viper.GetInt64("ARCHIVE_RANGE_SIZE"),
+			KeepBlocks: viper.GetInt64("ARCHIVE_KEEP_BLOCKS"),
+			Drop:       viper.GetBool("ARCHIVE_DROP"),
+			Interval:   viper.GetDuration("ARCHIVE_INTERVAL"),
+		},
+		leaderConfig: &LeaderConfig{
+			Enabled:       viper.GetBool("LEADER_ELECTION_ENABLED"),
+			CheckInterval: viper.GetDuration("LEADER_CHECK_INTERVAL"),
+		},
+		eventSinkConfig: &EventSinkConfig{
+			Broker:           strings.ToLower(viper.GetString("EVENT_SINK_BROKER")),
+			URLs:             splitAndTrim(viper.GetString("EVENT_SINK_URLS")),
+			TopicPrefix:      viper.GetString("EVENT_SINK_TOPIC_PREFIX"),
+			BatchSize:        viper.GetInt("EVENT_SINK_BATCH_SIZE"),
+			PollInterval:     viper.GetDuration("EVENT_SINK_POLL_INTERVAL"),
+			AutoCreateTopics: viper.GetBool("EVENT_SINK_AUTO_CREATE_TOPICS"),
+		},
+		webhookConfig: &WebhookConfig{
+			Enabled:          viper.GetBool("WEBHOOK_ENABLED"),
+			AdminToken:       viper.GetString("WEBHOOK_ADMIN_TOKEN"),
+			BatchSize:        viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			PollInterval:     viper.GetDuration("WEBHOOK_POLL_INTERVAL"),
+			Timeout:          viper.GetDuration("WEBHOOK_TIMEOUT"),
+			MaxAttempts:      viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			InitialBackoff:   viper.GetDuration("WEBHOOK_INITIAL_BACKOFF"),
+			MaxBackoff:       viper.GetDuration("WEBHOOK_MAX_BACKOFF"),
+			Concurrency:      viper.GetInt("WEBHOOK_CONCURRENCY"),
+			AllowPrivateUrls: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_URLS"),
+		},
+		extensionsConfig: &ExtensionsConfig{
+			Names:                  splitAndTrim(strings.ToLower(viper.GetString("EXTENSIONS"))),
+			DependencyPollInterval: viper.GetDuration("EXTENSION_DEPENDENCY_POLL_INTERVAL"),
+			RestartInitialBackoff:  viper.GetDuration("EXTENSION_RESTART_INITIAL_BACKOFF"),
+			RestartMaxBackoff:      viper.GetDuration("EXTENSION_RESTART_MAX_BACKOFF"),
+			MaxRestarts:            viper.GetInt("EXTENSION_MAX_RESTARTS"),
+		},
+		adaptiveConfig: &AdaptiveConfig{
+			Enabled:               viper.GetBool("ADAPTIVE_CONCURRENCY_ENABLED"),
+			MinConcurrency:        viper.GetInt("ADAPTIVE_MIN_CONCURRENCY"),
+			MinInflightBlocks:     viper.GetInt("ADAPTIVE_MIN_INFLIGHT_BLOCKS"),
+			MaxInflightBlocks:     viper.GetInt("ADAPTIVE_MAX_INFLIGHT_BLOCKS"),
+			TargetScrapeLatency:   viper.GetDuration("ADAPTIVE_TARGET_SCRAPE_LATENCY"),
+			TargetCollectDuration: viper.GetDuration("ADAPTIVE_TARGET_COLLECT_DURATION"),
+			MaxErrorRate:          viper.GetFloat64("ADAPTIVE_MAX_ERROR_RATE"),
+			Interval:              viper.GetDuration("ADAPTIVE_INTERVAL"),
+		},
+		adminConfig: &AdminConfig{
+			Token:        viper.GetString("INDEXER_ADMIN_TOKEN"),
+			DrainTimeout: viper.GetDuration("INDEXER_ADMIN_DRAIN_TIMEOUT"),
+		},
+		initialSyncConfig: &InitialSyncConfig{
+			Enabled:      viper.GetBool("INITIAL_SYNC_ENABLED"),
+			Threshold:    viper.GetInt64("INITIAL_SYNC_THRESHOLD"),
+			DeferIndexes: viper.GetBool("INITIAL_SYNC_DEFER_INDEXES"),
+		},
 		metricsConfig: &MetricsConfig{
 			Enabled: viper.GetBool("METRICS_ENABLED"),
 			Path:    viper.GetString("METRICS_PATH"),
@@ -312,6 +572,7 @@ func loadConfig() (*Config, error) {
 			NftCacheSize:              viper.GetInt("NFT_CACHE_SIZE"),
 			MsgTypeCacheSize:          viper.GetInt("MSG_TYPE_CACHE_SIZE"),
 			TypeTagCacheSize:          viper.GetInt("TYPE_TAG_CACHE_SIZE"),
+			MoveFunctionCacheSize:     viper.GetInt("MOVE_FUNCTION_CACHE_SIZE"),
 			MoveDenomCacheSize:        viper.GetInt("MOVE_DENOM_CACHE_SIZE"),
 			EvmTxHashCacheSize:        viper.GetInt("EVM_TX_HASH_CACHE_SIZE"),
 			EvmDenomContractCacheSize: viper.GetInt("EVM_DENOM_CONTRACT_CACHE_SIZE"),
@@ -387,6 +648,24 @@ func (c Config) GetRecvBufferSize() uint {
 	return c.recvBufSize
 }
 
+// GetTrustedProxies returns the addresses and CIDR ranges of the reverse proxies whose proxy header
+// carries the client IP, the header of other peers is ignored
+func (c Config) GetTrustedProxies() []string {
+	return c.trustedProxies
+}
+
+func (c Config) GetProxyHeader() string {
+	if c.proxyHeader == "" {
+		return DefaultProxyHeader
+	}
+	return c.proxyHeader
+}
+
+// SetTrustedProxies assigns the trusted proxies for testing purposes.
+func (c *Config) SetTrustedProxies(proxies []string) {
+	c.trustedProxies = proxies
+}
+
 func (c Config) GetIndexerListenPort() string {
 	return c.indexerListenPort
 }
@@ -419,6 +698,71 @@ func (c *Config) SetRichListConfig(richListCfg *RichListConfig) {
 	c.richListConfig = richListCfg
 }
 
+// SetExportConfig assigns the export config for testing purposes.
+func (c *Config) SetExportConfig(exportCfg *ExportConfig) {
+	c.exportConfig = exportCfg
+}
+
+// SetRateLimitConfig assigns the rate limit config for testing purposes.
+func (c *Config) SetRateLimitConfig(rateLimitCfg *RateLimitConfig) {
+	c.rateLimitConfig = rateLimitCfg
+}
+
+// SetAPICacheConfig assigns the API cache config for testing purposes.
+func (c *Config) SetAPICacheConfig(apiCacheCfg *APICacheConfig) {
+	c.apiCacheConfig = apiCacheCfg
+}
+
+// SetPartitionConfig assigns the partition config for testing purposes.
+func (c *Config) SetPartitionConfig(partitionCfg *PartitionConfig) {
+	c.partitionConfig = partitionCfg
+}
+
+// SetPruneConfig assigns the prune config for testing purposes.
+func (c *Config) SetPruneConfig(pruneCfg *PruneConfig) {
+	c.pruneConfig = pruneCfg
+}
+
+// SetArchiveConfig assigns the archive config for testing purposes.
+func (c *Config) SetArchiveConfig(archiveCfg *ArchiveConfig) {
+	c.archiveConfig = archiveCfg
+}
+
+// SetLeaderConfig assigns the leader election config for testing purposes.
+func (c *Config) SetLeaderConfig(leaderCfg *LeaderConfig) {
+	c.leaderConfig = leaderCfg
+}
+
+// SetEventSinkConfig assigns the event sink config for testing purposes.
+func (c *Config) SetEventSinkConfig(eventSinkCfg *EventSinkConfig) {
+	c.eventSinkConfig = eventSinkCfg
+}
+
+// SetWebhookConfig assigns the webhook config for testing purposes.
+func (c *Config) SetWebhookConfig(webhookCfg *WebhookConfig) {
+	c.webhookConfig = webhookCfg
+}
+
+// SetExtensionsConfig assigns the extensions config for testing purposes.
+func (c *Config) SetExtensionsConfig(extensionsCfg *ExtensionsConfig) {
+	c.extensionsConfig = extensionsCfg
+}
+
+// SetAdaptiveConfig assigns the adaptive concurrency config for testing purposes.
+func (c *Config) SetAdaptiveConfig(adaptiveCfg *AdaptiveConfig) {
+	c.adaptiveConfig = adaptiveCfg
+}
+
+// SetAdminConfig assigns the admin API config for testing purposes.
+func (c *Config) SetAdminConfig(adminCfg *AdminConfig) {
+	c.adminConfig = adminCfg
+}
+
+// SetInitialSyncConfig assigns the initial sync config for testing purposes.
+func (c *Config) SetInitialSyncConfig(initialSyncCfg *InitialSyncConfig) {
+	c.initialSyncConfig = initialSyncCfg
+}
+
 // SetCORSConfig assigns the CORS config for testing purposes.
 func (c *Config) SetCORSConfig(corsCfg *CORSConfig) {
 	c.corsConfig = corsCfg
@@ -480,6 +824,172 @@ func (c Config) TxAccountCleanupEnabled() bool {
 	return c.txAccountCleanupConfig != nil && c.txAccountCleanupConfig.Enabled
 }
 
+func (c Config) GetExportConfig() *ExportConfig {
+	if c.exportConfig == nil {
+		return &ExportConfig{MaxRows: DefaultExportMaxRows}
+	}
+	return c.exportConfig
+}
+
+func (c Config) GetRateLimitConfig() *RateLimitConfig {
+	return c.rateLimitConfig
+}
+
+func (c Config) GetAPICacheConfig() *APICacheConfig {
+	if c.apiCacheConfig == nil {
+		return &APICacheConfig{
+			Storage:            APICacheStorageMemory,
+			MaxAge:             DefaultAPICacheMaxAge,
+			HeightPollInterval: DefaultAPICacheHeightPollInterval,
+		}
+	}
+	return c.apiCacheConfig
+}
+
+func (c Config) GetPartitionConfig() *PartitionConfig {
+	if c.partitionConfig == nil {
+		return &PartitionConfig{
+			Size:          DefaultPartitionSize,
+			Ahead:         DefaultPartitionAhead,
+			CheckInterval: DefaultPartitionCheckInterval,
+		}
+	}
+	return c.partitionConfig
+}
+
+func (c Config) PruneEnabled() bool {
+	return c.pruneConfig != nil && c.pruneConfig.Enabled()
+}
+
+func (c Config) GetPruneConfig() *PruneConfig {
+	if c.pruneConfig == nil {
+		return &PruneConfig{
+			BatchSize: DefaultPruneBatchSize,
+			Interval:  DefaultPruneInterval,
+		}
+	}
+	return c.pruneConfig
+}
+
+func (c Config) ArchiveEnabled() bool {
+	return c.archiveConfig != nil && c.archiveConfig.Enabled()
+}
+
+func (c Config) GetArchiveConfig() *ArchiveConfig {
+	if c.archiveConfig == nil {
+		return &ArchiveConfig{
+			RangeSize:  DefaultArchiveRangeSize,
+			KeepBlocks: DefaultArchiveKeepBlocks,
+			Interval:   DefaultArchiveInterval,
+		}
+	}
+	return c.archiveConfig
+}
+
+func (c Config) LeaderElectionEnabled() bool {
+	return c.leaderConfig != nil && c.leaderConfig.Enabled
+}
+
+func (c Config) GetLeaderConfig() *LeaderConfig {
+	if c.leaderConfig == nil {
+		return &LeaderConfig{
+			CheckInterval: DefaultLeaderCheckInterval,
+		}
+	}
+	return c.leaderConfig
+}
+
+func (c Config) EventSinkEnabled() bool {
+	return c.eventSinkConfig != nil && c.eventSinkConfig.Enabled()
+}
+
+func (c Config) GetEventSinkConfig() *EventSinkConfig {
+	if c.eventSinkConfig == nil {
+		return &EventSinkConfig{
+			TopicPrefix:  DefaultEventSinkTopicPrefix,
+			BatchSize:    DefaultEventSinkBatchSize,
+			PollInterval: DefaultEventSinkPollInterval,
+		}
+	}
+	return c.eventSinkConfig
+}
+
+func (c Config) WebhookEnabled() bool {
+	return c.webhookConfig != nil && c.webhookConfig.Enabled
+}
+
+func (c Config) GetWebhookConfig() *WebhookConfig {
+	if c.webhookConfig == nil {
+		return &WebhookConfig{
+			BatchSize:      DefaultWebhookBatchSize,
+			PollInterval:   DefaultWebhookPollInterval,
+			Timeout:        DefaultWebhookTimeout,
+			MaxAttempts:    DefaultWebhookMaxAttempts,
+			InitialBackoff: DefaultWebhookInitialBackoff,
+			MaxBackoff:     DefaultWebhookMaxBackoff,
+			Concurrency:    DefaultWebhookConcurrency,
+		}
+	}
+	return c.webhookConfig
+}
+
+func (c Config) GetExtensionsConfig() *ExtensionsConfig {
+	if c.extensionsConfig == nil {
+		return &ExtensionsConfig{
+			DependencyPollInterval: DefaultExtensionDependencyPollInterval,
+			RestartInitialBackoff:  DefaultExtensionRestartInitialBackoff,
+			RestartMaxBackoff:      DefaultExtensionRestartMaxBackoff,
+			MaxRestarts:            DefaultExtensionMaxRestarts,
+		}
+	}
+	return c.extensionsConfig
+}
+
+func (c Config) AdaptiveEnabled() bool {
+	return c.adaptiveConfig != nil && c.adaptiveConfig.Enabled
+}
+
+func (c Config) GetAdaptiveConfig() *AdaptiveConfig {
+	if c.adaptiveConfig == nil {
+		return &AdaptiveConfig{
+			MinConcurrency:        DefaultAdaptiveMinConcurrency,
+			MinInflightBlocks:     DefaultAdaptiveMinInflightBlocks,
+			MaxInflightBlocks:     DefaultAdaptiveMaxInflightBlocks,
+			TargetScrapeLatency:   DefaultAdaptiveTargetScrapeLatency,
+			TargetCollectDuration: DefaultAdaptiveTargetCollectDuration,
+			MaxErrorRate:          DefaultAdaptiveMaxErrorRate,
+			Interval:              DefaultAdaptiveInterval,
+		}
+	}
+	return c.adaptiveConfig
+}
+
+func (c Config) AdminEnabled() bool {
+	return c.adminConfig != nil && c.adminConfig.Token != ""
+}
+
+func (c Config) GetAdminConfig() *AdminConfig {
+	if c.adminConfig == nil {
+		return &AdminConfig{
+			DrainTimeout: DefaultAdminDrainTimeout,
+		}
+	}
+	return c.adminConfig
+}
+
+func (c Config) InitialSyncEnabled() bool {
+	return c.initialSyncConfig != nil && c.initialSyncConfig.Enabled
+}
+
+func (c Config) GetInitialSyncConfig() *InitialSyncConfig {
+	if c.initialSyncConfig == nil {
+		return &InitialSyncConfig{
+			Threshold: DefaultInitialSyncThreshold,
+		}
+	}
+	return c.initialSyncConfig
+}
+
 func (c Config) GetSentryConfig() *SentryConfig {
 	if c.sentryConfig == nil || c.sentryConfig.DSN == "" {
 		return nil
@@ -514,6 +1024,10 @@ func (c Config) GetMaxConcurrentRequests() int {
 	return c.maxConcurrentRequests
 }
 
+func (c Config) GetCollectBatchSize() int {
+	return c.collectBatchSize
+}
+
 func (c Config) GetMetricsConfig() *MetricsConfig {
 	return c.metricsConfig
 }
@@ -542,6 +1056,14 @@ func (c Config) GetStartHeight() int64 {
 	return c.startHeight
 }
 
+// WithStartHeight returns a copy of the config starting at the height, for collecting a height range
+// apart from the blocks before it
+func (c Config) WithStartHeight(height int64) *Config {
+	c.startHeight = height
+	c.startHeightSet = true
+	return &c
+}
+
 func (c Config) Validate() error {
 	if err := c.validatePort(); err != nil {
 		return err
@@ -549,6 +1071,9 @@ func (c Config) Validate() error {
 	if err := c.validateRecvBuffersize(); err != nil {
 		return err
 	}
+	if err := c.validateTrustedProxies(); err != nil {
+		return err
+	}
 	if err := c.validateIndexerPort(); err != nil {
 		return err
 	}
@@ -564,6 +1089,42 @@ func (c Config) Validate() error {
 	if err := c.validateMetricsConfig(); err != nil {
 		return err
 	}
+	if err := c.validateRateLimitConfig(); err != nil {
+		return err
+	}
+	if err := c.validateAPICacheConfig(); err != nil {
+		return err
+	}
+	if err := c.validatePartitionConfig(); err != nil {
+		return err
+	}
+	if err := c.validatePruneConfig(); err != nil {
+		return err
+	}
+	if err := c.validateArchiveConfig(); err != nil {
+		return err
+	}
+	if err := c.validateLeaderConfig(); err != nil {
+		return err
+	}
+	if err := c.validateEventSinkConfig(); err != nil {
+		return err
+	}
+	if err := c.validateWebhookConfig(); err != nil {
+		return err
+	}
+	if err := c.validateExtensionsConfig(); err != nil {
+		return err
+	}
+	if err := c.validateAdminConfig(); err != nil {
+		return err
+	}
+	if err := c.validateAdaptiveConfig(); err != nil {
+		return err
+	}
+	if err := c.validateInitialSyncConfig(); err != nil {
+		return err
+	}
 	if err := c.validateSubConfigs(); err != nil {
 		return err
 	}
@@ -589,6 +1150,19 @@ func (c Config) validateRecvBuffersize() error {
 	return nil
 }
 
+// validateTrustedProxies validates that the trusted proxies are addresses or CIDR ranges
+func (c Config) validateTrustedProxies() error {
+	for _, proxy := range c.trustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			return types.NewInvalidValueError("TRUSTED_PROXIES", proxy, "must be an IP address or a CIDR range")
+		}
+	}
+	return nil
+}
+
 // validateIndexerPort validates the listen port configuration
 func (c Config) validateIndexerPort() error {
 	if len(c.indexerListenPort) == 0 {
@@ -643,6 +1217,15 @@ func (c Config) validateNumericSettings() error {
 	if c.maxConcurrentRequests > MaxAllowedConcurrentRequests {
 		return types.NewInvalidValueError("MAX_CONCURRENT_REQUESTS", fmt.Sprintf("%d", c.maxConcurrentRequests), fmt.Sprintf("must not exceed %d", MaxAllowedConcurrentRequests))
 	}
+	if c.collectBatchSize < 1 {
+		return types.NewValidationError("COLLECT_BATCH_SIZE", "must be at least 1")
+	}
+	if c.collectBatchSize > MaxAllowedCollectBatchSize {
+		return types.NewInvalidValueError("COLLECT_BATCH_SIZE", fmt.Sprintf("%d", c.collectBatchSize), fmt.Sprintf("must not exceed %d", MaxAllowedCollectBatchSize))
+	}
+	if c.exportConfig != nil && c.exportConfig.MaxRows < 1 {
+		return types.NewValidationError("EXPORT_MAX_ROWS", "must be at least 1")
+	}
 	return nil
 }
 
@@ -720,6 +1303,279 @@ func (c Config) validateMetricsPath() error {
 	return nil
 }
 
+// validateRateLimitConfig validates rate limit and API key configuration
+func (c Config) validateRateLimitConfig() error {
+	if c.rateLimitConfig == nil || !c.rateLimitConfig.Enabled {
+		return nil
+	}
+	rl := c.rateLimitConfig
+	if rl.AnonymousRate < 0 {
+		return types.NewValidationError("RATE_LIMIT_ANONYMOUS_RATE", "must be non-negative")
+	}
+	if rl.AnonymousRate > 0 && rl.AnonymousBurst < 1 {
+		return types.NewValidationError("RATE_LIMIT_ANONYMOUS_BURST", "must be at least 1")
+	}
+	if rl.KeyRate <= 0 {
+		return types.NewValidationError("RATE_LIMIT_KEY_RATE", "must be positive")
+	}
+	if rl.KeyBurst < 1 {
+		return types.NewValidationError("RATE_LIMIT_KEY_BURST", "must be at least 1")
+	}
+	if rl.ExpensiveCost < 1 {
+		return types.NewValidationError("RATE_LIMIT_EXPENSIVE_COST", "must be at least 1")
+	}
+	if rl.KeysFile != "" {
+		if _, err := os.Stat(rl.KeysFile); err != nil {
+			return types.NewInvalidValueError("API_KEYS_FILE", rl.KeysFile, "must be a readable file")
+		}
+	}
+	if rl.KeysFromDB && rl.RefreshInterval <= 0 {
+		return types.NewValidationError("API_KEYS_REFRESH_INTERVAL", "must be positive when API_KEYS_DB is enabled")
+	}
+	return nil
+}
+
+// validateAPICacheConfig validates the API response cache configuration
+func (c Config) validateAPICacheConfig() error {
+	if c.apiCacheConfig == nil {
+		return nil
+	}
+	ac := c.apiCacheConfig
+	switch ac.Storage {
+	case APICacheStorageMemory:
+	case APICacheStorageRedis:
+		if ac.RedisAddr == "" {
+			return types.NewValidationError("API_CACHE_REDIS_ADDR", "required when API_CACHE_STORAGE is redis")
+		}
+		if ac.RedisDB < 0 {
+			return types.NewValidationError("API_CACHE_REDIS_DB", "must be non-negative")
+		}
+	default:
+		return types.NewInvalidValueError("API_CACHE_STORAGE", ac.Storage, "must be memory or redis")
+	}
+	if ac.MaxAge < time.Second {
+		return types.NewValidationError("API_CACHE_MAX_AGE", "must be at least 1s")
+	}
+	if ac.HeightPollInterval <= 0 {
+		return types.NewValidationError("API_CACHE_HEIGHT_POLL_INTERVAL", "must be positive")
+	}
+	return nil
+}
+
+// validatePartitionConfig validates the partition maintenance configuration
+func (c Config) validatePartitionConfig() error {
+	if c.partitionConfig == nil {
+		return nil
+	}
+	pc := c.partitionConfig
+	if pc.Size < 1 {
+		return types.NewValidationError("PARTITION_SIZE", "must be at least 1")
+	}
+	if pc.Ahead < 1 {
+		return types.NewValidationError("PARTITION_AHEAD", "must be at least 1")
+	}
+	if pc.CheckInterval <= 0 {
+		return types.NewValidationError("PARTITION_CHECK_INTERVAL", "must be positive")
+	}
+	return nil
+}
+
+// validatePruneConfig validates the retention window of the pruning mode
+func (c Config) validatePruneConfig() error {
+	if c.pruneConfig == nil {
+		return nil
+	}
+	pc := c.pruneConfig
+	if pc.KeepBlocks < 0 {
+		return types.NewValidationError("PRUNE_KEEP_BLOCKS", "must be non-negative")
+	}
+	if pc.KeepDuration < 0 {
+		return types.NewValidationError("PRUNE_KEEP_DURATION", "must be non-negative")
+	}
+	if !pc.Enabled() {
+		return nil
+	}
+	if pc.BatchSize < 1 {
+		return types.NewValidationError("PRUNE_BATCH_SIZE", "must be at least 1")
+	}
+	if pc.Interval <= 0 {
+		return types.NewValidationError("PRUNE_INTERVAL", "must be positive")
+	}
+	return nil
+}
+
+// validateArchiveConfig validates the cold archive configuration
+func (c Config) validateArchiveConfig() error {
+	if c.archiveConfig == nil || !c.archiveConfig.Enabled() {
+		return nil
+	}
+	ac := c.archiveConfig
+	if ac.RangeSize < 1 {
+		return types.NewValidationError("ARCHIVE_RANGE_SIZE", "must be at least 1")
+	}
+	if ac.KeepBlocks < 0 {
+		return types.NewValidationError("ARCHIVE_KEEP_BLOCKS", "must be non-negative")
+	}
+	if ac.Interval <= 0 {
+		return types.NewValidationError("ARCHIVE_INTERVAL", "must be positive")
+	}
+	if c.PruneEnabled() {
+		return types.NewValidationError("ARCHIVE_PATH", "cannot be combined with PRUNE_KEEP_BLOCKS or PRUNE_KEEP_DURATION, pruned rows would be missing from the archive")
+	}
+	return nil
+}
+
+// validateLeaderConfig validates the leader election configuration
+func (c Config) validateLeaderConfig() error {
+	if !c.LeaderElectionEnabled() {
+		return nil
+	}
+	if c.leaderConfig.CheckInterval <= 0 {
+		return types.NewValidationError("LEADER_CHECK_INTERVAL", "must be positive")
+	}
+	return nil
+}
+
+// validateEventSinkConfig validates the event stream sink configuration
+func (c Config) validateEventSinkConfig() error {
+	if !c.EventSinkEnabled() {
+		return nil
+	}
+	ec := c.eventSinkConfig
+	switch ec.Broker {
+	case EventSinkBrokerKafka, EventSinkBrokerNats:
+	default:
+		return types.NewInvalidValueError("EVENT_SINK_BROKER", ec.Broker, "must be kafka or nats")
+	}
+	if len(ec.URLs) == 0 {
+		return types.NewValidationError("EVENT_SINK_URLS", "required when EVENT_SINK_BROKER is set")
+	}
+	if ec.TopicPrefix == "" {
+		return types.NewValidationError("EVENT_SINK_TOPIC_PREFIX", "required when EVENT_SINK_BROKER is set")
+	}
+	if ec.BatchSize < 1 {
+		return types.NewValidationError("EVENT_SINK_BATCH_SIZE", "must be at least 1")
+	}
+	if ec.PollInterval <= 0 {
+		return types.NewValidationError("EVENT_SINK_POLL_INTERVAL", "must be positive")
+	}
+	return nil
+}
+
+// validateWebhookConfig validates the webhook dispatcher configuration
+func (c Config) validateWebhookConfig() error {
+	if !c.WebhookEnabled() {
+		return nil
+	}
+	wc := c.webhookConfig
+	if wc.AdminToken == "" {
+		return types.NewValidationError("WEBHOOK_ADMIN_TOKEN", "required when WEBHOOK_ENABLED is true")
+	}
+	if wc.BatchSize < 1 {
+		return types.NewValidationError("WEBHOOK_BATCH_SIZE", "must be at least 1")
+	}
+	if wc.PollInterval <= 0 {
+		return types.NewValidationError("WEBHOOK_POLL_INTERVAL", "must be positive")
+	}
+	if wc.Timeout <= 0 {
+		return types.NewValidationError("WEBHOOK_TIMEOUT", "must be positive")
+	}
+	if wc.MaxAttempts < 1 {
+		return types.NewValidationError("WEBHOOK_MAX_ATTEMPTS", "must be at least 1")
+	}
+	if wc.InitialBackoff <= 0 {
+		return types.NewValidationError("WEBHOOK_INITIAL_BACKOFF", "must be positive")
+	}
+	if wc.MaxBackoff < wc.InitialBackoff {
+		return types.NewValidationError("WEBHOOK_MAX_BACKOFF", "must not be less than WEBHOOK_INITIAL_BACKOFF")
+	}
+	if wc.Concurrency < 1 {
+		return types.NewValidationError("WEBHOOK_CONCURRENCY", "must be at least 1")
+	}
+	return nil
+}
+
+// validateExtensionsConfig validates the extension selection; the names are checked against the
+// registry when the extension manager starts
+func (c Config) validateExtensionsConfig() error {
+	ec := c.GetExtensionsConfig()
+	seen := make(map[string]bool, len(ec.Names))
+	for _, name := range ec.Names {
+		if seen[name] {
+			return types.NewValidationError("EXTENSIONS", fmt.Sprintf("duplicate extension %q", name))
+		}
+		seen[name] = true
+	}
+	if ec.DependencyPollInterval <= 0 {
+		return types.NewValidationError("EXTENSION_DEPENDENCY_POLL_INTERVAL", "must be positive")
+	}
+	if ec.RestartInitialBackoff <= 0 {
+		return types.NewValidationError("EXTENSION_RESTART_INITIAL_BACKOFF", "must be positive")
+	}
+	if ec.RestartMaxBackoff < ec.RestartInitialBackoff {
+		return types.NewValidationError("EXTENSION_RESTART_MAX_BACKOFF", "must be at least EXTENSION_RESTART_INITIAL_BACKOFF")
+	}
+	if ec.MaxRestarts < 0 {
+		return types.NewValidationError("EXTENSION_MAX_RESTARTS", "must not be negative")
+	}
+	return nil
+}
+
+// validateAdaptiveConfig validates the adaptive concurrency configuration
+func (c Config) validateAdaptiveConfig() error {
+	if !c.AdaptiveEnabled() {
+		return nil
+	}
+	ac := c.adaptiveConfig
+	if ac.MinConcurrency < 1 {
+		return types.NewValidationError("ADAPTIVE_MIN_CONCURRENCY", "must be at least 1")
+	}
+	if ac.MinConcurrency > c.maxConcurrentRequests {
+		return types.NewValidationError("ADAPTIVE_MIN_CONCURRENCY", "must not exceed MAX_CONCURRENT_REQUESTS")
+	}
+	if ac.MinInflightBlocks < 2 {
+		return types.NewValidationError("ADAPTIVE_MIN_INFLIGHT_BLOCKS", "must be at least 2")
+	}
+	if ac.MaxInflightBlocks < ac.MinInflightBlocks {
+		return types.NewValidationError("ADAPTIVE_MAX_INFLIGHT_BLOCKS", "must be at least ADAPTIVE_MIN_INFLIGHT_BLOCKS")
+	}
+	if ac.TargetScrapeLatency <= 0 {
+		return types.NewValidationError("ADAPTIVE_TARGET_SCRAPE_LATENCY", "must be positive")
+	}
+	if ac.TargetCollectDuration <= 0 {
+		return types.NewValidationError("ADAPTIVE_TARGET_COLLECT_DURATION", "must be positive")
+	}
+	if ac.MaxErrorRate < 0 || ac.MaxErrorRate > 1 {
+		return types.NewValidationError("ADAPTIVE_MAX_ERROR_RATE", "must be between 0 and 1")
+	}
+	if ac.Interval <= 0 {
+		return types.NewValidationError("ADAPTIVE_INTERVAL", "must be positive")
+	}
+	return nil
+}
+
+// validateAdminConfig validates the admin API configuration
+func (c Config) validateAdminConfig() error {
+	if !c.AdminEnabled() {
+		return nil
+	}
+	if c.adminConfig.DrainTimeout <= 0 {
+		return types.NewValidationError("INDEXER_ADMIN_DRAIN_TIMEOUT", "must be positive")
+	}
+	return nil
+}
+
+// validateInitialSyncConfig validates the initial sync configuration
+func (c Config) validateInitialSyncConfig() error {
+	if !c.InitialSyncEnabled() {
+		return nil
+	}
+	if c.initialSyncConfig.Threshold <= 0 {
+		return types.NewValidationError("INITIAL_SYNC_THRESHOLD", "must be positive")
+	}
+	return nil
+}
+
 // validateSubConfigs validates nested configuration objects
 func (c Config) validateSubConfigs() error {
 	if err := c.dbConfig.Validate(); err != nil {
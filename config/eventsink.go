@@ -0,0 +1,32 @@
+package config
+
+import "time"
+
+const (
+	EventSinkBrokerKafka = "kafka"
+	EventSinkBrokerNats  = "nats"
+)
+
+// EventSinkConfig configures the event stream sink, which publishes the indexed blocks, txs, evm txs
+// and nft events to a broker. The events are written to an outbox table in the transaction of their
+// block and published in order from the outbox cursor, so they are delivered at least once.
+// Env vars:
+// - EVENT_SINK_BROKER (kafka|nats; empty disables the sink)
+// - EVENT_SINK_URLS (comma-separated list; kafka brokers or nats servers)
+// - EVENT_SINK_TOPIC_PREFIX (string; events are published to <prefix>.<event type>)
+// - EVENT_SINK_BATCH_SIZE (int; outbox events published per batch)
+// - EVENT_SINK_POLL_INTERVAL (duration; how often the outbox is read without new blocks)
+// - EVENT_SINK_AUTO_CREATE_TOPICS (bool; let kafka create the missing topics instead of failing)
+type EventSinkConfig struct {
+	Broker           string        `json:"broker"`
+	URLs             []string      `json:"urls"`
+	TopicPrefix      string        `json:"topic_prefix"`
+	BatchSize        int           `json:"batch_size"`
+	PollInterval     time.Duration `json:"poll_interval"`
+	AutoCreateTopics bool          `json:"auto_create_topics"`
+}
+
+// Enabled reports whether a broker is configured
+func (c EventSinkConfig) Enabled() bool {
+	return c.Broker != ""
+}
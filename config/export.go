@@ -0,0 +1,10 @@
+package config
+
+// ExportConfig bounds the CSV/NDJSON exports of the list endpoints
+type ExportConfig struct {
+	MaxRows int // hard cap on the number of rows a single export streams
+}
+
+func (c ExportConfig) GetMaxRows() int {
+	return c.MaxRows
+}
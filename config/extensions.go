@@ -0,0 +1,80 @@
+package config
+
+import (
+	"slices"
+	"time"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// ExtensionsConfig selects the registered extensions the indexer runs. Each selected extension still
+// needs its own settings to be enabled; an empty list selects every registered extension.
+// Env vars:
+// - EXTENSIONS (comma-separated extension names, e.g. "rich-list,prune")
+// - EXTENSION_DEPENDENCY_POLL_INTERVAL (duration; checkpoint polls while waiting for dependencies)
+// - EXTENSION_RESTART_INITIAL_BACKOFF (duration; wait before the first restart of a failed extension)
+// - EXTENSION_RESTART_MAX_BACKOFF (duration; longest wait between restarts)
+// - EXTENSION_MAX_RESTARTS (int; consecutive restarts before an extension is degraded, 0 for no limit)
+type ExtensionsConfig struct {
+	Names                  []string      `json:"names"`
+	DependencyPollInterval time.Duration `json:"dependency_poll_interval"`
+	RestartInitialBackoff  time.Duration `json:"restart_initial_backoff"`
+	RestartMaxBackoff      time.Duration `json:"restart_max_backoff"`
+	MaxRestarts            int           `json:"max_restarts"`
+}
+
+// Selected reports whether the extension is in the EXTENSIONS list
+func (c ExtensionsConfig) Selected(name string) bool {
+	return len(c.Names) == 0 || slices.Contains(c.Names, name)
+}
+
+// Names of the extensions, which their checkpoints are saved under
+const (
+	ExtensionEventSink        = "event-sink"
+	ExtensionInternalTx       = "internal-tx"
+	ExtensionRichList         = "rich-list"
+	ExtensionEvmRetCleanup    = "evm-ret-cleanup"
+	ExtensionTxAccountCleanup = "tx-account-cleanup"
+	ExtensionWebhook          = "webhook"
+	ExtensionPrune            = "prune"
+	ExtensionArchive          = "archive"
+)
+
+// extensions lists the settings enabling each extension in the run order of the extensions, so that
+// the processes which do not run them, like the API server, know the enabled ones
+var extensions = []struct {
+	name    string
+	enabled func(c Config) bool
+}{
+	{ExtensionEventSink, Config.EventSinkEnabled},
+	{ExtensionInternalTx, func(c Config) bool { return c.GetVmType() == types.EVM && c.InternalTxEnabled() }},
+	{ExtensionRichList, Config.GetRichListEnabled},
+	{ExtensionEvmRetCleanup, func(c Config) bool { return c.GetVmType() == types.EVM && c.EvmRetCleanupEnabled() }},
+	{ExtensionTxAccountCleanup, Config.TxAccountCleanupEnabled},
+	{ExtensionWebhook, Config.WebhookEnabled},
+	{ExtensionPrune, Config.PruneEnabled},
+	{ExtensionArchive, Config.ArchiveEnabled},
+}
+
+// ExtensionEnabled reports whether the settings of the extension enable it
+func (c Config) ExtensionEnabled(name string) bool {
+	for _, ext := range extensions {
+		if ext.name == name {
+			return ext.enabled(c)
+		}
+	}
+	return false
+}
+
+// EnabledExtensions returns the extensions selected by EXTENSIONS and enabled by their settings, in
+// their run order
+func (c Config) EnabledExtensions() []string {
+	selection := c.GetExtensionsConfig()
+	var names []string
+	for _, ext := range extensions {
+		if selection.Selected(ext.name) && ext.enabled(c) {
+			names = append(names, ext.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,16 @@
+package config
+
+// InitialSyncConfig configures the initial sync mode of the indexer. When the indexer starts further
+// behind the chain than the threshold, the tx collector loads its rows with COPY instead of batched
+// inserts, optionally without the secondary indexes of the tx tables, until the scraper reaches the
+// latest height. The normal transactional path then resumes while the deferred indexes are recreated
+// in the background.
+// Env vars:
+// - INITIAL_SYNC_ENABLED (bool)
+// - INITIAL_SYNC_THRESHOLD (int; blocks behind the chain above which the initial sync starts)
+// - INITIAL_SYNC_DEFER_INDEXES (bool; drop the secondary indexes of the tx tables during the initial sync)
+type InitialSyncConfig struct {
+	Enabled      bool  `json:"enabled"`
+	Threshold    int64 `json:"threshold"`
+	DeferIndexes bool  `json:"defer_indexes"`
+}
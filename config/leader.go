@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// LeaderConfig configures the leader election between indexer replicas sharing one database.
+// The indexer pipeline and the extensions each hold a Postgres advisory lock while they lead;
+// the other replicas stay warm as standbys and take over once the lock is released. The writes of
+// a leader are fenced by the epoch of its election, kept in leader_epoch.
+// Env vars:
+// - LEADER_ELECTION_ENABLED (bool)
+// - LEADER_CHECK_INTERVAL (duration; lock attempts as standby and liveness checks as leader)
+type LeaderConfig struct {
+	Enabled       bool          `json:"enabled"`
+	CheckInterval time.Duration `json:"check_interval"`
+}
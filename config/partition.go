@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// PartitionConfig configures the range partitions of the tx and edge tables, by sequence.
+// The indexer keeps Ahead partitions of Size sequences ready past the head of every partitioned table.
+// Env vars:
+// - PARTITION_SIZE (int; sequences per partition)
+// - PARTITION_AHEAD (int; partitions created ahead of the head)
+// - PARTITION_CHECK_INTERVAL (duration)
+type PartitionConfig struct {
+	Size          int64         `json:"size"`
+	Ahead         int           `json:"ahead"`
+	CheckInterval time.Duration `json:"check_interval"`
+}
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+// PruneConfig configures the pruning mode, which deletes the txs older than the retention window
+// for deployments that do not need the full history. Pruning is disabled unless a window is set;
+// with both settings, the rows are kept until they fall outside both windows.
+// Env vars:
+// - PRUNE_KEEP_BLOCKS (int; latest blocks whose txs are kept)
+// - PRUNE_KEEP_DURATION (duration; age of the oldest block whose txs are kept)
+// - PRUNE_BATCH_SIZE (int; heights deleted per batch)
+// - PRUNE_INTERVAL (duration)
+type PruneConfig struct {
+	KeepBlocks   int64         `json:"keep_blocks"`
+	KeepDuration time.Duration `json:"keep_duration"`
+	BatchSize    int64         `json:"batch_size"`
+	Interval     time.Duration `json:"interval"`
+}
+
+// Enabled reports whether a retention window is configured
+func (c PruneConfig) Enabled() bool {
+	return c.KeepBlocks > 0 || c.KeepDuration > 0
+}
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+// RateLimitConfig configures API key authentication and the per-client token bucket rate limit of the API.
+// Requests without an API key fall into the anonymous tier, keyed by client IP, which is only taken from
+// the proxy header of TRUSTED_PROXIES.
+// Env vars:
+// - RATE_LIMIT_ENABLED (bool)
+// - RATE_LIMIT_ANONYMOUS_RATE (float; tokens per second, 0 rejects requests without an API key)
+// - RATE_LIMIT_ANONYMOUS_BURST (int)
+// - RATE_LIMIT_KEY_RATE (float; tokens per second of keys without their own rate)
+// - RATE_LIMIT_KEY_BURST (int)
+// - RATE_LIMIT_EXPENSIVE_COST (int; tokens charged for expensive requests, others cost 1)
+// - API_KEYS_FILE (path to a JSON file of static API keys)
+// - API_KEYS_DB (bool; also load API keys from the api_key table)
+// - API_KEYS_REFRESH_INTERVAL (duration; reload interval of the api_key table)
+type RateLimitConfig struct {
+	Enabled         bool          `json:"enabled"`
+	AnonymousRate   float64       `json:"anonymous_rate"`
+	AnonymousBurst  int           `json:"anonymous_burst"`
+	KeyRate         float64       `json:"key_rate"`
+	KeyBurst        int           `json:"key_burst"`
+	ExpensiveCost   int           `json:"expensive_cost"`
+	KeysFile        string        `json:"keys_file"`
+	KeysFromDB      bool          `json:"keys_from_db"`
+	RefreshInterval time.Duration `json:"refresh_interval"`
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// WebhookConfig configures the webhook dispatcher, which posts signed notifications to the subscribed
+// URLs when a watched account is part of a tx, a watched collection mints or the top of a watched rich
+// list changes. The subscriptions are managed through the API, guarded by the admin token.
+// Env vars:
+// - WEBHOOK_ENABLED (bool)
+// - WEBHOOK_ADMIN_TOKEN (string; required by the subscription management API)
+// - WEBHOOK_BATCH_SIZE (int; txs scanned per batch)
+// - WEBHOOK_POLL_INTERVAL (duration; how often new txs are scanned)
+// - WEBHOOK_TIMEOUT (duration; per delivery attempt)
+// - WEBHOOK_MAX_ATTEMPTS (int; attempts before a delivery is dead-lettered)
+// - WEBHOOK_INITIAL_BACKOFF (duration; doubled after every failed attempt)
+// - WEBHOOK_MAX_BACKOFF (duration)
+// - WEBHOOK_CONCURRENCY (int; deliveries in flight)
+// - WEBHOOK_ALLOW_PRIVATE_URLS (bool; allow receivers on loopback and private addresses)
+type WebhookConfig struct {
+	Enabled          bool          `json:"enabled"`
+	AdminToken       string        `json:"-"`
+	BatchSize        int           `json:"batch_size"`
+	PollInterval     time.Duration `json:"poll_interval"`
+	Timeout          time.Duration `json:"timeout"`
+	MaxAttempts      int           `json:"max_attempts"`
+	InitialBackoff   time.Duration `json:"initial_backoff"`
+	MaxBackoff       time.Duration `json:"max_backoff"`
+	Concurrency      int           `json:"concurrency"`
+	AllowPrivateUrls bool          `json:"allow_private_urls"`
+}
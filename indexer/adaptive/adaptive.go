@@ -0,0 +1,190 @@
+package adaptive
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/querier"
+)
+
+// windowStep is the additive increase of the inflight window per healthy interval
+const windowStep = 10
+
+// Controller adjusts the scrape concurrency and the inflight window with AIMD: both grow
+// additively after an interval without congestion and are halved after a congested one.
+// When disabled it returns the fixed MAX_CONCURRENT_REQUESTS and inflight constants.
+type Controller struct {
+	cfg            *config.AdaptiveConfig
+	maxConcurrency int
+	rpcUrls        []string
+	logger         *slog.Logger
+
+	mtx         sync.Mutex
+	concurrency int
+	window      int
+	sample      sample
+}
+
+// sample holds the observations of the current interval
+type sample struct {
+	scrapes         int
+	scrapeErrors    int
+	scrapeLatency   time.Duration
+	collects        int
+	collectDuration time.Duration
+}
+
+func New(cfg *config.Config, logger *slog.Logger) *Controller {
+	c := &Controller{
+		cfg:            cfg.GetAdaptiveConfig(),
+		maxConcurrency: max(cfg.GetMaxConcurrentRequests(), 1),
+		rpcUrls:        cfg.GetChainConfig().RpcUrls,
+		logger:         logger.With("module", "adaptive"),
+	}
+	if !cfg.AdaptiveEnabled() {
+		c.cfg = &config.AdaptiveConfig{}
+	}
+
+	// Start from the fixed limits, so enabling the controller never starts more aggressive
+	c.concurrency = c.maxConcurrency
+	c.window = types.MaxInflightBlocks
+	if c.cfg.Enabled {
+		c.window = min(max(c.window, c.cfg.MinInflightBlocks), c.cfg.MaxInflightBlocks)
+	}
+	c.report()
+	return c
+}
+
+// Enabled reports whether the limits adapt to the observations
+func (c *Controller) Enabled() bool {
+	return c.cfg.Enabled
+}
+
+// Concurrency returns the current limit of concurrent scrapes
+func (c *Controller) Concurrency() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.concurrency
+}
+
+// Window returns the inflight blocks above which scraping pauses, and below which it resumes
+func (c *Controller) Window() (high, low int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !c.cfg.Enabled {
+		return types.MaxInflightBlocks, types.MinInflightBlocks
+	}
+	return c.window, c.window / 2
+}
+
+// ObserveScrape records the latency and the result of a block scrape
+func (c *Controller) ObserveScrape(latency time.Duration, err error) {
+	if !c.cfg.Enabled {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.sample.scrapes++
+	c.sample.scrapeLatency += latency
+	if err != nil {
+		c.sample.scrapeErrors++
+	}
+}
+
+// ObserveCollect records the duration of a block collect
+func (c *Controller) ObserveCollect(duration time.Duration) {
+	if !c.cfg.Enabled {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.sample.collects++
+	c.sample.collectDuration += duration
+}
+
+// Run adjusts the limits every interval until the context is done
+func (c *Controller) Run(ctx context.Context) {
+	if !c.cfg.Enabled {
+		return
+	}
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.adjust(querier.FailingEndpoints(c.rpcUrls))
+	}
+}
+
+// adjust applies the observations of the interval to the limits and starts a new interval
+func (c *Controller) adjust(failingEndpoints int) {
+	if !c.cfg.Enabled {
+		return
+	}
+
+	c.mtx.Lock()
+	s := c.sample
+	c.sample = sample{}
+
+	prevConcurrency, prevWindow := c.concurrency, c.window
+	if s.scrapes > 0 || failingEndpoints > 0 {
+		if c.scrapeCongested(s, failingEndpoints) {
+			c.concurrency = max(c.concurrency/2, c.cfg.MinConcurrency)
+		} else {
+			c.concurrency = min(c.concurrency+1, c.maxConcurrency)
+		}
+	}
+	if s.collects > 0 {
+		if s.collectDuration/time.Duration(s.collects) > c.cfg.TargetCollectDuration {
+			c.window = max(c.window/2, c.cfg.MinInflightBlocks)
+		} else {
+			c.window = min(c.window+windowStep, c.cfg.MaxInflightBlocks)
+		}
+	}
+	concurrency, window := c.concurrency, c.window
+	c.mtx.Unlock()
+
+	if concurrency != prevConcurrency || window != prevWindow {
+		c.logger.Debug("adjusted limits",
+			slog.Int("concurrency", concurrency),
+			slog.Int("inflight_window", window),
+			slog.Int("scrapes", s.scrapes),
+			slog.Int("scrape_errors", s.scrapeErrors),
+			slog.Int("failing_endpoints", failingEndpoints),
+			slog.Int("collects", s.collects))
+	}
+	c.report()
+}
+
+// scrapeCongested reports whether the RPC endpoints are slow or failing
+func (c *Controller) scrapeCongested(s sample, failingEndpoints int) bool {
+	if len(c.rpcUrls) > 0 && float64(failingEndpoints)/float64(len(c.rpcUrls)) > c.cfg.MaxErrorRate {
+		return true
+	}
+	if s.scrapes == 0 {
+		return false
+	}
+	if float64(s.scrapeErrors)/float64(s.scrapes) > c.cfg.MaxErrorRate {
+		return true
+	}
+	return s.scrapeLatency/time.Duration(s.scrapes) > c.cfg.TargetScrapeLatency
+}
+
+func (c *Controller) report() {
+	m := metrics.GetMetrics()
+	if m == nil {
+		return
+	}
+	high, _ := c.Window()
+	m.IndexerMetrics().ScrapeConcurrency.Set(float64(c.Concurrency()))
+	m.IndexerMetrics().InflightWindow.Set(float64(high))
+}
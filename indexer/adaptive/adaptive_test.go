@@ -0,0 +1,109 @@
+package adaptive
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/types"
+)
+
+func newTestController(enabled bool) *Controller {
+	cfg := &config.Config{}
+	cfg.SetChainConfig(&config.ChainConfig{RpcUrls: []string{"http://rpc-1", "http://rpc-2"}})
+	cfg.SetAdaptiveConfig(&config.AdaptiveConfig{
+		Enabled:               enabled,
+		MinConcurrency:        2,
+		MinInflightBlocks:     20,
+		MaxInflightBlocks:     120,
+		TargetScrapeLatency:   time.Second,
+		TargetCollectDuration: 100 * time.Millisecond,
+		MaxErrorRate:          0.1,
+		Interval:              time.Second,
+	})
+	c := New(cfg, slog.New(slog.DiscardHandler))
+	// MAX_CONCURRENT_REQUESTS
+	c.maxConcurrency = 16
+	c.concurrency = 16
+	return c
+}
+
+func TestController_Disabled(t *testing.T) {
+	c := newTestController(false)
+	c.ObserveScrape(10*time.Second, errors.New("timeout"))
+	c.adjust(2)
+
+	assert.Equal(t, 16, c.Concurrency())
+	high, low := c.Window()
+	assert.Equal(t, types.MaxInflightBlocks, high)
+	assert.Equal(t, types.MinInflightBlocks, low)
+}
+
+func TestController_Concurrency(t *testing.T) {
+	c := newTestController(true)
+	max := c.Concurrency()
+
+	// slow scrapes halve the concurrency
+	c.ObserveScrape(3*time.Second, nil)
+	c.adjust(0)
+	assert.Equal(t, max/2, c.Concurrency())
+
+	// healthy scrapes add one
+	c.ObserveScrape(100*time.Millisecond, nil)
+	c.adjust(0)
+	assert.Equal(t, max/2+1, c.Concurrency())
+
+	// failed scrapes above the error rate halve it
+	for range 9 {
+		c.ObserveScrape(100*time.Millisecond, nil)
+	}
+	c.ObserveScrape(100*time.Millisecond, errors.New("timeout"))
+	c.ObserveScrape(100*time.Millisecond, errors.New("timeout"))
+	c.adjust(0)
+	assert.Equal(t, (max/2+1)/2, c.Concurrency())
+
+	// a failing endpoint halves it even without scrapes, down to the lower bound
+	for range 10 {
+		c.adjust(1)
+	}
+	assert.Equal(t, 2, c.Concurrency())
+
+	// an idle interval keeps it
+	c.adjust(0)
+	assert.Equal(t, 2, c.Concurrency())
+
+	// never above MAX_CONCURRENT_REQUESTS
+	for range 100 {
+		c.ObserveScrape(100*time.Millisecond, nil)
+		c.adjust(0)
+	}
+	assert.Equal(t, max, c.Concurrency())
+}
+
+func TestController_Window(t *testing.T) {
+	c := newTestController(true)
+	high, low := c.Window()
+	assert.Equal(t, types.MaxInflightBlocks, high)
+	assert.Equal(t, types.MaxInflightBlocks/2, low)
+
+	// fast collects grow the window up to its upper bound
+	for range 10 {
+		c.ObserveCollect(10 * time.Millisecond)
+		c.adjust(0)
+	}
+	high, _ = c.Window()
+	assert.Equal(t, 120, high)
+
+	// slow collects halve it down to its lower bound
+	for range 10 {
+		c.ObserveCollect(time.Second)
+		c.adjust(0)
+	}
+	high, low = c.Window()
+	assert.Equal(t, 20, high)
+	assert.Equal(t, 10, low)
+}
@@ -0,0 +1,142 @@
+package indexer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/initia-labs/rollytics/indexer/extension"
+	"github.com/initia-labs/rollytics/indexer/leader"
+	"github.com/initia-labs/rollytics/indexer/scraper"
+)
+
+// drainPollInterval is how often a drain checks the in-flight blocks
+const drainPollInterval = 100 * time.Millisecond
+
+// Status is a snapshot of the indexer pipeline for the admin API
+type Status struct {
+	Role             leader.Role
+	Leading          bool
+	AdminPaused      bool
+	InitialSync      bool  // the tx rows are loaded with COPY until the head is reached
+	RestoringIndexes bool  // the indexes deferred by the initial sync are being recreated
+	Paused           bool  // scraping is paused, by backpressure or the admin
+	Height           int64 // next height to collect
+	BlockMapSize     int
+	BlockMapMin      int64 // lowest prepared height waiting to be collected, 0 when empty
+	BlockMapMax      int64
+	MaxInflight      int // inflight blocks above which scraping pauses
+	PrepareCount     int
+	BlockChanLen     int
+	Scraper          scraper.Status
+	Extensions       []ExtensionStatus
+}
+
+// ExtensionStatus is the supervision state of an enabled extension in this process
+type ExtensionStatus struct {
+	Name   string
+	Health extension.Health
+	Known  bool // false when the extension was never supervised by this process
+}
+
+// Drained reports whether no block is in flight between the scraper and the database
+func (s Status) Drained() bool {
+	return s.BlockMapSize == 0 && s.PrepareCount == 0 && s.BlockChanLen == 0 && s.Scraper.InFlight == 0
+}
+
+// Pause holds the scraping until Resume, the blocks in flight are still collected
+func (i *Indexer) Pause() {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+
+	if !i.adminPaused {
+		i.logger.Info("indexer paused by admin")
+	}
+	i.adminPaused = true
+	if i.leading && !i.paused {
+		i.controlChan <- "pause"
+		i.paused = true
+	}
+}
+
+// Resume releases the pause of the admin, collect starts the scraping again once the backpressure allows
+func (i *Indexer) Resume() {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+
+	if i.adminPaused {
+		i.logger.Info("indexer resumed by admin")
+	}
+	i.adminPaused = false
+	// wake up collect waiting for blocks, which were held back by the pause
+	i.cond.Broadcast()
+}
+
+// Drain pauses the scraping and waits until every block in flight is collected or the context
+// is done, returning the last status and whether the pipeline drained
+func (i *Indexer) Drain(ctx context.Context) (Status, bool) {
+	i.Pause()
+
+	for {
+		status := i.Status()
+		if status.Drained() {
+			i.logger.Info("indexer drained", slog.Int64("height", status.Height))
+			return status, true
+		}
+
+		select {
+		case <-ctx.Done():
+			i.logger.Warn("indexer drain timed out",
+				slog.Int64("height", status.Height),
+				slog.Int("block_map_size", status.BlockMapSize),
+				slog.Int("prepare_count", status.PrepareCount),
+				slog.Int64("scraping", status.Scraper.InFlight))
+			return status, false
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// Status returns a snapshot of the pipeline and the extensions
+func (i *Indexer) Status() Status {
+	i.mtx.Lock()
+	status := Status{
+		Role:         leader.RoleOf(leader.WorkloadIndexer),
+		Leading:      i.leading,
+		AdminPaused:  i.adminPaused,
+		Paused:       i.paused,
+		Height:       i.height,
+		BlockMapSize: len(i.blockMap),
+		PrepareCount: i.prepareCount,
+		BlockChanLen: len(i.blockChan),
+	}
+	for height := range i.blockMap {
+		if status.BlockMapMin == 0 || height < status.BlockMapMin {
+			status.BlockMapMin = height
+		}
+		status.BlockMapMax = max(status.BlockMapMax, height)
+	}
+	i.mtx.Unlock()
+	status.InitialSync = i.initialSync.Load()
+	status.RestoringIndexes = i.restoringIndexes.Load()
+	status.MaxInflight, _ = i.adaptive.Window()
+
+	if status.Leading {
+		status.Scraper = i.scraper.Status()
+	}
+	for _, name := range i.extensionManager.Names() {
+		health, ok := extension.HealthOf(name)
+		status.Extensions = append(status.Extensions, ExtensionStatus{Name: name, Health: health, Known: ok})
+	}
+	return status
+}
+
+// PauseExtension stops the extension until it is resumed
+func (i *Indexer) PauseExtension(name string) error {
+	return i.extensionManager.PauseExtension(name)
+}
+
+// ResumeExtension runs the paused extension again
+func (i *Indexer) ResumeExtension(name string) error {
+	return i.extensionManager.ResumeExtension(name)
+}
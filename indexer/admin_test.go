@@ -0,0 +1,99 @@
+package indexer
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/adaptive"
+	"github.com/initia-labs/rollytics/indexer/extension"
+	"github.com/initia-labs/rollytics/indexer/scraper"
+	indexertypes "github.com/initia-labs/rollytics/indexer/types"
+	"github.com/initia-labs/rollytics/types"
+)
+
+func newTestIndexer(leading bool) *Indexer {
+	cfg := &config.Config{}
+	cfg.SetChainConfig(&config.ChainConfig{})
+	logger := slog.New(slog.DiscardHandler)
+	controller := adaptive.New(cfg, logger)
+	i := &Indexer{
+		logger:           logger,
+		scraper:          scraper.New(cfg, logger, controller),
+		adaptive:         controller,
+		extensionManager: &extension.ExtensionManager{},
+		blockMap:         make(map[int64]indexertypes.ScrapedBlock),
+		blockChan:        make(chan indexertypes.ScrapedBlock, types.MaxInflightBlocks),
+		controlChan:      make(chan string, 1),
+		leading:          leading,
+	}
+	i.cond = sync.NewCond(&i.mtx)
+	return i
+}
+
+func TestPauseResume(t *testing.T) {
+	i := newTestIndexer(true)
+
+	i.Pause()
+	assert.Equal(t, "pause", <-i.controlChan)
+	assert.True(t, i.paused)
+	assert.True(t, i.adminPaused)
+
+	// pausing twice signals once
+	i.Pause()
+	assert.Empty(t, i.controlChan)
+
+	// collect starts the scraping again, not the resume
+	i.Resume()
+	assert.False(t, i.adminPaused)
+	assert.True(t, i.paused)
+	assert.Empty(t, i.controlChan)
+}
+
+func TestPause_NotLeading(t *testing.T) {
+	i := newTestIndexer(false)
+
+	i.Pause()
+	i.Resume()
+	i.Pause()
+	assert.Empty(t, i.controlChan)
+	assert.False(t, i.paused)
+	assert.True(t, i.adminPaused)
+}
+
+func TestDrain(t *testing.T) {
+	i := newTestIndexer(true)
+	i.height = 10
+	i.blockMap[10] = indexertypes.ScrapedBlock{Height: 10}
+	i.blockMap[12] = indexertypes.ScrapedBlock{Height: 12}
+
+	status := i.Status()
+	assert.Equal(t, 2, status.BlockMapSize)
+	assert.Equal(t, int64(10), status.BlockMapMin)
+	assert.Equal(t, int64(12), status.BlockMapMax)
+	assert.False(t, status.Drained())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	status, drained := i.Drain(ctx)
+	assert.False(t, drained)
+	assert.True(t, status.AdminPaused)
+	assert.Equal(t, "pause", <-i.controlChan)
+
+	// collect the blocks in flight
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		i.mtx.Lock()
+		i.blockMap = make(map[int64]indexertypes.ScrapedBlock)
+		i.mtx.Unlock()
+	}()
+	status, drained = i.Drain(context.Background())
+	require.True(t, drained)
+	assert.Equal(t, 0, status.BlockMapSize)
+}
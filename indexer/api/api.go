@@ -9,6 +9,7 @@ import (
 
 	"github.com/initia-labs/rollytics/config"
 	"github.com/initia-labs/rollytics/indexer/api/handler"
+	"github.com/initia-labs/rollytics/indexer/api/handler/admin"
 	"github.com/initia-labs/rollytics/orm"
 )
 
@@ -19,7 +20,8 @@ type Api struct {
 	db     *orm.Database
 }
 
-func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *Api {
+// New creates the indexer API, the admin routes drive the controller when INDEXER_ADMIN_TOKEN is set
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database, controller admin.Controller) *Api {
 	app := fiber.New(fiber.Config{
 		AppName:               "Rollytics Indexer API",
 		DisableStartupMessage: true,
@@ -27,7 +29,7 @@ func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *Api {
 		ReadBufferSize:        int(cfg.GetRecvBufferSize()),
 	})
 
-	handler.Register(app, db, cfg, logger)
+	handler.Register(app, db, cfg, logger, controller)
 
 	return &Api{
 		app:    app,
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer"
+	"github.com/initia-labs/rollytics/indexer/extension"
+	"github.com/initia-labs/rollytics/util/common-handler/common"
+)
+
+// HeaderAdminToken carries the admin token of the indexer admin API
+const HeaderAdminToken = "X-Admin-Token"
+
+// Controller is the part of the indexer driven by the admin API
+type Controller interface {
+	Pause()
+	Resume()
+	Drain(ctx context.Context) (indexer.Status, bool)
+	Status() indexer.Status
+	PauseExtension(name string) error
+	ResumeExtension(name string) error
+}
+
+type AdminHandler struct {
+	cfg        *config.Config
+	controller Controller
+}
+
+var _ common.HandlerRegistrar = (*AdminHandler)(nil)
+
+func NewAdminHandler(cfg *config.Config, controller Controller) *AdminHandler {
+	return &AdminHandler{
+		cfg:        cfg,
+		controller: controller,
+	}
+}
+
+// Register adds the admin routes when an admin token is configured
+func (h *AdminHandler) Register(router fiber.Router) {
+	if !h.cfg.AdminEnabled() || h.controller == nil {
+		return
+	}
+
+	admin := router.Group("/admin", h.requireAdminToken)
+	admin.Get("/status", h.GetStatus)
+	admin.Post("/pause", h.Pause)
+	admin.Post("/resume", h.Resume)
+	admin.Post("/drain", h.Drain)
+	admin.Post("/extensions/:name/pause", h.PauseExtension)
+	admin.Post("/extensions/:name/resume", h.ResumeExtension)
+}
+
+func (h *AdminHandler) requireAdminToken(c *fiber.Ctx) error {
+	token := c.Get(HeaderAdminToken)
+	expected := h.cfg.GetAdminConfig().Token
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid admin token")
+	}
+	return c.Next()
+}
+
+// GetStatus handles GET /admin/status
+func (h *AdminHandler) GetStatus(c *fiber.Ctx) error {
+	return c.JSON(ToStatusResponse(h.controller.Status()))
+}
+
+// Pause handles POST /admin/pause
+func (h *AdminHandler) Pause(c *fiber.Ctx) error {
+	h.controller.Pause()
+	return c.JSON(ToStatusResponse(h.controller.Status()))
+}
+
+// Resume handles POST /admin/resume
+func (h *AdminHandler) Resume(c *fiber.Ctx) error {
+	h.controller.Resume()
+	return c.JSON(ToStatusResponse(h.controller.Status()))
+}
+
+// Drain handles POST /admin/drain, pausing the scraping and waiting for the blocks in flight.
+// The optional timeout query overrides INDEXER_ADMIN_DRAIN_TIMEOUT.
+func (h *AdminHandler) Drain(c *fiber.Ctx) error {
+	timeout := h.cfg.GetAdminConfig().DrainTimeout
+	if value := c.Query("timeout"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "timeout must be a positive duration")
+		}
+		timeout = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+	defer cancel()
+
+	status, drained := h.controller.Drain(ctx)
+	return c.JSON(DrainResponse{
+		Drained: drained,
+		Status:  ToStatusResponse(status),
+	})
+}
+
+// PauseExtension handles POST /admin/extensions/:name/pause
+func (h *AdminHandler) PauseExtension(c *fiber.Ctx) error {
+	if err := h.controller.PauseExtension(c.Params("name")); err != nil {
+		return extensionError(err)
+	}
+	return c.JSON(ToStatusResponse(h.controller.Status()))
+}
+
+// ResumeExtension handles POST /admin/extensions/:name/resume
+func (h *AdminHandler) ResumeExtension(c *fiber.Ctx) error {
+	if err := h.controller.ResumeExtension(c.Params("name")); err != nil {
+		return extensionError(err)
+	}
+	return c.JSON(ToStatusResponse(h.controller.Status()))
+}
+
+func extensionError(err error) error {
+	if errors.Is(err, extension.ErrUnknownExtension) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
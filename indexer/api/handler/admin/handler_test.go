@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer"
+	"github.com/initia-labs/rollytics/indexer/extension"
+)
+
+type fakeController struct {
+	status       indexer.Status
+	drained      bool
+	drainTimeout time.Duration
+}
+
+func (f *fakeController) Pause()  { f.status.AdminPaused = true }
+func (f *fakeController) Resume() { f.status.AdminPaused = false }
+
+func (f *fakeController) Drain(ctx context.Context) (indexer.Status, bool) {
+	deadline, _ := ctx.Deadline()
+	f.drainTimeout = time.Until(deadline).Round(time.Second)
+	f.status.AdminPaused = true
+	return f.status, f.drained
+}
+
+func (f *fakeController) Status() indexer.Status { return f.status }
+
+func (f *fakeController) PauseExtension(name string) error {
+	return f.setExtension(name, extension.StatePaused)
+}
+
+func (f *fakeController) ResumeExtension(name string) error {
+	return f.setExtension(name, extension.StateRunning)
+}
+
+func (f *fakeController) setExtension(name string, state extension.State) error {
+	for i, ext := range f.status.Extensions {
+		if ext.Name == name {
+			f.status.Extensions[i].Health.State = state
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", extension.ErrUnknownExtension, name)
+}
+
+func newTestApp(token string, controller Controller) *fiber.App {
+	cfg := &config.Config{}
+	cfg.SetAdminConfig(&config.AdminConfig{Token: token, DrainTimeout: time.Minute})
+	app := fiber.New()
+	NewAdminHandler(cfg, controller).Register(app)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, token string, out any) int {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set(HeaderAdminToken, token)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	if out != nil && resp.StatusCode == fiber.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestAdmin_Disabled(t *testing.T) {
+	app := newTestApp("", &fakeController{})
+	assert.Equal(t, fiber.StatusNotFound, doRequest(t, app, "GET", "/admin/status", "secret", nil))
+}
+
+func TestAdmin_Unauthorized(t *testing.T) {
+	app := newTestApp("secret", &fakeController{})
+	assert.Equal(t, fiber.StatusUnauthorized, doRequest(t, app, "GET", "/admin/status", "", nil))
+	assert.Equal(t, fiber.StatusUnauthorized, doRequest(t, app, "POST", "/admin/pause", "wrong", nil))
+}
+
+func TestAdmin_PauseResume(t *testing.T) {
+	controller := &fakeController{status: indexer.Status{Height: 42}}
+	app := newTestApp("secret", controller)
+
+	var res StatusResponse
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "POST", "/admin/pause", "secret", &res))
+	assert.True(t, res.AdminPaused)
+	assert.Equal(t, int64(42), res.Height)
+
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "POST", "/admin/resume", "secret", &res))
+	assert.False(t, res.AdminPaused)
+}
+
+func TestAdmin_Drain(t *testing.T) {
+	controller := &fakeController{drained: true}
+	app := newTestApp("secret", controller)
+
+	var res DrainResponse
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "POST", "/admin/drain", "secret", &res))
+	assert.True(t, res.Drained)
+	assert.True(t, res.Status.AdminPaused)
+	assert.Equal(t, time.Minute, controller.drainTimeout)
+
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "POST", "/admin/drain?timeout=5s", "secret", &res))
+	assert.Equal(t, 5*time.Second, controller.drainTimeout)
+
+	assert.Equal(t, fiber.StatusBadRequest, doRequest(t, app, "POST", "/admin/drain?timeout=soon", "secret", nil))
+}
+
+func TestAdmin_Extensions(t *testing.T) {
+	controller := &fakeController{status: indexer.Status{
+		Extensions: []indexer.ExtensionStatus{
+			{Name: "rich-list", Known: true, Health: extension.Health{State: extension.StateRunning}},
+			{Name: "prune"},
+		},
+	}}
+	app := newTestApp("secret", controller)
+
+	var res StatusResponse
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "POST", "/admin/extensions/rich-list/pause", "secret", &res))
+	require.Len(t, res.Extensions, 2)
+	assert.Equal(t, "paused", res.Extensions[0].State)
+	// an extension never supervised by this process has no state
+	assert.Empty(t, res.Extensions[1].State)
+
+	require.Equal(t, fiber.StatusOK, doRequest(t, app, "POST", "/admin/extensions/rich-list/resume", "secret", &res))
+	assert.Equal(t, "running", res.Extensions[0].State)
+
+	assert.Equal(t, fiber.StatusNotFound, doRequest(t, app, "POST", "/admin/extensions/unknown/pause", "secret", nil))
+}
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"github.com/initia-labs/rollytics/indexer"
+)
+
+type StatusResponse struct {
+	Role             string              `json:"role,omitempty"`
+	Leading          bool                `json:"leading"`
+	AdminPaused      bool                `json:"admin_paused"`
+	InitialSync      bool                `json:"initial_sync"`
+	RestoringIndexes bool                `json:"restoring_indexes"`
+	Paused           bool                `json:"paused"`
+	Height           int64               `json:"height"`
+	BlockMap         BlockMapStatus      `json:"block_map"`
+	MaxInflight      int                 `json:"max_inflight"`
+	PrepareCount     int                 `json:"prepare_count"`
+	BlockChanLen     int                 `json:"block_chan_len"`
+	Scraper          ScraperStatus       `json:"scraper"`
+	Extensions       []ExtensionResponse `json:"extensions"`
+}
+
+type BlockMapStatus struct {
+	Size int   `json:"size"`
+	Min  int64 `json:"min,omitempty"`
+	Max  int64 `json:"max,omitempty"`
+}
+
+type ScraperStatus struct {
+	Mode        string `json:"mode,omitempty"`
+	Paused      bool   `json:"paused"`
+	InFlight    int64  `json:"in_flight"`
+	Concurrency int    `json:"concurrency,omitempty"`
+}
+
+type ExtensionResponse struct {
+	Name      string `json:"name"`
+	State     string `json:"state,omitempty"`
+	Restarts  int    `json:"restarts"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+type DrainResponse struct {
+	Drained bool           `json:"drained"`
+	Status  StatusResponse `json:"status"`
+}
+
+// ToStatusResponse converts the indexer status to the admin response
+func ToStatusResponse(status indexer.Status) StatusResponse {
+	res := StatusResponse{
+		Role:             string(status.Role),
+		Leading:          status.Leading,
+		AdminPaused:      status.AdminPaused,
+		InitialSync:      status.InitialSync,
+		RestoringIndexes: status.RestoringIndexes,
+		Paused:           status.Paused,
+		Height:           status.Height,
+		BlockMap: BlockMapStatus{
+			Size: status.BlockMapSize,
+			Min:  status.BlockMapMin,
+			Max:  status.BlockMapMax,
+		},
+		MaxInflight:  status.MaxInflight,
+		PrepareCount: status.PrepareCount,
+		BlockChanLen: status.BlockChanLen,
+		Scraper: ScraperStatus{
+			Mode:        string(status.Scraper.Mode),
+			Paused:      status.Scraper.Paused,
+			InFlight:    status.Scraper.InFlight,
+			Concurrency: status.Scraper.Concurrency,
+		},
+		Extensions: make([]ExtensionResponse, 0, len(status.Extensions)),
+	}
+	for _, ext := range status.Extensions {
+		extRes := ExtensionResponse{Name: ext.Name}
+		if ext.Known {
+			extRes.State = string(ext.Health.State)
+			extRes.Restarts = ext.Health.Restarts
+			extRes.LastError = ext.Health.LastError
+		}
+		res.Extensions = append(res.Extensions, extRes)
+	}
+	return res
+}
@@ -6,15 +6,17 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/api/handler/admin"
 	"github.com/initia-labs/rollytics/orm"
 	"github.com/initia-labs/rollytics/util/common-handler/common"
 	"github.com/initia-labs/rollytics/util/common-handler/status"
 )
 
-func Register(router fiber.Router, db *orm.Database, cfg *config.Config, logger *slog.Logger) {
+func Register(router fiber.Router, db *orm.Database, cfg *config.Config, logger *slog.Logger, controller admin.Controller) {
 	base := common.NewBaseHandler(db, cfg, logger)
 	handlers := []common.HandlerRegistrar{
-		status.NewStatusHandler(base),
+		status.NewStatusHandler(base, nil),
+		admin.NewAdminHandler(cfg, controller),
 	}
 
 	for _, handler := range handlers {
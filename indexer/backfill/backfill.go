@@ -0,0 +1,249 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/querier"
+)
+
+const (
+	// schemaPrefix names the staging schema of a shard
+	schemaPrefix = "backfill_"
+	// lookahead is how many blocks a worker scrapes and prepares ahead of its collect
+	lookahead = 16
+	// maxAttempts bounds the tries to scrape and prepare a block
+	maxAttempts = 5
+	// progressInterval is how many blocks a worker indexes between progress logs
+	progressInterval = 1000
+)
+
+// stagedTable is a table the staging collectors write into the schema of their shard
+type stagedTable struct {
+	name string
+	// seqInfo is the sequence of the rows, offset by the public sequence at merge; empty without sequence
+	seqInfo types.SeqInfoName
+	// skipConflicts merges the rows which may already be in the public table from another shard
+	skipConflicts bool
+}
+
+// stagedTables are merged in this order. seq_info is staged as well, and merged as the offsets.
+var stagedTables = []stagedTable{
+	{name: types.CollectedBlock{}.TableName()},
+	{name: types.CollectedFAStore{}.TableName(), skipConflicts: true},
+	{name: types.CollectedTx{}.TableName(), seqInfo: types.SeqInfoTx},
+	{name: types.CollectedTxAccount{}.TableName(), seqInfo: types.SeqInfoTx},
+	{name: types.CollectedTxMsgType{}.TableName(), seqInfo: types.SeqInfoTx},
+	{name: types.CollectedTxTypeTag{}.TableName(), seqInfo: types.SeqInfoTx},
+	{name: types.CollectedTxMsg{}.TableName(), seqInfo: types.SeqInfoTx},
+	{name: types.CollectedTxMoveCall{}.TableName(), seqInfo: types.SeqInfoTx},
+	{name: types.CollectedEvmTx{}.TableName(), seqInfo: types.SeqInfoEvmTx},
+	{name: types.CollectedEvmTxAccount{}.TableName(), seqInfo: types.SeqInfoEvmTx},
+}
+
+// seqInfos are the sequences assigned by the staging collectors
+var seqInfos = []types.SeqInfoName{types.SeqInfoTx, types.SeqInfoEvmTx}
+
+// Backfill indexes a height range with parallel workers. The range is split into shards, each
+// indexed by a worker into the staging tables of its own schema with sequences starting at zero.
+// The shards are then merged into the public tables in order, offsetting their sequences by the
+// public ones, so the range must follow the last indexed block and the indexer must be stopped.
+type Backfill struct {
+	cfg     *config.Config
+	logger  *slog.Logger
+	db      *orm.Database
+	from    int64
+	to      int64
+	workers int
+}
+
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database, from, to int64, workers int) (*Backfill, error) {
+	if from < 1 || to < from {
+		return nil, fmt.Errorf("invalid height range %d-%d", from, to)
+	}
+	if workers < 1 {
+		return nil, errors.New("workers must be positive")
+	}
+
+	return &Backfill{
+		cfg:     cfg,
+		logger:  logger.With("module", "backfill"),
+		db:      db,
+		from:    from,
+		to:      to,
+		workers: workers,
+	}, nil
+}
+
+// Run indexes the shards not staged yet, workers at a time, and merges each staged shard once the
+// shards before it are merged. An interrupted backfill resumes with the same range: the merged shards
+// are skipped and the others continue from their last staged block.
+func (b *Backfill) Run(ctx context.Context) error {
+	shards, err := b.plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	staged := make([]chan struct{}, len(shards))
+	sem := make(chan struct{}, b.workers)
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, shard := range shards {
+		staged[i] = make(chan struct{})
+		if shard.Staged || shard.Merged {
+			close(staged[i])
+			continue
+		}
+
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-gCtx.Done():
+				return gCtx.Err()
+			}
+			defer func() { <-sem }()
+
+			if err := b.index(gCtx, shard); err != nil {
+				return err
+			}
+			close(staged[i])
+			return nil
+		})
+	}
+
+	g.Go(func() error {
+		for i, shard := range shards {
+			if !shard.Merged {
+				select {
+				case <-staged[i]:
+				case <-gCtx.Done():
+					return gCtx.Err()
+				}
+				if err := b.merge(gCtx, shard); err != nil {
+					return err
+				}
+			}
+			// the schema outlives a merge interrupted before the drop
+			if err := b.dropStaging(gCtx, schemaOf(shard)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	b.logger.Info("backfill completed", slog.Int64("from_height", b.from), slog.Int64("to_height", b.to))
+	return nil
+}
+
+// plan returns the shards of the range, resuming the ones of an earlier run of the same range
+func (b *Backfill) plan(ctx context.Context) ([]types.CollectedBackfillShard, error) {
+	var shards []types.CollectedBackfillShard
+	if err := b.db.WithContext(ctx).Order("id").Find(&shards).Error; err != nil {
+		return nil, types.NewDatabaseError("get backfill shards", err)
+	}
+
+	if len(shards) > 0 {
+		from, to := shards[0].FromHeight, shards[len(shards)-1].ToHeight
+		if from == b.from && to == b.to {
+			b.logger.Info("resuming backfill", slog.Int64("from_height", from), slog.Int64("to_height", to), slog.Int("shards", len(shards)))
+			return shards, nil
+		}
+		for _, shard := range shards {
+			if !shard.Merged {
+				return nil, fmt.Errorf("backfill of heights %d-%d is in progress, run it again with the same range to resume", from, to)
+			}
+		}
+	}
+
+	last, err := lastIndexedHeight(b.db.WithContext(ctx), b.cfg.GetChainId())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFollows(last, b.from); err != nil {
+		return nil, err
+	}
+
+	chainHeight, err := querier.NewQuerier(b.cfg.GetChainConfig()).GetLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if b.to > chainHeight {
+		return nil, fmt.Errorf("height %d is above the chain height %d", b.to, chainHeight)
+	}
+
+	shards = splitRange(b.from, b.to, b.workers)
+	if err := b.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&types.CollectedBackfillShard{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&shards).Error
+	}); err != nil {
+		return nil, types.NewDatabaseError("create backfill shards", err)
+	}
+
+	b.logger.Info("starting backfill", slog.Int64("from_height", b.from), slog.Int64("to_height", b.to), slog.Int("shards", len(shards)))
+	return shards, nil
+}
+
+// splitRange splits the heights into at most count shards of consecutive heights, the first ones one
+// block larger when the heights do not split evenly
+func splitRange(from, to int64, count int) []types.CollectedBackfillShard {
+	total := to - from + 1
+	n := min(int64(count), total)
+	size, rest := total/n, total%n
+
+	shards := make([]types.CollectedBackfillShard, 0, n)
+	next := from
+	for i := int64(0); i < n; i++ {
+		end := next + size - 1
+		if i < rest {
+			end++
+		}
+		shards = append(shards, types.CollectedBackfillShard{Id: int(i), FromHeight: next, ToHeight: end})
+		next = end + 1
+	}
+	return shards
+}
+
+// lastIndexedHeight returns the height of the last public block, 0 without blocks
+func lastIndexedHeight(tx *gorm.DB, chainId string) (int64, error) {
+	var last int64
+	if err := tx.Model(&types.CollectedBlock{}).
+		Where("chain_id = ?", chainId).
+		Select("COALESCE(MAX(height), 0)").
+		Scan(&last).Error; err != nil {
+		return 0, types.NewDatabaseError("get last block", err)
+	}
+	return last, nil
+}
+
+// checkFollows checks that the heights from the given one follow the last indexed block, since the
+// sequences are assigned in the order of the heights
+func checkFollows(last, from int64) error {
+	if last >= from {
+		return fmt.Errorf("heights up to %d are already indexed, the backfill must start after them", last)
+	}
+	if last > 0 && last+1 < from {
+		return fmt.Errorf("heights %d-%d are not indexed, the backfill must start right after the last indexed block", last+1, from-1)
+	}
+	return nil
+}
+
+func schemaOf(shard types.CollectedBackfillShard) string {
+	return fmt.Sprintf("%s%d", schemaPrefix, shard.Id)
+}
+
+func quote(parts ...string) string {
+	return pgx.Identifier(parts).Sanitize()
+}
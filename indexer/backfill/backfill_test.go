@@ -0,0 +1,108 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	indexertypes "github.com/initia-labs/rollytics/indexer/types"
+	"github.com/initia-labs/rollytics/types"
+)
+
+func TestSplitRange(t *testing.T) {
+	shards := splitRange(101, 110, 3)
+	require.Len(t, shards, 3)
+	assert.Equal(t, types.CollectedBackfillShard{Id: 0, FromHeight: 101, ToHeight: 104}, shards[0])
+	assert.Equal(t, types.CollectedBackfillShard{Id: 1, FromHeight: 105, ToHeight: 107}, shards[1])
+	assert.Equal(t, types.CollectedBackfillShard{Id: 2, FromHeight: 108, ToHeight: 110}, shards[2])
+
+	// no more shards than heights
+	shards = splitRange(5, 6, 4)
+	require.Len(t, shards, 2)
+	assert.Equal(t, int64(5), shards[0].ToHeight)
+	assert.Equal(t, int64(6), shards[1].FromHeight)
+}
+
+func TestCheckFollows(t *testing.T) {
+	assert.NoError(t, checkFollows(0, 1))
+	assert.NoError(t, checkFollows(0, 1000)) // starting mid-chain
+	assert.NoError(t, checkFollows(99, 100))
+	assert.ErrorContains(t, checkFollows(100, 100), "already indexed")
+	assert.ErrorContains(t, checkFollows(98, 100), "heights 99-99 are not indexed")
+}
+
+func TestMergeSQL(t *testing.T) {
+	tx := stagedTable{name: "tx", seqInfo: types.SeqInfoTx}
+	assert.Equal(t,
+		`INSERT INTO "public"."tx" ("hash", "height", "sequence", "data") SELECT "hash", "height", "sequence" + 42, "data" FROM "backfill_3"."tx"`,
+		mergeSQL("backfill_3", tx, []string{"hash", "height", "sequence", "data"}, 42))
+
+	block := stagedTable{name: "block"}
+	assert.Equal(t,
+		`INSERT INTO "public"."block" ("chain_id", "height") SELECT "chain_id", "height" FROM "backfill_0"."block"`,
+		mergeSQL("backfill_0", block, []string{"chain_id", "height"}, 0))
+
+	faStore := stagedTable{name: "fa_store", skipConflicts: true}
+	assert.Equal(t,
+		`INSERT INTO "public"."fa_store" ("store_addr", "owner") SELECT "store_addr", "owner" FROM "backfill_1"."fa_store" ON CONFLICT DO NOTHING`,
+		mergeSQL("backfill_1", faStore, []string{"store_addr", "owner"}, 7))
+}
+
+// nftSubmodule stores an nft per block, failing the collect of a block it did not prepare
+type nftSubmodule struct {
+	mtx      sync.Mutex
+	prepared map[int64]bool
+}
+
+func (s *nftSubmodule) Name() string { return "nft" }
+
+func (s *nftSubmodule) Prepare(_ context.Context, block indexertypes.ScrapedBlock) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.prepared[block.Height] = true
+	return nil
+}
+
+func (s *nftSubmodule) Collect(block indexertypes.ScrapedBlock, tx *gorm.DB) error {
+	s.mtx.Lock()
+	prepared := s.prepared[block.Height]
+	s.mtx.Unlock()
+	if !prepared {
+		return errors.New("data is not prepared")
+	}
+	return tx.Create(&types.CollectedNft{
+		CollectionAddr: []byte("collection"),
+		TokenId:        fmt.Sprintf("%d", block.Height),
+		Height:         block.Height,
+	}).Error
+}
+
+func TestReplayNft(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	// sqlite has no hash indexes
+	require.NoError(t, db.Exec("CREATE TABLE nft (collection_addr blob, token_id text, addr blob, height integer, timestamp datetime, owner_id integer, uri text)").Error)
+
+	b := &Backfill{logger: slog.Default()}
+	scrape := func(_ context.Context, height int64) (indexertypes.ScrapedBlock, error) {
+		return indexertypes.ScrapedBlock{Height: height}, nil
+	}
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return b.replayNft(context.Background(), tx, &nftSubmodule{prepared: map[int64]bool{}}, scrape, 101, 140)
+	}))
+
+	var heights []int64
+	require.NoError(t, db.Model(&types.CollectedNft{}).Order("height").Pluck("height", &heights).Error)
+	require.Len(t, heights, 40, "nft tables of the backfilled range are empty")
+	assert.Equal(t, int64(101), heights[0])
+	assert.Equal(t, int64(140), heights[39])
+
+}
@@ -0,0 +1,161 @@
+package backfill
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/indexer/collector"
+	indexertypes "github.com/initia-labs/rollytics/indexer/types"
+	indexerutil "github.com/initia-labs/rollytics/indexer/util"
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/orm/partition"
+	"github.com/initia-labs/rollytics/types"
+)
+
+// merge moves the staged rows of the shard into the public tables in one transaction, assigning the
+// final sequences after the public ones
+func (b *Backfill) merge(ctx context.Context, shard types.CollectedBackfillShard) error {
+	schema := schemaOf(shard)
+	partitionCfg := b.cfg.GetPartitionConfig()
+	client := fiber.AcquireClient()
+	defer fiber.ReleaseClient(client)
+
+	err := b.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		last, err := lastIndexedHeight(tx, b.cfg.GetChainId())
+		if err != nil {
+			return err
+		}
+		if err := checkFollows(last, shard.FromHeight); err != nil {
+			return fmt.Errorf("failed to merge shard %d: %w", shard.Id, err)
+		}
+
+		// the staged sequences start at zero, the public ones continue after the offsets
+		offsets := make(map[types.SeqInfoName]int64, len(seqInfos))
+		for _, name := range seqInfos {
+			seqInfo, err := indexerutil.GetSeqInfo(name, tx)
+			if err != nil {
+				return err
+			}
+			var staged types.CollectedSeqInfo
+			if err := tx.Table(schema+"."+seqInfo.TableName()).Where("name = ?", name).Limit(1).Find(&staged).Error; err != nil {
+				return types.NewDatabaseError("get staged sequence info", err)
+			}
+
+			offsets[name] = seqInfo.Sequence
+			if staged.Sequence == 0 {
+				continue
+			}
+			seqInfo.Sequence += staged.Sequence
+			if err := tx.Clauses(orm.UpdateAllWhenConflict).Create(&seqInfo).Error; err != nil {
+				return err
+			}
+
+			// keep the merged rows out of the default partition
+			for _, table := range partition.Tables {
+				if table.SeqInfo != name {
+					continue
+				}
+				partitioned, err := partition.IsPartitioned(ctx, tx, table.Name)
+				if err != nil {
+					return err
+				}
+				if !partitioned {
+					continue
+				}
+				if _, err := partition.Ensure(ctx, tx, table.Name, partitionCfg.Size, seqInfo.Sequence); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, table := range stagedTables {
+			columns, err := tableColumns(tx, table.name)
+			if err != nil {
+				return err
+			}
+			if err := tx.Exec(mergeSQL(schema, table, columns, offsets[table.seqInfo])).Error; err != nil {
+				return types.NewDatabaseError("merge "+table.name, err)
+			}
+		}
+
+		// the block time of the first block was left out, the block before it was not staged
+		if err := tx.Exec(`UPDATE block AS b SET block_time = FLOOR(EXTRACT(EPOCH FROM b.timestamp - p.timestamp) * 1000)::bigint
+			FROM block AS p
+			WHERE b.chain_id = ? AND b.height = ? AND p.chain_id = b.chain_id AND p.height = b.height - 1`,
+			b.cfg.GetChainId(), shard.FromHeight).Error; err != nil {
+			return types.NewDatabaseError("update block time", err)
+		}
+
+		if err := b.replayNft(ctx, tx, collector.NewNft(b.cfg, b.logger), b.scraper(client), shard.FromHeight, shard.ToHeight); err != nil {
+			return fmt.Errorf("failed to merge shard %d: %w", shard.Id, err)
+		}
+
+		return tx.Model(&types.CollectedBackfillShard{}).Where("id = ?", shard.Id).Update("merged", true).Error
+	}, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+
+	b.logger.Info("merged shard",
+		slog.Int("shard", shard.Id),
+		slog.Int64("from_height", shard.FromHeight),
+		slog.Int64("to_height", shard.ToHeight))
+	return nil
+}
+
+// replayNft collects the nft submodule over the merged blocks in height order, in the merge
+// transaction. The nft rows depend on the ones of the blocks before, so the staging collectors leave
+// them out.
+func (b *Backfill) replayNft(ctx context.Context, tx *gorm.DB, nft indexertypes.Submodule, scrape scrapeFunc, from, to int64) error {
+	if nft == nil {
+		return nil
+	}
+	return b.pipeline(ctx, nft, scrape, from, to, func(block indexertypes.ScrapedBlock) error {
+		if err := nft.Collect(block, tx); err != nil {
+			return fmt.Errorf("failed to collect nfts of block %d: %w", block.Height, err)
+		}
+		return nil
+	})
+}
+
+// tableColumns returns the columns of the public table in their order
+func tableColumns(tx *gorm.DB, table string) ([]string, error) {
+	var columns []string
+	if err := tx.Raw(`SELECT column_name FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = ?
+		ORDER BY ordinal_position`, table).Scan(&columns).Error; err != nil {
+		return nil, types.NewDatabaseError("get columns of "+table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	return columns, nil
+}
+
+// mergeSQL returns the statement copying the staged rows of the table into the public one, with the
+// sequences offset
+func mergeSQL(schema string, table stagedTable, columns []string, offset int64) string {
+	names := make([]string, 0, len(columns))
+	values := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, quote(column))
+		if table.seqInfo != "" && column == partition.Key {
+			values = append(values, fmt.Sprintf("%s + %d", quote(column), offset))
+			continue
+		}
+		values = append(values, quote(column))
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+		quote("public", table.name), strings.Join(names, ", "), strings.Join(values, ", "), quote(schema, table.name))
+	if table.skipConflicts {
+		stmt += " ON CONFLICT DO NOTHING"
+	}
+	return stmt
+}
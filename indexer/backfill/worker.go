@@ -0,0 +1,174 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/indexer/collector"
+	"github.com/initia-labs/rollytics/indexer/scraper"
+	indexertypes "github.com/initia-labs/rollytics/indexer/types"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/querier"
+)
+
+// prepared is a scraped block prepared for the collect, or the error to get it
+type prepared struct {
+	block indexertypes.ScrapedBlock
+	err   error
+}
+
+// index collects the heights of the shard into its staging schema, from the block after the last one
+// staged by an earlier run. The blocks are scraped and prepared ahead and collected in order.
+func (b *Backfill) index(ctx context.Context, shard types.CollectedBackfillShard) error {
+	schema := schemaOf(shard)
+	if err := b.createStaging(ctx, schema); err != nil {
+		return err
+	}
+
+	var last int64
+	if err := b.db.WithContext(ctx).
+		Raw("SELECT COALESCE(MAX(height), 0) FROM " + quote(schema, types.CollectedBlock{}.TableName())).
+		Scan(&last).Error; err != nil {
+		return types.NewDatabaseError("get last staged block", err)
+	}
+	next := max(shard.FromHeight, last+1)
+	b.logger.Info("indexing shard",
+		slog.Int("shard", shard.Id),
+		slog.Int64("from_height", shard.FromHeight),
+		slog.Int64("to_height", shard.ToHeight),
+		slog.Int64("next_height", next))
+
+	c := collector.NewStaging(b.cfg, b.logger, b.db, schema, shard.FromHeight)
+	client := fiber.AcquireClient()
+	defer fiber.ReleaseClient(client)
+
+	if err := b.pipeline(ctx, c, b.scraper(client), next, shard.ToHeight, func(block indexertypes.ScrapedBlock) error {
+		if err := c.Collect(ctx, block); err != nil {
+			return fmt.Errorf("failed to collect block %d of shard %d: %w", block.Height, shard.Id, err)
+		}
+		if (block.Height-shard.FromHeight+1)%progressInterval == 0 {
+			b.logger.Info("indexed shard blocks", slog.Int("shard", shard.Id), slog.Int64("height", block.Height), slog.Int64("to_height", shard.ToHeight))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := b.db.WithContext(ctx).Model(&types.CollectedBackfillShard{}).
+		Where("id = ?", shard.Id).
+		Update("staged", true).Error; err != nil {
+		return types.NewDatabaseError("update backfill shard", err)
+	}
+	b.logger.Info("staged shard", slog.Int("shard", shard.Id))
+	return nil
+}
+
+// scrapeFunc scrapes the block of a height
+type scrapeFunc func(ctx context.Context, height int64) (indexertypes.ScrapedBlock, error)
+
+// preparer prepares a scraped block for its collect, a collector or a submodule
+type preparer interface {
+	Prepare(ctx context.Context, block indexertypes.ScrapedBlock) error
+}
+
+// scraper returns the scrapeFunc of the chain, with the given client
+func (b *Backfill) scraper(client *fiber.Client) scrapeFunc {
+	q := querier.NewQuerier(b.cfg.GetChainConfig())
+	return func(ctx context.Context, height int64) (indexertypes.ScrapedBlock, error) {
+		return scraper.ScrapeBlock(ctx, client, height, b.cfg, q)
+	}
+}
+
+// pipeline scrapes and prepares the blocks of the heights ahead, and passes them to fn in order
+func (b *Backfill) pipeline(ctx context.Context, p preparer, scrape scrapeFunc, from, to int64, fn func(block indexertypes.ScrapedBlock) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the capacity bounds the blocks prepared ahead of fn
+	results := make(chan chan prepared, lookahead)
+	go func() {
+		defer close(results)
+		for height := from; height <= to; height++ {
+			res := make(chan prepared, 1)
+			select {
+			case results <- res:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				block, err := b.prepare(ctx, p, scrape, height)
+				res <- prepared{block: block, err: err}
+			}()
+		}
+	}()
+
+	for res := range results {
+		r := <-res
+		if r.err != nil {
+			return r.err
+		}
+		if err := fn(r.block); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// prepare scrapes the block and prepares it, retrying a few times
+func (b *Backfill) prepare(ctx context.Context, p preparer, scrape scrapeFunc, height int64) (indexertypes.ScrapedBlock, error) {
+	for attempt := 1; ; attempt++ {
+		block, err := scrape(ctx, height)
+		if err == nil {
+			if err = p.Prepare(ctx, block); err == nil {
+				return block, nil
+			}
+		}
+		if ctx.Err() != nil {
+			return block, ctx.Err()
+		}
+		if attempt == maxAttempts {
+			return block, fmt.Errorf("failed to prepare block %d: %w", height, err)
+		}
+
+		b.logger.Warn("failed to prepare block, retrying", slog.Int64("height", height), slog.Int("attempt", attempt), slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return block, ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+	}
+}
+
+// createStaging creates the staging schema with the staged tables shaped like the public ones
+func (b *Backfill) createStaging(ctx context.Context, schema string) error {
+	tables := []string{types.CollectedSeqInfo{}.TableName()}
+	for _, table := range stagedTables {
+		tables = append(tables, table.name)
+	}
+
+	return b.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE SCHEMA IF NOT EXISTS " + quote(schema)).Error; err != nil {
+			return types.NewDatabaseError("create staging schema", err)
+		}
+		for _, table := range tables {
+			if err := tx.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (LIKE %s INCLUDING ALL)",
+				quote(schema, table), quote("public", table))).Error; err != nil {
+				return types.NewDatabaseError("create staging table", err)
+			}
+		}
+		return nil
+	})
+}
+
+// dropStaging drops the staging schema of a merged shard
+func (b *Backfill) dropStaging(ctx context.Context, schema string) error {
+	if err := b.db.WithContext(ctx).Exec("DROP SCHEMA IF EXISTS " + quote(schema) + " CASCADE").Error; err != nil {
+		return types.NewDatabaseError("drop staging schema", err)
+	}
+	return nil
+}
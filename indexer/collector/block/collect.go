@@ -1,6 +1,7 @@
 package block
 
 import (
+	"errors"
 	"log/slog"
 
 	"gorm.io/gorm"
@@ -11,6 +12,10 @@ import (
 	"github.com/initia-labs/rollytics/util"
 )
 
+// ErrAlreadyIndexed is returned when the block was indexed by a concurrent collect, whose
+// transaction must then be rolled back
+var ErrAlreadyIndexed = errors.New("block already indexed")
+
 func (sub *BlockSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) (err error) {
 	hashBytes, err := util.HexToBytes(block.Hash)
 	if err != nil {
@@ -55,5 +60,13 @@ func (sub *BlockSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB)
 		cb.GasWanted += res.GasWanted
 	}
 
-	return tx.Clauses(orm.DoNothingWhenConflict).Create(&cb).Error
+	// the block row guards the rows of its txs, which the partitioned tables no longer keep unique
+	res := tx.Clauses(orm.DoNothingWhenConflict).Create(&cb)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrAlreadyIndexed
+	}
+	return nil
 }
@@ -3,8 +3,10 @@ package collector
 import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/std"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/initia-labs/initia/app/params"
 	cryptocodec "github.com/initia-labs/initia/crypto/codec"
+	evmtypes "github.com/initia-labs/minievm/x/evm/types"
 )
 
 func getCodec() codec.Codec {
@@ -13,5 +15,8 @@ func getCodec() codec.Codec {
 	std.RegisterInterfaces(encodingConfig.InterfaceRegistry)
 	cryptocodec.RegisterLegacyAminoCodec(encodingConfig.Amino)
 	cryptocodec.RegisterInterfaces(encodingConfig.InterfaceRegistry)
+	// msgs decoded into the structured fields of tx_msg
+	banktypes.RegisterInterfaces(encodingConfig.InterfaceRegistry)
+	evmtypes.RegisterInterfaces(encodingConfig.InterfaceRegistry)
 	return encodingConfig.Codec
 }
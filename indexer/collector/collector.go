@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
@@ -15,41 +17,77 @@ import (
 	"github.com/initia-labs/rollytics/indexer/collector/block"
 	evm_nft "github.com/initia-labs/rollytics/indexer/collector/evm-nft"
 	move_nft "github.com/initia-labs/rollytics/indexer/collector/move-nft"
+	"github.com/initia-labs/rollytics/indexer/collector/outbox"
 	"github.com/initia-labs/rollytics/indexer/collector/tx"
 	wasm_nft "github.com/initia-labs/rollytics/indexer/collector/wasm-nft"
+	"github.com/initia-labs/rollytics/indexer/leader"
 	indexertypes "github.com/initia-labs/rollytics/indexer/types"
 	"github.com/initia-labs/rollytics/orm"
 	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/cache"
 )
 
 type Collector struct {
 	logger     *slog.Logger
 	db         *orm.Database
 	submodules []indexertypes.Submodule
+	outbox     bool
+	bulk       atomic.Bool // load the tx rows with COPY, during the initial sync
+	schema     string      // schema of the staging tables, empty for the public tables
 }
 
 func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *Collector {
 	cdc := getCodec()
 	blockSubmodule := block.New(logger, cfg, cdc)
 	txSubmodule := tx.New(logger, cfg, cdc)
-	var nftSubmodule indexertypes.Submodule
+	nftSubmodule := NewNft(cfg, logger)
+
+	submodules := []indexertypes.Submodule{ // NOTE: order should be preserved
+		blockSubmodule,
+		txSubmodule,
+		nftSubmodule,
+	}
+	// the outbox reads back the rows of the other submodules
+	if cfg.EventSinkEnabled() {
+		submodules = append(submodules, outbox.New(logger, cfg))
+	}
+
+	return &Collector{
+		logger:     logger.With("module", "collector"),
+		db:         db,
+		submodules: submodules,
+		outbox:     cfg.EventSinkEnabled(),
+	}
+}
+
+// NewNft returns the nft submodule of the vm type
+func NewNft(cfg *config.Config, logger *slog.Logger) indexertypes.Submodule {
 	switch cfg.GetVmType() {
 	case types.MoveVM:
-		nftSubmodule = move_nft.New(logger, cfg)
+		return move_nft.New(logger, cfg)
 	case types.WasmVM:
-		nftSubmodule = wasm_nft.New(logger, cfg)
+		return wasm_nft.New(logger, cfg)
 	case types.EVM:
-		nftSubmodule = evm_nft.New(logger, cfg)
+		return evm_nft.New(logger, cfg)
 	}
+	return nil
+}
 
+// NewStaging returns a collector of the blocks and txs from the given height on into the tables of the
+// schema, for the backfill workers. The tables missing in the schema, like the dictionaries, are the
+// public ones, and the dictionary entries are committed apart from the collect transactions. The nft
+// submodule is left out since its state depends on the blocks before, the backfill replays it over
+// the merged blocks in order.
+func NewStaging(cfg *config.Config, logger *slog.Logger, db *orm.Database, schema string, from int64) *Collector {
+	cdc := getCodec()
 	return &Collector{
-		logger: logger.With("module", "collector"),
+		logger: logger.With("module", "collector", "schema", schema),
 		db:     db,
 		submodules: []indexertypes.Submodule{ // NOTE: order should be preserved
-			blockSubmodule,
-			txSubmodule,
-			nftSubmodule,
+			block.New(logger, cfg.WithStartHeight(from), cdc),
+			tx.New(logger, cfg, cdc),
 		},
+		schema: schema,
 	}
 }
 
@@ -76,8 +114,8 @@ func (c *Collector) Prepare(ctx context.Context, sb indexertypes.ScrapedBlock) e
 	return nil
 }
 
-func (c *Collector) Collect(sb indexertypes.ScrapedBlock) error {
-	err := c.db.Transaction(func(tx *gorm.DB) error {
+func (c *Collector) Collect(ctx context.Context, sb indexertypes.ScrapedBlock) error {
+	err := c.transaction(ctx, func(tx *gorm.DB) error {
 		// skip if block already exists
 		_, err := block.GetBlock(sb.ChainId, sb.Height, tx)
 		if err == nil {
@@ -95,14 +133,142 @@ func (c *Collector) Collect(sb indexertypes.ScrapedBlock) error {
 
 		c.logger.Info("indexed block", slog.Int64("height", sb.Height))
 		return nil
-	}, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	})
 
-	// handle serialization error
-	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) && pgErr.Code == "40001" {
+	if err != nil {
+		// the dictionary ids created in the rolled back transaction are gone
+		cache.PurgeDictionaries()
+	}
+	if concurrentlyCollected(err) {
 		c.logger.Info("block already indexed", slog.Int64("height", sb.Height))
 		return nil
 	}
+	if err != nil {
+		return err
+	}
+
+	if c.outbox {
+		outbox.Notify()
+	}
+
+	return nil
+}
+
+// concurrentlyCollected reports whether the collect transaction was rolled back on a serialization
+// failure or a block indexed by a concurrent collect
+func concurrentlyCollected(err error) bool {
+	var pgErr *pgconn.PgError
+	return (errors.As(err, &pgErr) && pgErr.Code == "40001") || errors.Is(err, block.ErrAlreadyIndexed)
+}
+
+// SetBulk switches the collect transactions between the bulk loading of the initial sync and the
+// normal transactional path
+func (c *Collector) SetBulk(enabled bool) {
+	c.bulk.Store(enabled)
+}
+
+// transaction runs fc in a collect transaction, on the staging tables of the schema when set and on a
+// dedicated connection in the bulk mode. The transaction is fenced by the leadership of the context,
+// but not cancelled with it, so that a shutdown lets the block in flight be committed.
+func (c *Collector) transaction(ctx context.Context, fc func(tx *gorm.DB) error) error {
+	ctx = context.WithoutCancel(ctx)
+	fenced := func(tx *gorm.DB) error {
+		if err := leader.Fence(tx); err != nil {
+			return err
+		}
+		return fc(tx)
+	}
+
+	opts := &sql.TxOptions{Isolation: sql.LevelReadCommitted}
+	if c.schema != "" {
+		ctx = cache.WithDictionaryDB(ctx, c.db.DB)
+		return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SET LOCAL search_path TO " + pgx.Identifier{c.schema}.Sanitize() + ", public").Error; err != nil {
+				return err
+			}
+			return fenced(tx)
+		}, opts)
+	}
+	if c.bulk.Load() {
+		return c.db.BulkTransaction(ctx, fenced, opts)
+	}
+	return c.db.WithContext(ctx).Transaction(fenced, opts)
+}
+
+// CollectBatch collects consecutive prepared blocks in one transaction, so that the batch submodules
+// insert the rows of every block at once. When a concurrent collect conflicts with the batch, the
+// blocks are collected one by one.
+func (c *Collector) CollectBatch(ctx context.Context, blocks []indexertypes.ScrapedBlock) error {
+	if len(blocks) == 1 {
+		return c.Collect(ctx, blocks[0])
+	}
+
+	from, to := blocks[0].Height, blocks[len(blocks)-1].Height
+	err := c.transaction(ctx, func(tx *gorm.DB) error {
+		// skip the blocks already indexed
+		var indexed []int64
+		if err := tx.Model(&types.CollectedBlock{}).
+			Where("chain_id = ? AND height BETWEEN ? AND ?", blocks[0].ChainId, from, to).
+			Pluck("height", &indexed).Error; err != nil {
+			return fmt.Errorf("failed to get blocks %d-%d, %+w", from, to, err)
+		}
+		pending := blocks
+		if len(indexed) > 0 {
+			skip := make(map[int64]bool, len(indexed))
+			for _, height := range indexed {
+				skip[height] = true
+				c.logger.Info("block already indexed", slog.Int64("height", height))
+			}
+			pending = nil
+			for _, sb := range blocks {
+				if !skip[sb.Height] {
+					pending = append(pending, sb)
+				}
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		for _, sub := range c.submodules {
+			if batch, ok := sub.(indexertypes.BatchSubmodule); ok {
+				if err := batch.CollectBatch(pending, tx); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, sb := range pending {
+				if err := sub.Collect(sb, tx); err != nil {
+					return err
+				}
+			}
+		}
+
+		c.logger.Info("indexed blocks", slog.Int64("from_height", from), slog.Int64("to_height", to))
+		return nil
+	})
+
+	if err != nil {
+		// the dictionary ids created in the rolled back transaction are gone
+		cache.PurgeDictionaries()
+	}
+	// the blocks of the batch are all rolled back, including those no concurrent collect indexed
+	if concurrentlyCollected(err) {
+		c.logger.Info("collecting blocks one by one after a concurrent collect", slog.Int64("from_height", from), slog.Int64("to_height", to))
+		for _, sb := range blocks {
+			if err := c.Collect(ctx, sb); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	if c.outbox {
+		outbox.Notify()
+	}
+
+	return nil
 }
@@ -0,0 +1,188 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/collector/block"
+	indexertypes "github.com/initia-labs/rollytics/indexer/types"
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/cache"
+)
+
+func init() {
+	cache.InitializeCaches(&config.CacheConfig{
+		AccountCacheSize:          1024,
+		NftCacheSize:              1024,
+		MsgTypeCacheSize:          256,
+		TypeTagCacheSize:          256,
+		MoveFunctionCacheSize:     256,
+		MoveDenomCacheSize:        1024,
+		EvmTxHashCacheSize:        1024,
+		EvmDenomContractCacheSize: 1024,
+		ValidatorCacheSize:        1024,
+	})
+}
+
+// recordingSubmodule records the heights it collects, one call per block
+type recordingSubmodule struct {
+	name    string
+	heights []int64
+}
+
+func (s *recordingSubmodule) Name() string { return s.name }
+
+func (s *recordingSubmodule) Prepare(context.Context, indexertypes.ScrapedBlock) error { return nil }
+
+func (s *recordingSubmodule) Collect(block indexertypes.ScrapedBlock, tx *gorm.DB) error {
+	s.heights = append(s.heights, block.Height)
+	return tx.Create(&types.CollectedBlock{ChainId: block.ChainId, Height: block.Height, TotalFee: json.RawMessage(`[]`)}).Error
+}
+
+// batchSubmodule records the batches it collects
+type batchSubmodule struct {
+	recordingSubmodule
+	batches [][]int64
+	err     error
+}
+
+func (s *batchSubmodule) Collect(block indexertypes.ScrapedBlock, tx *gorm.DB) error {
+	return s.CollectBatch([]indexertypes.ScrapedBlock{block}, tx)
+}
+
+func (s *batchSubmodule) CollectBatch(blocks []indexertypes.ScrapedBlock, _ *gorm.DB) error {
+	var heights []int64
+	for _, block := range blocks {
+		heights = append(heights, block.Height)
+	}
+	s.batches = append(s.batches, heights)
+	return s.err
+}
+
+// conflictSubmodule fails the blocks of the given heights like a concurrent collect
+type conflictSubmodule struct {
+	recordingSubmodule
+	conflicts map[int64]bool
+}
+
+func (s *conflictSubmodule) Collect(sb indexertypes.ScrapedBlock, _ *gorm.DB) error {
+	if s.conflicts[sb.Height] {
+		return block.ErrAlreadyIndexed
+	}
+	return nil
+}
+
+// accountSubmodule creates the dictionary id of an account, then fails like a concurrent collect
+type accountSubmodule struct {
+	recordingSubmodule
+	account string
+}
+
+func (s *accountSubmodule) Collect(_ indexertypes.ScrapedBlock, tx *gorm.DB) error {
+	if _, err := cache.GetOrCreateAccountIds(tx, []string{s.account}, true); err != nil {
+		return err
+	}
+	return block.ErrAlreadyIndexed
+}
+
+func setupCollector(t *testing.T, submodules ...indexertypes.Submodule) *Collector {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE block (
+		chain_id text, height integer, hash blob, timestamp datetime, block_time integer, proposer text,
+		gas_used integer, gas_wanted integer, tx_count integer, total_fee blob, PRIMARY KEY (chain_id, height))`).Error)
+
+	return &Collector{
+		logger:     slog.New(slog.DiscardHandler),
+		db:         &orm.Database{DB: db},
+		submodules: submodules,
+	}
+}
+
+func scrapedBlocks(heights ...int64) []indexertypes.ScrapedBlock {
+	var blocks []indexertypes.ScrapedBlock
+	for _, height := range heights {
+		blocks = append(blocks, indexertypes.ScrapedBlock{ChainId: "test-1", Height: height})
+	}
+	return blocks
+}
+
+func TestCollectBatch(t *testing.T) {
+	block := &recordingSubmodule{name: "block"}
+	tx := &batchSubmodule{recordingSubmodule: recordingSubmodule{name: "tx"}}
+	c := setupCollector(t, block, tx)
+
+	require.NoError(t, c.CollectBatch(context.Background(), scrapedBlocks(10, 11, 12)))
+	assert.Equal(t, []int64{10, 11, 12}, block.heights)
+	assert.Equal(t, [][]int64{{10, 11, 12}}, tx.batches)
+
+	// the blocks already indexed are skipped
+	require.NoError(t, c.CollectBatch(context.Background(), scrapedBlocks(12, 13)))
+	assert.Equal(t, []int64{10, 11, 12, 13}, block.heights)
+	assert.Equal(t, [][]int64{{10, 11, 12}, {13}}, tx.batches)
+}
+
+func TestCollectBatch_Rollback(t *testing.T) {
+	block := &recordingSubmodule{name: "block"}
+	tx := &batchSubmodule{recordingSubmodule: recordingSubmodule{name: "tx"}, err: errors.New("insert failed")}
+	c := setupCollector(t, block, tx)
+
+	require.Error(t, c.CollectBatch(context.Background(), scrapedBlocks(10, 11)))
+
+	// nothing of the batch is committed
+	var count int64
+	require.NoError(t, c.db.Model(&types.CollectedBlock{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestCollect_ConcurrentlyIndexed(t *testing.T) {
+	rec := &recordingSubmodule{name: "block"}
+	tx := &batchSubmodule{recordingSubmodule: recordingSubmodule{name: "tx"}, err: block.ErrAlreadyIndexed}
+	c := setupCollector(t, rec, tx)
+
+	// a block indexed by a concurrent collect is skipped, and none of the rows of this collect is kept
+	require.NoError(t, c.Collect(context.Background(), scrapedBlocks(10)[0]))
+	require.NoError(t, c.CollectBatch(context.Background(), scrapedBlocks(10, 11)))
+
+	var count int64
+	require.NoError(t, c.db.Model(&types.CollectedBlock{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestCollect_ConcurrentlyIndexedPurgesDictionaries(t *testing.T) {
+	account := &accountSubmodule{recordingSubmodule: recordingSubmodule{name: "tx"}, account: "0x1"}
+	c := setupCollector(t, &recordingSubmodule{name: "block"}, account)
+	require.NoError(t, c.db.Exec("CREATE TABLE account_dict (id integer PRIMARY KEY, account blob UNIQUE)").Error)
+
+	// the account id created in the rolled back transaction is not kept in the cache
+	require.NoError(t, c.Collect(context.Background(), scrapedBlocks(10)[0]))
+	_, ok := cache.GetAccountCache(account.account)
+	assert.False(t, ok)
+
+	var count int64
+	require.NoError(t, c.db.Table("account_dict").Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestCollectBatch_ConcurrentlyIndexed(t *testing.T) {
+	rec := &recordingSubmodule{name: "block"}
+	conflict := &conflictSubmodule{recordingSubmodule: recordingSubmodule{name: "tx"}, conflicts: map[int64]bool{11: true}}
+	c := setupCollector(t, rec, conflict)
+
+	// the batch is rolled back on the conflicting block, and the other blocks are collected one by one
+	require.NoError(t, c.CollectBatch(context.Background(), scrapedBlocks(10, 11, 12)))
+
+	var heights []int64
+	require.NoError(t, c.db.Model(&types.CollectedBlock{}).Order("height").Pluck("height", &heights).Error)
+	assert.Equal(t, []int64{10, 12}, heights)
+}
@@ -0,0 +1,185 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/indexer/sink"
+	indexertypes "github.com/initia-labs/rollytics/indexer/types"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+)
+
+// Block is the payload of a block event, formatted like the block API
+type Block struct {
+	ChainId   string          `json:"chain_id"`
+	Height    string          `json:"height"`
+	Hash      string          `json:"hash"`
+	BlockTime string          `json:"block_time"`
+	Timestamp string          `json:"timestamp"`
+	GasUsed   string          `json:"gas_used"`
+	GasWanted string          `json:"gas_wanted"`
+	TxCount   string          `json:"tx_count"`
+	TotalFee  json.RawMessage `json:"total_fee"`
+	Proposer  string          `json:"proposer"`
+}
+
+// Nft is the payload of an nft event: the nft minted, transferred or updated in the block,
+// or burned when a tx of the block references an nft which no longer exists
+type Nft struct {
+	CollectionAddr string `json:"collection_addr"`
+	TokenId        string `json:"token_id"`
+	ObjectAddr     string `json:"object_addr,omitempty"` // only used in Move
+	Owner          string `json:"owner,omitempty"`
+	Uri            string `json:"uri,omitempty"`
+	Height         int64  `json:"height"`
+	Burned         bool   `json:"burned"`
+}
+
+func (sub *OutboxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) error {
+	events, err := Events(tx, block.ChainId, block.Height, sub.cfg.GetVmType() == types.EVM)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	return tx.CreateInBatches(events, sub.cfg.GetDBBatchSize()).Error
+}
+
+// Events reads the block, txs, evm txs and nft changes of a height back as outbox events
+func Events(tx *gorm.DB, chainId string, height int64, evm bool) ([]types.CollectedEventOutbox, error) {
+	var events []types.CollectedEventOutbox
+	add := func(eventType, key string, payload any) error {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		events = append(events, types.CollectedEventOutbox{
+			Height:  height,
+			Type:    eventType,
+			Key:     key,
+			Payload: data,
+		})
+		return nil
+	}
+
+	var cb types.CollectedBlock
+	if err := tx.Where("chain_id = ? AND height = ?", chainId, height).First(&cb).Error; err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+	if err := add(sink.EventTypeBlock, fmt.Sprintf("%d", height), Block{
+		ChainId:   cb.ChainId,
+		Height:    fmt.Sprintf("%d", cb.Height),
+		Hash:      strings.ToUpper(util.BytesToHex(cb.Hash)),
+		BlockTime: fmt.Sprintf("%d", cb.BlockTime),
+		Timestamp: cb.Timestamp.Format(time.RFC3339),
+		GasUsed:   fmt.Sprintf("%d", cb.GasUsed),
+		GasWanted: fmt.Sprintf("%d", cb.GasWanted),
+		TxCount:   fmt.Sprintf("%d", cb.TxCount),
+		TotalFee:  cb.TotalFee,
+		Proposer:  cb.Proposer,
+	}); err != nil {
+		return nil, err
+	}
+
+	var txs []types.CollectedTx
+	if err := tx.Where("height = ?", height).Order("sequence").Find(&txs).Error; err != nil {
+		return nil, err
+	}
+	for _, ctx := range txs {
+		if err := add(sink.EventTypeTx, strings.ToUpper(util.BytesToHex(ctx.Hash)), ctx.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if evm {
+		var evmTxs []types.CollectedEvmTx
+		if err := tx.Where("height = ?", height).Order("sequence").Find(&evmTxs).Error; err != nil {
+			return nil, err
+		}
+		for _, etx := range evmTxs {
+			if err := add(sink.EventTypeEvmTx, util.BytesToHexWithPrefix(etx.Hash), etx.Data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	nfts, err := nftEvents(tx, height)
+	if err != nil {
+		return nil, err
+	}
+	for _, nft := range nfts {
+		if err := add(sink.EventTypeNft, nft.CollectionAddr+"/"+nft.TokenId, nft); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+func nftEvents(tx *gorm.DB, height int64) ([]Nft, error) {
+	var nfts []types.CollectedNft
+	if err := tx.Where("height = ?", height).Order("collection_addr, token_id").Find(&nfts).Error; err != nil {
+		return nil, err
+	}
+
+	ownerIds := make([]int64, 0, len(nfts))
+	for _, nft := range nfts {
+		ownerIds = append(ownerIds, nft.OwnerId)
+	}
+	owners := make(map[int64][]byte, len(ownerIds))
+	if len(ownerIds) > 0 {
+		var accounts []types.CollectedAccountDict
+		if err := tx.Where("id IN ?", ownerIds).Find(&accounts).Error; err != nil {
+			return nil, err
+		}
+		for _, account := range accounts {
+			owners[account.Id] = account.Account
+		}
+	}
+
+	events := make([]Nft, 0, len(nfts))
+	for _, nft := range nfts {
+		event := Nft{
+			CollectionAddr: util.BytesToHexWithPrefixIfPresent(nft.CollectionAddr),
+			TokenId:        nft.TokenId,
+			ObjectAddr:     util.BytesToHexWithPrefixIfPresent(nft.Addr),
+			Uri:            nft.Uri,
+			Height:         height,
+		}
+		if owner, ok := owners[nft.OwnerId]; ok {
+			event.Owner = sdk.AccAddress(owner).String()
+		}
+		events = append(events, event)
+	}
+
+	// burned nfts are only left in the tx-nft edges, which Move does not record
+	var burned []types.CollectedNftDict
+	if err := tx.Model(&types.CollectedNftDict{}).
+		Distinct("nft_dict.id", "nft_dict.collection_addr", "nft_dict.token_id").
+		Joins("JOIN tx_nfts ON tx_nfts.nft_id = nft_dict.id").
+		Joins("JOIN tx ON tx.sequence = tx_nfts.sequence").
+		Where("tx.height = ?", height).
+		Where("NOT EXISTS (SELECT 1 FROM nft WHERE nft.collection_addr = nft_dict.collection_addr AND nft.token_id = nft_dict.token_id)").
+		Order("nft_dict.id").
+		Find(&burned).Error; err != nil {
+		return nil, err
+	}
+	for _, nft := range burned {
+		events = append(events, Nft{
+			CollectionAddr: util.BytesToHexWithPrefixIfPresent(nft.CollectionAddr),
+			TokenId:        nft.TokenId,
+			Height:         height,
+			Burned:         true,
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/indexer/sink"
+	"github.com/initia-labs/rollytics/types"
+)
+
+var owner = []byte{0x01, 0x02, 0x03}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// sqlite only parses the times of datetime columns, and has no hash indexes
+	require.NoError(t, db.Exec(`CREATE TABLE block (
+		chain_id text, height integer, hash blob, timestamp datetime, block_time integer, proposer text,
+		gas_used integer, gas_wanted integer, tx_count integer, total_fee blob, PRIMARY KEY (chain_id, height))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE nft (
+		collection_addr blob, token_id text, addr blob, height integer, timestamp datetime, owner_id integer, uri text,
+		PRIMARY KEY (collection_addr, token_id))`).Error)
+	require.NoError(t, db.AutoMigrate(
+		&types.CollectedTx{},
+		&types.CollectedEvmTx{},
+		&types.CollectedTxNft{},
+		&types.CollectedNftDict{},
+		&types.CollectedAccountDict{},
+	))
+
+	timestamp := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Create(&types.CollectedBlock{
+		ChainId:   "test-1",
+		Height:    5,
+		Hash:      []byte{0xab, 0xcd},
+		Timestamp: timestamp,
+		BlockTime: 1000,
+		TxCount:   2,
+		TotalFee:  json.RawMessage(`[]`),
+		Proposer:  "proposer",
+	}).Error)
+	for seq := int64(1); seq <= 2; seq++ {
+		require.NoError(t, db.Create(&types.CollectedTx{
+			Hash:     []byte{0xaa, byte(seq)},
+			Height:   5,
+			Sequence: seq,
+			Data:     json.RawMessage(fmt.Sprintf(`{"seq":%d}`, seq)),
+		}).Error)
+	}
+	require.NoError(t, db.Create(&types.CollectedTx{Hash: []byte{0xaa, 0x00}, Height: 4, Sequence: 0}).Error)
+	require.NoError(t, db.Create(&types.CollectedEvmTx{Hash: []byte{0xbb, 0x01}, Height: 5, Sequence: 1, Data: json.RawMessage(`{}`)}).Error)
+	require.NoError(t, db.Create(&types.CollectedAccountDict{Id: 1, Account: owner}).Error)
+
+	// minted at this height, burned at this height, and left untouched at an older height
+	require.NoError(t, db.Create(&types.CollectedNft{CollectionAddr: []byte{0xc1}, TokenId: "1", Height: 5, Timestamp: timestamp, OwnerId: 1, Uri: "uri"}).Error)
+	require.NoError(t, db.Create(&types.CollectedNft{CollectionAddr: []byte{0xc1}, TokenId: "3", Height: 3, Timestamp: timestamp, OwnerId: 1}).Error)
+	require.NoError(t, db.Create(&types.CollectedNftDict{Id: 1, CollectionAddr: []byte{0xc1}, TokenId: "1"}).Error)
+	require.NoError(t, db.Create(&types.CollectedNftDict{Id: 2, CollectionAddr: []byte{0xc1}, TokenId: "2"}).Error)
+	require.NoError(t, db.Create(&types.CollectedTxNft{NftId: 1, Sequence: 1}).Error)
+	require.NoError(t, db.Create(&types.CollectedTxNft{NftId: 2, Sequence: 2}).Error)
+
+	return db
+}
+
+func TestEvents(t *testing.T) {
+	db := setupTestDB(t)
+
+	events, err := Events(db, "test-1", 5, true)
+	require.NoError(t, err)
+
+	var kinds []string
+	for _, event := range events {
+		assert.Equal(t, int64(5), event.Height)
+		kinds = append(kinds, event.Type+":"+event.Key)
+	}
+	assert.Equal(t, []string{
+		"block:5",
+		"tx:AA01",
+		"tx:AA02",
+		"evm_tx:0xbb01",
+		"nft:0xc1/1",
+		"nft:0xc1/2",
+	}, kinds)
+
+	var block Block
+	require.NoError(t, json.Unmarshal(events[0].Payload, &block))
+	assert.Equal(t, "ABCD", block.Hash)
+	assert.Equal(t, "2026-01-01T00:00:00Z", block.Timestamp)
+	assert.Equal(t, "2", block.TxCount)
+
+	assert.JSONEq(t, `{"seq":1}`, string(events[1].Payload))
+
+	var minted, burned Nft
+	require.NoError(t, json.Unmarshal(events[4].Payload, &minted))
+	require.NoError(t, json.Unmarshal(events[5].Payload, &burned))
+	assert.Equal(t, Nft{CollectionAddr: "0xc1", TokenId: "1", Owner: sdk.AccAddress(owner).String(), Uri: "uri", Height: 5}, minted)
+	assert.Equal(t, Nft{CollectionAddr: "0xc1", TokenId: "2", Height: 5, Burned: true}, burned)
+}
+
+func TestEvents_WithoutEvm(t *testing.T) {
+	db := setupTestDB(t)
+
+	events, err := Events(db, "test-1", 5, false)
+	require.NoError(t, err)
+	for _, event := range events {
+		assert.NotEqual(t, sink.EventTypeEvmTx, event.Type)
+	}
+}
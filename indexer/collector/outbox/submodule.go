@@ -0,0 +1,63 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/types"
+)
+
+const SubmoduleName = "outbox"
+
+var _ types.Submodule = &OutboxSubmodule{}
+
+// committed wakes the event sink up after a block with outbox events is committed
+var committed = make(chan struct{}, 1)
+
+// Notify signals a committed block to the event sink without blocking
+func Notify() {
+	select {
+	case committed <- struct{}{}:
+	default:
+	}
+}
+
+// Committed returns the channel signalled by Notify
+func Committed() <-chan struct{} {
+	return committed
+}
+
+// OutboxSubmodule writes the events of a block to the outbox in the transaction of the block,
+// so that the event sink publishes exactly the committed blocks. It must run after the other
+// submodules, whose rows it reads back.
+type OutboxSubmodule struct {
+	logger *slog.Logger
+	cfg    *config.Config
+}
+
+func New(logger *slog.Logger, cfg *config.Config) *OutboxSubmodule {
+	return &OutboxSubmodule{
+		logger: logger.With("submodule", SubmoduleName),
+		cfg:    cfg,
+	}
+}
+
+func (sub *OutboxSubmodule) Name() string {
+	return SubmoduleName
+}
+
+func (sub *OutboxSubmodule) Prepare(ctx context.Context, block types.ScrapedBlock) error {
+	return nil
+}
+
+func (sub *OutboxSubmodule) Collect(block types.ScrapedBlock, tx *gorm.DB) error {
+	if err := sub.collect(block, tx); err != nil {
+		sub.logger.Error("failed to collect data", slog.Int64("height", block.Height), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/cometbft/cometbft/crypto/tmhash"
@@ -24,46 +25,121 @@ import (
 	"github.com/initia-labs/rollytics/util/cache"
 )
 
+// txRows are the rows of the txs of consecutive blocks, inserted together
+type txRows struct {
+	txs           []types.CollectedTx
+	txAccounts    []types.CollectedTxAccount
+	txMsgTypes    []types.CollectedTxMsgType
+	txTypeTags    []types.CollectedTxTypeTag
+	txMsgs        []types.CollectedTxMsg
+	txMoveCalls   []types.CollectedTxMoveCall
+	evmTxs        []types.CollectedEvmTx
+	evmTxAccounts []types.CollectedEvmTxAccount
+}
+
 func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) error {
+	return sub.collectBatch([]indexertypes.ScrapedBlock{block}, tx)
+}
+
+// collectBatch collects the txs of consecutive blocks with one batched insert per table,
+// continuing the sequences from one block to the next
+func (sub *TxSubmodule) collectBatch(blocks []indexertypes.ScrapedBlock, tx *gorm.DB) error {
 	batchSize := sub.cfg.GetDBBatchSize()
-	height := block.Height
+	evm := sub.cfg.GetVmType() == types.EVM
+
+	// get seq info
+	seqInfo, err := indexerutil.GetSeqInfo(types.SeqInfoTx, tx)
+	if err != nil {
+		return err
+	}
+	var evmSeqInfo types.CollectedSeqInfo
+	if evm {
+		if evmSeqInfo, err = indexerutil.GetSeqInfo(types.SeqInfoEvmTx, tx); err != nil {
+			return err
+		}
+	}
+
+	var rows txRows
+	for _, block := range blocks {
+		sub.mtx.Lock()
+		cacheData, ok := sub.cache[block.Height]
+		delete(sub.cache, block.Height)
+		sub.mtx.Unlock()
+
+		if !ok {
+			return errors.New("data is not prepared")
+		}
+
+		// collect fa before collecting tx (only for move)
+		if err := collectFA(block, sub.cfg, tx); err != nil {
+			return err
+		}
+
+		if err := sub.buildTxs(block, cacheData.RestTxs, &seqInfo, &rows, tx); err != nil {
+			return err
+		}
 
-	sub.mtx.Lock()
-	cacheData, ok := sub.cache[height]
-	delete(sub.cache, height)
-	sub.mtx.Unlock()
+		if evm {
+			if err := sub.buildEvmTxs(block, cacheData.EvmTxs, &evmSeqInfo, &rows, tx); err != nil {
+				return err
+			}
+		}
+	}
 
-	if !ok {
-		return errors.New("data is not prepared")
+	// insert txs
+	if err := insertRows(tx, txCopy, rows.txs, batchSize); err != nil {
+		return err
+	}
+	if err := insertRows(tx, txAccountCopy, rows.txAccounts, batchSize); err != nil {
+		return err
+	}
+	if err := insertRows(tx, txMsgTypeCopy, rows.txMsgTypes, batchSize); err != nil {
+		return err
+	}
+	if err := insertRows(tx, txTypeTagCopy, rows.txTypeTags, batchSize); err != nil {
+		return err
+	}
+	if err := insertRows(tx, txMsgCopy, rows.txMsgs, batchSize); err != nil {
+		return err
+	}
+	if err := insertRows(tx, txMoveCallCopy, rows.txMoveCalls, batchSize); err != nil {
+		return err
 	}
 
-	// collect fa before collecting tx (only for move)
-	if err := collectFA(block, sub.cfg, tx); err != nil {
+	// update seq info
+	if err := tx.Clauses(orm.UpdateAllWhenConflict).Create(&seqInfo).Error; err != nil {
 		return err
 	}
 
-	// get seq info
-	seqInfo, err := indexerutil.GetSeqInfo(types.SeqInfoTx, tx)
-	if err != nil {
+	if !evm {
+		return nil
+	}
+
+	// insert evm txs
+	if err := insertRows(tx, evmTxCopy, rows.evmTxs, batchSize); err != nil {
 		return err
 	}
+	if err := insertRows(tx, evmTxAccountCopy, rows.evmTxAccounts, batchSize); err != nil {
+		return err
+	}
+
+	// update seq info
+	return tx.Clauses(orm.UpdateAllWhenConflict).Create(&evmSeqInfo).Error
+}
+
+// buildTxs appends the rows of the txs of the block, taking the next sequences
+func (sub *TxSubmodule) buildTxs(block indexertypes.ScrapedBlock, restTxs []types.RestTx, seqInfo *types.CollectedSeqInfo, rows *txRows, tx *gorm.DB) error {
+	height := block.Height
 
 	// create rest tx map
 	restTxMap := make(map[string]types.RestTx) // signatures -> rest tx
-	for _, restTx := range cacheData.RestTxs {
+	for _, restTx := range restTxs {
 		sigKey := strings.Join(restTx.Signatures, ",")
 		if sigKey != "" {
 			restTxMap[sigKey] = restTx
 		}
 	}
 
-	var (
-		ctxs       []types.CollectedTx
-		txAccounts []types.CollectedTxAccount
-		txMsgTypes []types.CollectedTxMsgType
-		txTypeTags []types.CollectedTxTypeTag
-	)
-
 	for txIndex, txRaw := range block.Txs {
 		txByte, err := base64.StdEncoding.DecodeString(txRaw)
 		if err != nil {
@@ -97,8 +173,20 @@ func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 			return err
 		}
 
-		// convert to msg type ids
-		msgTypeIdMap, err := cache.GetOrCreateMsgTypeIds(tx, msgTypes, true)
+		// decode the fields of the msgs
+		msgs, err := grepMsgsFromTx(sub.logger, sub.cdc, txHash, raw.BodyBytes, restTx)
+		if err != nil {
+			return err
+		}
+
+		// convert to msg type ids, with the types of the msgs the rest tx may lack
+		dictMsgTypes := slices.Clone(msgTypes)
+		for _, msg := range msgs {
+			if !slices.Contains(dictMsgTypes, msg.msgType) {
+				dictMsgTypes = append(dictMsgTypes, msg.msgType)
+			}
+		}
+		msgTypeIdMap, err := cache.GetOrCreateMsgTypeIds(tx, dictMsgTypes, true)
 		if err != nil {
 			return err
 		}
@@ -110,6 +198,15 @@ func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 			}
 		}
 
+		// grep move calls from msgs
+		moveFunctions := grepMoveCallsFromMsgs(sub.cfg, msgs)
+
+		// convert to move function ids
+		moveFunctionIdMap, err := cache.GetOrCreateMoveFunctionIds(tx, moveFunctions, true)
+		if err != nil {
+			return err
+		}
+
 		res := block.TxResults[txIndex]
 		// grep type tags from events
 		typeTags := grepTypeTagsFromEvents(sub.cfg, res.Events)
@@ -161,6 +258,15 @@ func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 		signer := sdk.AccAddress(pk.Address()).String()
 		accountMap[signer] = nil
 
+		// msg senders in the bech32 form of the accounts
+		senders := make([]string, len(msgs))
+		for i, msg := range msgs {
+			if addr := parseAddress(msg.sender); addr != nil {
+				senders[i] = sdk.AccAddress(addr).String()
+				accountMap[senders[i]] = nil
+			}
+		}
+
 		var uniqueAccounts []string
 		for account := range accountMap {
 			uniqueAccounts = append(uniqueAccounts, account)
@@ -222,12 +328,14 @@ func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 
 		seqInfo.Sequence++
 		currentSeq := seqInfo.Sequence
-		ctxs = append(ctxs, types.CollectedTx{
-			Hash:     hashBytes,
-			Height:   height,
-			Sequence: currentSeq,
-			SignerId: signerId,
-			Data:     json.RawMessage(txResJSON),
+		rows.txs = append(rows.txs, types.CollectedTx{
+			Hash:      hashBytes,
+			Height:    height,
+			Sequence:  currentSeq,
+			SignerId:  signerId,
+			Code:      res.Code,
+			Codespace: res.Codespace,
+			Data:      json.RawMessage(txResJSON),
 		})
 
 		if len(accountIds) > 0 {
@@ -237,7 +345,7 @@ func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 					continue
 				}
 				accountSeen[id] = struct{}{}
-				txAccounts = append(txAccounts, types.CollectedTxAccount{
+				rows.txAccounts = append(rows.txAccounts, types.CollectedTxAccount{
 					AccountId: id,
 					Sequence:  currentSeq,
 					Signer:    id == signerId,
@@ -252,13 +360,41 @@ func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 					continue
 				}
 				msgSeen[id] = struct{}{}
-				txMsgTypes = append(txMsgTypes, types.CollectedTxMsgType{
+				rows.txMsgTypes = append(rows.txMsgTypes, types.CollectedTxMsgType{
 					MsgTypeId: id,
 					Sequence:  currentSeq,
 				})
 			}
 		}
 
+		for i, msg := range msgs {
+			var funds json.RawMessage
+			if len(msg.funds) > 0 {
+				if funds, err = json.Marshal(msg.funds); err != nil {
+					return err
+				}
+			}
+			rows.txMsgs = append(rows.txMsgs, types.CollectedTxMsg{
+				Sequence:  currentSeq,
+				MsgIndex:  int32(i), //nolint:gosec
+				MsgTypeId: msgTypeIdMap[msg.msgType],
+				SenderId:  accountIdMap[senders[i]],
+				Contract:  msg.contract,
+				Module:    msg.module,
+				Function:  msg.function,
+				Funds:     funds,
+			})
+		}
+
+		for _, function := range moveFunctions {
+			if id, ok := moveFunctionIdMap[function]; ok {
+				rows.txMoveCalls = append(rows.txMoveCalls, types.CollectedTxMoveCall{
+					FunctionId: id,
+					Sequence:   currentSeq,
+				})
+			}
+		}
+
 		if len(typeTagIds) > 0 {
 			tagSeen := make(map[int64]struct{}, len(typeTagIds))
 			for _, id := range typeTagIds {
@@ -266,7 +402,7 @@ func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 					continue
 				}
 				tagSeen[id] = struct{}{}
-				txTypeTags = append(txTypeTags, types.CollectedTxTypeTag{
+				rows.txTypeTags = append(rows.txTypeTags, types.CollectedTxTypeTag{
 					TypeTagId: id,
 					Sequence:  currentSeq,
 				})
@@ -274,55 +410,13 @@ func (sub *TxSubmodule) collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 		}
 	}
 
-	// insert txs
-	if err := tx.Clauses(orm.DoNothingWhenConflict).CreateInBatches(ctxs, batchSize).Error; err != nil {
-		return err
-	}
-
-	if len(txAccounts) > 0 {
-		if err := tx.Clauses(orm.DoNothingWhenConflict).CreateInBatches(txAccounts, batchSize).Error; err != nil {
-			return err
-		}
-	}
-
-	if len(txMsgTypes) > 0 {
-		if err := tx.Clauses(orm.DoNothingWhenConflict).CreateInBatches(txMsgTypes, batchSize).Error; err != nil {
-			return err
-		}
-	}
-
-	if len(txTypeTags) > 0 {
-		if err := tx.Clauses(orm.DoNothingWhenConflict).CreateInBatches(txTypeTags, batchSize).Error; err != nil {
-			return err
-		}
-	}
-
-	// update seq info
-	if err := tx.Clauses(orm.UpdateAllWhenConflict).Create(&seqInfo).Error; err != nil {
-		return err
-	}
-
-	return sub.collectEvm(block, cacheData.EvmTxs, tx)
+	return nil
 }
 
-func (sub *TxSubmodule) collectEvm(block indexertypes.ScrapedBlock, evmTxs []types.EvmTx, tx *gorm.DB) (err error) {
-	if sub.cfg.GetVmType() != types.EVM {
-		return nil
-	}
-
-	batchSize := sub.cfg.GetDBBatchSize()
+// buildEvmTxs appends the rows of the evm txs of the block, taking the next evm sequences
+func (sub *TxSubmodule) buildEvmTxs(block indexertypes.ScrapedBlock, evmTxs []types.EvmTx, seqInfo *types.CollectedSeqInfo, rows *txRows, tx *gorm.DB) error {
 	height := block.Height
 
-	// get seq info
-	seqInfo, err := indexerutil.GetSeqInfo(types.SeqInfoEvmTx, tx)
-	if err != nil {
-		return err
-	}
-
-	var (
-		cetxs         []types.CollectedEvmTx
-		evmTxAccounts []types.CollectedEvmTxAccount
-	)
 	for _, evmTx := range evmTxs {
 		txJSON, err := json.Marshal(evmTx)
 		if err != nil {
@@ -374,7 +468,7 @@ func (sub *TxSubmodule) collectEvm(block indexertypes.ScrapedBlock, evmTxs []typ
 
 		seqInfo.Sequence++
 		currentSeq := seqInfo.Sequence
-		cetxs = append(cetxs, types.CollectedEvmTx{
+		rows.evmTxs = append(rows.evmTxs, types.CollectedEvmTx{
 			Hash:     hashBytes,
 			Height:   height,
 			Sequence: currentSeq,
@@ -389,7 +483,7 @@ func (sub *TxSubmodule) collectEvm(block indexertypes.ScrapedBlock, evmTxs []typ
 					continue
 				}
 				accountSeen[id] = struct{}{}
-				evmTxAccounts = append(evmTxAccounts, types.CollectedEvmTxAccount{
+				rows.evmTxAccounts = append(rows.evmTxAccounts, types.CollectedEvmTxAccount{
 					AccountId: id,
 					Sequence:  currentSeq,
 					Signer:    id == signerId,
@@ -398,21 +492,5 @@ func (sub *TxSubmodule) collectEvm(block indexertypes.ScrapedBlock, evmTxs []typ
 		}
 	}
 
-	// insert evm txs
-	if err := tx.Clauses(orm.DoNothingWhenConflict).CreateInBatches(cetxs, batchSize).Error; err != nil {
-		return err
-	}
-
-	if len(evmTxAccounts) > 0 {
-		if err := tx.Clauses(orm.DoNothingWhenConflict).CreateInBatches(evmTxAccounts, batchSize).Error; err != nil {
-			return err
-		}
-	}
-
-	// update seq info
-	if err := tx.Clauses(orm.UpdateAllWhenConflict).Create(&seqInfo).Error; err != nil {
-		return err
-	}
-
 	return nil
 }
@@ -0,0 +1,100 @@
+package tx
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	indexertypes "github.com/initia-labs/rollytics/indexer/types"
+	dbconfig "github.com/initia-labs/rollytics/orm/config"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/cache"
+)
+
+func init() {
+	cache.InitializeCaches(&config.CacheConfig{
+		AccountCacheSize:          1024,
+		NftCacheSize:              1024,
+		MsgTypeCacheSize:          256,
+		TypeTagCacheSize:          256,
+		MoveFunctionCacheSize:     256,
+		MoveDenomCacheSize:        1024,
+		EvmTxHashCacheSize:        1024,
+		EvmDenomContractCacheSize: 1024,
+		ValidatorCacheSize:        1024,
+	})
+}
+
+func TestCollect_MalformedMsgs(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&types.CollectedSeqInfo{},
+		&types.CollectedTx{},
+		&types.CollectedTxAccount{},
+		&types.CollectedTxMsgType{},
+		&types.CollectedTxMsg{},
+	))
+	// sqlite only assigns the ids of integer primary keys
+	require.NoError(t, db.Exec("CREATE TABLE account_dict (id integer PRIMARY KEY, account blob UNIQUE)").Error)
+	require.NoError(t, db.Exec("CREATE TABLE msg_type_dict (id integer PRIMARY KEY, msg_type text UNIQUE)").Error)
+
+	cdc := newTestCodec()
+	cfg := &config.Config{}
+	cfg.SetChainConfig(&config.ChainConfig{VmType: types.WasmVM})
+	cfg.SetDBConfig(&dbconfig.Config{BatchSize: 100})
+	sub := New(slog.New(slog.DiscardHandler), cfg, cdc)
+
+	pubKey, err := codectypes.NewAnyWithValue(secp256k1.GenPrivKey().PubKey())
+	require.NoError(t, err)
+	authInfo, err := (&sdktx.AuthInfo{SignerInfos: []*sdktx.SignerInfo{{PublicKey: pubKey}}}).Marshal()
+	require.NoError(t, err)
+	// an unknown msg whose rest json has a number for its contract, and a msg the rest tx lacks
+	body, err := (&sdktx.TxBody{Messages: []*codectypes.Any{
+		{TypeUrl: "/cosmwasm.wasm.v1.MsgExecuteContract", Value: []byte{0x0a, 0x00}},
+		{TypeUrl: "/unknown.v1.MsgUnknown"},
+	}}).Marshal()
+	require.NoError(t, err)
+	signature := []byte("signature")
+	txBytes, err := (&sdktx.TxRaw{BodyBytes: body, AuthInfoBytes: authInfo, Signatures: [][]byte{signature}}).Marshal()
+	require.NoError(t, err)
+
+	sub.cache[10] = CacheData{RestTxs: []types.RestTx{{
+		Body:       json.RawMessage(`{"messages":[{"@type":"/cosmwasm.wasm.v1.MsgExecuteContract","contract":123}]}`),
+		Signatures: []string{base64.StdEncoding.EncodeToString(signature)},
+	}}}
+	block := indexertypes.ScrapedBlock{
+		ChainId:   "test-1",
+		Height:    10,
+		Txs:       []string{base64.StdEncoding.EncodeToString(txBytes)},
+		TxResults: []abci.ExecTxResult{{}},
+	}
+
+	require.NoError(t, sub.Collect(block, db))
+
+	// the msgs are kept with their types only
+	var msgs []types.CollectedTxMsg
+	require.NoError(t, db.Order("msg_index").Find(&msgs).Error)
+	require.Len(t, msgs, 2)
+	for i, msg := range msgs {
+		assert.Equal(t, int32(i), msg.MsgIndex)
+		assert.NotZero(t, msg.MsgTypeId)
+		assert.Nil(t, msg.Contract)
+		assert.Zero(t, msg.SenderId)
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&types.CollectedTx{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
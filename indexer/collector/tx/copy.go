@@ -0,0 +1,101 @@
+package tx
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/types"
+)
+
+// copyTable describes how the rows of a tx table are loaded with COPY
+type copyTable[T any] struct {
+	name    string
+	columns []string
+	values  func(T) []any
+}
+
+var (
+	txCopy = copyTable[types.CollectedTx]{
+		name:    "tx",
+		columns: []string{"hash", "height", "sequence", "signer_id", "code", "codespace", "data"},
+		values: func(r types.CollectedTx) []any {
+			return []any{r.Hash, r.Height, r.Sequence, r.SignerId, int64(r.Code), r.Codespace, []byte(r.Data)}
+		},
+	}
+	txAccountCopy = copyTable[types.CollectedTxAccount]{
+		name:    "tx_accounts",
+		columns: []string{"account_id", "sequence", "signer"},
+		values: func(r types.CollectedTxAccount) []any {
+			return []any{r.AccountId, r.Sequence, r.Signer}
+		},
+	}
+	txMsgTypeCopy = copyTable[types.CollectedTxMsgType]{
+		name:    "tx_msg_types",
+		columns: []string{"msg_type_id", "sequence"},
+		values: func(r types.CollectedTxMsgType) []any {
+			return []any{r.MsgTypeId, r.Sequence}
+		},
+	}
+	txTypeTagCopy = copyTable[types.CollectedTxTypeTag]{
+		name:    "tx_type_tags",
+		columns: []string{"type_tag_id", "sequence"},
+		values: func(r types.CollectedTxTypeTag) []any {
+			return []any{r.TypeTagId, r.Sequence}
+		},
+	}
+	txMsgCopy = copyTable[types.CollectedTxMsg]{
+		name:    "tx_msg",
+		columns: []string{"sequence", "msg_index", "msg_type_id", "sender_id", "contract", "module", "function", "funds"},
+		values: func(r types.CollectedTxMsg) []any {
+			return []any{r.Sequence, r.MsgIndex, r.MsgTypeId, r.SenderId, r.Contract, r.Module, r.Function, []byte(r.Funds)}
+		},
+	}
+	txMoveCallCopy = copyTable[types.CollectedTxMoveCall]{
+		name:    "tx_move_calls",
+		columns: []string{"function_id", "sequence"},
+		values: func(r types.CollectedTxMoveCall) []any {
+			return []any{r.FunctionId, r.Sequence}
+		},
+	}
+	evmTxCopy = copyTable[types.CollectedEvmTx]{
+		name:    "evm_tx",
+		columns: []string{"hash", "height", "sequence", "signer_id", "data"},
+		values: func(r types.CollectedEvmTx) []any {
+			return []any{r.Hash, r.Height, r.Sequence, r.SignerId, []byte(r.Data)}
+		},
+	}
+	evmTxAccountCopy = copyTable[types.CollectedEvmTxAccount]{
+		name:    "evm_tx_accounts",
+		columns: []string{"account_id", "sequence", "signer"},
+		values: func(r types.CollectedEvmTxAccount) []any {
+			return []any{r.AccountId, r.Sequence, r.Signer}
+		},
+	}
+)
+
+// CopyTables are the tables loaded with COPY during the initial sync
+var CopyTables = []string{
+	txCopy.name, txAccountCopy.name, txMsgTypeCopy.name, txTypeTagCopy.name, txMsgCopy.name, txMoveCallCopy.name, evmTxCopy.name, evmTxAccountCopy.name,
+}
+
+// insertRows loads the rows with COPY in a bulk transaction, and inserts them in batches skipping
+// the conflicts otherwise, or when some of the rows already exist
+func insertRows[T any](tx *gorm.DB, table copyTable[T], rows []T, batchSize int) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if orm.CopyEnabled(tx) {
+		values := make([][]any, 0, len(rows))
+		for _, row := range rows {
+			values = append(values, table.values(row))
+		}
+		err := orm.CopyFrom(tx, table.name, table.columns, values)
+		if !errors.Is(err, orm.ErrCopyConflict) {
+			return err
+		}
+	}
+
+	return tx.Clauses(orm.DoNothingWhenConflict).CreateInBatches(rows, batchSize).Error
+}
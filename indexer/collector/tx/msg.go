@@ -0,0 +1,201 @@
+package tx
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	evmtypes "github.com/initia-labs/minievm/x/evm/types"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+)
+
+// txMsg is the structured fields of a msg, empty when the msg has none of them
+type txMsg struct {
+	msgType  string
+	sender   string
+	contract []byte
+	module   string
+	function string
+	funds    sdk.Coins
+}
+
+// restMsg is the fields of a msg read from the rest tx when its type is not in the interface registry
+type restMsg struct {
+	Sender           string          `json:"sender"`
+	FromAddress      string          `json:"from_address"`
+	DelegatorAddress string          `json:"delegator_address"`
+	Contract         string          `json:"contract"`
+	ContractAddr     string          `json:"contract_addr"`
+	ModuleAddress    string          `json:"module_address"`
+	ModuleName       string          `json:"module_name"`
+	FunctionName     string          `json:"function_name"`
+	Funds            json.RawMessage `json:"funds"`
+	Amount           json.RawMessage `json:"amount"`
+}
+
+// grepMsgsFromTx decodes the msgs of the tx body in their order. The msgs of the types registered in
+// the interface registry are unpacked, the others are read from the json of the rest tx. A msg which
+// fails to decode is logged and kept with its type only, so that it does not fail the collect.
+func grepMsgsFromTx(logger *slog.Logger, cdc codec.Codec, txHash string, bodyBytes []byte, restTx types.RestTx) ([]txMsg, error) {
+	// the body is not unmarshaled by the codec, which fails on the msgs of unregistered types
+	var body sdktx.TxBody
+	if err := body.Unmarshal(bodyBytes); err != nil {
+		return nil, err
+	}
+
+	var restBody struct {
+		Messages []json.RawMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(restTx.Body, &restBody); err != nil {
+		logger.Warn("failed to read msgs of rest tx", slog.String("tx_hash", txHash), slog.Any("error", err))
+		restBody.Messages = nil
+	}
+	if restBody.Messages != nil && len(restBody.Messages) != len(body.Messages) {
+		logger.Warn("msg count mismatch between rest tx and tx body",
+			slog.String("tx_hash", txHash),
+			slog.Int("rest_msgs", len(restBody.Messages)),
+			slog.Int("body_msgs", len(body.Messages)))
+		restBody.Messages = nil
+	}
+
+	msgs := make([]txMsg, 0, len(body.Messages))
+	for i, anyMsg := range body.Messages {
+		msgType := strings.TrimPrefix(anyMsg.TypeUrl, "/")
+		var rest json.RawMessage
+		if restBody.Messages != nil {
+			rest = restBody.Messages[i]
+		}
+		msg, err := decodeMsg(cdc, anyMsg, rest)
+		if err != nil {
+			logger.Warn("failed to decode msg",
+				slog.String("tx_hash", txHash),
+				slog.Int("msg_index", i),
+				slog.String("msg_type", msgType),
+				slog.Any("error", err))
+			msg = txMsg{}
+		}
+		msg.msgType = msgType
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// decodeMsg unpacks the msg when its type is registered and has fields decoded from it, and reads
+// the msg from its json in the rest tx otherwise
+func decodeMsg(cdc codec.Codec, anyMsg *codectypes.Any, rest json.RawMessage) (txMsg, error) {
+	if _, err := cdc.InterfaceRegistry().Resolve(anyMsg.TypeUrl); err != nil {
+		return msgFromRest(rest)
+	}
+
+	var sdkMsg sdk.Msg
+	if err := cdc.UnpackAny(anyMsg, &sdkMsg); err != nil {
+		return txMsg{}, err
+	}
+
+	switch m := sdkMsg.(type) {
+	case *banktypes.MsgSend:
+		return txMsg{sender: m.FromAddress, funds: m.Amount}, nil
+	case *banktypes.MsgMultiSend:
+		if len(m.Inputs) == 0 {
+			return txMsg{}, nil
+		}
+		return txMsg{sender: m.Inputs[0].Address, funds: m.Inputs[0].Coins}, nil
+	case *evmtypes.MsgCall:
+		return txMsg{sender: m.Sender, contract: parseAddress(m.ContractAddr), function: evmSelector(m.Input)}, nil
+	case *evmtypes.MsgCreate:
+		return txMsg{sender: m.Sender}, nil
+	case *evmtypes.MsgCreate2:
+		return txMsg{sender: m.Sender}, nil
+	default:
+		return msgFromRest(rest)
+	}
+}
+
+// msgFromRest reads the fields of a msg from its json in the rest tx, none without the json
+func msgFromRest(raw json.RawMessage) (txMsg, error) {
+	if len(raw) == 0 {
+		return txMsg{}, nil
+	}
+
+	var rm restMsg
+	if err := json.Unmarshal(raw, &rm); err != nil {
+		return txMsg{}, err
+	}
+
+	msg := txMsg{
+		sender:   firstNonEmpty(rm.Sender, rm.FromAddress, rm.DelegatorAddress),
+		contract: parseAddress(firstNonEmpty(rm.Contract, rm.ContractAddr)),
+		function: rm.FunctionName,
+		funds:    parseCoins(rm.Funds),
+	}
+	if msg.funds == nil {
+		msg.funds = parseCoins(rm.Amount)
+	}
+	if rm.ModuleAddress != "" && rm.ModuleName != "" {
+		module, err := util.MoveModuleId(rm.ModuleAddress, rm.ModuleName)
+		if err != nil {
+			return txMsg{}, err
+		}
+		msg.module = module
+	}
+
+	return msg, nil
+}
+
+// parseCoins reads coins from a list of coins or a single coin, nil for any other json
+func parseCoins(raw json.RawMessage) sdk.Coins {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var coins sdk.Coins
+	if err := json.Unmarshal(raw, &coins); err == nil {
+		if len(coins) == 0 {
+			return nil
+		}
+		return coins
+	}
+	var coin sdk.Coin
+	if err := json.Unmarshal(raw, &coin); err == nil && coin.Denom != "" {
+		return sdk.Coins{coin}
+	}
+	return nil
+}
+
+// parseAddress returns the bytes of a bech32 or hex address, nil when it is not one
+func parseAddress(addr string) []byte {
+	if addr == "" {
+		return nil
+	}
+	bz, err := util.AccAddressFromString(addr)
+	if err != nil {
+		return nil
+	}
+	return bz
+}
+
+// evmSelector returns the function selector of the hex call input, empty without one
+func evmSelector(input string) string {
+	input = strings.ToLower(strings.TrimPrefix(input, "0x"))
+	if len(input) < 8 {
+		return ""
+	}
+	return "0x" + input[:8]
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
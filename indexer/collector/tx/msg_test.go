@@ -0,0 +1,156 @@
+package tx
+
+import (
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/std"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/initia-labs/initia/app/params"
+	evmtypes "github.com/initia-labs/minievm/x/evm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/types"
+)
+
+func newTestCodec() codec.Codec {
+	encodingConfig := params.MakeEncodingConfig()
+	std.RegisterInterfaces(encodingConfig.InterfaceRegistry)
+	banktypes.RegisterInterfaces(encodingConfig.InterfaceRegistry)
+	evmtypes.RegisterInterfaces(encodingConfig.InterfaceRegistry)
+	return encodingConfig.Codec
+}
+
+func TestGrepMsgsFromTx(t *testing.T) {
+	cdc := newTestCodec()
+	sender := sdk.AccAddress([]byte("sender______________"))
+	contract := sdk.AccAddress([]byte("contract____________"))
+
+	send, err := codectypes.NewAnyWithValue(&banktypes.MsgSend{
+		FromAddress: sender.String(),
+		ToAddress:   contract.String(),
+		Amount:      sdk.NewCoins(sdk.NewCoin("uinit", math.NewInt(10))),
+	})
+	require.NoError(t, err)
+	call, err := codectypes.NewAnyWithValue(&evmtypes.MsgCall{
+		Sender:       sender.String(),
+		ContractAddr: "0x000000000000000000000000000000000000abcd",
+		Input:        "0xA9059CBB0000",
+		Value:        math.ZeroInt(),
+	})
+	require.NoError(t, err)
+	// not registered, read from the rest tx
+	execute := &codectypes.Any{TypeUrl: "/initia.move.v1.MsgExecute", Value: []byte{0x0a, 0x00}}
+	executeContract := &codectypes.Any{TypeUrl: "/cosmwasm.wasm.v1.MsgExecuteContract", Value: []byte{0x0a, 0x00}}
+
+	body := sdktx.TxBody{Messages: []*codectypes.Any{send, call, execute, executeContract}}
+	bodyBytes, err := body.Marshal()
+	require.NoError(t, err)
+
+	restBody, err := json.Marshal(map[string]any{
+		"messages": []map[string]any{
+			{"@type": "/cosmos.bank.v1beta1.MsgSend"},
+			{"@type": "/minievm.evm.v1.MsgCall"},
+			{
+				"@type":          "/initia.move.v1.MsgExecute",
+				"sender":         sender.String(),
+				"module_address": "0x0000000000000000000000000000000000000001",
+				"module_name":    "dex",
+				"function_name":  "swap",
+			},
+			{
+				"@type":    "/cosmwasm.wasm.v1.MsgExecuteContract",
+				"sender":   sender.String(),
+				"contract": contract.String(),
+				"msg":      map[string]any{"transfer": map[string]any{}},
+				"funds":    []map[string]string{{"denom": "uinit", "amount": "5"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	msgs, err := grepMsgsFromTx(slog.New(slog.DiscardHandler), cdc, "HASH", bodyBytes, types.RestTx{Body: restBody})
+	require.NoError(t, err)
+	require.Len(t, msgs, 4)
+
+	assert.Equal(t, txMsg{
+		msgType: "cosmos.bank.v1beta1.MsgSend",
+		sender:  sender.String(),
+		funds:   sdk.NewCoins(sdk.NewCoin("uinit", math.NewInt(10))),
+	}, msgs[0])
+
+	assert.Equal(t, "minievm.evm.v1.MsgCall", msgs[1].msgType)
+	assert.Equal(t, sender.String(), msgs[1].sender)
+	assert.Equal(t, "0xa9059cbb", msgs[1].function)
+	assert.Len(t, msgs[1].contract, 20)
+	assert.Equal(t, byte(0xcd), msgs[1].contract[19])
+
+	assert.Equal(t, txMsg{
+		msgType:  "initia.move.v1.MsgExecute",
+		sender:   sender.String(),
+		module:   "0x1::dex",
+		function: "swap",
+	}, msgs[2])
+
+	assert.Equal(t, txMsg{
+		msgType:  "cosmwasm.wasm.v1.MsgExecuteContract",
+		sender:   sender.String(),
+		contract: contract,
+		funds:    sdk.NewCoins(sdk.NewCoin("uinit", math.NewInt(5))),
+	}, msgs[3])
+}
+
+func TestGrepMsgsFromTx_MsgCountMismatch(t *testing.T) {
+	execute := &codectypes.Any{TypeUrl: "/initia.move.v1.MsgExecute"}
+	bodyBytes, err := (&sdktx.TxBody{Messages: []*codectypes.Any{execute}}).Marshal()
+	require.NoError(t, err)
+
+	// the msgs are kept with their types only
+	msgs, err := grepMsgsFromTx(slog.New(slog.DiscardHandler), newTestCodec(), "HASH", bodyBytes, types.RestTx{Body: json.RawMessage(`{"messages":[]}`)})
+	require.NoError(t, err)
+	assert.Equal(t, []txMsg{{msgType: "initia.move.v1.MsgExecute"}}, msgs)
+}
+
+func TestGrepMsgsFromTx_MalformedMsgs(t *testing.T) {
+	execute := &codectypes.Any{TypeUrl: "/initia.move.v1.MsgExecute"}
+	executeContract := &codectypes.Any{TypeUrl: "/cosmwasm.wasm.v1.MsgExecuteContract"}
+	bodyBytes, err := (&sdktx.TxBody{Messages: []*codectypes.Any{execute, executeContract, execute}}).Marshal()
+	require.NoError(t, err)
+
+	restBody := json.RawMessage(`{"messages":[
+		{"@type":"/initia.move.v1.MsgExecute","module_address":"0xzz","module_name":"dex","function_name":"swap"},
+		{"@type":"/cosmwasm.wasm.v1.MsgExecuteContract","contract":123},
+		{"@type":"/initia.move.v1.MsgExecute","module_address":"0x1","module_name":"dex","function_name":"swap"}
+	]}`)
+	msgs, err := grepMsgsFromTx(slog.New(slog.DiscardHandler), newTestCodec(), "HASH", bodyBytes, types.RestTx{Body: restBody})
+	require.NoError(t, err)
+	assert.Equal(t, []txMsg{
+		{msgType: "initia.move.v1.MsgExecute"},
+		{msgType: "cosmwasm.wasm.v1.MsgExecuteContract"},
+		{msgType: "initia.move.v1.MsgExecute", module: "0x1::dex", function: "swap"},
+	}, msgs)
+}
+
+func TestGrepMoveCallsFromMsgs(t *testing.T) {
+	msgs := []txMsg{
+		{msgType: types.MoveMsgExecute, module: "0x1::coin", function: "transfer"},
+		{msgType: types.MoveMsgExecuteJSON, module: "0x1::coin", function: "transfer"},
+		{msgType: types.MoveMsgScript},
+		{msgType: "cosmos.bank.v1beta1.MsgSend", module: "0x1::ignored", function: "ignored"},
+	}
+
+	cfg := &config.Config{}
+	cfg.SetChainConfig(&config.ChainConfig{VmType: types.MoveVM})
+	assert.ElementsMatch(t, []string{"0x1::coin::transfer", types.MoveScriptFunction}, grepMoveCallsFromMsgs(cfg, msgs))
+
+	cfg.SetChainConfig(&config.ChainConfig{VmType: types.WasmVM})
+	assert.Empty(t, grepMoveCallsFromMsgs(cfg, msgs))
+}
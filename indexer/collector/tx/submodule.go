@@ -15,7 +15,10 @@ import (
 
 const SubmoduleName = "tx"
 
-var _ indexertypes.Submodule = &TxSubmodule{}
+var (
+	_ indexertypes.Submodule      = &TxSubmodule{}
+	_ indexertypes.BatchSubmodule = &TxSubmodule{}
+)
 
 type TxSubmodule struct {
 	logger  *slog.Logger
@@ -57,3 +60,15 @@ func (sub *TxSubmodule) Collect(block indexertypes.ScrapedBlock, tx *gorm.DB) er
 
 	return nil
 }
+
+func (sub *TxSubmodule) CollectBatch(blocks []indexertypes.ScrapedBlock, tx *gorm.DB) error {
+	if err := sub.collectBatch(blocks, tx); err != nil {
+		sub.logger.Error("failed to collect data",
+			slog.Int64("from_height", blocks[0].Height),
+			slog.Int64("to_height", blocks[len(blocks)-1].Height),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
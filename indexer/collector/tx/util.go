@@ -56,6 +56,33 @@ func grepTypeTagsFromEvents(cfg *config.Config, events []abci.Event) (typeTags [
 	return
 }
 
+// grepMoveCallsFromMsgs returns the move functions called by the msgs, <address>::<module>::<function>
+// for the entry functions and script for the scripts
+func grepMoveCallsFromMsgs(cfg *config.Config, msgs []txMsg) (functions []string) {
+	if cfg.GetVmType() != types.MoveVM {
+		return
+	}
+
+	functionMap := make(map[string]interface{})
+
+	for _, msg := range msgs {
+		switch msg.msgType {
+		case types.MoveMsgExecute, types.MoveMsgExecuteJSON:
+			if msg.module != "" && msg.function != "" {
+				functionMap[msg.module+"::"+msg.function] = nil
+			}
+		case types.MoveMsgScript, types.MoveMsgScriptJSON:
+			functionMap[types.MoveScriptFunction] = nil
+		}
+	}
+
+	for function := range functionMap {
+		functions = append(functions, function)
+	}
+
+	return
+}
+
 // sanitizeJSONBytes removes null bytes from JSON bytes that cannot be stored
 // in PostgreSQL. PostgreSQL explicitly rejects null bytes (\u0000) in text fields.
 // It replaces null bytes with the Unicode replacement character (\uFFFD) and
@@ -0,0 +1,195 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/initia-labs/rollytics/config"
+	evmret "github.com/initia-labs/rollytics/indexer/extension/evmret"
+	internaltx "github.com/initia-labs/rollytics/indexer/extension/internaltx"
+	"github.com/initia-labs/rollytics/indexer/extension/prune"
+	richlist "github.com/initia-labs/rollytics/indexer/extension/richlist"
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
+	webhook "github.com/initia-labs/rollytics/indexer/extension/webhook"
+	"github.com/initia-labs/rollytics/orm"
+	archivestore "github.com/initia-labs/rollytics/orm/archive"
+	"github.com/initia-labs/rollytics/types"
+)
+
+const ExtensionName = config.ExtensionArchive
+
+var (
+	_ exttypes.Extension = (*ArchiveExtension)(nil)
+	_ exttypes.Dependent = (*ArchiveExtension)(nil)
+)
+
+// a range is only archived, and possibly dropped, once the extensions writing or reading its txs processed it
+func init() {
+	exttypes.Register(ExtensionName, New, exttypes.After(
+		internaltx.ExtensionName,
+		richlist.ExtensionName,
+		evmret.ExtensionName,
+		webhook.ExtensionName,
+	))
+}
+
+type ArchiveExtension struct {
+	cfg    *config.Config
+	logger *slog.Logger
+	db     *orm.Database
+	store  *archivestore.Store
+	deps   *exttypes.Dependencies
+}
+
+// New creates a new ArchiveExtension instance
+// Returns nil if no archive path is configured
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *ArchiveExtension {
+	if !cfg.ExtensionEnabled(ExtensionName) {
+		return nil
+	}
+
+	return &ArchiveExtension{
+		cfg:    cfg,
+		logger: logger.With("extension", ExtensionName),
+		db:     db,
+		store:  archivestore.NewStore(cfg.GetArchiveConfig().Path),
+	}
+}
+
+func (e *ArchiveExtension) Name() string {
+	return ExtensionName
+}
+
+func (e *ArchiveExtension) SetDependencies(deps *exttypes.Dependencies) {
+	e.deps = deps
+}
+
+func (e *ArchiveExtension) Run(ctx context.Context) error {
+	archiveCfg := e.cfg.GetArchiveConfig()
+	evm := e.cfg.GetVmType() == types.EVM
+
+	if err := os.MkdirAll(archiveCfg.Path, 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	// Finish the drops interrupted by a restart
+	if archiveCfg.Drop {
+		ranges, err := e.store.Ranges()
+		if err != nil {
+			return err
+		}
+		for _, r := range ranges {
+			if !r.Dropped {
+				if err := e.drop(ctx, r, evm); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for {
+		from, to, ok, err := e.nextRange(ctx, archiveCfg)
+		if err != nil {
+			return err
+		}
+
+		// Wait for the next range to leave the hot window
+		if !ok {
+			select {
+			case <-ctx.Done():
+				e.logger.Info("archive stopped")
+				return ctx.Err()
+			case <-time.After(archiveCfg.Interval):
+			}
+			continue
+		}
+
+		r, err := e.store.Archive(ctx, e.db.DB, from, to, evm)
+		if err != nil {
+			return err
+		}
+		e.logger.Info("archived heights",
+			slog.Int64("from", from),
+			slog.Int64("to", to),
+			slog.Any("rows", r.Rows))
+
+		if archiveCfg.Drop {
+			if err := e.drop(ctx, r, evm); err != nil {
+				return err
+			}
+		}
+
+		if err := exttypes.SaveCheckpoint(e.db.WithContext(ctx), ExtensionName, exttypes.Checkpoint{
+			Height: r.To,
+			Meta:   map[string]bool{"dropped": archiveCfg.Drop},
+		}); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+}
+
+// nextRange returns the range following the archived ones, aligned to the range size, and whether
+// all of its heights are older than the hot window
+func (e *ArchiveExtension) nextRange(ctx context.Context, archiveCfg *config.ArchiveConfig) (from, to int64, ok bool, err error) {
+	var bounds struct {
+		Lowest int64
+		Latest int64
+	}
+	if err := e.db.WithContext(ctx).
+		Model(&types.CollectedBlock{}).
+		Select("COALESCE(MIN(height), 0) AS lowest, COALESCE(MAX(height), 0) AS latest").
+		Scan(&bounds).Error; err != nil {
+		return 0, 0, false, fmt.Errorf("failed to get block heights: %w", err)
+	}
+	if bounds.Latest == 0 {
+		return 0, 0, false, nil
+	}
+
+	from, err = e.store.Next()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if from == 0 {
+		from = bounds.Lowest
+	}
+	to = (from-1)/archiveCfg.RangeSize*archiveCfg.RangeSize + archiveCfg.RangeSize
+
+	// Wait as well for the extensions running before archiving to process the range
+	depHeight, bounded, err := e.deps.Height(ctx)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to get dependency height: %w", err)
+	}
+	if bounded && to > depHeight {
+		return from, to, false, nil
+	}
+
+	return from, to, to <= bounds.Latest-archiveCfg.KeepBlocks, nil
+}
+
+// drop deletes the archived rows of the range from the database, tx tables and their edges first
+func (e *ArchiveExtension) drop(ctx context.Context, r archivestore.Range, evm bool) error {
+	deleted, err := prune.PruneBatch(ctx, e.db.DB, r.To, evm)
+	if err != nil {
+		return fmt.Errorf("failed to drop archived txs [%d-%d]: %w", r.From, r.To, err)
+	}
+
+	result := e.db.WithContext(ctx).
+		Where("height <= ?", r.To).
+		Delete(&types.CollectedBlock{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to drop archived blocks [%d-%d]: %w", r.From, r.To, result.Error)
+	}
+
+	if err := e.store.MarkDropped(r.From); err != nil {
+		return err
+	}
+
+	e.logger.Info("dropped archived heights",
+		slog.Int64("from", r.From),
+		slog.Int64("to", r.To),
+		slog.Int64("deleted", deleted+result.RowsAffected))
+	return nil
+}
@@ -0,0 +1,184 @@
+package eventsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/collector/outbox"
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
+	"github.com/initia-labs/rollytics/indexer/sink"
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/types"
+)
+
+const ExtensionName = config.ExtensionEventSink
+
+var _ exttypes.Extension = (*EventSinkExtension)(nil)
+
+// EventSinkExtension publishes the outbox events to the broker in order of their id, and deletes
+// them once the broker acknowledged them. A crash between both steps publishes them again.
+type EventSinkExtension struct {
+	cfg    *config.Config
+	logger *slog.Logger
+	db     *orm.Database
+}
+
+func init() {
+	exttypes.Register(ExtensionName, New)
+}
+
+// New creates a new EventSinkExtension instance
+// Returns nil if no broker is configured
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *EventSinkExtension {
+	if !cfg.ExtensionEnabled(ExtensionName) {
+		return nil
+	}
+
+	return &EventSinkExtension{
+		cfg:    cfg,
+		logger: logger.With("extension", ExtensionName),
+		db:     db,
+	}
+}
+
+func (e *EventSinkExtension) Name() string {
+	return ExtensionName
+}
+
+func (e *EventSinkExtension) Initialize(ctx context.Context) (*types.CollectedEventSinkStatus, error) {
+	var status types.CollectedEventSinkStatus
+	err := e.db.WithContext(ctx).First(&status).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = types.CollectedEventSinkStatus{}
+			if err := e.db.WithContext(ctx).Create(&status).Error; err != nil {
+				return nil, fmt.Errorf("failed to create initial status: %w", err)
+			}
+			e.logger.Info("initialized event sink status")
+			return &status, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve event sink status: %w", err)
+	}
+
+	return &status, nil
+}
+
+func (e *EventSinkExtension) Run(ctx context.Context) error {
+	status, err := e.Initialize(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	sinkCfg := e.cfg.GetEventSinkConfig()
+	s, err := sink.New(sinkCfg, e.logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect the %s event sink: %w", sinkCfg.Broker, err)
+	}
+	defer func() {
+		if err := s.Close(); err != nil {
+			e.logger.Warn("failed to close the event sink", slog.Any("error", err))
+		}
+	}()
+
+	e.logger.Info("starting event sink",
+		slog.String("broker", s.Name()),
+		slog.Int64("last_event_id", status.LastEventId),
+		slog.Int64("last_height", status.LastHeight))
+
+	for {
+		published, err := PublishBatch(ctx, e.db.DB, s, e.cfg.GetChainId(), sinkCfg, status)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the events stay in the outbox, retry once the broker is back
+			e.logger.Error("failed to publish events", slog.Any("error", err))
+			metrics.TrackError("event_sink", "publish")
+		}
+
+		// A full batch suggests a backlog, continue without waiting
+		if err == nil && published == sinkCfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			e.logger.Info("event sink stopped",
+				slog.Int64("last_event_id", status.LastEventId),
+				slog.Int64("published_events", status.PublishedEvents))
+			return ctx.Err()
+		case <-outbox.Committed():
+		case <-time.After(sinkCfg.PollInterval):
+		}
+	}
+}
+
+// PublishBatch publishes the oldest outbox events, then deletes them and advances the status in one
+// transaction. The events are selected by id rather than after the cursor, so that an event which
+// became visible late is still published.
+func PublishBatch(ctx context.Context, db *gorm.DB, s sink.Sink, chainId string, cfg *config.EventSinkConfig, status *types.CollectedEventSinkStatus) (int, error) {
+	var events []types.CollectedEventOutbox
+	if err := db.WithContext(ctx).Order("id").Limit(cfg.BatchSize).Find(&events).Error; err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	messages := make([]sink.Message, 0, len(events))
+	ids := make([]int64, 0, len(events))
+	next := *status
+	for _, event := range events {
+		message, err := sink.Encode(event, chainId, cfg)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode event %d: %w", event.Id, err)
+		}
+		messages = append(messages, message)
+		ids = append(ids, event.Id)
+		next.LastEventId = max(next.LastEventId, event.Id)
+		next.LastHeight = max(next.LastHeight, event.Height)
+	}
+	next.PublishedEvents += int64(len(events))
+
+	if err := s.Publish(ctx, messages); err != nil {
+		return 0, err
+	}
+
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id IN ?", ids).Delete(&types.CollectedEventOutbox{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&types.CollectedEventSinkStatus{}).
+			Where("1 = 1").
+			Updates(map[string]any{
+				"last_event_id":    next.LastEventId,
+				"last_height":      next.LastHeight,
+				"published_events": next.PublishedEvents,
+			}).Error; err != nil {
+			return err
+		}
+		return exttypes.SaveCheckpoint(tx, ExtensionName, exttypes.Checkpoint{
+			Height:   next.LastHeight,
+			Sequence: next.LastEventId,
+			Meta:     map[string]int64{"published_events": next.PublishedEvents},
+		})
+	}); err != nil {
+		return 0, err
+	}
+	*status = next
+
+	if m := metrics.GetMetrics(); m != nil {
+		for _, event := range events {
+			m.IndexerMetrics().EventsPublished.WithLabelValues(event.Type).Inc()
+		}
+	}
+
+	return len(events), nil
+}
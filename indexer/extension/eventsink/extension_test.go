@@ -0,0 +1,119 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/sink"
+	"github.com/initia-labs/rollytics/types"
+)
+
+// memorySink keeps the published messages, or fails while err is set
+type memorySink struct {
+	messages []sink.Message
+	err      error
+}
+
+func (s *memorySink) Name() string { return "memory" }
+
+func (s *memorySink) Publish(_ context.Context, messages []sink.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+func (s *memorySink) Close() error { return nil }
+
+var testConfig = &config.EventSinkConfig{
+	TopicPrefix: "rollytics",
+	BatchSize:   2,
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	// sqlite only assigns the ids of integer primary keys
+	require.NoError(t, db.Exec(`CREATE TABLE event_outbox (
+		id integer PRIMARY KEY AUTOINCREMENT, height integer, type text, key text, payload blob)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE extension_checkpoint (
+		name text PRIMARY KEY, height integer, sequence integer, updated_at datetime, meta blob)`).Error)
+	require.NoError(t, db.AutoMigrate(&types.CollectedEventSinkStatus{}))
+	require.NoError(t, db.Create(&types.CollectedEventSinkStatus{}).Error)
+
+	for i, eventType := range []string{sink.EventTypeBlock, sink.EventTypeTx, sink.EventTypeBlock} {
+		require.NoError(t, db.Create(&types.CollectedEventOutbox{
+			Height:  int64(i/2 + 1),
+			Type:    eventType,
+			Key:     eventType,
+			Payload: json.RawMessage(`{}`),
+		}).Error)
+	}
+	return db
+}
+
+func remainingIds(t *testing.T, db *gorm.DB) []int64 {
+	var ids []int64
+	require.NoError(t, db.Model(&types.CollectedEventOutbox{}).Order("id").Pluck("id", &ids).Error)
+	return ids
+}
+
+func TestPublishBatch(t *testing.T) {
+	db := setupTestDB(t)
+	s := &memorySink{}
+	status := &types.CollectedEventSinkStatus{}
+
+	published, err := PublishBatch(context.Background(), db, s, "test-1", testConfig, status)
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []int64{3}, remainingIds(t, db))
+	require.Len(t, s.messages, 2)
+	assert.Equal(t, "rollytics.block", s.messages[0].Topic)
+	assert.Equal(t, "test-1/2", s.messages[1].Id)
+
+	published, err = PublishBatch(context.Background(), db, s, "test-1", testConfig, status)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Empty(t, remainingIds(t, db))
+
+	var stored types.CollectedEventSinkStatus
+	require.NoError(t, db.First(&stored).Error)
+	assert.Equal(t, types.CollectedEventSinkStatus{LastEventId: 3, LastHeight: 2, PublishedEvents: 3}, stored)
+	assert.Equal(t, stored, *status)
+
+	var checkpoint types.CollectedExtensionCheckpoint
+	require.NoError(t, db.Where("name = ?", ExtensionName).First(&checkpoint).Error)
+	assert.Equal(t, int64(2), checkpoint.Height)
+	assert.Equal(t, int64(3), checkpoint.Sequence)
+
+	published, err = PublishBatch(context.Background(), db, s, "test-1", testConfig, status)
+	require.NoError(t, err)
+	assert.Zero(t, published)
+}
+
+func TestPublishBatch_KeepsEventsOnFailure(t *testing.T) {
+	db := setupTestDB(t)
+	s := &memorySink{err: errors.New("broker unavailable")}
+	status := &types.CollectedEventSinkStatus{}
+
+	_, err := PublishBatch(context.Background(), db, s, "test-1", testConfig, status)
+	require.Error(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, remainingIds(t, db))
+	assert.Zero(t, status.LastEventId)
+
+	// the same events are published once the broker is back
+	s.err = nil
+	published, err := PublishBatch(context.Background(), db, s, "test-1", testConfig, status)
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, "test-1/1", s.messages[0].Id)
+}
@@ -16,12 +16,16 @@ import (
 )
 
 const (
-	ExtensionName = "evm-ret-cleanup"
+	ExtensionName = config.ExtensionEvmRetCleanup
 	BatchSize     = 1000 // Process 1000 blocks per batch
 )
 
 var _ exttypes.Extension = (*EvmRetCleanupExtension)(nil)
 
+func init() {
+	exttypes.Register(ExtensionName, New)
+}
+
 type EvmRetCleanupExtension struct {
 	cfg    *config.Config
 	logger *slog.Logger
@@ -31,7 +35,7 @@ type EvmRetCleanupExtension struct {
 // New creates a new EvmRetCleanupExtension instance
 // Returns nil if the chain is not EVM-based or if the extension is disabled
 func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *EvmRetCleanupExtension {
-	if cfg.GetVmType() != types.EVM || !cfg.EvmRetCleanupEnabled() {
+	if !cfg.ExtensionEnabled(ExtensionName) {
 		return nil
 	}
 
@@ -63,9 +67,14 @@ func (e *EvmRetCleanupExtension) Initialize(ctx context.Context) (*types.Collect
 				return nil, fmt.Errorf("failed to create initial status: %w", err)
 			}
 			e.logger.Info("initialized cleanup status")
-			return &status, nil
+		} else {
+			return nil, fmt.Errorf("failed to retrieve cleanup status: %w", err)
 		}
-		return nil, fmt.Errorf("failed to retrieve cleanup status: %w", err)
+	}
+
+	// Publish the progress for the extensions running after this one
+	if err := exttypes.SaveCheckpoint(e.db.WithContext(ctx), ExtensionName, exttypes.Checkpoint{Height: status.LastCleanedHeight}); err != nil {
+		return nil, fmt.Errorf("failed to save checkpoint: %w", err)
 	}
 
 	return &status, nil
@@ -141,13 +150,18 @@ func (e *EvmRetCleanupExtension) Run(ctx context.Context) error {
 	}
 }
 
-// updateStatus updates the status in the database
+// updateStatus updates the status and the checkpoint in the database
 func (e *EvmRetCleanupExtension) updateStatus(ctx context.Context, status *types.CollectedEvmRetCleanupStatus) error {
-	return e.db.WithContext(ctx).
-		Model(&types.CollectedEvmRetCleanupStatus{}).
-		Where("1 = 1"). // Update the single row
-		Updates(map[string]interface{}{
-			"last_cleaned_height": status.LastCleanedHeight,
-			"corrected_records":   status.CorrectedRecords,
-		}).Error
+	return e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&types.CollectedEvmRetCleanupStatus{}).
+			Where("1 = 1"). // Update the single row
+			Updates(map[string]interface{}{
+				"last_cleaned_height": status.LastCleanedHeight,
+				"corrected_records":   status.CorrectedRecords,
+			}).Error; err != nil {
+			return err
+		}
+		return exttypes.SaveCheckpoint(tx, ExtensionName, exttypes.Checkpoint{Height: status.LastCleanedHeight})
+	})
 }
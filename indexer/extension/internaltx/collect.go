@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
 	indexerutil "github.com/initia-labs/rollytics/indexer/util"
 	"github.com/initia-labs/rollytics/orm"
 	"github.com/initia-labs/rollytics/sentry_integration"
@@ -26,7 +27,18 @@ type InternalTxResult struct {
 }
 
 func (i *InternalTxExtension) CollectInternalTxs(ctx context.Context, db *orm.Database, internalTx *InternalTxResult) error {
-	err := db.Transaction(func(tx *gorm.DB) error {
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the partitioned table keys the rows by sequence as well, so a replayed height is skipped here
+		var indexed bool
+		if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM evm_internal_tx WHERE height = ?)", internalTx.Height).
+			Scan(&indexed).Error; err != nil {
+			return types.NewDatabaseError("check indexed internal txs", err)
+		}
+		if indexed {
+			i.logger.Info("evm internal tx already indexed", slog.Int64("height", internalTx.Height))
+			return nil
+		}
+
 		seqInfo, err := indexerutil.GetSeqInfo(types.SeqInfoEvmInternalTx, tx)
 		if err != nil {
 			return err
@@ -120,6 +132,14 @@ func (i *InternalTxExtension) CollectInternalTxs(ctx context.Context, db *orm.Da
 			span.Finish() // Finish span before return
 			return err
 		}
+
+		if err := exttypes.SaveCheckpoint(tx, ExtensionName, exttypes.Checkpoint{
+			Height:   internalTx.Height,
+			Sequence: seqInfo.Sequence,
+		}); err != nil {
+			span.Finish() // Finish span before return
+			return err
+		}
 		span.Finish() // Finish span at the end of successful iteration
 
 		return nil
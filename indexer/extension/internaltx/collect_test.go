@@ -119,6 +119,7 @@ func TestIndexer_CollectInternalTxs(t *testing.T) {
 		NftCacheSize:              1000,
 		MsgTypeCacheSize:          100,
 		TypeTagCacheSize:          100,
+		MoveFunctionCacheSize:     100,
 		MoveDenomCacheSize:        1000,
 		EvmTxHashCacheSize:        1000,
 		EvmDenomContractCacheSize: 1000,
@@ -128,6 +129,7 @@ func TestIndexer_CollectInternalTxs(t *testing.T) {
 	indexer := internaltx.New(cfg, logger, db)
 
 	mock.ExpectBegin()
+	expectIndexed(mock, 100, false)
 	mock.ExpectQuery(`SELECT \* FROM "seq_info" WHERE name = \$1 ORDER BY "seq_info"\."name" LIMIT \$2`).
 		WithArgs("evm_internal_tx", 1).
 		WillReturnRows(sqlmock.NewRows([]string{"name", "sequence"}).
@@ -298,6 +300,9 @@ func TestIndexer_CollectInternalTxs(t *testing.T) {
 		WithArgs("evm_internal_tx", int64(4)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	mock.ExpectExec(`INSERT INTO "extension_checkpoint".*ON CONFLICT \("name"\) DO UPDATE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	mock.ExpectCommit()
 
 	testRes := getTestResponse()
@@ -314,6 +319,7 @@ func TestIndexer_CollectInternalTxs_EmptyInternalTxs(t *testing.T) {
 		NftCacheSize:              1000,
 		MsgTypeCacheSize:          100,
 		TypeTagCacheSize:          100,
+		MoveFunctionCacheSize:     100,
 		EvmTxHashCacheSize:        1000,
 		EvmDenomContractCacheSize: 1000,
 		ValidatorCacheSize:        100,
@@ -325,6 +331,7 @@ func TestIndexer_CollectInternalTxs_EmptyInternalTxs(t *testing.T) {
 	indexer := internaltx.New(cfg, logger, db)
 
 	mock.ExpectBegin()
+	expectIndexed(mock, 100, false)
 
 	mock.ExpectQuery(`SELECT \* FROM "seq_info" WHERE name = \$1 ORDER BY "seq_info"\."name" LIMIT \$2`).
 		WithArgs("evm_internal_tx", 1).
@@ -373,6 +380,9 @@ func TestIndexer_CollectInternalTxs_EmptyInternalTxs(t *testing.T) {
 		WithArgs("evm_internal_tx", int64(1)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	mock.ExpectExec(`INSERT INTO "extension_checkpoint".*ON CONFLICT \("name"\) DO UPDATE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	mock.ExpectCommit()
 
 	callTraceRes := &types.DebugCallTraceBlockResponse{
@@ -396,3 +406,23 @@ func TestIndexer_CollectInternalTxs_EmptyInternalTxs(t *testing.T) {
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func expectIndexed(mock sqlmock.Sqlmock, height int64, indexed bool) {
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM evm_internal_tx WHERE height = \$1\)`).
+		WithArgs(height).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(indexed))
+}
+
+func TestIndexer_CollectInternalTxs_AlreadyIndexed(t *testing.T) {
+	db, mock := setupTestDB(t)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	indexer := internaltx.New(setupTestConfig(), logger, db)
+
+	// a replayed height inserts nothing, its rows would take new sequences
+	mock.ExpectBegin()
+	expectIndexed(mock, 100, true)
+	mock.ExpectCommit()
+
+	require.NoError(t, indexer.CollectInternalTxs(context.Background(), db, getTestResponse()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -20,7 +20,7 @@ import (
 	"github.com/initia-labs/rollytics/util/querier"
 )
 
-const ExtensionName = "internal-tx"
+const ExtensionName = config.ExtensionInternalTx
 
 var _ exttypes.Extension = (*InternalTxExtension)(nil)
 
@@ -94,8 +94,12 @@ type InternalTxExtension struct {
 	querier            *querier.Querier
 }
 
+func init() {
+	exttypes.Register(ExtensionName, New)
+}
+
 func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *InternalTxExtension {
-	if cfg.GetVmType() != types.EVM || !cfg.InternalTxEnabled() {
+	if !cfg.ExtensionEnabled(ExtensionName) {
 		return nil
 	}
 
@@ -117,6 +121,12 @@ func (i *InternalTxExtension) Initialize(ctx context.Context) error {
 		return err
 	}
 
+	// Publish the progress for the extensions running after this one
+	if err := exttypes.SaveCheckpoint(i.db.WithContext(ctx), ExtensionName, exttypes.Checkpoint{Height: lastItx.Height}); err != nil {
+		i.logger.Error("failed to save the checkpoint", slog.Any("error", err))
+		return err
+	}
+
 	i.lastProducedHeight = lastItx.Height
 	return nil
 }
@@ -131,6 +141,8 @@ func (i *InternalTxExtension) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to initialize internal transaction extension: %w", err)
 	}
 
+	// A fresh queue per run, as the extension restarts after a leader handover
+	i.workQueue = NewWorkQueue(i.cfg.GetInternalTxConfig().GetQueueSize())
 	// Ensure work queue is closed when function exits
 	defer i.workQueue.Close()
 
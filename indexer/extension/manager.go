@@ -2,51 +2,83 @@ package extension
 
 import (
 	"context"
-	"errors"
 	"log/slog"
-
-	"golang.org/x/sync/errgroup"
+	"sync"
+	"time"
 
 	"github.com/initia-labs/rollytics/config"
-	evmret "github.com/initia-labs/rollytics/indexer/extension/evmret"
-	internaltx "github.com/initia-labs/rollytics/indexer/extension/internaltx"
-	richlist "github.com/initia-labs/rollytics/indexer/extension/richlist"
-	txaccountcleanup "github.com/initia-labs/rollytics/indexer/extension/txaccountcleanup"
 	"github.com/initia-labs/rollytics/indexer/extension/types"
+	"github.com/initia-labs/rollytics/indexer/leader"
 	"github.com/initia-labs/rollytics/orm"
+
+	// The extensions register themselves to the registry
+	_ "github.com/initia-labs/rollytics/indexer/extension/archive"
+	_ "github.com/initia-labs/rollytics/indexer/extension/eventsink"
+	_ "github.com/initia-labs/rollytics/indexer/extension/evmret"
+	_ "github.com/initia-labs/rollytics/indexer/extension/internaltx"
+	_ "github.com/initia-labs/rollytics/indexer/extension/prune"
+	_ "github.com/initia-labs/rollytics/indexer/extension/richlist"
+	_ "github.com/initia-labs/rollytics/indexer/extension/txaccountcleanup"
+	_ "github.com/initia-labs/rollytics/indexer/extension/webhook"
 )
 
+// managedExtension is an enabled extension along with the enabled extensions it runs after
+type managedExtension struct {
+	types.Extension
+	deps *types.Dependencies
+}
+
 type ExtensionManager struct {
 	cfg        *config.Config
 	logger     *slog.Logger
 	db         *orm.Database
-	extensions []types.Extension
+	extensions []managedExtension
+	controls   map[string]*control
+	elector    *leader.Elector
 }
 
-func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *ExtensionManager {
-	var extensions []types.Extension
-	// Internal Transaction
-	if itxIndexer := internaltx.New(cfg, logger, db); itxIndexer != nil {
-		extensions = append(extensions, itxIndexer)
+// New builds the registered extensions selected by EXTENSIONS and enabled by their config,
+// ordered by their dependencies
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) (*ExtensionManager, error) {
+	regs, err := types.Select(cfg.GetExtensionsConfig().Names)
+	if err != nil {
+		return nil, err
 	}
-	// Rich List
-	if rlIndexer := richlist.New(cfg, logger, db); rlIndexer != nil {
-		extensions = append(extensions, rlIndexer)
-	}
-	// EVM Ret Cleanup
-	if retCleanup := evmret.New(cfg, logger, db); retCleanup != nil {
-		extensions = append(extensions, retCleanup)
-	}
-	// TX Account Cleanup
-	if taCleanup := txaccountcleanup.New(cfg, logger, db); taCleanup != nil {
-		extensions = append(extensions, taCleanup)
+
+	var extensions []managedExtension
+	controls := make(map[string]*control, len(regs))
+	enabled := make(map[string]bool, len(regs))
+	for _, reg := range regs {
+		ext := reg.Factory(cfg, logger, db)
+		if ext == nil {
+			continue
+		}
+
+		// Only the enabled dependencies are waited for
+		var depNames []string
+		for _, dep := range reg.After {
+			if enabled[dep] {
+				depNames = append(depNames, dep)
+			}
+		}
+		enabled[reg.Name] = true
+
+		deps := types.NewDependencies(db.DB, depNames)
+		if dependent, ok := ext.(types.Dependent); ok {
+			dependent.SetDependencies(deps)
+		}
+		extensions = append(extensions, managedExtension{Extension: ext, deps: deps})
+		controls[ext.Name()] = newControl()
 	}
+
 	return &ExtensionManager{
 		cfg:        cfg,
 		logger:     logger,
 		db:         db,
 		extensions: extensions,
-	}
+		controls:   controls,
+		elector:    leader.New(cfg, logger, db, leader.WorkloadExtensions),
+	}, nil
 }
 
 func (m *ExtensionManager) Run(ctx context.Context) {
@@ -55,43 +87,49 @@ func (m *ExtensionManager) Run(ctx context.Context) {
 		return
 	}
 
-	// Use context-aware errgroup for coordinated shutdown
-	g, gCtx := errgroup.WithContext(ctx)
+	// The extensions run only on the replica leading them, and restart after a takeover.
+	// A failure stays within the extensions and never stops the indexing.
+	if err := m.elector.Run(ctx, m.run, nil); err != nil {
+		m.logger.Error("Extension manager stopped", slog.Any("error", err))
+	}
+}
 
+// run supervises every extension on its own, so that a failing extension neither cancels the
+// others nor the indexer, until the context is done
+func (m *ExtensionManager) run(ctx context.Context) error {
+	var wg sync.WaitGroup
 	for _, extension := range m.extensions {
-		ext := extension // Capture for goroutine
-		g.Go(func() error {
-			m.logger.Info("Starting extension", slog.String("name", ext.Name()))
-
-			// All extensions now implement Run(ctx)
-			err := ext.Run(gCtx)
-			if err != nil {
-				if errors.Is(err, context.Canceled) {
-					m.logger.Info("Extension stopped",
-						slog.String("name", ext.Name()),
-						slog.String("reason", "context cancelled"))
-					return nil // Context cancellation is expected during shutdown
-				}
-
-				m.logger.Error("Extension error",
-					slog.String("name", ext.Name()),
-					slog.Any("error", err))
-				return err
-			}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.supervise(ctx, extension)
+		}()
+	}
+	wg.Wait()
 
-			m.logger.Info("Extension completed", slog.String("name", ext.Name()))
+	m.logger.Info("Extension manager shutdown complete")
+	return nil
+}
+
+// waitForDependencies blocks until every enabled dependency of the extension saved a checkpoint
+func (m *ExtensionManager) waitForDependencies(ctx context.Context, ext managedExtension) error {
+	interval := m.cfg.GetExtensionsConfig().DependencyPollInterval
+	for {
+		missing, err := ext.deps.Missing(ctx)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
 			return nil
-		})
-	}
+		}
 
-	// Wait for all extensions to complete
-	if err := g.Wait(); err != nil {
-		if !errors.Is(err, context.Canceled) {
-			m.logger.Error("Extension manager fatal error", slog.Any("error", err))
-			// Only panic for actual errors, not context cancellation
-			panic(err)
+		m.logger.Info("Extension waiting for dependencies",
+			slog.String("name", ext.Name()),
+			slog.Any("missing", missing))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
 		}
 	}
-
-	m.logger.Info("Extension manager shutdown complete")
 }
@@ -0,0 +1,86 @@
+package extension
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/types"
+)
+
+func newTestConfig(names ...string) *config.Config {
+	cfg := &config.Config{}
+	cfg.SetChainConfig(&config.ChainConfig{ChainId: "test-1", VmType: types.MoveVM})
+	cfg.SetRichListConfig(&config.RichListConfig{Enabled: true})
+	cfg.SetPruneConfig(&config.PruneConfig{KeepBlocks: 100, BatchSize: 10})
+	cfg.SetExtensionsConfig(&config.ExtensionsConfig{Names: names, DependencyPollInterval: 1})
+	return cfg
+}
+
+func extensionNames(m *ExtensionManager) []string {
+	var names []string
+	for _, ext := range m.extensions {
+		names = append(names, ext.Name())
+	}
+	return names
+}
+
+func TestNew(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	db := &orm.Database{}
+
+	// prune runs after the rich list it depends on
+	m, err := New(newTestConfig(), logger, db)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rich-list", "prune"}, extensionNames(m))
+	assert.Empty(t, m.extensions[0].deps.Names())
+	assert.Equal(t, []string{"rich-list"}, m.extensions[1].deps.Names())
+
+	// an extension left out of EXTENSIONS is not waited for
+	m, err = New(newTestConfig("prune"), logger, db)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prune"}, extensionNames(m))
+	assert.Empty(t, m.extensions[0].deps.Names())
+
+	// selected but disabled by its config
+	m, err = New(newTestConfig("webhook"), logger, db)
+	require.NoError(t, err)
+	assert.Empty(t, m.extensions)
+
+	_, err = New(newTestConfig("rich-list", "unknown"), logger, db)
+	assert.ErrorContains(t, err, "unknown")
+}
+
+func TestEnabledExtensions(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	db := &orm.Database{}
+
+	// the extensions listed by the config are the ones the manager runs, in the same order
+	for _, names := range [][]string{nil, {"prune"}, {"webhook"}} {
+		m, err := New(newTestConfig(names...), logger, db)
+		require.NoError(t, err)
+		assert.Equal(t, extensionNames(m), newTestConfig(names...).EnabledExtensions())
+	}
+
+	evmCfg := newTestConfig()
+	evmCfg.GetChainConfig().VmType = types.EVM
+	evmCfg.SetInternalTxConfig(&config.InternalTxConfig{Enabled: true})
+	evmCfg.SetEvmRetCleanupConfig(&config.EvmRetCleanupConfig{Enabled: true})
+	m, err := New(evmCfg, logger, db)
+	require.NoError(t, err)
+	assert.Equal(t, extensionNames(m), evmCfg.EnabledExtensions())
+
+	// every registered extension is known to the config
+	known := []string{
+		config.ExtensionEventSink, config.ExtensionInternalTx, config.ExtensionRichList, config.ExtensionEvmRetCleanup,
+		config.ExtensionTxAccountCleanup, config.ExtensionWebhook, config.ExtensionPrune, config.ExtensionArchive,
+	}
+	for _, reg := range exttypes.Registered() {
+		assert.Contains(t, known, reg.Name)
+	}
+}
@@ -0,0 +1,166 @@
+package prune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	evmret "github.com/initia-labs/rollytics/indexer/extension/evmret"
+	internaltx "github.com/initia-labs/rollytics/indexer/extension/internaltx"
+	richlist "github.com/initia-labs/rollytics/indexer/extension/richlist"
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
+	webhook "github.com/initia-labs/rollytics/indexer/extension/webhook"
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/types"
+)
+
+const ExtensionName = config.ExtensionPrune
+
+var (
+	_ exttypes.Extension = (*PruneExtension)(nil)
+	_ exttypes.Dependent = (*PruneExtension)(nil)
+)
+
+// the txs are only removed once the extensions reading them processed their heights
+func init() {
+	exttypes.Register(ExtensionName, New, exttypes.After(
+		internaltx.ExtensionName,
+		richlist.ExtensionName,
+		evmret.ExtensionName,
+		webhook.ExtensionName,
+	))
+}
+
+type PruneExtension struct {
+	cfg    *config.Config
+	logger *slog.Logger
+	db     *orm.Database
+	deps   *exttypes.Dependencies
+}
+
+// New creates a new PruneExtension instance
+// Returns nil if no retention window is configured
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *PruneExtension {
+	if !cfg.ExtensionEnabled(ExtensionName) {
+		return nil
+	}
+
+	return &PruneExtension{
+		cfg:    cfg,
+		logger: logger.With("extension", ExtensionName),
+		db:     db,
+	}
+}
+
+func (e *PruneExtension) Name() string {
+	return ExtensionName
+}
+
+func (e *PruneExtension) SetDependencies(deps *exttypes.Dependencies) {
+	e.deps = deps
+}
+
+func (e *PruneExtension) Initialize(ctx context.Context) (*types.CollectedPruneStatus, error) {
+	var status types.CollectedPruneStatus
+	err := e.db.WithContext(ctx).First(&status).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status = types.CollectedPruneStatus{}
+			if err := e.db.WithContext(ctx).Create(&status).Error; err != nil {
+				return nil, fmt.Errorf("failed to create initial status: %w", err)
+			}
+			e.logger.Info("initialized prune status")
+			return &status, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve prune status: %w", err)
+	}
+
+	return &status, nil
+}
+
+func (e *PruneExtension) Run(ctx context.Context) error {
+	status, err := e.Initialize(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	pruneCfg := e.cfg.GetPruneConfig()
+	evm := e.cfg.GetVmType() == types.EVM
+
+	e.logger.Info("starting prune",
+		slog.Int64("pruned_height", status.PrunedHeight),
+		slog.Int64("keep_blocks", pruneCfg.KeepBlocks),
+		slog.Duration("keep_duration", pruneCfg.KeepDuration))
+
+	for {
+		target, err := Target(ctx, e.db.DB, pruneCfg, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// Keep the heights the extensions running before pruning have not processed yet
+		depHeight, bounded, err := e.deps.Height(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get dependency height: %w", err)
+		}
+		if bounded {
+			target = min(target, depHeight)
+		}
+
+		// Wait for the retention window to move past the pruned height
+		if target <= status.PrunedHeight {
+			select {
+			case <-ctx.Done():
+				e.logger.Info("prune stopped",
+					slog.Int64("pruned_height", status.PrunedHeight),
+					slog.Int64("deleted_records", status.DeletedRecords))
+				return ctx.Err()
+			case <-time.After(pruneCfg.Interval):
+			}
+			continue
+		}
+
+		endHeight := min(status.PrunedHeight+pruneCfg.BatchSize, target)
+
+		deleted, err := PruneBatch(ctx, e.db.DB, endHeight, evm)
+		if err != nil {
+			return fmt.Errorf("failed to prune heights [%d-%d]: %w", status.PrunedHeight+1, endHeight, err)
+		}
+
+		status.PrunedHeight = endHeight
+		status.DeletedRecords += deleted
+
+		if err := e.updateStatus(ctx, status); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+
+		if deleted > 0 {
+			e.logger.Info("pruned heights",
+				slog.Int64("pruned_height", endHeight),
+				slog.Int64("batch_deleted", deleted))
+		}
+	}
+}
+
+func (e *PruneExtension) updateStatus(ctx context.Context, status *types.CollectedPruneStatus) error {
+	return e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&types.CollectedPruneStatus{}).
+			Where("1 = 1").
+			Updates(map[string]any{
+				"pruned_height":   status.PrunedHeight,
+				"deleted_records": status.DeletedRecords,
+			}).Error; err != nil {
+			return err
+		}
+		return exttypes.SaveCheckpoint(tx, ExtensionName, exttypes.Checkpoint{
+			Height: status.PrunedHeight,
+			Meta:   map[string]int64{"deleted_records": status.DeletedRecords},
+		})
+	})
+}
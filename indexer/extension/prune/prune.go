@@ -0,0 +1,120 @@
+package prune
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/types"
+)
+
+// txTable is a table of records keyed by height, whose edge tables reference them by sequence
+type txTable struct {
+	name  string
+	edges []string
+}
+
+var (
+	cosmosTables = []txTable{
+		{
+			name: types.CollectedTx{}.TableName(),
+			edges: []string{
+				types.CollectedTxAccount{}.TableName(),
+				types.CollectedTxNft{}.TableName(),
+				types.CollectedTxMsgType{}.TableName(),
+				types.CollectedTxTypeTag{}.TableName(),
+				types.CollectedTxMsg{}.TableName(),
+				types.CollectedTxMoveCall{}.TableName(),
+			},
+		},
+	}
+	evmTables = []txTable{
+		{
+			name:  types.CollectedEvmTx{}.TableName(),
+			edges: []string{types.CollectedEvmTxAccount{}.TableName()},
+		},
+		{
+			name:  types.CollectedEvmInternalTx{}.TableName(),
+			edges: []string{types.CollectedEvmInternalTxAccount{}.TableName()},
+		},
+	}
+)
+
+// Target returns the highest height whose txs are outside the retention window, or 0 if there is none.
+// With both KeepBlocks and KeepDuration set, a height must be outside both windows.
+func Target(ctx context.Context, db *gorm.DB, cfg *config.PruneConfig, now time.Time) (int64, error) {
+	var latest int64
+	if err := db.WithContext(ctx).
+		Model(&types.CollectedBlock{}).
+		Select("COALESCE(MAX(height), 0)").
+		Scan(&latest).Error; err != nil {
+		return 0, fmt.Errorf("failed to get latest height: %w", err)
+	}
+
+	target := latest
+	if cfg.KeepBlocks > 0 {
+		target = min(target, latest-cfg.KeepBlocks)
+	}
+	if cfg.KeepDuration > 0 {
+		var expired int64
+		if err := db.WithContext(ctx).
+			Model(&types.CollectedBlock{}).
+			Select("COALESCE(MAX(height), 0)").
+			Where("timestamp < ?", now.Add(-cfg.KeepDuration)).
+			Scan(&expired).Error; err != nil {
+			return 0, fmt.Errorf("failed to get expired height: %w", err)
+		}
+		target = min(target, expired)
+	}
+
+	return max(target, 0), nil
+}
+
+// PruneBatch deletes the txs of the heights up to endHeight with their edges in a single transaction,
+// and returns the number of deleted rows. Earlier heights are included, so that rows indexed late,
+// e.g. by the internal tx extension, below the pruned height are deleted as well.
+// Blocks, NFT state, dictionaries and the rich list are kept.
+func PruneBatch(ctx context.Context, db *gorm.DB, endHeight int64, evm bool) (deleted int64, err error) {
+	tables := cosmosTables
+	if evm {
+		tables = append(tables[:len(tables):len(tables)], evmTables...)
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			var maxSeq int64
+			if err := tx.Table(table.name).
+				Select("COALESCE(MAX(sequence), 0)").
+				Where("height <= ?", endHeight).
+				Scan(&maxSeq).Error; err != nil {
+				return fmt.Errorf("failed to get max sequence of %s: %w", table.name, err)
+			}
+			if maxSeq == 0 {
+				continue
+			}
+
+			for _, edge := range table.edges {
+				result := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE sequence <= ?", edge), maxSeq)
+				if result.Error != nil {
+					return fmt.Errorf("failed to delete %s: %w", edge, result.Error)
+				}
+				deleted += result.RowsAffected
+			}
+
+			result := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE height <= ?", table.name), endHeight)
+			if result.Error != nil {
+				return fmt.Errorf("failed to delete %s: %w", table.name, result.Error)
+			}
+			deleted += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
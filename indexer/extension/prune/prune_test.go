@@ -0,0 +1,133 @@
+package prune
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/types"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(
+		&types.CollectedBlock{},
+		&types.CollectedTx{},
+		&types.CollectedTxAccount{},
+		&types.CollectedTxNft{},
+		&types.CollectedTxMsgType{},
+		&types.CollectedTxTypeTag{},
+		&types.CollectedTxMsg{},
+		&types.CollectedTxMoveCall{},
+		&types.CollectedEvmTx{},
+		&types.CollectedEvmTxAccount{},
+		&types.CollectedEvmInternalTx{},
+		&types.CollectedEvmInternalTxAccount{},
+		&types.CollectedNftCollection{},
+		&types.CollectedAccountDict{},
+	)
+	require.NoError(t, err)
+
+	return db
+}
+
+func count(t *testing.T, db *gorm.DB, model any) int64 {
+	var n int64
+	require.NoError(t, db.Model(model).Count(&n).Error)
+	return n
+}
+
+func TestTarget(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// one block per hour, height 24 is the latest
+	for h := int64(1); h <= 24; h++ {
+		require.NoError(t, db.Create(&types.CollectedBlock{
+			ChainId:   "test-chain",
+			Height:    h,
+			Timestamp: now.Add(time.Duration(h-24) * time.Hour),
+		}).Error)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.PruneConfig
+		expected int64
+	}{
+		{"keep blocks", config.PruneConfig{KeepBlocks: 10}, 14},
+		{"keep duration", config.PruneConfig{KeepDuration: 5*time.Hour + time.Minute}, 18},
+		{"keep both", config.PruneConfig{KeepBlocks: 10, KeepDuration: 5 * time.Hour}, 14},
+		{"window larger than the chain", config.PruneConfig{KeepBlocks: 100}, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			target, err := Target(context.Background(), db, &tc.cfg, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, target)
+		})
+	}
+}
+
+func TestPruneBatch(t *testing.T) {
+	db := setupTestDB(t)
+
+	// two txs per height, sequences 1..10 for heights 1..5
+	for h := int64(1); h <= 5; h++ {
+		for i := int64(0); i < 2; i++ {
+			seq := (h-1)*2 + i + 1
+			require.NoError(t, db.Create(&types.CollectedTx{Hash: []byte{byte(seq)}, Height: h, Sequence: seq}).Error)
+			require.NoError(t, db.Create(&types.CollectedTxAccount{AccountId: 1, Sequence: seq}).Error)
+			require.NoError(t, db.Create(&types.CollectedTxMsgType{MsgTypeId: 1, Sequence: seq}).Error)
+		}
+		require.NoError(t, db.Create(&types.CollectedEvmTx{Hash: []byte{byte(h)}, Height: h, Sequence: h}).Error)
+		require.NoError(t, db.Create(&types.CollectedEvmTxAccount{AccountId: 1, Sequence: h}).Error)
+		require.NoError(t, db.Create(&types.CollectedEvmInternalTx{Height: h, HashId: h, Sequence: h}).Error)
+		require.NoError(t, db.Create(&types.CollectedEvmInternalTxAccount{AccountId: 1, Sequence: h}).Error)
+	}
+	require.NoError(t, db.Create(&types.CollectedNftCollection{Addr: []byte{1}, Height: 1, NftCount: 1}).Error)
+	require.NoError(t, db.Create(&types.CollectedAccountDict{Id: 1, Account: []byte{1}}).Error)
+
+	deleted, err := PruneBatch(context.Background(), db, 3, true)
+	require.NoError(t, err)
+	// 6 txs with 2 edges each, 3 evm txs, 3 internal txs and their edges
+	assert.Equal(t, int64(6*3+3*2+3*2), deleted)
+
+	assert.Equal(t, int64(4), count(t, db, &types.CollectedTx{}))
+	assert.Equal(t, int64(4), count(t, db, &types.CollectedTxAccount{}))
+	assert.Equal(t, int64(4), count(t, db, &types.CollectedTxMsgType{}))
+	assert.Equal(t, int64(2), count(t, db, &types.CollectedEvmTx{}))
+	assert.Equal(t, int64(2), count(t, db, &types.CollectedEvmTxAccount{}))
+	assert.Equal(t, int64(2), count(t, db, &types.CollectedEvmInternalTx{}))
+	assert.Equal(t, int64(2), count(t, db, &types.CollectedEvmInternalTxAccount{}))
+
+	// current NFT state and dictionaries are kept
+	assert.Equal(t, int64(1), count(t, db, &types.CollectedNftCollection{}))
+	assert.Equal(t, int64(1), count(t, db, &types.CollectedAccountDict{}))
+
+	// a pruned range holds nothing to delete
+	deleted, err = PruneBatch(context.Background(), db, 3, true)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestPruneBatch_SkipsEvmTablesOnOtherVMs(t *testing.T) {
+	db := setupTestDB(t)
+
+	require.NoError(t, db.Create(&types.CollectedTx{Hash: []byte{1}, Height: 1, Sequence: 1}).Error)
+	require.NoError(t, db.Create(&types.CollectedEvmTx{Hash: []byte{1}, Height: 1, Sequence: 1}).Error)
+
+	deleted, err := PruneBatch(context.Background(), db, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, int64(1), count(t, db, &types.CollectedEvmTx{}))
+}
@@ -19,10 +19,14 @@ import (
 	"github.com/initia-labs/rollytics/util/querier"
 )
 
-const ExtensionName = "rich-list"
+const ExtensionName = richlistutils.ExtensionName
 
 var _ exttypes.Extension = (*RichListExtension)(nil)
 
+func init() {
+	exttypes.Register(ExtensionName, New)
+}
+
 type RichListExtension struct {
 	cfg         *config.Config
 	logger      *slog.Logger
@@ -33,7 +37,7 @@ type RichListExtension struct {
 }
 
 func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *RichListExtension {
-	if !cfg.GetRichListEnabled() {
+	if !cfg.ExtensionEnabled(ExtensionName) {
 		return nil
 	}
 
@@ -84,6 +88,12 @@ func (r *RichListExtension) Initialize(ctx context.Context) error {
 		return err
 	}
 
+	// Publish the progress for the extensions running after this one
+	if err := exttypes.SaveCheckpoint(r.db.WithContext(ctx), ExtensionName, exttypes.Checkpoint{Height: lastHeight.Height}); err != nil {
+		r.logger.Error("failed to save the richlist checkpoint", slog.Any("error", err))
+		return err
+	}
+
 	r.startHeight = lastHeight.Height + 1
 	return nil
 }
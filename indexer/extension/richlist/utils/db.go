@@ -8,10 +8,15 @@ import (
 	sdkmath "cosmossdk.io/math"
 	"gorm.io/gorm"
 
+	"github.com/initia-labs/rollytics/config"
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
 	"github.com/initia-labs/rollytics/types"
 	"github.com/initia-labs/rollytics/util/cache"
 )
 
+// ExtensionName is the name of the rich list extension, which its checkpoint is saved under
+const ExtensionName = config.ExtensionRichList
+
 // RICH_LIST_BLOCK_DELAY requires richlist processing to lag by at least 5 blocks.
 const RICH_LIST_BLOCK_DELAY = 5
 
@@ -192,7 +197,7 @@ func GetAllAddresses(ctx context.Context, db *gorm.DB, vmType types.VMType) ([]A
 	return addresses, nil
 }
 
-// UpdateRichListStatus updates the rich_list_status table with the current height.
+// UpdateRichListStatus updates the rich_list_status table and the extension checkpoint with the current height.
 // This should be called before incrementing the height to track progress of the rich list indexer.
 //
 // Parameters:
@@ -222,6 +227,10 @@ func UpdateRichListStatus(ctx context.Context, db *gorm.DB, currentHeight int64)
 		return fmt.Errorf("failed to update rich list status: %w", result.Error)
 	}
 
+	if err := exttypes.SaveCheckpoint(db.WithContext(ctx), ExtensionName, exttypes.Checkpoint{Height: currentHeight}); err != nil {
+		return fmt.Errorf("failed to save rich list checkpoint: %w", err)
+	}
+
 	return nil
 }
 
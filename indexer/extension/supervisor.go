@@ -0,0 +1,276 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/types"
+)
+
+// State is the supervision state of an extension in this process
+type State string
+
+const (
+	StateWaiting    State = "waiting"    // waiting for its dependencies to checkpoint
+	StateRunning    State = "running"    // running
+	StateRestarting State = "restarting" // backing off before a restart after a failure
+	StatePaused     State = "paused"     // paused through the admin API until resumed
+	StateDegraded   State = "degraded"   // stopped after exceeding its restarts, the others keep running
+	StateCompleted  State = "completed"  // returned without an error, e.g. a finished cleanup
+	StateStopped    State = "stopped"    // stopped on shutdown or after losing leadership
+)
+
+// Health is the supervision state of an extension along with its recent failures
+type Health struct {
+	State     State
+	Restarts  int // consecutive restarts
+	LastError string
+}
+
+// HealthOf returns the health of the extension, or false when the extension is not supervised
+// by this process, e.g. on the API server or a standby replica before it ever led
+func HealthOf(name string) (Health, bool) {
+	health, ok := types.ExtensionHealthOf(name)
+	if !ok {
+		return Health{}, false
+	}
+	return Health{State: State(health.State), Restarts: health.Restarts, LastError: health.LastError}, true
+}
+
+func setHealth(name string, health Health) {
+	types.SetExtensionHealth(name, types.ExtensionHealth{
+		State:     string(health.State),
+		Restarts:  health.Restarts,
+		LastError: health.LastError,
+	})
+
+	if m := metrics.GetMetrics(); m != nil {
+		value := 0.0
+		if health.State == StateDegraded {
+			value = 1
+		}
+		m.IndexerMetrics().ExtensionDegraded.WithLabelValues(name).Set(value)
+	}
+}
+
+func trackRestart(name string) {
+	if m := metrics.GetMetrics(); m != nil {
+		m.IndexerMetrics().ExtensionRestarts.WithLabelValues(name).Inc()
+	}
+}
+
+// ErrUnknownExtension is returned when pausing or resuming an extension which is not enabled
+var ErrUnknownExtension = errors.New("unknown extension")
+
+// control pauses and resumes a supervised extension. Pausing cancels the running extension,
+// which then waits for the resume instead of being restarted.
+type control struct {
+	mtx    sync.Mutex
+	paused bool
+	resume chan struct{} // closed on resume
+	cancel context.CancelFunc
+}
+
+func newControl() *control {
+	return &control{resume: make(chan struct{})}
+}
+
+func (c *control) pause() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.paused {
+		return
+	}
+	c.paused = true
+	c.resume = make(chan struct{})
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *control) unpause() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !c.paused {
+		return
+	}
+	c.paused = false
+	close(c.resume)
+}
+
+// resumed returns a channel closed once the extension may run, nil when it is not paused
+func (c *control) resumed() <-chan struct{} {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !c.paused {
+		return nil
+	}
+	return c.resume
+}
+
+// start returns the context of a run, which is cancelled on pause
+func (c *control) start(ctx context.Context) context.Context {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	runCtx, cancel := context.WithCancel(ctx)
+	if c.paused {
+		cancel()
+	}
+	c.cancel = cancel
+	return runCtx
+}
+
+// stop ends a run and reports whether it was paused
+func (c *control) stop() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.cancel()
+	c.cancel = nil
+	return c.paused
+}
+
+// PauseExtension stops the extension until it is resumed, a paused extension stays paused
+// across leadership changes
+func (m *ExtensionManager) PauseExtension(name string) error {
+	ctrl, ok := m.controls[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownExtension, name)
+	}
+	ctrl.pause()
+	m.logger.Info("Extension paused", slog.String("name", name))
+	return nil
+}
+
+// ResumeExtension runs the paused extension again
+func (m *ExtensionManager) ResumeExtension(name string) error {
+	ctrl, ok := m.controls[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownExtension, name)
+	}
+	ctrl.unpause()
+	m.logger.Info("Extension resumed", slog.String("name", name))
+	return nil
+}
+
+// Names returns the names of the enabled extensions in their run order
+func (m *ExtensionManager) Names() []string {
+	names := make([]string, 0, len(m.extensions))
+	for _, ext := range m.extensions {
+		names = append(names, ext.Name())
+	}
+	return names
+}
+
+// supervise runs the extension until the context is done, restarting it with exponential backoff
+// after a failure. An extension which fails more than the max restarts in a row is degraded and left
+// stopped; a run lasting longer than the max backoff resets the count.
+func (m *ExtensionManager) supervise(ctx context.Context, ext managedExtension) {
+	extCfg := m.cfg.GetExtensionsConfig()
+	name := ext.Name()
+	backoff := extCfg.RestartInitialBackoff
+	health := Health{State: StateWaiting}
+	ctrl := m.controls[name]
+
+	for {
+		if resumed := ctrl.resumed(); resumed != nil {
+			health.State = StatePaused
+			setHealth(name, health)
+			select {
+			case <-ctx.Done():
+				health.State = StateStopped
+				setHealth(name, health)
+				return
+			case <-resumed:
+			}
+			health.State = StateWaiting
+		}
+
+		setHealth(name, health)
+		started := time.Now()
+		err := m.runOnce(ctrl.start(ctx), ext, &health)
+		paused := ctrl.stop()
+		if ctx.Err() != nil {
+			m.logger.Info("Extension stopped",
+				slog.String("name", name),
+				slog.String("reason", "context cancelled"))
+			health.State = StateStopped
+			setHealth(name, health)
+			return
+		}
+		if paused {
+			m.logger.Info("Extension stopped",
+				slog.String("name", name),
+				slog.String("reason", "paused"))
+			continue
+		}
+		if err == nil {
+			m.logger.Info("Extension completed", slog.String("name", name))
+			health.State = StateCompleted
+			setHealth(name, health)
+			return
+		}
+
+		// A failure after a healthy run starts over
+		if time.Since(started) > extCfg.RestartMaxBackoff {
+			health.Restarts = 0
+			backoff = extCfg.RestartInitialBackoff
+		}
+		health.LastError = err.Error()
+
+		if extCfg.MaxRestarts > 0 && health.Restarts >= extCfg.MaxRestarts {
+			m.logger.Error("Extension degraded, exceeded max restarts",
+				slog.String("name", name),
+				slog.Int("restarts", health.Restarts),
+				slog.Any("error", err))
+			health.State = StateDegraded
+			setHealth(name, health)
+			return
+		}
+
+		health.Restarts++
+		health.State = StateRestarting
+		setHealth(name, health)
+		m.logger.Error("Extension error, restarting",
+			slog.String("name", name),
+			slog.Int("restart", health.Restarts),
+			slog.Duration("backoff", backoff),
+			slog.Any("error", err))
+		trackRestart(name)
+
+		select {
+		case <-ctx.Done():
+			health.State = StateStopped
+			setHealth(name, health)
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, extCfg.RestartMaxBackoff)
+		health.State = StateWaiting
+	}
+}
+
+// runOnce waits for the dependencies of the extension and runs it, turning a panic into an error
+func (m *ExtensionManager) runOnce(ctx context.Context, ext managedExtension, health *Health) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if err := m.waitForDependencies(ctx, ext); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return fmt.Errorf("failed to wait for dependencies: %w", err)
+	}
+
+	health.State = StateRunning
+	setHealth(ext.Name(), *health)
+	m.logger.Info("Starting extension", slog.String("name", ext.Name()))
+	return ext.Run(ctx)
+}
@@ -0,0 +1,135 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+)
+
+// fakeExtension fails its first failures runs, panicking when panics is set, then blocks until cancelled
+type fakeExtension struct {
+	name     string
+	failures int32
+	panics   bool
+	runs     atomic.Int32
+}
+
+func (e *fakeExtension) Name() string { return e.name }
+
+func (e *fakeExtension) Run(ctx context.Context) error {
+	if e.runs.Add(1) <= e.failures {
+		if e.panics {
+			panic("trace failed")
+		}
+		return errors.New("trace failed")
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func newTestManager(maxRestarts int, extensions ...*fakeExtension) *ExtensionManager {
+	cfg := &config.Config{}
+	cfg.SetExtensionsConfig(&config.ExtensionsConfig{
+		DependencyPollInterval: time.Millisecond,
+		RestartInitialBackoff:  time.Millisecond,
+		RestartMaxBackoff:      time.Second,
+		MaxRestarts:            maxRestarts,
+	})
+	m := &ExtensionManager{cfg: cfg, logger: slog.New(slog.DiscardHandler), controls: make(map[string]*control)}
+	for _, ext := range extensions {
+		m.extensions = append(m.extensions, managedExtension{Extension: ext})
+		m.controls[ext.name] = newControl()
+	}
+	return m
+}
+
+func TestSupervise_Restarts(t *testing.T) {
+	flaky := &fakeExtension{name: "test-flaky", failures: 2, panics: true}
+	m := newTestManager(3, flaky)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, m.run(ctx))
+	}()
+
+	require.Eventually(t, func() bool {
+		health, _ := HealthOf("test-flaky")
+		return health.State == StateRunning
+	}, time.Second, time.Millisecond)
+	health, _ := HealthOf("test-flaky")
+	assert.Equal(t, 2, health.Restarts)
+	assert.Equal(t, "panic: trace failed", health.LastError)
+
+	cancel()
+	<-done
+	health, _ = HealthOf("test-flaky")
+	assert.Equal(t, StateStopped, health.State)
+}
+
+func TestSupervise_Degraded(t *testing.T) {
+	broken := &fakeExtension{name: "test-broken", failures: 100}
+	healthy := &fakeExtension{name: "test-healthy"}
+	m := newTestManager(2, broken, healthy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = m.run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		health, _ := HealthOf("test-broken")
+		return health.State == StateDegraded
+	}, time.Second, time.Millisecond)
+	health, _ := HealthOf("test-broken")
+	assert.Equal(t, 2, health.Restarts)
+	assert.Equal(t, "trace failed", health.LastError)
+	assert.Equal(t, int32(3), broken.runs.Load())
+
+	// the other extension is not affected
+	health, _ = HealthOf("test-healthy")
+	assert.Equal(t, StateRunning, health.State)
+	assert.Equal(t, int32(1), healthy.runs.Load())
+}
+
+func TestSupervise_PauseResume(t *testing.T) {
+	ext := &fakeExtension{name: "test-paused"}
+	m := newTestManager(0, ext)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = m.run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		health, _ := HealthOf("test-paused")
+		return health.State == StateRunning
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, m.PauseExtension("test-paused"))
+	require.Eventually(t, func() bool {
+		health, _ := HealthOf("test-paused")
+		return health.State == StatePaused
+	}, time.Second, time.Millisecond)
+	// a pause is not a failure
+	health, _ := HealthOf("test-paused")
+	assert.Equal(t, 0, health.Restarts)
+	assert.Equal(t, int32(1), ext.runs.Load())
+
+	require.NoError(t, m.ResumeExtension("test-paused"))
+	require.Eventually(t, func() bool {
+		return ext.runs.Load() == 2
+	}, time.Second, time.Millisecond)
+	health, _ = HealthOf("test-paused")
+	assert.Equal(t, StateRunning, health.State)
+
+	assert.ErrorIs(t, m.PauseExtension("test-unknown"), ErrUnknownExtension)
+	assert.ErrorIs(t, m.ResumeExtension("test-unknown"), ErrUnknownExtension)
+}
@@ -15,12 +15,16 @@ import (
 )
 
 const (
-	ExtensionName = "tx-account-cleanup"
+	ExtensionName = config.ExtensionTxAccountCleanup
 	BatchSize     = 1000
 )
 
 var _ exttypes.Extension = (*TxAccountCleanupExtension)(nil)
 
+func init() {
+	exttypes.Register(ExtensionName, New)
+}
+
 type TxAccountCleanupExtension struct {
 	cfg    *config.Config
 	logger *slog.Logger
@@ -28,7 +32,7 @@ type TxAccountCleanupExtension struct {
 }
 
 func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *TxAccountCleanupExtension {
-	if !cfg.TxAccountCleanupEnabled() {
+	if !cfg.ExtensionEnabled(ExtensionName) {
 		return nil
 	}
 
@@ -139,13 +143,25 @@ func (e *TxAccountCleanupExtension) Run(ctx context.Context) error {
 	}
 }
 
+// updateStatus updates the status and the checkpoint, which follows the cleaned sequence downwards
 func (e *TxAccountCleanupExtension) updateStatus(ctx context.Context, status *types.CollectedTxAccountCleanupStatus) error {
-	return e.db.WithContext(ctx).
-		Model(&types.CollectedTxAccountCleanupStatus{}).
-		Where("1 = 1").
-		Updates(map[string]any{
-			"last_cleaned_sequence": status.LastCleanedSequence,
-			"deleted_records":       status.DeletedRecords,
-			"inserted_records":      status.InsertedRecords,
-		}).Error
+	return e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&types.CollectedTxAccountCleanupStatus{}).
+			Where("1 = 1").
+			Updates(map[string]any{
+				"last_cleaned_sequence": status.LastCleanedSequence,
+				"deleted_records":       status.DeletedRecords,
+				"inserted_records":      status.InsertedRecords,
+			}).Error; err != nil {
+			return err
+		}
+		return exttypes.SaveCheckpoint(tx, ExtensionName, exttypes.Checkpoint{
+			Sequence: status.LastCleanedSequence,
+			Meta: map[string]int64{
+				"deleted_records":  status.DeletedRecords,
+				"inserted_records": status.InsertedRecords,
+			},
+		})
+	})
 }
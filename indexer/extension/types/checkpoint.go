@@ -0,0 +1,66 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/initia-labs/rollytics/indexer/leader"
+	rollytypes "github.com/initia-labs/rollytics/types"
+)
+
+// Checkpoint is the progress of an extension, stored in the extension_checkpoint table
+type Checkpoint struct {
+	Height   int64
+	Sequence int64
+	Meta     any
+}
+
+// SaveCheckpoint upserts the checkpoint of the extension. Pass the transaction of the extension's own
+// status update so that both stay consistent, it fails with the transaction once the replica lost the
+// leadership of the extensions.
+func SaveCheckpoint(tx *gorm.DB, name string, cp Checkpoint) error {
+	if err := leader.Fence(tx); err != nil {
+		return err
+	}
+
+	var meta json.RawMessage
+	if cp.Meta != nil {
+		raw, err := json.Marshal(cp.Meta)
+		if err != nil {
+			return err
+		}
+		meta = raw
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"height", "sequence", "updated_at", "meta"}),
+	}).Create(&rollytypes.CollectedExtensionCheckpoint{
+		Name:      name,
+		Height:    cp.Height,
+		Sequence:  cp.Sequence,
+		UpdatedAt: time.Now().UTC(),
+		Meta:      meta,
+	}).Error
+}
+
+// LoadCheckpoints returns the checkpoints of the extensions by name; extensions without a checkpoint
+// are missing from the map
+func LoadCheckpoints(tx *gorm.DB, names []string) (map[string]rollytypes.CollectedExtensionCheckpoint, error) {
+	checkpoints := make(map[string]rollytypes.CollectedExtensionCheckpoint, len(names))
+	if len(names) == 0 {
+		return checkpoints, nil
+	}
+
+	var rows []rollytypes.CollectedExtensionCheckpoint
+	if err := tx.Where("name IN ?", names).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		checkpoints[row.Name] = row
+	}
+	return checkpoints, nil
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	// sqlite only parses the times of datetime columns
+	require.NoError(t, db.Exec(`CREATE TABLE extension_checkpoint (
+		name text PRIMARY KEY, height integer, sequence integer, updated_at datetime, meta blob)`).Error)
+	return db
+}
+
+func TestSaveCheckpoint(t *testing.T) {
+	db := setupTestDB(t)
+
+	require.NoError(t, SaveCheckpoint(db, "prune", Checkpoint{Height: 10}))
+	require.NoError(t, SaveCheckpoint(db, "prune", Checkpoint{Height: 20, Meta: map[string]int64{"deleted_records": 3}}))
+	require.NoError(t, SaveCheckpoint(db, "webhook", Checkpoint{Height: 5, Sequence: 7}))
+
+	checkpoints, err := LoadCheckpoints(db, []string{"prune", "webhook", "archive"})
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 2)
+	assert.Equal(t, int64(20), checkpoints["prune"].Height)
+	assert.JSONEq(t, `{"deleted_records":3}`, string(checkpoints["prune"].Meta))
+	assert.Equal(t, int64(7), checkpoints["webhook"].Sequence)
+	assert.False(t, checkpoints["webhook"].UpdatedAt.IsZero())
+}
+
+func TestDependencies(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	// no dependencies, nothing to wait for or bound by
+	var none *Dependencies
+	missing, err := none.Missing(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+	_, bounded, err := none.Height(ctx)
+	require.NoError(t, err)
+	assert.False(t, bounded)
+
+	deps := NewDependencies(db, []string{"rich-list", "webhook"})
+	require.NoError(t, SaveCheckpoint(db, "rich-list", Checkpoint{Height: 30}))
+
+	missing, err = deps.Missing(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"webhook"}, missing)
+	height, bounded, err := deps.Height(ctx)
+	require.NoError(t, err)
+	assert.True(t, bounded)
+	assert.Zero(t, height)
+
+	require.NoError(t, SaveCheckpoint(db, "webhook", Checkpoint{Height: 25}))
+	missing, err = deps.Missing(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+	height, _, err = deps.Height(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), height)
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Dependent is implemented by the extensions which bound their progress by the extensions they run after.
+// The manager sets the dependencies before running the extension.
+type Dependent interface {
+	SetDependencies(deps *Dependencies)
+}
+
+// Dependencies reads the checkpoints of the enabled extensions another extension runs after
+type Dependencies struct {
+	db    *gorm.DB
+	names []string
+}
+
+func NewDependencies(db *gorm.DB, names []string) *Dependencies {
+	return &Dependencies{db: db, names: names}
+}
+
+// Names returns the names of the dependencies
+func (d *Dependencies) Names() []string {
+	if d == nil {
+		return nil
+	}
+	return d.names
+}
+
+// Missing returns the dependencies which have not saved a checkpoint yet
+func (d *Dependencies) Missing(ctx context.Context) ([]string, error) {
+	if d == nil || len(d.names) == 0 {
+		return nil, nil
+	}
+
+	checkpoints, err := LoadCheckpoints(d.db.WithContext(ctx), d.names)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, name := range d.names {
+		if _, ok := checkpoints[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
+
+// Height returns the lowest checkpointed height of the dependencies; a dependency without a checkpoint
+// counts as height 0. bounded is false when there are no dependencies.
+func (d *Dependencies) Height(ctx context.Context) (height int64, bounded bool, err error) {
+	if d == nil || len(d.names) == 0 {
+		return 0, false, nil
+	}
+
+	checkpoints, err := LoadCheckpoints(d.db.WithContext(ctx), d.names)
+	if err != nil {
+		return 0, false, err
+	}
+	for i, name := range d.names {
+		h := checkpoints[name].Height
+		if i == 0 || h < height {
+			height = h
+		}
+	}
+	return height, true, nil
+}
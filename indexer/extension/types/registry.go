@@ -0,0 +1,154 @@
+package types
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/orm"
+)
+
+// Factory builds an extension, returning nil when the extension is disabled by its config
+type Factory func(cfg *config.Config, logger *slog.Logger, db *orm.Database) Extension
+
+// Registration is an extension known to the registry
+type Registration struct {
+	Name    string
+	Factory Factory
+	// After lists the extensions which must be checkpointed before this one starts.
+	// Dependencies that are not enabled are ignored.
+	After []string
+}
+
+// Option configures a registration
+type Option func(*Registration)
+
+// After declares the extensions this one runs after
+func After(names ...string) Option {
+	return func(r *Registration) {
+		r.After = append(r.After, names...)
+	}
+}
+
+var (
+	registryMtx   sync.RWMutex
+	registrations []Registration
+)
+
+// Register adds an extension to the registry, usually from the init function of its package.
+// The constructor follows the New(cfg, logger, db) convention of the extensions and returns a nil
+// pointer when the extension is disabled. Registering the same name twice panics.
+func Register[T any, E interface {
+	*T
+	Extension
+}](name string, newFn func(cfg *config.Config, logger *slog.Logger, db *orm.Database) E, opts ...Option) {
+	factory := func(cfg *config.Config, logger *slog.Logger, db *orm.Database) Extension {
+		// a nil pointer would otherwise become a non-nil interface
+		if ext := newFn(cfg, logger, db); ext != nil {
+			return ext
+		}
+		return nil
+	}
+	RegisterFactory(name, factory, opts...)
+}
+
+// RegisterFactory adds an extension built by factory to the registry
+func RegisterFactory(name string, factory Factory, opts ...Option) {
+	reg := Registration{Name: name, Factory: factory}
+	for _, opt := range opts {
+		opt(&reg)
+	}
+
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	for _, existing := range registrations {
+		if existing.Name == name {
+			panic(fmt.Sprintf("extension %q is already registered", name))
+		}
+	}
+	registrations = append(registrations, reg)
+}
+
+// Registered returns the registered extensions in registration order
+func Registered() []Registration {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	return append([]Registration(nil), registrations...)
+}
+
+// Lookup returns the registration of the extension
+func Lookup(name string) (Registration, bool) {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	for _, reg := range registrations {
+		if reg.Name == name {
+			return reg, true
+		}
+	}
+	return Registration{}, false
+}
+
+// Sort orders the registrations so that each one comes after its dependencies, keeping the given
+// order otherwise. Dependencies missing from regs are ignored.
+func Sort(regs []Registration) ([]Registration, error) {
+	index := make(map[string]int, len(regs))
+	for i, reg := range regs {
+		index[reg.Name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(regs))
+	sorted := make([]Registration, 0, len(regs))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("extension dependency cycle: %v", append(path, regs[i].Name))
+		}
+		state[i] = visiting
+		for _, dep := range regs[i].After {
+			if j, ok := index[dep]; ok {
+				if err := visit(j, append(path, regs[i].Name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = visited
+		sorted = append(sorted, regs[i])
+		return nil
+	}
+
+	for i := range regs {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// Select returns the registrations named by names, or every registration when names is empty,
+// ordered by their dependencies
+func Select(names []string) ([]Registration, error) {
+	for _, name := range names {
+		if _, ok := Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown extension %q", name)
+		}
+	}
+
+	var regs []Registration
+	for _, reg := range Registered() {
+		if len(names) == 0 || slices.Contains(names, reg.Name) {
+			regs = append(regs, reg)
+		}
+	}
+	return Sort(regs)
+}
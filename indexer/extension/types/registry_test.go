@@ -0,0 +1,80 @@
+package types
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/orm"
+)
+
+type testExtension struct {
+	name string
+}
+
+func (e *testExtension) Name() string                  { return e.name }
+func (e *testExtension) Run(ctx context.Context) error { return nil }
+
+func names(regs []Registration) []string {
+	out := make([]string, 0, len(regs))
+	for _, reg := range regs {
+		out = append(out, reg.Name)
+	}
+	return out
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-enabled", func(*config.Config, *slog.Logger, *orm.Database) *testExtension {
+		return &testExtension{name: "test-enabled"}
+	})
+	Register("test-disabled", func(*config.Config, *slog.Logger, *orm.Database) *testExtension {
+		return nil
+	}, After("test-enabled"))
+
+	enabled, ok := Lookup("test-enabled")
+	require.True(t, ok)
+	assert.NotNil(t, enabled.Factory(nil, nil, nil))
+
+	// a nil pointer maps to a nil extension
+	disabled, ok := Lookup("test-disabled")
+	require.True(t, ok)
+	assert.Nil(t, disabled.Factory(nil, nil, nil))
+	assert.Equal(t, []string{"test-enabled"}, disabled.After)
+
+	assert.Panics(t, func() {
+		Register("test-enabled", func(*config.Config, *slog.Logger, *orm.Database) *testExtension { return nil })
+	})
+
+	_, ok = Lookup("test-unknown")
+	assert.False(t, ok)
+
+	_, err := Select([]string{"test-enabled", "test-unknown"})
+	assert.Error(t, err)
+
+	regs, err := Select([]string{"test-disabled", "test-enabled"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-enabled", "test-disabled"}, names(regs))
+}
+
+func TestSort(t *testing.T) {
+	regs := []Registration{
+		{Name: "prune", After: []string{"rich-list", "webhook"}},
+		{Name: "webhook", After: []string{"rich-list"}},
+		{Name: "rich-list"},
+		{Name: "event-sink", After: []string{"missing"}},
+	}
+
+	sorted, err := Sort(regs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rich-list", "webhook", "prune", "event-sink"}, names(sorted))
+
+	_, err = Sort([]Registration{
+		{Name: "a", After: []string{"b"}},
+		{Name: "b", After: []string{"a"}},
+	})
+	assert.ErrorContains(t, err, "cycle")
+}
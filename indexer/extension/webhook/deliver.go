@@ -0,0 +1,346 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/webhook"
+)
+
+// maxResponseBody bounds the response body read before the connection is reused
+const maxResponseBody = 64 << 10
+
+// deliver delivers the notifications and returns the number delivered and dead-lettered. The
+// notifications of a subscription are delivered one after another in order, the subscriptions
+// concurrently, with a single attempt each: once a delivery fails, it and the notifications following
+// it are queued for redelivery, so that a failing receiver does not hold back the others. The
+// notifications of a subscription with queued retries are queued behind them. An error is returned
+// only when the context is done or a result cannot be recorded.
+func (d *Dispatcher) deliver(ctx context.Context, notifications []Notification) (int64, int64, error) {
+	if len(notifications) == 0 {
+		return 0, 0, nil
+	}
+
+	var retrying []int64
+	if err := d.db.WithContext(ctx).Model(&types.CollectedWebhookRetry{}).
+		Distinct("subscription_id").
+		Pluck("subscription_id", &retrying).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to get the subscriptions with retries: %w", err)
+	}
+
+	var order []int64
+	bySubscription := make(map[int64][]Notification)
+	for _, n := range notifications {
+		id := n.Subscription.Id
+		if _, ok := bySubscription[id]; !ok {
+			order = append(order, id)
+		}
+		bySubscription[id] = append(bySubscription[id], n)
+	}
+
+	var delivered, deadLetters atomic.Int64
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(d.cfg.Concurrency)
+	for _, id := range order {
+		queue := bySubscription[id]
+		g.Go(func() error {
+			if slices.Contains(retrying, id) {
+				return d.enqueue(gCtx, queue, 0, nil)
+			}
+			for i, n := range queue {
+				body, err := marshalPayload(d.chainId, n)
+				if err != nil {
+					return err
+				}
+				statusCode, err := d.post(gCtx, n.Subscription, n.Id, n.Event, body)
+				switch {
+				case err == nil:
+					if err := d.recordDelivered(gCtx, n.Subscription.Id, n.Event, statusCode); err != nil {
+						return err
+					}
+					delivered.Add(1)
+				case gCtx.Err() != nil:
+					return gCtx.Err()
+				case d.cfg.MaxAttempts > 1 && retryable(statusCode):
+					return d.enqueue(gCtx, queue[i:], statusCode, err)
+				default:
+					if err := d.recordDeadLetter(gCtx, n.Subscription.Id, n.Id, n.Event, body, 1, statusCode, err); err != nil {
+						return err
+					}
+					deadLetters.Add(1)
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return 0, 0, err
+	}
+
+	return delivered.Load(), deadLetters.Load(), nil
+}
+
+// enqueue queues the notifications of a subscription for redelivery. With deliveryErr, the first one
+// failed its first attempt and is redelivered after the initial backoff, the others wait behind it.
+func (d *Dispatcher) enqueue(ctx context.Context, queue []Notification, statusCode int, deliveryErr error) error {
+	now := deliveryTime()
+	retries := make([]types.CollectedWebhookRetry, 0, len(queue))
+	for _, n := range queue {
+		body, err := marshalPayload(d.chainId, n)
+		if err != nil {
+			return err
+		}
+		retries = append(retries, types.CollectedWebhookRetry{
+			SubscriptionId: n.Subscription.Id,
+			DeliveryId:     n.Id,
+			Event:          n.Event,
+			Payload:        body,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+	if deliveryErr != nil {
+		retries[0].Attempts = 1
+		retries[0].NextAttemptAt = now.Add(d.cfg.InitialBackoff)
+		retries[0].LastStatusCode = statusCode
+		retries[0].LastError = deliveryErr.Error()
+
+		d.logger.Debug("webhook delivery failed, queued for retry",
+			slog.Int64("subscription_id", retries[0].SubscriptionId),
+			slog.String("delivery_id", retries[0].DeliveryId),
+			slog.Int("queued", len(retries)),
+			slog.Any("error", deliveryErr))
+		trackDelivery(retries[0].Event, "retry")
+	}
+
+	if err := d.db.WithContext(ctx).Create(&retries).Error; err != nil {
+		return fmt.Errorf("failed to queue webhook retries: %w", err)
+	}
+	return nil
+}
+
+// redeliver attempts the queued retries whose turn came and returns the number delivered and
+// dead-lettered. The retries of a subscription are attempted in order from the first, once it is
+// due, until one fails again and is postponed with a doubled backoff; a retry failing its last attempt
+// or rejected is dead-lettered. The subscriptions are redelivered concurrently.
+func (d *Dispatcher) redeliver(ctx context.Context, subscriptions []types.CollectedWebhookSubscription) (int64, int64, error) {
+	db := d.db.WithContext(ctx)
+
+	// the subscriptions whose first retry is due
+	var due []int64
+	if err := db.Model(&types.CollectedWebhookRetry{}).
+		Where("id IN (?)", db.Model(&types.CollectedWebhookRetry{}).Select("MIN(id)").Group("subscription_id")).
+		Where("next_attempt_at <= ?", deliveryTime()).
+		Order("subscription_id").
+		Pluck("subscription_id", &due).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to get the due webhook retries: %w", err)
+	}
+	if len(due) == 0 {
+		return 0, 0, nil
+	}
+
+	byId := make(map[int64]*types.CollectedWebhookSubscription, len(subscriptions))
+	for i := range subscriptions {
+		byId[subscriptions[i].Id] = &subscriptions[i]
+	}
+
+	var delivered, deadLetters atomic.Int64
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(d.cfg.Concurrency)
+	for _, id := range due {
+		g.Go(func() error {
+			sub, ok := byId[id]
+			if !ok {
+				// the subscription was deleted meanwhile
+				return d.db.WithContext(gCtx).Where("subscription_id = ?", id).Delete(&types.CollectedWebhookRetry{}).Error
+			}
+
+			var retries []types.CollectedWebhookRetry
+			if err := d.db.WithContext(gCtx).
+				Where("subscription_id = ?", id).
+				Order("id").
+				Limit(d.cfg.BatchSize).
+				Find(&retries).Error; err != nil {
+				return fmt.Errorf("failed to get the webhook retries: %w", err)
+			}
+			for _, retry := range retries {
+				ok, dead, err := d.redeliverOne(gCtx, sub, retry)
+				if err != nil {
+					return err
+				}
+				switch {
+				case ok:
+					delivered.Add(1)
+				case dead:
+					deadLetters.Add(1)
+				default:
+					return nil
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return 0, 0, err
+	}
+
+	return delivered.Load(), deadLetters.Load(), nil
+}
+
+// redeliverOne attempts a queued retry once and reports whether it was delivered or dead-lettered,
+// otherwise it is postponed
+func (d *Dispatcher) redeliverOne(ctx context.Context, sub *types.CollectedWebhookSubscription, retry types.CollectedWebhookRetry) (bool, bool, error) {
+	attempts := retry.Attempts + 1
+	statusCode, deliveryErr := d.post(ctx, sub, retry.DeliveryId, retry.Event, retry.Payload)
+	if deliveryErr != nil && ctx.Err() != nil {
+		return false, false, ctx.Err()
+	}
+
+	db := d.db.WithContext(ctx)
+	switch {
+	case deliveryErr == nil:
+		if err := db.Delete(&retry).Error; err != nil {
+			return false, false, fmt.Errorf("failed to delete webhook retry %s: %w", retry.DeliveryId, err)
+		}
+		return true, false, d.recordDelivered(ctx, sub.Id, retry.Event, statusCode)
+	case attempts >= d.cfg.MaxAttempts || !retryable(statusCode):
+		if err := db.Delete(&retry).Error; err != nil {
+			return false, false, fmt.Errorf("failed to delete webhook retry %s: %w", retry.DeliveryId, err)
+		}
+		return false, true, d.recordDeadLetter(ctx, sub.Id, retry.DeliveryId, retry.Event, retry.Payload, attempts, statusCode, deliveryErr)
+	}
+
+	d.logger.Debug("webhook redelivery failed, retrying",
+		slog.Int64("subscription_id", sub.Id),
+		slog.String("delivery_id", retry.DeliveryId),
+		slog.Int("attempt", attempts),
+		slog.Any("error", deliveryErr))
+	trackDelivery(retry.Event, "retry")
+
+	if err := db.Model(&retry).Updates(map[string]any{
+		"attempts":         attempts,
+		"next_attempt_at":  deliveryTime().Add(d.backoff(attempts)),
+		"last_status_code": statusCode,
+		"last_error":       deliveryErr.Error(),
+	}).Error; err != nil {
+		return false, false, fmt.Errorf("failed to postpone webhook retry %s: %w", retry.DeliveryId, err)
+	}
+	return false, false, nil
+}
+
+// backoff returns the wait after the failed attempts: the initial backoff doubled after every further
+// failure, up to the max backoff
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.cfg.InitialBackoff
+	for i := 1; i < attempts && backoff < d.cfg.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, d.cfg.MaxBackoff)
+}
+
+// post sends the signed payload once, a non-2xx response is an error
+func (d *Dispatcher) post(ctx context.Context, sub *types.CollectedWebhookSubscription, deliveryId, event string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rollytics-webhook")
+	req.Header.Set(webhook.HeaderEvent, event)
+	req.Header.Set(webhook.HeaderDelivery, deliveryId)
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(sub.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable reports whether a failed attempt may succeed later: transport errors, server errors,
+// timeouts and rate limits are retried, other client errors are not
+func retryable(statusCode int) bool {
+	return statusCode == 0 ||
+		statusCode >= http.StatusInternalServerError ||
+		statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests
+}
+
+func (d *Dispatcher) recordDelivered(ctx context.Context, subscriptionId int64, event string, statusCode int) error {
+	if err := d.db.WithContext(ctx).Model(&types.CollectedWebhookSubscription{}).
+		Where("id = ?", subscriptionId).
+		Updates(map[string]any{
+			"delivered_count":  gorm.Expr("delivered_count + 1"),
+			"last_delivery_at": deliveryTime(),
+			"last_status_code": statusCode,
+			"last_error":       "",
+		}).Error; err != nil {
+		return fmt.Errorf("failed to record delivery of subscription %d: %w", subscriptionId, err)
+	}
+
+	trackDelivery(event, "delivered")
+	return nil
+}
+
+func (d *Dispatcher) recordDeadLetter(ctx context.Context, subscriptionId int64, deliveryId, event string, body []byte, attempts, statusCode int, deliveryErr error) error {
+	d.logger.Warn("webhook delivery dead-lettered",
+		slog.Int64("subscription_id", subscriptionId),
+		slog.String("delivery_id", deliveryId),
+		slog.Int("attempts", attempts),
+		slog.Any("error", deliveryErr))
+
+	now := deliveryTime()
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&types.CollectedWebhookDeadLetter{
+			SubscriptionId: subscriptionId,
+			DeliveryId:     deliveryId,
+			Payload:        body,
+			Attempts:       attempts,
+			StatusCode:     statusCode,
+			Error:          deliveryErr.Error(),
+			CreatedAt:      now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&types.CollectedWebhookSubscription{}).
+			Where("id = ?", subscriptionId).
+			Updates(map[string]any{
+				"failed_count":     gorm.Expr("failed_count + 1"),
+				"last_delivery_at": now,
+				"last_status_code": statusCode,
+				"last_error":       deliveryErr.Error(),
+			}).Error
+	}); err != nil {
+		return fmt.Errorf("failed to record dead letter %s: %w", deliveryId, err)
+	}
+
+	trackDelivery(event, "dead_letter")
+	return nil
+}
+
+func trackDelivery(event, result string) {
+	if m := metrics.GetMetrics(); m != nil {
+		m.IndexerMetrics().WebhookDeliveries.WithLabelValues(event, result).Inc()
+	}
+}
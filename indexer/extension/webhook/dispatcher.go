@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
+	"github.com/initia-labs/rollytics/types"
+)
+
+// Notification is a notification of a subscription waiting for delivery
+type Notification struct {
+	Subscription *types.CollectedWebhookSubscription
+	// Id identifies the notification across redeliveries, receivers deduplicate on it
+	Id    string
+	Event string
+	Data  any
+}
+
+// Payload is the signed JSON body posted to the subscribed URL
+type Payload struct {
+	Id             string `json:"id"`
+	Event          string `json:"event"`
+	SubscriptionId int64  `json:"subscription_id"`
+	ChainId        string `json:"chain_id"`
+	Data           any    `json:"data"`
+}
+
+// Dispatcher finds the notifications of the subscriptions and delivers them
+type Dispatcher struct {
+	cfg     *config.WebhookConfig
+	chainId string
+	vmType  types.VMType
+	logger  *slog.Logger
+	db      *gorm.DB
+	client  *http.Client
+}
+
+func NewDispatcher(cfg *config.Config, logger *slog.Logger, db *gorm.DB, client *http.Client) *Dispatcher {
+	return &Dispatcher{
+		cfg:     cfg.GetWebhookConfig(),
+		chainId: cfg.GetChainId(),
+		vmType:  cfg.GetVmType(),
+		logger:  logger,
+		db:      db,
+		client:  client,
+	}
+}
+
+// Dispatch redelivers the due retries, scans the next batch of txs and the rich list, delivers their
+// notifications and advances the status. The failed deliveries are queued for retry, so the status
+// advances past them. It returns the number of tx sequences scanned.
+func (d *Dispatcher) Dispatch(ctx context.Context, status *types.CollectedWebhookStatus) (int, error) {
+	db := d.db.WithContext(ctx)
+
+	var subscriptions []types.CollectedWebhookSubscription
+	if err := db.Order("id").Find(&subscriptions).Error; err != nil {
+		return 0, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+
+	// read before the scan, the txs of heights up to it are visible to the scan
+	latestHeight, err := getLatestHeight(db, d.chainId)
+	if err != nil {
+		return 0, err
+	}
+	lastSequence, txNotifications, err := d.scanTxs(db, subscriptions, status.LastSequence)
+	if err != nil {
+		return 0, err
+	}
+	checkpointHeight, err := scannedHeight(db, latestHeight, lastSequence)
+	if err != nil {
+		return 0, err
+	}
+	richListHeight, richListNotifications, states, err := d.scanRichList(db, subscriptions, status.LastRichListHeight)
+	if err != nil {
+		return 0, err
+	}
+
+	// the queued retries go first, so that the new notifications of a subscription whose retries are
+	// all delivered are not queued behind them
+	redelivered, redeadLetters, err := d.redeliver(ctx, subscriptions)
+	if err != nil {
+		return 0, err
+	}
+	delivered, deadLetters, err := d.deliver(ctx, append(txNotifications, richListNotifications...))
+	if err != nil {
+		return 0, err
+	}
+	delivered += redelivered
+	deadLetters += redeadLetters
+
+	scanned := int(lastSequence - status.LastSequence)
+	next := *status
+	next.LastSequence = lastSequence
+	next.LastRichListHeight = richListHeight
+	next.DeliveredEvents += delivered
+	next.DeadLetters += deadLetters
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		for id, state := range states {
+			if err := tx.Model(&types.CollectedWebhookSubscription{}).
+				Where("id = ?", id).
+				Update("state", state).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&types.CollectedWebhookStatus{}).
+			Where("1 = 1").
+			Updates(map[string]any{
+				"last_sequence":         next.LastSequence,
+				"last_rich_list_height": next.LastRichListHeight,
+				"delivered_events":      next.DeliveredEvents,
+				"dead_letters":          next.DeadLetters,
+			}).Error; err != nil {
+			return err
+		}
+		return exttypes.SaveCheckpoint(tx, ExtensionName, exttypes.Checkpoint{
+			Height:   checkpointHeight,
+			Sequence: next.LastSequence,
+			Meta: map[string]int64{
+				"last_rich_list_height": next.LastRichListHeight,
+				"delivered_events":      next.DeliveredEvents,
+				"dead_letters":          next.DeadLetters,
+			},
+		})
+	}); err != nil {
+		return 0, fmt.Errorf("failed to update webhook status: %w", err)
+	}
+	*status = next
+
+	return scanned, nil
+}
+
+// deliveryTime is the time recorded for a delivery
+func deliveryTime() time.Time {
+	return time.Now().UTC()
+}
+
+func marshalPayload(chainId string, n Notification) ([]byte, error) {
+	return json.Marshal(Payload{
+		Id:             n.Id,
+		Event:          n.Event,
+		SubscriptionId: n.Subscription.Id,
+		ChainId:        chainId,
+		Data:           n.Data,
+	})
+}
@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/webhook"
+)
+
+const ExtensionName = config.ExtensionWebhook
+
+var _ exttypes.Extension = (*WebhookExtension)(nil)
+
+// WebhookExtension notifies the webhook subscriptions. It scans the newly collected txs after its
+// sequence cursor and the rich list once it moved to a new height, delivers the notifications and
+// advances the cursor once every notification is delivered, queued for retry or dead-lettered, so
+// that a restart delivers the notifications in flight again.
+type WebhookExtension struct {
+	cfg    *config.Config
+	logger *slog.Logger
+	db     *orm.Database
+	client *http.Client
+}
+
+func init() {
+	exttypes.Register(ExtensionName, New)
+}
+
+// New creates a new WebhookExtension instance
+// Returns nil if webhooks are disabled
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *WebhookExtension {
+	if !cfg.ExtensionEnabled(ExtensionName) {
+		return nil
+	}
+
+	return &WebhookExtension{
+		cfg:    cfg,
+		logger: logger.With("extension", ExtensionName),
+		db:     db,
+		client: webhook.NewClient(cfg.GetWebhookConfig().Timeout, cfg.GetWebhookConfig().AllowPrivateUrls),
+	}
+}
+
+func (e *WebhookExtension) Name() string {
+	return ExtensionName
+}
+
+// Initialize returns the dispatcher status. A new dispatcher starts at the latest tx and rich list,
+// the history is not notified.
+func (e *WebhookExtension) Initialize(ctx context.Context) (*types.CollectedWebhookStatus, error) {
+	var status types.CollectedWebhookStatus
+	err := e.db.WithContext(ctx).First(&status).Error
+	if err == nil {
+		return &status, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to retrieve webhook status: %w", err)
+	}
+
+	lastSequence, err := getLastSequence(e.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	richListHeight, err := getRichListHeight(e.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	status = types.CollectedWebhookStatus{
+		LastSequence:       lastSequence,
+		LastRichListHeight: richListHeight,
+	}
+	if err := e.db.WithContext(ctx).Create(&status).Error; err != nil {
+		return nil, fmt.Errorf("failed to create initial status: %w", err)
+	}
+	e.logger.Info("initialized webhook status", slog.Int64("last_sequence", lastSequence))
+	return &status, nil
+}
+
+func (e *WebhookExtension) Run(ctx context.Context) error {
+	status, err := e.Initialize(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	webhookCfg := e.cfg.GetWebhookConfig()
+	dispatcher := NewDispatcher(e.cfg, e.logger, e.db.DB, e.client)
+
+	e.logger.Info("starting webhook dispatcher",
+		slog.Int64("last_sequence", status.LastSequence),
+		slog.Int64("last_rich_list_height", status.LastRichListHeight))
+
+	for {
+		scanned, err := dispatcher.Dispatch(ctx, status)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the cursor did not move, the same range is scanned again
+			e.logger.Error("failed to dispatch webhooks", slog.Any("error", err))
+			metrics.TrackError("webhook", "dispatch")
+		}
+
+		// A full batch suggests a backlog, continue without waiting
+		if err == nil && scanned == webhookCfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			e.logger.Info("webhook dispatcher stopped",
+				slog.Int64("last_sequence", status.LastSequence),
+				slog.Int64("delivered_events", status.DeliveredEvents))
+			return ctx.Err()
+		case <-time.After(webhookCfg.PollInterval):
+		}
+	}
+}
+
+// getLastSequence returns the sequence of the latest collected tx
+func getLastSequence(tx *gorm.DB) (int64, error) {
+	var sequence int64
+	if err := tx.Model(&types.CollectedTx{}).Select("COALESCE(MAX(sequence), 0)").Scan(&sequence).Error; err != nil {
+		return 0, fmt.Errorf("failed to get the last tx sequence: %w", err)
+	}
+	return sequence, nil
+}
+
+// getLatestHeight returns the height of the latest collected block
+func getLatestHeight(tx *gorm.DB, chainId string) (int64, error) {
+	var height int64
+	if err := tx.Model(&types.CollectedBlock{}).
+		Where("chain_id = ?", chainId).
+		Select("COALESCE(MAX(height), 0)").
+		Scan(&height).Error; err != nil {
+		return 0, fmt.Errorf("failed to get the latest block height: %w", err)
+	}
+	return height, nil
+}
+
+// scannedHeight returns the height up to which every tx is scanned: the latest block read before the
+// scan once the scan caught up, otherwise the height below the next tx to scan
+func scannedHeight(tx *gorm.DB, latestHeight, lastSequence int64) (int64, error) {
+	var heights []int64
+	if err := tx.Model(&types.CollectedTx{}).
+		Where("sequence > ?", lastSequence).
+		Order("sequence").
+		Limit(1).
+		Pluck("height", &heights).Error; err != nil {
+		return 0, fmt.Errorf("failed to get the next tx height: %w", err)
+	}
+	if len(heights) == 0 {
+		return latestHeight, nil
+	}
+	return min(latestHeight, heights[0]-1), nil
+}
+
+// getRichListHeight returns the height the rich list is built up to, zero without a rich list
+func getRichListHeight(tx *gorm.DB) (int64, error) {
+	var height int64
+	if err := tx.Model(&types.CollectedRichListStatus{}).Select("COALESCE(MAX(height), 0)").Scan(&height).Error; err != nil {
+		return 0, fmt.Errorf("failed to get the rich list height: %w", err)
+	}
+	return height, nil
+}
@@ -0,0 +1,381 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util/webhook"
+)
+
+const testSecret = "secret"
+
+var (
+	discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	watched    = []byte{0x01, 0x02, 0x03}
+	collection = []byte{0xc1, 0xc2, 0xc3, 0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xcb, 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3, 0xd4}
+)
+
+// receiver is a local webhook endpoint answering with status and keeping the requests it verified
+type receiver struct {
+	*httptest.Server
+	mtx      sync.Mutex
+	status   int
+	payloads []Payload
+	hits     int
+}
+
+func newReceiver(t *testing.T, status int) *receiver {
+	r := &receiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		timestamp, err := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		assert.NoError(t, err)
+		assert.True(t, webhook.Verify(testSecret, timestamp, body, req.Header.Get(webhook.HeaderSignature)))
+
+		var payload Payload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, payload.Id, req.Header.Get(webhook.HeaderDelivery))
+		assert.Equal(t, payload.Event, req.Header.Get(webhook.HeaderEvent))
+
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		r.hits++
+		if r.status < 300 {
+			r.payloads = append(r.payloads, payload)
+		}
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// sqlite only assigns the ids of integer primary keys, and only parses the times of datetime columns
+	require.NoError(t, db.Exec(`CREATE TABLE webhook_subscription (
+		id integer PRIMARY KEY AUTOINCREMENT, event text, target text, url text, secret text, state blob,
+		created_at datetime, delivered_count integer DEFAULT 0, failed_count integer DEFAULT 0,
+		last_delivery_at datetime, last_status_code integer DEFAULT 0, last_error text DEFAULT '')`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE webhook_dead_letter (
+		id integer PRIMARY KEY AUTOINCREMENT, subscription_id integer, delivery_id text, payload blob,
+		attempts integer, status_code integer DEFAULT 0, error text DEFAULT '', created_at datetime)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE webhook_retry (
+		id integer PRIMARY KEY AUTOINCREMENT, subscription_id integer, delivery_id text, event text, payload blob,
+		attempts integer DEFAULT 0, next_attempt_at datetime, last_status_code integer DEFAULT 0,
+		last_error text DEFAULT '', created_at datetime)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE extension_checkpoint (
+		name text PRIMARY KEY, height integer, sequence integer, updated_at datetime, meta blob)`).Error)
+	require.NoError(t, db.AutoMigrate(
+		&types.CollectedBlock{},
+		&types.CollectedTx{},
+		&types.CollectedTxAccount{},
+		&types.CollectedAccountDict{},
+		&types.CollectedRichList{},
+		&types.CollectedRichListStatus{},
+		&types.CollectedWebhookStatus{},
+	))
+	require.NoError(t, db.Create(&types.CollectedWebhookStatus{}).Error)
+	require.NoError(t, db.Create(&types.CollectedAccountDict{Id: 1, Account: watched}).Error)
+	require.NoError(t, db.Create(&types.CollectedAccountDict{Id: 2, Account: []byte{0x09}}).Error)
+
+	return db
+}
+
+func newTestDispatcher(db *gorm.DB) *Dispatcher {
+	cfg := &config.Config{}
+	cfg.SetChainConfig(&config.ChainConfig{ChainId: "test-1", VmType: types.WasmVM})
+	cfg.SetWebhookConfig(&config.WebhookConfig{
+		Enabled:        true,
+		BatchSize:      10,
+		PollInterval:   time.Millisecond,
+		Timeout:        time.Second,
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Concurrency:    2,
+	})
+	return NewDispatcher(cfg, discardLogger, db, http.DefaultClient)
+}
+
+func subscribe(t *testing.T, db *gorm.DB, event, target, url string) *types.CollectedWebhookSubscription {
+	sub := &types.CollectedWebhookSubscription{
+		Event:     event,
+		Target:    target,
+		Url:       url,
+		Secret:    testSecret,
+		CreatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, db.Create(sub).Error)
+	return sub
+}
+
+func addTx(t *testing.T, db *gorm.DB, sequence int64, data string, accounts ...int64) {
+	require.NoError(t, db.Create(&types.CollectedTx{
+		Hash:     []byte{0xaa, byte(sequence)},
+		Height:   sequence + 10,
+		Sequence: sequence,
+		Data:     json.RawMessage(data),
+	}).Error)
+	for idx, account := range accounts {
+		require.NoError(t, db.Create(&types.CollectedTxAccount{AccountId: account, Sequence: sequence, Signer: idx == 0}).Error)
+	}
+}
+
+func getStatus(t *testing.T, db *gorm.DB) *types.CollectedWebhookStatus {
+	var status types.CollectedWebhookStatus
+	require.NoError(t, db.First(&status).Error)
+	return &status
+}
+
+func TestDispatch_AccountTx(t *testing.T) {
+	db := setupTestDB(t)
+	r := newReceiver(t, http.StatusOK)
+	sub := subscribe(t, db, webhook.EventAccountTx, sdk.AccAddress(watched).String(), r.URL)
+
+	addTx(t, db, 1, `{}`, 2)
+	addTx(t, db, 2, `{}`, 2, 1)
+	addTx(t, db, 3, `{}`, 1)
+
+	status := getStatus(t, db)
+	scanned, err := newTestDispatcher(db).Dispatch(context.Background(), status)
+	require.NoError(t, err)
+	assert.Equal(t, 3, scanned)
+
+	require.Len(t, r.payloads, 2)
+	assert.Equal(t, fmt.Sprintf("%d/2", sub.Id), r.payloads[0].Id)
+	assert.Equal(t, fmt.Sprintf("%d/3", sub.Id), r.payloads[1].Id)
+	assert.Equal(t, "test-1", r.payloads[0].ChainId)
+	data := r.payloads[0].Data.(map[string]any)
+	assert.Equal(t, "AA02", data["tx_hash"])
+	assert.Equal(t, false, data["signer"])
+	assert.Equal(t, true, r.payloads[1].Data.(map[string]any)["signer"])
+
+	status = getStatus(t, db)
+	assert.Equal(t, int64(3), status.LastSequence)
+	assert.Equal(t, int64(2), status.DeliveredEvents)
+
+	var stored types.CollectedWebhookSubscription
+	require.NoError(t, db.First(&stored, sub.Id).Error)
+	assert.Equal(t, int64(2), stored.DeliveredCount)
+	assert.Equal(t, http.StatusOK, stored.LastStatusCode)
+	assert.NotNil(t, stored.LastDeliveryAt)
+
+	// nothing new, nothing delivered
+	scanned, err = newTestDispatcher(db).Dispatch(context.Background(), status)
+	require.NoError(t, err)
+	assert.Equal(t, 0, scanned)
+	assert.Len(t, r.payloads, 2)
+}
+
+func TestDispatch_Checkpoint(t *testing.T) {
+	db := setupTestDB(t)
+	for height := int64(1); height <= 25; height++ {
+		require.NoError(t, db.Create(&types.CollectedBlock{ChainId: "test-1", Height: height}).Error)
+	}
+	for sequence := int64(1); sequence <= 12; sequence++ {
+		addTx(t, db, sequence, `{}`)
+	}
+
+	checkpoint := func() types.CollectedExtensionCheckpoint {
+		var cp types.CollectedExtensionCheckpoint
+		require.NoError(t, db.Where("name = ?", ExtensionName).First(&cp).Error)
+		return cp
+	}
+
+	// a backlog remains, only the heights below the next tx are covered
+	status := getStatus(t, db)
+	_, err := newTestDispatcher(db).Dispatch(context.Background(), status)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), checkpoint().Sequence)
+	assert.Equal(t, int64(20), checkpoint().Height)
+
+	// caught up, every collected block is covered
+	_, err = newTestDispatcher(db).Dispatch(context.Background(), status)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), checkpoint().Sequence)
+	assert.Equal(t, int64(25), checkpoint().Height)
+}
+
+func TestDispatch_CollectionMint(t *testing.T) {
+	db := setupTestDB(t)
+	r := newReceiver(t, http.StatusOK)
+	collectionAddr := fmt.Sprintf("0x%x", collection)
+	sub := subscribe(t, db, webhook.EventCollectionMint, collectionAddr, r.URL)
+
+	wasmEvent := func(contract []byte, action, tokenId string) string {
+		return fmt.Sprintf(`{"type":"wasm","attributes":[{"key":"_contract_address","value":"%s"},{"key":"action","value":"%s"},{"key":"token_id","value":"%s"}]}`,
+			sdk.AccAddress(contract).String(), action, tokenId)
+	}
+	addTx(t, db, 1, fmt.Sprintf(`{"events":[%s,%s,%s]}`,
+		wasmEvent(collection, "mint", "7"),
+		wasmEvent(collection, "transfer_nft", "7"),
+		wasmEvent([]byte{0xee}, "mint", "8")))
+
+	_, err := newTestDispatcher(db).Dispatch(context.Background(), getStatus(t, db))
+	require.NoError(t, err)
+
+	require.Len(t, r.payloads, 1)
+	assert.Equal(t, fmt.Sprintf("%d/1/7", sub.Id), r.payloads[0].Id)
+	data := r.payloads[0].Data.(map[string]any)
+	assert.Equal(t, collectionAddr, data["collection_addr"])
+	assert.Equal(t, "7", data["token_id"])
+}
+
+func TestDispatch_DeadLetter(t *testing.T) {
+	db := setupTestDB(t)
+	unavailable := newReceiver(t, http.StatusServiceUnavailable)
+	rejecting := newReceiver(t, http.StatusBadRequest)
+	retried := subscribe(t, db, webhook.EventAccountTx, sdk.AccAddress(watched).String(), unavailable.URL)
+	rejected := subscribe(t, db, webhook.EventAccountTx, sdk.AccAddress(watched).String(), rejecting.URL)
+
+	addTx(t, db, 1, `{}`, 1)
+
+	dispatcher := newTestDispatcher(db)
+	_, err := dispatcher.Dispatch(context.Background(), getStatus(t, db))
+	require.NoError(t, err)
+
+	// client errors are not retried, server errors are queued for retry
+	assert.Equal(t, 1, unavailable.hits)
+	assert.Equal(t, 1, rejecting.hits)
+	var retries []types.CollectedWebhookRetry
+	require.NoError(t, db.Find(&retries).Error)
+	require.Len(t, retries, 1)
+	assert.Equal(t, retried.Id, retries[0].SubscriptionId)
+	assert.Equal(t, 1, retries[0].Attempts)
+
+	// the retry fails its last attempt
+	time.Sleep(5 * time.Millisecond)
+	_, err = dispatcher.Dispatch(context.Background(), getStatus(t, db))
+	require.NoError(t, err)
+	assert.Equal(t, 2, unavailable.hits)
+
+	var letters []types.CollectedWebhookDeadLetter
+	require.NoError(t, db.Order("subscription_id").Find(&letters).Error)
+	require.Len(t, letters, 2)
+	assert.Equal(t, retried.Id, letters[0].SubscriptionId)
+	assert.Equal(t, 2, letters[0].Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, letters[0].StatusCode)
+	assert.Equal(t, fmt.Sprintf("%d/1", retried.Id), letters[0].DeliveryId)
+	assert.Equal(t, rejected.Id, letters[1].SubscriptionId)
+	assert.Equal(t, 1, letters[1].Attempts)
+
+	var count int64
+	require.NoError(t, db.Model(&types.CollectedWebhookRetry{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	var stored types.CollectedWebhookSubscription
+	require.NoError(t, db.First(&stored, retried.Id).Error)
+	assert.Equal(t, int64(1), stored.FailedCount)
+	assert.Contains(t, stored.LastError, "503")
+
+	status := getStatus(t, db)
+	assert.Equal(t, int64(1), status.LastSequence)
+	assert.Equal(t, int64(2), status.DeadLetters)
+}
+
+func TestDispatch_Retry(t *testing.T) {
+	db := setupTestDB(t)
+	failing := newReceiver(t, http.StatusServiceUnavailable)
+	healthy := newReceiver(t, http.StatusOK)
+	sub := subscribe(t, db, webhook.EventAccountTx, sdk.AccAddress(watched).String(), failing.URL)
+	subscribe(t, db, webhook.EventAccountTx, sdk.AccAddress(watched).String(), healthy.URL)
+
+	addTx(t, db, 1, `{}`, 1)
+	addTx(t, db, 2, `{}`, 1)
+
+	// the failing receiver neither holds back the other subscription nor the cursor
+	dispatcher := newTestDispatcher(db)
+	_, err := dispatcher.Dispatch(context.Background(), getStatus(t, db))
+	require.NoError(t, err)
+	assert.Equal(t, 1, failing.hits)
+	assert.Len(t, healthy.payloads, 2)
+	assert.Equal(t, int64(2), getStatus(t, db).LastSequence)
+
+	// the notification following the failed one waits behind it without an attempt
+	var retries []types.CollectedWebhookRetry
+	require.NoError(t, db.Order("id").Find(&retries).Error)
+	require.Len(t, retries, 2)
+	assert.Equal(t, fmt.Sprintf("%d/1", sub.Id), retries[0].DeliveryId)
+	assert.Equal(t, 1, retries[0].Attempts)
+	assert.Equal(t, fmt.Sprintf("%d/2", sub.Id), retries[1].DeliveryId)
+	assert.Equal(t, 0, retries[1].Attempts)
+
+	// once recovered, the receiver gets the retries then the new notification in order
+	failing.mtx.Lock()
+	failing.status = http.StatusOK
+	failing.mtx.Unlock()
+	addTx(t, db, 3, `{}`, 1)
+	time.Sleep(5 * time.Millisecond)
+	_, err = dispatcher.Dispatch(context.Background(), getStatus(t, db))
+	require.NoError(t, err)
+
+	require.Len(t, failing.payloads, 3)
+	for i, payload := range failing.payloads {
+		assert.Equal(t, fmt.Sprintf("%d/%d", sub.Id, i+1), payload.Id)
+	}
+	var count int64
+	require.NoError(t, db.Model(&types.CollectedWebhookRetry{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	status := getStatus(t, db)
+	assert.Equal(t, int64(3), status.LastSequence)
+	assert.Equal(t, int64(6), status.DeliveredEvents)
+}
+
+func TestDispatch_RichListTop(t *testing.T) {
+	db := setupTestDB(t)
+	r := newReceiver(t, http.StatusOK)
+	sub := subscribe(t, db, webhook.EventRichListTop, "uinit", r.URL)
+
+	require.NoError(t, db.Create(&types.CollectedRichListStatus{Height: 5}).Error)
+	require.NoError(t, db.Create(&types.CollectedRichList{Id: 1, Denom: "uinit", Amount: "100"}).Error)
+	require.NoError(t, db.Create(&types.CollectedRichList{Id: 2, Denom: "uinit", Amount: "50"}).Error)
+
+	dispatcher := newTestDispatcher(db)
+	status := getStatus(t, db)
+
+	// the first top of a subscription is stored without a notification
+	_, err := dispatcher.Dispatch(context.Background(), status)
+	require.NoError(t, err)
+	assert.Empty(t, r.payloads)
+	assert.Equal(t, int64(5), status.LastRichListHeight)
+
+	// a new height with the same top is not notified
+	require.NoError(t, db.Model(&types.CollectedRichListStatus{}).Where("1 = 1").Update("height", 6).Error)
+	_, err = dispatcher.Dispatch(context.Background(), status)
+	require.NoError(t, err)
+	assert.Empty(t, r.payloads)
+
+	require.NoError(t, db.Model(&types.CollectedRichList{}).Where("id = ?", 2).Update("amount", "150").Error)
+	require.NoError(t, db.Model(&types.CollectedRichListStatus{}).Where("1 = 1").Update("height", 7).Error)
+	_, err = dispatcher.Dispatch(context.Background(), status)
+	require.NoError(t, err)
+
+	require.Len(t, r.payloads, 1)
+	assert.Equal(t, fmt.Sprintf("%d/rich_list/7", sub.Id), r.payloads[0].Id)
+	holders := r.payloads[0].Data.(map[string]any)["holders"].([]any)
+	require.Len(t, holders, 2)
+	assert.Equal(t, sdk.AccAddress([]byte{0x09}).String(), holders[0].(map[string]any)["account"])
+	assert.Equal(t, int64(7), status.LastRichListHeight)
+}
@@ -0,0 +1,440 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	evmtypes "github.com/initia-labs/minievm/x/evm/types"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+	"github.com/initia-labs/rollytics/util/webhook"
+)
+
+// AccountTx is the data of an account_tx notification
+type AccountTx struct {
+	Account  string `json:"account"`
+	TxHash   string `json:"tx_hash"`
+	Height   int64  `json:"height"`
+	Sequence int64  `json:"sequence"`
+	Signer   bool   `json:"signer"`
+	Code     uint32 `json:"code"`
+}
+
+// CollectionMint is the data of a collection_mint notification
+type CollectionMint struct {
+	CollectionAddr string `json:"collection_addr"`
+	TokenId        string `json:"token_id,omitempty"`
+	ObjectAddr     string `json:"object_addr,omitempty"` // only used in Move
+	TxHash         string `json:"tx_hash"`
+	Height         int64  `json:"height"`
+	Sequence       int64  `json:"sequence"`
+}
+
+// RichListTop is the data of a rich_list_top notification
+type RichListTop struct {
+	Denom   string   `json:"denom"`
+	Height  int64    `json:"height"`
+	Holders []Holder `json:"holders"`
+}
+
+type Holder struct {
+	Account string `json:"account"`
+	Amount  string `json:"amount"`
+}
+
+// mint is an nft minted by a tx, keyed by its collection address bytes in hex
+type mint struct {
+	collection string
+	tokenId    string
+	objectAddr string
+}
+
+// scanTxs returns the notifications of the txs after the cursor, up to the batch size, and the
+// sequence scanned up to
+func (d *Dispatcher) scanTxs(db *gorm.DB, subscriptions []types.CollectedWebhookSubscription, from int64) (int64, []Notification, error) {
+	head, err := getLastSequence(db)
+	if err != nil {
+		return from, nil, err
+	}
+	to := min(from+int64(d.cfg.BatchSize), head)
+	if to <= from {
+		return from, nil, nil
+	}
+
+	accountSubs := make(map[string][]*types.CollectedWebhookSubscription)
+	mintSubs := make(map[string][]*types.CollectedWebhookSubscription)
+	for idx := range subscriptions {
+		sub := &subscriptions[idx]
+		switch sub.Event {
+		case webhook.EventAccountTx, webhook.EventCollectionMint:
+			addr, err := util.AccAddressFromString(sub.Target)
+			if err != nil {
+				d.logger.Warn("skipping subscription with invalid target", "id", sub.Id, "target", sub.Target)
+				continue
+			}
+			key := util.BytesToHex(addr)
+			if sub.Event == webhook.EventAccountTx {
+				accountSubs[key] = append(accountSubs[key], sub)
+			} else {
+				mintSubs[key] = append(mintSubs[key], sub)
+			}
+		}
+	}
+	if len(accountSubs) == 0 && len(mintSubs) == 0 {
+		return to, nil, nil
+	}
+
+	columns := []string{"hash", "height", "sequence", "code"}
+	if len(mintSubs) > 0 {
+		columns = append(columns, "data")
+	}
+	var txs []types.CollectedTx
+	if err := db.Select(columns).
+		Where("sequence > ? AND sequence <= ?", from, to).
+		Order("sequence").
+		Find(&txs).Error; err != nil {
+		return from, nil, fmt.Errorf("failed to load txs: %w", err)
+	}
+
+	accountTxs, err := d.accountTxs(db, accountSubs, from, to)
+	if err != nil {
+		return from, nil, err
+	}
+
+	var notifications []Notification
+	for _, tx := range txs {
+		txHash := strings.ToUpper(util.BytesToHex(tx.Hash))
+
+		for _, edge := range accountTxs[tx.Sequence] {
+			for _, sub := range accountSubs[edge.account] {
+				notifications = append(notifications, Notification{
+					Subscription: sub,
+					Id:           fmt.Sprintf("%d/%d", sub.Id, tx.Sequence),
+					Event:        sub.Event,
+					Data: AccountTx{
+						Account:  sub.Target,
+						TxHash:   txHash,
+						Height:   tx.Height,
+						Sequence: tx.Sequence,
+						Signer:   edge.signer,
+						Code:     tx.Code,
+					},
+				})
+			}
+		}
+
+		if len(mintSubs) == 0 {
+			continue
+		}
+		mints, err := d.mints(db, tx.Data)
+		if err != nil {
+			return from, nil, fmt.Errorf("failed to parse the mints of tx %s: %w", txHash, err)
+		}
+		for _, m := range mints {
+			for _, sub := range mintSubs[m.collection] {
+				notifications = append(notifications, Notification{
+					Subscription: sub,
+					Id:           fmt.Sprintf("%d/%d/%s", sub.Id, tx.Sequence, m.key()),
+					Event:        sub.Event,
+					Data: CollectionMint{
+						CollectionAddr: sub.Target,
+						TokenId:        m.tokenId,
+						ObjectAddr:     m.objectAddr,
+						TxHash:         txHash,
+						Height:         tx.Height,
+						Sequence:       tx.Sequence,
+					},
+				})
+			}
+		}
+	}
+
+	return to, notifications, nil
+}
+
+type accountTx struct {
+	account string
+	signer  bool
+}
+
+// accountTxs returns the watched accounts of the txs in the sequence range, by sequence
+func (d *Dispatcher) accountTxs(db *gorm.DB, accountSubs map[string][]*types.CollectedWebhookSubscription, from, to int64) (map[int64][]accountTx, error) {
+	if len(accountSubs) == 0 {
+		return nil, nil
+	}
+
+	accounts := make([][]byte, 0, len(accountSubs))
+	for key := range accountSubs {
+		account, err := util.HexToBytes(key)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	var dicts []types.CollectedAccountDict
+	if err := db.Where("account IN ?", accounts).Find(&dicts).Error; err != nil {
+		return nil, fmt.Errorf("failed to load watched accounts: %w", err)
+	}
+	if len(dicts) == 0 {
+		return nil, nil
+	}
+	keys := make(map[int64]string, len(dicts))
+	ids := make([]int64, 0, len(dicts))
+	for _, dict := range dicts {
+		keys[dict.Id] = util.BytesToHex(dict.Account)
+		ids = append(ids, dict.Id)
+	}
+
+	var edges []types.CollectedTxAccount
+	if err := db.Where("sequence > ? AND sequence <= ? AND account_id IN ?", from, to, ids).
+		Order("sequence, account_id").
+		Find(&edges).Error; err != nil {
+		return nil, fmt.Errorf("failed to load account txs: %w", err)
+	}
+	accountTxs := make(map[int64][]accountTx, len(edges))
+	for _, edge := range edges {
+		accountTxs[edge.Sequence] = append(accountTxs[edge.Sequence], accountTx{
+			account: keys[edge.AccountId],
+			signer:  edge.Signer,
+		})
+	}
+	return accountTxs, nil
+}
+
+// mints parses the nfts minted by a tx from its events, the way the nft collector of the vm does
+func (d *Dispatcher) mints(db *gorm.DB, data json.RawMessage) ([]mint, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var tx struct {
+		Events []abci.Event `json:"events"`
+	}
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+
+	var mints []mint
+	for _, event := range tx.Events {
+		attrs := make(map[string]string, len(event.Attributes))
+		for _, attr := range event.Attributes {
+			attrs[attr.Key] = attr.Value
+		}
+
+		switch {
+		case d.vmType == types.MoveVM && event.Type == "move":
+			if attrs["type_tag"] != "0x1::collection::MintEvent" {
+				continue
+			}
+			var mintEvent struct {
+				Collection string `json:"collection"`
+				Nft        string `json:"nft"`
+			}
+			if err := json.Unmarshal([]byte(attrs["data"]), &mintEvent); err != nil {
+				return nil, err
+			}
+			m, ok := newMint(mintEvent.Collection, "")
+			if !ok {
+				continue
+			}
+			m.objectAddr = mintEvent.Nft
+			mints = append(mints, m)
+		case d.vmType == types.WasmVM && event.Type == "wasm":
+			if attrs["action"] != "mint" {
+				continue
+			}
+			if m, ok := newMint(attrs["_contract_address"], attrs["token_id"]); ok {
+				mints = append(mints, m)
+			}
+		case d.vmType == types.EVM && event.Type == "evm":
+			var log evmtypes.Log
+			if err := json.Unmarshal([]byte(attrs["log"]), &log); err != nil {
+				return nil, err
+			}
+			if len(log.Topics) != 4 || log.Topics[0] != types.EvmTransferTopic || log.Data != "0x" ||
+				log.Topics[1] != types.EvmEmptyAddress || log.Topics[2] == types.EvmEmptyAddress {
+				continue
+			}
+			tokenId, err := hexToDec(log.Topics[3])
+			if err != nil {
+				return nil, err
+			}
+			if m, ok := newMint(log.Address, tokenId); ok {
+				mints = append(mints, m)
+			}
+		}
+	}
+
+	return d.resolveObjectTokenIds(db, mints)
+}
+
+// resolveObjectTokenIds fills the token ids of the Move nfts, which their mint events leave out
+func (d *Dispatcher) resolveObjectTokenIds(db *gorm.DB, mints []mint) ([]mint, error) {
+	var addrs [][]byte
+	for _, m := range mints {
+		if m.objectAddr == "" {
+			continue
+		}
+		addr, err := util.HexToBytes(m.objectAddr)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		return mints, nil
+	}
+
+	var nfts []types.CollectedNft
+	if err := db.Select("addr", "token_id").Where("addr IN ?", addrs).Find(&nfts).Error; err != nil {
+		return nil, err
+	}
+	for idx := range mints {
+		addr, err := util.HexToBytes(mints[idx].objectAddr)
+		if err != nil {
+			return nil, err
+		}
+		// a burned nft keeps its object address only
+		for _, nft := range nfts {
+			if bytes.Equal(nft.Addr, addr) {
+				mints[idx].tokenId = nft.TokenId
+				break
+			}
+		}
+	}
+	return mints, nil
+}
+
+// key identifies the nft within its collection, Move nfts by their object address
+func (m mint) key() string {
+	if m.objectAddr != "" {
+		return m.objectAddr
+	}
+	return m.tokenId
+}
+
+func newMint(collection, tokenId string) (mint, bool) {
+	addr, err := util.AccAddressFromString(collection)
+	if err != nil {
+		return mint{}, false
+	}
+	return mint{collection: util.BytesToHex(addr), tokenId: tokenId}, true
+}
+
+// scanRichList returns the notifications of the rich list subscriptions whose top holders changed,
+// the height scanned up to and the new top of every subscription to store. The first top of a new
+// subscription is stored without a notification.
+func (d *Dispatcher) scanRichList(db *gorm.DB, subscriptions []types.CollectedWebhookSubscription, from int64) (int64, []Notification, map[int64]json.RawMessage, error) {
+	height, err := getRichListHeight(db)
+	if err != nil {
+		return from, nil, nil, err
+	}
+
+	var subs []*types.CollectedWebhookSubscription
+	for idx := range subscriptions {
+		sub := &subscriptions[idx]
+		if sub.Event != webhook.EventRichListTop {
+			continue
+		}
+		if height > from || len(sub.State) == 0 {
+			subs = append(subs, sub)
+		}
+	}
+	if len(subs) == 0 {
+		return max(height, from), nil, nil, nil
+	}
+
+	tops := make(map[string][]Holder)
+	var notifications []Notification
+	states := make(map[int64]json.RawMessage)
+	for _, sub := range subs {
+		top, ok := tops[sub.Target]
+		if !ok {
+			if top, err = d.richListTop(db, sub.Target); err != nil {
+				return from, nil, nil, err
+			}
+			tops[sub.Target] = top
+		}
+
+		state, err := json.Marshal(top)
+		if err != nil {
+			return from, nil, nil, err
+		}
+		if len(sub.State) > 0 && sameHolders(sub.State, state) {
+			continue
+		}
+		states[sub.Id] = state
+		if len(sub.State) == 0 {
+			continue
+		}
+		notifications = append(notifications, Notification{
+			Subscription: sub,
+			Id:           fmt.Sprintf("%d/rich_list/%d", sub.Id, height),
+			Event:        sub.Event,
+			Data: RichListTop{
+				Denom:   sub.Target,
+				Height:  height,
+				Holders: top,
+			},
+		})
+	}
+
+	return max(height, from), notifications, states, nil
+}
+
+// richListTop returns the top holders of the denom
+func (d *Dispatcher) richListTop(db *gorm.DB, denom string) ([]Holder, error) {
+	var rows []struct {
+		Account []byte
+		Amount  string
+	}
+	if err := db.Table("rich_list").
+		Select("account_dict.account, rich_list.amount").
+		Joins("JOIN account_dict ON account_dict.id = rich_list.id").
+		Where("rich_list.denom = ?", denom).
+		Order("rich_list.amount DESC, rich_list.id").
+		Limit(webhook.RichListTopSize).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load the rich list of %s: %w", denom, err)
+	}
+
+	holders := make([]Holder, 0, len(rows))
+	for _, row := range rows {
+		holders = append(holders, Holder{
+			Account: webhook.FormatAccount(d.vmType, row.Account),
+			Amount:  row.Amount,
+		})
+	}
+	return holders, nil
+}
+
+// sameHolders compares the holders of a stored top with a new top, jsonb does not keep the formatting
+func sameHolders(a, b json.RawMessage) bool {
+	var x, y []Holder
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	if len(x) != len(y) {
+		return false
+	}
+	for idx := range x {
+		if x[idx] != y[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+func hexToDec(hex string) (string, error) {
+	bi, ok := new(big.Int).SetString(strings.TrimPrefix(hex, "0x"), 16)
+	if !ok {
+		return "", errors.New("failed to convert hex to dec")
+	}
+	return bi.String(), nil
+}
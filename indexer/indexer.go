@@ -5,17 +5,22 @@ import (
 	"errors"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/adaptive"
 	"github.com/initia-labs/rollytics/indexer/collector"
+	"github.com/initia-labs/rollytics/indexer/collector/tx"
 	"github.com/initia-labs/rollytics/indexer/extension"
+	"github.com/initia-labs/rollytics/indexer/leader"
 	"github.com/initia-labs/rollytics/indexer/scraper"
 	indexertypes "github.com/initia-labs/rollytics/indexer/types"
 	"github.com/initia-labs/rollytics/metrics"
 	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/orm/partition"
 	"github.com/initia-labs/rollytics/types"
 	"github.com/initia-labs/rollytics/util/querier"
 )
@@ -23,6 +28,9 @@ import (
 const (
 	// ShutdownTimeout defines the maximum time to wait for graceful shutdown
 	ShutdownTimeout = 30 * time.Second
+
+	// restoreIndexesRetryInterval is the wait before a failed restore of the deferred indexes is retried
+	restoreIndexesRetryInterval = time.Minute
 )
 
 type Indexer struct {
@@ -30,12 +38,20 @@ type Indexer struct {
 	logger           *slog.Logger
 	db               *orm.Database
 	scraper          *scraper.Scraper
+	adaptive         *adaptive.Controller
 	collector        *collector.Collector
 	extensionManager *extension.ExtensionManager
+	partitionManager *partition.Manager
+	elector          *leader.Elector
 	blockMap         map[int64]indexertypes.ScrapedBlock
 	blockChan        chan indexertypes.ScrapedBlock
 	controlChan      chan string
-	paused           bool
+	cond             *sync.Cond // signaled when a block is prepared, guarded by mtx
+	paused           bool       // scraping is paused, by backpressure or the admin
+	adminPaused      bool       // scraping is held paused by the admin until resumed
+	leading          bool       // the pipeline runs and reads the control signals
+	initialSync      atomic.Bool
+	restoringIndexes atomic.Bool // the deferred indexes are being recreated in the background
 	mtx              sync.Mutex
 	height           int64
 	prepareCount     int
@@ -43,14 +59,23 @@ type Indexer struct {
 	querier          *querier.Querier
 }
 
-func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *Indexer {
-	return &Indexer{
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) (*Indexer, error) {
+	extensionManager, err := extension.New(cfg, logger, db)
+	if err != nil {
+		return nil, err
+	}
+
+	controller := adaptive.New(cfg, logger)
+	i := &Indexer{
 		cfg:              cfg,
 		logger:           logger,
 		db:               db,
-		scraper:          scraper.New(cfg, logger),
+		scraper:          scraper.New(cfg, logger, controller),
+		adaptive:         controller,
 		collector:        collector.New(cfg, logger, db),
-		extensionManager: extension.New(cfg, logger, db),
+		extensionManager: extensionManager,
+		partitionManager: partition.NewManager(cfg, logger, db),
+		elector:          leader.New(cfg, logger, db, leader.WorkloadIndexer),
 		blockMap:         make(map[int64]indexertypes.ScrapedBlock),
 		// Buffering reduces backpressure stalls between scraper -> prepare when downstream is briefly slow.
 		// It also prevents fastSync goroutines from piling up solely because the channel is unbuffered.
@@ -58,6 +83,8 @@ func New(cfg *config.Config, logger *slog.Logger, db *orm.Database) *Indexer {
 		controlChan: make(chan string, 1),
 		querier:     querier.NewQuerier(cfg.GetChainConfig()),
 	}
+	i.cond = sync.NewCond(&i.mtx)
+	return i, nil
 }
 
 func (i *Indexer) Run(ctx context.Context) error {
@@ -65,12 +92,41 @@ func (i *Indexer) Run(ctx context.Context) error {
 	// wait for the chain to be ready
 	i.wait()
 
+	// Extensions elect their own leader, so they run on whichever replica holds their lock
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		i.extend()
+	}()
+
+	err := i.elector.Run(ctx, i.lead, i.standby)
+	if err != nil {
+		return err
+	}
+
+	i.logger.Info("indexer shutdown initiated")
+	if waitTimeout(&wg, ShutdownTimeout) {
+		i.logger.Info("indexer shutdown completed gracefully")
+	} else {
+		i.logger.Warn("indexer shutdown timed out, some goroutines may still be running")
+	}
+
+	return nil
+}
+
+// lead indexes blocks until the context is done, either on shutdown or once leadership is lost
+func (i *Indexer) lead(ctx context.Context) error {
 	var lastBlock types.CollectedBlock
 	if err := i.db.
+		WithContext(ctx).
 		Where("chain_id = ?", i.cfg.GetChainId()).
 		Order("height desc").
 		Limit(1).
 		First(&lastBlock).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		if ctx.Err() != nil {
+			return nil
+		}
 		i.logger.Error("failed to get the last block from db", slog.Any("error", err))
 		return types.NewDatabaseError("get last block", err)
 	}
@@ -78,6 +134,9 @@ func (i *Indexer) Run(ctx context.Context) error {
 
 	chainHeight, err := i.querier.GetLatestHeight(ctx)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
 		i.logger.Error("failed to get chain height", slog.Any("error", err))
 		return err
 	}
@@ -100,36 +159,96 @@ func (i *Indexer) Run(ctx context.Context) error {
 		slog.Int64("effective_start_height", i.height),
 	)
 
+	if err := i.startInitialSync(ctx, chainHeight); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		i.logger.Error("failed to start initial sync", slog.Any("error", err))
+		return types.NewDatabaseError("start initial sync", err)
+	}
+
+	// A previous term may have left blocks behind, start over from the resume height
+	i.mtx.Lock()
+	i.blockMap = make(map[int64]indexertypes.ScrapedBlock)
+	i.blockChan = make(chan indexertypes.ScrapedBlock, types.MaxInflightBlocks)
+	i.controlChan = make(chan string, 1)
+	i.paused = false
+	i.prepareCount = 0
+	i.leading = true
+	// A pause by the admin outlives leadership changes
+	if i.adminPaused {
+		i.controlChan <- "pause"
+		i.paused = true
+	}
+	i.mtx.Unlock()
+
 	// Use a wait group to track all goroutines
 	var wg sync.WaitGroup
 
 	// Start all components
-	wg.Add(4)
+	wg.Add(5)
 	go func() {
 		defer wg.Done()
-		i.extend()
+		i.partitionManager.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		i.adaptive.Run(ctx)
 	}()
 	go func() {
 		defer wg.Done()
-		i.scrape()
+		i.scrape(ctx)
 	}()
 	go func() {
 		defer wg.Done()
-		i.prepare()
+		i.prepare(ctx)
 	}()
 	go func() {
 		defer wg.Done()
-		i.collect()
+		i.collect(ctx)
 	}()
 
 	// Wait for context cancellation
 	<-ctx.Done()
-	i.logger.Info("indexer shutdown initiated")
+	i.logger.Info("indexer pipeline stopping")
+	i.mtx.Lock()
+	i.leading = false
+	i.mtx.Unlock()
+
+	// Wait for graceful shutdown or timeout
+	if !waitTimeout(&wg, ShutdownTimeout) {
+		i.logger.Warn("indexer pipeline stop timed out, some goroutines may still be running")
+		return nil
+	}
+	// collect was the only sender left, closing stops the control reader of the scraper
+	close(i.controlChan)
+
+	return nil
+}
 
-	// Create shutdown context with timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
-	defer cancel()
+// standby keeps the chain connection warm while another replica leads
+func (i *Indexer) standby(ctx context.Context) {
+	i.logger.Info("standing by for indexer leadership")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(types.ChainCheckInterval):
+		}
 
+		chainHeight, err := i.querier.GetLatestHeight(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				i.logger.Warn("failed to get chain height on standby", slog.Any("error", err))
+			}
+			continue
+		}
+		i.logger.Debug("standing by", slog.Int64("chain_height", chainHeight))
+	}
+}
+
+// waitTimeout waits for the group and reports whether it finished before the timeout
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
 	// Channel to signal completion
 	done := make(chan struct{})
 	go func() {
@@ -137,15 +256,12 @@ func (i *Indexer) Run(ctx context.Context) error {
 		close(done)
 	}()
 
-	// Wait for graceful shutdown or timeout
 	select {
 	case <-done:
-		i.logger.Info("indexer shutdown completed gracefully")
-	case <-shutdownCtx.Done():
-		i.logger.Warn("indexer shutdown timed out, some goroutines may still be running")
+		return true
+	case <-time.After(timeout):
+		return false
 	}
-
-	return nil
 }
 
 func (i *Indexer) wait() {
@@ -167,14 +283,17 @@ func (i *Indexer) extend() {
 	i.extensionManager.Run(i.ctx)
 }
 
-func (i *Indexer) scrape() {
-	i.scraper.Run(i.ctx, i.height, i.blockChan, i.controlChan)
+func (i *Indexer) scrape(ctx context.Context) {
+	i.scraper.Run(ctx, i.height, i.blockChan, i.controlChan)
 }
 
-func (i *Indexer) prepare() {
+func (i *Indexer) prepare(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
 	for {
 		select {
-		case <-i.ctx.Done():
+		case <-ctx.Done():
 			i.logger.Info("prepare() shutting down gracefully")
 			return
 		case block := <-i.blockChan:
@@ -187,7 +306,9 @@ func (i *Indexer) prepare() {
 			i.mtx.Unlock()
 
 			b := block
+			wg.Add(1)
 			go func() {
+				defer wg.Done()
 				defer func() {
 					if r := recover(); r != nil {
 						metrics.TrackPanic("indexer")
@@ -202,14 +323,14 @@ func (i *Indexer) prepare() {
 				for {
 					// Check if context is cancelled (Ctrl+C) - this is the only way to stop
 					select {
-					case <-i.ctx.Done():
+					case <-ctx.Done():
 						i.logger.Info("prepare cancelled, stopping", slog.Int64("height", b.Height))
 						i.decrementPrepareCount()
 						return
 					default:
 					}
 
-					err := i.collector.Prepare(i.ctx, b)
+					err := i.collector.Prepare(ctx, b)
 					if err == nil {
 						// Success - break out of retry loop
 						indexerMetrics.BlockProcessingTime.WithLabelValues("prepare").Observe(time.Since(start).Seconds())
@@ -230,7 +351,7 @@ func (i *Indexer) prepare() {
 						metrics.TrackError("indexer", "prepare_timeout_retry")
 						// Wait a bit before retrying
 						select {
-						case <-i.ctx.Done():
+						case <-ctx.Done():
 							i.logger.Info("prepare cancelled during retry wait, stopping", slog.Int64("height", b.Height))
 							i.decrementPrepareCount()
 							return
@@ -251,6 +372,7 @@ func (i *Indexer) prepare() {
 				i.mtx.Lock()
 				i.blockMap[b.Height] = b
 				i.prepareCount--
+				i.cond.Broadcast()
 				i.mtx.Unlock()
 			}()
 		}
@@ -264,42 +386,21 @@ func (i *Indexer) decrementPrepareCount() {
 	i.mtx.Unlock()
 }
 
-func (i *Indexer) collect() {
-	for {
-		select {
-		case <-i.ctx.Done():
-			i.logger.Info("collect() shutting down gracefully")
-			return
-		default:
-		}
-
+func (i *Indexer) collect(ctx context.Context) {
+	// wake up the wait for prepared blocks on shutdown
+	stop := context.AfterFunc(ctx, func() {
 		i.mtx.Lock()
-
-		inflightCount := len(i.blockMap) + i.prepareCount
-		indexerMetrics := metrics.GetMetrics().IndexerMetrics()
-		indexerMetrics.InflightBlocksCount.Set(float64(inflightCount))
-
-		switch {
-		case inflightCount > types.MaxInflightBlocks && !i.paused:
-			i.controlChan <- "pause"
-			i.paused = true
-		case inflightCount < types.MinInflightBlocks && i.paused:
-			i.controlChan <- "start"
-			i.paused = false
-		}
-
-		block, ok := i.blockMap[i.height]
-		delete(i.blockMap, i.height)
+		i.cond.Broadcast()
 		i.mtx.Unlock()
+	})
+	defer stop()
 
+	batchSize := max(i.cfg.GetCollectBatchSize(), 1)
+	for {
+		blocks, ok := i.nextBlocks(ctx, batchSize)
 		if !ok {
-			select {
-			case <-i.ctx.Done():
-				i.logger.Info("collect() shutting down gracefully")
-				return
-			case <-time.After(i.cfg.GetCoolingDuration()):
-			}
-			continue
+			i.logger.Info("collect() shutting down gracefully")
+			return
 		}
 
 		func() {
@@ -312,19 +413,137 @@ func (i *Indexer) collect() {
 
 			start := time.Now()
 			indexerMetrics := metrics.GetMetrics().IndexerMetrics()
-			if err := i.collector.Collect(block); err != nil {
-				i.logger.Error("failed to collect block", slog.Int64("height", block.Height))
+			if err := i.collector.CollectBatch(ctx, blocks); err != nil {
+				i.logger.Error("failed to collect block",
+					slog.Int64("height", blocks[0].Height),
+					slog.Int("count", len(blocks)))
 				indexerMetrics.ProcessingErrors.WithLabelValues("collect", "collector_error").Inc()
 				metrics.TrackError("indexer", "collect_error")
 				panic(err)
 			}
-			indexerMetrics.BlockProcessingTime.WithLabelValues("collect").Observe(time.Since(start).Seconds())
+			elapsed := time.Since(start)
+			indexerMetrics.BlockProcessingTime.WithLabelValues("collect").Observe(elapsed.Seconds())
+			i.adaptive.ObserveCollect(elapsed / time.Duration(len(blocks)))
 
-			indexerMetrics.BlocksProcessedTotal.Inc()
-			indexerMetrics.CurrentBlockHeight.Set(float64(block.Height))
+			indexerMetrics.BlocksProcessedTotal.Add(float64(len(blocks)))
+			indexerMetrics.CurrentBlockHeight.Set(float64(blocks[len(blocks)-1].Height))
 		}()
 
-		i.height++
+		i.mtx.Lock()
+		i.height += int64(len(blocks))
+		i.mtx.Unlock()
+
+		if i.initialSync.Load() && i.scraper.Status().Mode == scraper.ModeSlow {
+			i.finishInitialSync(ctx)
+		}
+	}
+}
+
+// startInitialSync switches the collector to the bulk loading when the indexer is further behind the
+// chain than the threshold. Otherwise it recreates the indexes left deferred by an earlier initial sync.
+func (i *Indexer) startInitialSync(ctx context.Context, chainHeight int64) error {
+	cfg := i.cfg.GetInitialSyncConfig()
+	if !i.cfg.InitialSyncEnabled() || chainHeight-i.height <= cfg.Threshold {
+		i.initialSync.Store(false)
+		i.collector.SetBulk(false)
+		i.restoreIndexes(ctx)
+		return nil
+	}
+
+	i.logger.Info("starting initial sync",
+		slog.Int64("height", i.height),
+		slog.Int64("chain_height", chainHeight),
+		slog.Bool("defer_indexes", cfg.DeferIndexes))
+	if cfg.DeferIndexes {
+		if err := i.db.DeferIndexes(ctx, tx.CopyTables, i.logger); err != nil {
+			return err
+		}
+	}
+	i.initialSync.Store(true)
+	i.collector.SetBulk(true)
+	return nil
+}
+
+// finishInitialSync switches the collector back to the normal transactional path once the scraper
+// reached the latest height, and recreates the deferred indexes in the background.
+func (i *Indexer) finishInitialSync(ctx context.Context) {
+	i.collector.SetBulk(false)
+	i.initialSync.Store(false)
+	i.restoreIndexes(ctx)
+
+	i.mtx.Lock()
+	height := i.height
+	i.mtx.Unlock()
+	i.logger.Info("finished initial sync", slog.Int64("height", height))
+}
+
+// restoreIndexes recreates the deferred indexes in the background, off the collect path, retrying
+// until they are all restored or the context is done. The next term resumes an interrupted restore.
+func (i *Indexer) restoreIndexes(ctx context.Context) {
+	if !i.restoringIndexes.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer i.restoringIndexes.Store(false)
+		for {
+			err := i.db.RestoreIndexes(ctx, i.logger)
+			if err == nil || ctx.Err() != nil {
+				return
+			}
+			i.logger.Error("failed to restore deferred indexes", slog.Any("error", err))
+			metrics.TrackError("indexer", "restore_indexes")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(restoreIndexesRetryInterval):
+			}
+		}
+	}()
+}
+
+// nextBlocks waits until the block at the collect height is prepared, and takes it along with up to
+// batchSize-1 prepared blocks following it. Meanwhile it pauses and resumes scraping on the inflight
+// blocks. It reports false once the context is done.
+func (i *Indexer) nextBlocks(ctx context.Context, batchSize int) ([]indexertypes.ScrapedBlock, bool) {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+
+	for {
+		if ctx.Err() != nil {
+			return nil, false
+		}
+
+		inflightCount := len(i.blockMap) + i.prepareCount
+		indexerMetrics := metrics.GetMetrics().IndexerMetrics()
+		indexerMetrics.InflightBlocksCount.Set(float64(inflightCount))
+
+		maxInflight, minInflight := i.adaptive.Window()
+		switch {
+		case inflightCount > maxInflight && !i.paused:
+			i.controlChan <- "pause"
+			i.paused = true
+		case inflightCount < minInflight && i.paused && !i.adminPaused:
+			i.controlChan <- "start"
+			i.paused = false
+		}
+
+		var blocks []indexertypes.ScrapedBlock
+		for height := i.height; len(blocks) < batchSize; height++ {
+			block, ok := i.blockMap[height]
+			if !ok {
+				break
+			}
+			delete(i.blockMap, height)
+			blocks = append(blocks, block)
+		}
+		if len(blocks) > 0 {
+			return blocks, true
+		}
+
+		// prepare, resume and shutdown signal the condition
+		i.cond.Wait()
 	}
 }
 
@@ -0,0 +1,301 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/metrics"
+	"github.com/initia-labs/rollytics/orm"
+)
+
+// Role is the part a replica plays for a workload
+type Role string
+
+const (
+	RoleLeader  Role = "leader"
+	RoleStandby Role = "standby"
+)
+
+// Workloads elected independently, each under its own advisory lock
+const (
+	WorkloadIndexer    = "indexer"
+	WorkloadExtensions = "extensions"
+)
+
+// ErrLeadershipLost aborts a write of a leader once another replica took over
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// roles holds the current role of this process per workload
+var roles sync.Map
+
+// RoleOf returns the current role of this process for the workload, or an empty role
+// when the workload does not run under leader election
+func RoleOf(workload string) Role {
+	if role, ok := roles.Load(workload); ok {
+		return role.(Role)
+	}
+	return ""
+}
+
+// Elector elects one leader for a workload among the replicas sharing a database. The leader
+// holds a session-level Postgres advisory lock on a dedicated connection, so the lock is freed
+// by the server as soon as the session dies, and standbys take over on their next attempt.
+// Each election increments the epoch of the workload in leader_epoch, which the writes of the
+// leader check with Fence, so that a leader still writing after its session died is fenced off.
+type Elector struct {
+	cfg      *config.LeaderConfig
+	logger   *slog.Logger
+	db       *orm.Database
+	workload string
+	key      int64
+	epoch    atomic.Int64 // epoch of the current leadership, zero while standing by
+}
+
+// electorKey is the context key of the elector leading the work of the context
+type electorKey struct{}
+
+// New returns nil when leader election is disabled; a nil Elector always leads
+func New(cfg *config.Config, logger *slog.Logger, db *orm.Database, workload string) *Elector {
+	if !cfg.LeaderElectionEnabled() {
+		return nil
+	}
+
+	return &Elector{
+		cfg:      cfg.GetLeaderConfig(),
+		logger:   logger.With("module", "leader", "workload", workload),
+		db:       db,
+		workload: workload,
+		key:      LockKey(cfg.GetChainId(), workload),
+	}
+}
+
+// LockKey derives the advisory lock key of a workload, so that rollups sharing a database
+// server elect their leaders independently
+func LockKey(chainId, workload string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("rollytics/" + chainId + "/" + workload))
+	return int64(h.Sum64())
+}
+
+// Run alternates between standby and lead until the context is done. lead runs with a context
+// that is cancelled once the lock is lost and must return after it is done, its transactions check
+// the leadership with Fence; an error from lead releases the lock and is returned. standby, when set, runs while the lock is held elsewhere.
+func (e *Elector) Run(ctx context.Context, lead func(context.Context) error, standby func(context.Context)) error {
+	if e == nil {
+		return lead(ctx)
+	}
+
+	for {
+		e.setRole(RoleStandby)
+		conn, epoch, err := e.acquire(ctx, standby)
+		if err != nil {
+			return nil // context is done
+		}
+
+		e.logger.Info("acquired leadership", slog.Int64("epoch", epoch))
+		e.epoch.Store(epoch)
+		e.setRole(RoleLeader)
+		err = e.hold(ctx, conn, lead)
+		e.setRole(RoleStandby)
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		e.logger.Warn("lost leadership, standing by")
+	}
+}
+
+// acquire tries the lock once per check interval until it is taken or the context is done
+func (e *Elector) acquire(ctx context.Context, standby func(context.Context)) (*sql.Conn, int64, error) {
+	standbyCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	// the standby work stops before the caller starts leading
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	if standby != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			standby(standbyCtx)
+		}()
+	}
+
+	for {
+		conn, epoch, err := e.tryLock(ctx)
+		if err != nil && ctx.Err() == nil {
+			e.logger.Error("failed to try the leader lock", slog.Any("error", err))
+		}
+		if conn != nil {
+			return conn, epoch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(e.cfg.CheckInterval):
+		}
+	}
+}
+
+// tryLock returns the connection holding the lock with the epoch it starts, or nil when another
+// session holds it
+func (e *Elector) tryLock(ctx context.Context) (*sql.Conn, int64, error) {
+	sqlDB, err := e.db.DB.DB()
+	if err != nil {
+		return nil, 0, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", e.key).Scan(&locked); err != nil {
+		discard(conn)
+		return nil, 0, err
+	}
+	if !locked {
+		_ = conn.Close()
+		return nil, 0, nil
+	}
+
+	// the increment waits for the fenced transactions of the previous leader to end
+	var epoch int64
+	if err := conn.QueryRowContext(ctx, `INSERT INTO leader_epoch (lock_key, epoch) VALUES ($1, 1)
+		ON CONFLICT (lock_key) DO UPDATE SET epoch = leader_epoch.epoch + 1 RETURNING epoch`, e.key).Scan(&epoch); err != nil {
+		e.release(conn)
+		return nil, 0, err
+	}
+	return conn, epoch, nil
+}
+
+// hold runs lead while checking once per check interval that the session holding the lock is alive
+func (e *Elector) hold(ctx context.Context, conn *sql.Conn, lead func(context.Context) error) error {
+	leadCtx, cancel := context.WithCancel(context.WithValue(ctx, electorKey{}, e))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- lead(leadCtx)
+	}()
+
+	ticker := time.NewTicker(e.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	var err error
+	finished := false
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case err = <-done:
+			finished = true
+			if err != nil {
+				break loop
+			}
+			// lead is over, keep the lock so that no standby repeats the work
+			done = nil
+		case <-ticker.C:
+			if checkErr := e.check(ctx, conn); checkErr != nil {
+				if ctx.Err() == nil {
+					e.logger.Error("leader lock connection failed", slog.Any("error", checkErr))
+				}
+				break loop
+			}
+		}
+	}
+
+	// the writes still running fail their fence from now on
+	e.epoch.Store(0)
+	cancel()
+	if !finished {
+		err = <-done
+	}
+	e.release(conn)
+	return err
+}
+
+// Fence checks within the transaction that the elector leading the work of its context still holds
+// the current epoch, and returns ErrLeadershipLost otherwise. The epoch row stays share locked until
+// the transaction ends, so that no other replica takes over before the writes are committed. Work
+// running without leader election is never fenced.
+func Fence(tx *gorm.DB) error {
+	ctx := tx.Statement.Context
+	if ctx == nil {
+		return nil
+	}
+	e, ok := ctx.Value(electorKey{}).(*Elector)
+	if !ok {
+		return nil
+	}
+
+	epoch := e.epoch.Load()
+	if epoch == 0 {
+		return ErrLeadershipLost
+	}
+	var current int64
+	if err := tx.Raw("SELECT epoch FROM leader_epoch WHERE lock_key = ? FOR SHARE", e.key).Scan(&current).Error; err != nil {
+		return err
+	}
+	if current != epoch {
+		return ErrLeadershipLost
+	}
+	return nil
+}
+
+// check verifies the session holding the lock, the lock lives as long as the session
+func (e *Elector) check(ctx context.Context, conn *sql.Conn) error {
+	checkCtx, cancel := context.WithTimeout(ctx, e.cfg.CheckInterval)
+	defer cancel()
+
+	var one int
+	return conn.QueryRowContext(checkCtx, "SELECT 1").Scan(&one)
+}
+
+// release unlocks and returns the connection to the pool, or discards the connection when it
+// cannot be unlocked, so that the lock never stays behind on a pooled session
+func (e *Elector) release(conn *sql.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.CheckInterval)
+	defer cancel()
+
+	var unlocked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", e.key).Scan(&unlocked); err != nil || !unlocked {
+		discard(conn)
+		return
+	}
+	_ = conn.Close()
+}
+
+// discard closes the connection instead of returning it to the pool
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
+
+func (e *Elector) setRole(role Role) {
+	roles.Store(e.workload, role)
+
+	if m := metrics.GetMetrics(); m != nil {
+		value := 0.0
+		if role == RoleLeader {
+			value = 1
+		}
+		m.IndexerMetrics().LeaderRole.WithLabelValues(e.workload).Set(value)
+	}
+}
@@ -0,0 +1,180 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/orm/testutil"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+const testWorkload = "test"
+
+func newTestConfig(enabled bool) *config.Config {
+	cfg := &config.Config{}
+	cfg.SetChainConfig(&config.ChainConfig{ChainId: "test-1"})
+	cfg.SetLeaderConfig(&config.LeaderConfig{Enabled: enabled, CheckInterval: 50 * time.Millisecond})
+	return cfg
+}
+
+func expectTryLock(mock sqlmock.Sqlmock, locked bool) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")).
+		WithArgs(LockKey("test-1", testWorkload)).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(locked))
+}
+
+func expectEpoch(mock sqlmock.Sqlmock, epoch int64) {
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO leader_epoch")).
+		WithArgs(LockKey("test-1", testWorkload)).
+		WillReturnRows(sqlmock.NewRows([]string{"epoch"}).AddRow(epoch))
+}
+
+func TestLockKey(t *testing.T) {
+	assert.Equal(t, LockKey("test-1", WorkloadIndexer), LockKey("test-1", WorkloadIndexer))
+	assert.NotEqual(t, LockKey("test-1", WorkloadIndexer), LockKey("test-1", WorkloadExtensions))
+	assert.NotEqual(t, LockKey("test-1", WorkloadIndexer), LockKey("test-2", WorkloadIndexer))
+}
+
+func TestRun_Disabled(t *testing.T) {
+	elector := New(newTestConfig(false), discardLogger, nil, testWorkload)
+	require.Nil(t, elector)
+
+	led := false
+	err := elector.Run(context.Background(), func(context.Context) error {
+		led = true
+		return nil
+	}, nil)
+	require.NoError(t, err)
+	assert.True(t, led)
+}
+
+func TestRun_StandbyThenLead(t *testing.T) {
+	db, mock, err := testutil.NewMockDB(discardLogger)
+	require.NoError(t, err)
+	elector := New(newTestConfig(true), discardLogger, db, testWorkload)
+
+	expectTryLock(mock, false)
+	expectTryLock(mock, true)
+	expectEpoch(mock, 1)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).
+		WithArgs(LockKey("test-1", testWorkload)).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_advisory_unlock"}).AddRow(true))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var standbyRole, leadRole Role
+	err = elector.Run(ctx, func(context.Context) error {
+		leadRole = RoleOf(testWorkload)
+		cancel()
+		return nil
+	}, func(ctx context.Context) {
+		standbyRole = RoleOf(testWorkload)
+		<-ctx.Done()
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, RoleStandby, standbyRole)
+	assert.Equal(t, RoleLeader, leadRole)
+	assert.Equal(t, RoleStandby, RoleOf(testWorkload))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRun_LockLost(t *testing.T) {
+	db, mock, err := testutil.NewMockDB(discardLogger)
+	require.NoError(t, err)
+	elector := New(newTestConfig(true), discardLogger, db, testWorkload)
+
+	expectTryLock(mock, true)
+	expectEpoch(mock, 1)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1")).WillReturnError(errors.New("connection reset"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnError(errors.New("connection reset"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var leads, standbys atomic.Int32
+	err = elector.Run(ctx, func(leadCtx context.Context) error {
+		leads.Add(1)
+		<-leadCtx.Done()
+		return nil
+	}, func(context.Context) {
+		// the second standby follows the lost lock
+		if standbys.Add(1) == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), leads.Load())
+	assert.Equal(t, int32(2), standbys.Load())
+	assert.Equal(t, RoleStandby, RoleOf(testWorkload))
+}
+
+func TestRun_LeadError(t *testing.T) {
+	db, mock, err := testutil.NewMockDB(discardLogger)
+	require.NoError(t, err)
+	elector := New(newTestConfig(true), discardLogger, db, testWorkload)
+
+	expectTryLock(mock, true)
+	expectEpoch(mock, 1)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_advisory_unlock"}).AddRow(true))
+
+	leadErr := errors.New("lead failed")
+	err = elector.Run(context.Background(), func(context.Context) error {
+		return leadErr
+	}, nil)
+	require.ErrorIs(t, err, leadErr)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFence(t *testing.T) {
+	db, mock, err := testutil.NewMockDB(discardLogger)
+	require.NoError(t, err)
+	elector := New(newTestConfig(true), discardLogger, db, testWorkload)
+
+	expectTryLock(mock, true)
+	expectEpoch(mock, 3)
+	expectFence := func(epoch int64) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT epoch FROM leader_epoch WHERE lock_key = $1 FOR SHARE")).
+			WithArgs(LockKey("test-1", testWorkload)).
+			WillReturnRows(sqlmock.NewRows([]string{"epoch"}).AddRow(epoch))
+	}
+	expectFence(3)
+	// another replica took over
+	expectFence(4)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_advisory_unlock"}).AddRow(true))
+
+	// work without leader election is not fenced
+	require.NoError(t, Fence(db.WithContext(context.Background())))
+
+	var fenced, deposed error
+	var leadCtx context.Context
+	err = elector.Run(context.Background(), func(ctx context.Context) error {
+		leadCtx = ctx
+		fenced = Fence(db.WithContext(ctx))
+		deposed = Fence(db.WithContext(ctx))
+		return errors.New("stop")
+	}, nil)
+	require.Error(t, err)
+	require.NoError(t, fenced)
+	require.ErrorIs(t, deposed, ErrLeadershipLost)
+
+	// the writes still running once the lead is over are refused without a query
+	require.ErrorIs(t, Fence(db.WithContext(leadCtx)), ErrLeadershipLost)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
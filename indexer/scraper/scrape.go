@@ -18,6 +18,11 @@ import (
 	"github.com/initia-labs/rollytics/util/querier"
 )
 
+// ScrapeBlock scrapes the block at the height outside of the scraper, e.g. for the backfill workers
+func ScrapeBlock(ctx context.Context, client *fiber.Client, height int64, cfg *config.Config, q *querier.Querier) (types.ScrapedBlock, error) {
+	return scrapeBlock(ctx, client, height, cfg, q)
+}
+
 func scrapeBlock(ctx context.Context, client *fiber.Client, height int64, cfg *config.Config, q *querier.Querier) (types.ScrapedBlock, error) {
 	start := time.Now()
 
@@ -4,11 +4,13 @@ import (
 	"context"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/indexer/adaptive"
 	"github.com/initia-labs/rollytics/indexer/types"
 	"github.com/initia-labs/rollytics/metrics"
 	commontypes "github.com/initia-labs/rollytics/types"
@@ -19,20 +21,41 @@ const (
 	layout = "2006-01-02T15:04:05.999999999Z"
 )
 
+// Mode is the sync mode of the scraper
+type Mode string
+
+const (
+	ModeFast Mode = "fast" // scraping ahead concurrently until the latest height
+	ModeSlow Mode = "slow" // following the chain in batches
+)
+
+// Status is a snapshot of the scraper
+type Status struct {
+	Mode        Mode
+	Paused      bool
+	InFlight    int64 // blocks being scraped or waiting to be handed over
+	Concurrency int   // limit of concurrent scrapes during fast sync
+}
+
 type Scraper struct {
 	cfg            *config.Config
 	querier        *querier.Querier
 	logger         *slog.Logger
+	adaptive       *adaptive.Controller
 	mtx            sync.Mutex
 	lastScrapeTime time.Time
 	scrapedCount   int64
+	mode           atomic.Value
+	paused         atomic.Bool
+	inFlight       atomic.Int64
 }
 
-func New(cfg *config.Config, logger *slog.Logger) *Scraper {
+func New(cfg *config.Config, logger *slog.Logger, controller *adaptive.Controller) *Scraper {
 	return &Scraper{
 		cfg:            cfg,
 		querier:        querier.NewQuerier(cfg.GetChainConfig()),
 		logger:         logger.With("module", "scraper"),
+		adaptive:       controller,
 		lastScrapeTime: time.Now(),
 		scrapedCount:   0,
 	}
@@ -43,21 +66,56 @@ func (s *Scraper) Run(ctx context.Context, height int64, blockChan chan<- types.
 	defer fiber.ReleaseClient(client)
 
 	// Start metrics updater
-	go s.updateScrapeSpeedMetrics()
+	go s.updateScrapeSpeedMetrics(ctx)
+
+	// The signals apply to both modes, the reader stops once the channel is closed
+	s.paused.Store(false)
+	go s.control(controlChan)
 
 	s.logger.Info("fast syncing until fully synced")
-	syncedHeight := s.fastSync(ctx, client, height, blockChan, controlChan)
+	s.mode.Store(ModeFast)
+	syncedHeight := s.fastSync(ctx, client, height, blockChan)
 
 	s.logger.Info("switching to slow syncing")
+	s.mode.Store(ModeSlow)
 	s.slowSync(ctx, client, syncedHeight+1, blockChan)
 }
 
+// control pauses and resumes the scraping on the signals of the indexer
+func (s *Scraper) control(controlChan <-chan string) {
+	for signal := range controlChan {
+		switch signal {
+		case "pause":
+			s.paused.Store(true)
+		case "start":
+			s.paused.Store(false)
+		}
+	}
+}
+
+// Status returns a snapshot of the scraper
+func (s *Scraper) Status() Status {
+	mode, _ := s.mode.Load().(Mode)
+	return Status{
+		Mode:        mode,
+		Paused:      s.paused.Load(),
+		InFlight:    s.inFlight.Load(),
+		Concurrency: s.adaptive.Concurrency(),
+	}
+}
+
 // updateScrapeSpeedMetrics periodically updates scrape speed metrics
-func (s *Scraper) updateScrapeSpeedMetrics() {
+func (s *Scraper) updateScrapeSpeedMetrics(ctx context.Context) {
 	ticker := time.NewTicker(commontypes.ScrapeSpeedUpdateInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
 		s.mtx.Lock()
 		now := time.Now()
 		elapsed := now.Sub(s.lastScrapeTime).Seconds()
@@ -17,42 +17,13 @@ import (
 	commontypes "github.com/initia-labs/rollytics/types"
 )
 
-const (
-	running int32 = iota
-	paused
-	stopped
-)
-
-func (s *Scraper) fastSync(ctx context.Context, client *fiber.Client, height int64, blockChan chan<- types.ScrapedBlock, controlChan <-chan string) int64 {
+func (s *Scraper) fastSync(ctx context.Context, client *fiber.Client, height int64, blockChan chan<- types.ScrapedBlock) int64 {
 	var (
 		syncedHeight = height - 1
-		status       atomic.Int32
+		stopped      atomic.Bool
 		wg           sync.WaitGroup
 	)
 
-	// Limit the number of concurrent scraping goroutines.
-	// This prevents unbounded goroutine growth when downstream (prepare/collect/DB) is slow.
-	maxConc := s.cfg.GetMaxConcurrentRequests()
-	if maxConc < 1 {
-		maxConc = 1
-	}
-	sem := make(chan struct{}, maxConc)
-
-	go func() {
-		for signal := range controlChan {
-			switch signal {
-			case "pause":
-				if status.Load() == running {
-					status.Store(paused)
-				}
-			case "start":
-				if status.Load() == paused {
-					status.Store(running)
-				}
-			}
-		}
-	}()
-
 	defer func() {
 		// wait for all goroutines to finish
 		wg.Wait()
@@ -66,35 +37,38 @@ func (s *Scraper) fastSync(ctx context.Context, client *fiber.Client, height int
 			return syncedHeight
 		default:
 		}
-		currentState := status.Load()
-
 		// exit if stopped (reached latest height)
-		if currentState == stopped {
+		if stopped.Load() {
 			wg.Wait()
 			return syncedHeight
 		}
 
 		// continue if paused
-		if currentState == paused {
+		if s.paused.Load() {
 			time.Sleep(1 * time.Second)
 			continue
 		}
 
-		// spin up new goroutine for scraping block with incrementing height
-		h := height
-		select {
-		case sem <- struct{}{}:
-			// acquired a slot; safe to spawn and account in wg
-		case <-ctx.Done():
-			s.logger.Info("fastSync() shutting down gracefully")
-			wg.Wait()
-			return syncedHeight
+		// Limit the number of concurrent scraping goroutines to the current concurrency.
+		// This prevents unbounded goroutine growth when downstream (prepare/collect/DB) is slow.
+		if s.inFlight.Load() >= int64(s.adaptive.Concurrency()) {
+			select {
+			case <-ctx.Done():
+				s.logger.Info("fastSync() shutting down gracefully")
+				wg.Wait()
+				return syncedHeight
+			case <-time.After(s.cfg.GetCoolingDuration()):
+			}
+			continue
 		}
 
+		// spin up new goroutine for scraping block with incrementing height
+		h := height
 		wg.Add(1)
+		s.inFlight.Add(1)
 		go func(errCount int) {
 			defer wg.Done()
-			defer func() { <-sem }()
+			defer s.inFlight.Add(-1)
 
 			for {
 				select {
@@ -103,7 +77,7 @@ func (s *Scraper) fastSync(ctx context.Context, client *fiber.Client, height int
 				default:
 				}
 
-				block, err := scrapeBlock(ctx, client, h, s.cfg, s.querier)
+				block, err := s.scrape(ctx, client, h)
 
 				// if no error, cache the scraped block to block map and return
 				if err == nil {
@@ -126,7 +100,7 @@ func (s *Scraper) fastSync(ctx context.Context, client *fiber.Client, height int
 
 				// stop fast syncing after reaching latest height
 				if reachedLatestHeight(fmt.Sprintf("%+v", err)) {
-					status.Store(stopped)
+					stopped.Store(true)
 					return
 				}
 
@@ -136,7 +110,10 @@ func (s *Scraper) fastSync(ctx context.Context, client *fiber.Client, height int
 		}(0)
 
 		height++
-		time.Sleep(s.cfg.GetCoolingDuration())
+		// The adaptive concurrency paces the scrapes by itself
+		if !s.adaptive.Enabled() {
+			time.Sleep(s.cfg.GetCoolingDuration())
+		}
 	}
 }
 
@@ -148,6 +125,12 @@ func (s *Scraper) slowSync(ctx context.Context, client *fiber.Client, height int
 			return
 		default:
 		}
+
+		if s.paused.Load() {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
 		var (
 			results []ScrapResult
 			g       errgroup.Group
@@ -155,8 +138,10 @@ func (s *Scraper) slowSync(ctx context.Context, client *fiber.Client, height int
 
 		for i := range commontypes.BatchScrapSize {
 			h := height + int64(i)
+			s.inFlight.Add(1)
 			g.Go(func() error {
-				block, err := scrapeBlock(ctx, client, h, s.cfg, s.querier)
+				defer s.inFlight.Add(-1)
+				block, err := s.scrape(ctx, client, h)
 				result := ScrapResult{
 					Height: h,
 					Err:    err,
@@ -211,6 +196,17 @@ func (s *Scraper) slowSync(ctx context.Context, client *fiber.Client, height int
 	}
 }
 
+// scrape scrapes the block and reports its latency to the adaptive controller, reaching the
+// latest height is not a failure
+func (s *Scraper) scrape(ctx context.Context, client *fiber.Client, height int64) (types.ScrapedBlock, error) {
+	start := time.Now()
+	block, err := scrapeBlock(ctx, client, height, s.cfg, s.querier)
+	if err == nil || !reachedLatestHeight(fmt.Sprintf("%+v", err)) {
+		s.adaptive.ObserveScrape(time.Since(start), err)
+	}
+	return block, err
+}
+
 func reachedLatestHeight(errString string) bool {
 	return strings.HasPrefix(errString, "current height") || strings.HasPrefix(errString, "could not find")
 }
@@ -0,0 +1,43 @@
+package sink
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/types"
+)
+
+// Envelope wraps the payload of an event, it is published as JSON
+type Envelope struct {
+	Id      int64           `json:"id"`
+	ChainId string          `json:"chain_id"`
+	Type    string          `json:"type"`
+	Height  int64           `json:"height"`
+	Key     string          `json:"key"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Encode turns an outbox event into the message of its topic
+func Encode(event types.CollectedEventOutbox, chainId string, cfg *config.EventSinkConfig) (Message, error) {
+	envelope := Envelope{
+		Id:      event.Id,
+		ChainId: chainId,
+		Type:    event.Type,
+		Height:  event.Height,
+		Key:     event.Key,
+		Payload: event.Payload,
+	}
+
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Id:    chainId + "/" + strconv.FormatInt(event.Id, 10),
+		Topic: Topic(cfg.TopicPrefix, event.Type),
+		Key:   event.Key,
+		Value: value,
+	}, nil
+}
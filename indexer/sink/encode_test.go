@@ -0,0 +1,31 @@
+package sink
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/types"
+)
+
+var testEvent = types.CollectedEventOutbox{
+	Id:      42,
+	Height:  7,
+	Type:    EventTypeTx,
+	Key:     "ABCD",
+	Payload: json.RawMessage(`{"txhash":"ABCD"}`),
+}
+
+func TestEncode_JSON(t *testing.T) {
+	cfg := &config.EventSinkConfig{TopicPrefix: "rollytics"}
+
+	message, err := Encode(testEvent, "test-1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "test-1/42", message.Id)
+	assert.Equal(t, "rollytics.tx", message.Topic)
+	assert.Equal(t, "ABCD", message.Key)
+	assert.JSONEq(t, `{"id":42,"chain_id":"test-1","type":"tx","height":7,"key":"ABCD","payload":{"txhash":"ABCD"}}`, string(message.Value))
+}
@@ -0,0 +1,51 @@
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/initia-labs/rollytics/config"
+)
+
+var _ Sink = (*KafkaSink)(nil)
+
+// KafkaSink publishes to Kafka, keyed so that the events of a key stay on one partition
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(cfg *config.EventSinkConfig) *KafkaSink {
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(cfg.URLs...),
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: cfg.AutoCreateTopics,
+			BatchSize:              cfg.BatchSize,
+			BatchTimeout:           10 * time.Millisecond,
+		},
+	}
+}
+
+func (s *KafkaSink) Name() string {
+	return config.EventSinkBrokerKafka
+}
+
+func (s *KafkaSink) Publish(ctx context.Context, messages []Message) error {
+	msgs := make([]kafka.Message, 0, len(messages))
+	for _, m := range messages {
+		msgs = append(msgs, kafka.Message{
+			Topic:   m.Topic,
+			Key:     []byte(m.Key),
+			Value:   m.Value,
+			Headers: []kafka.Header{{Key: "event-id", Value: []byte(m.Id)}},
+		})
+	}
+	return s.writer.WriteMessages(ctx, msgs...)
+}
+
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/initia-labs/rollytics/config"
+)
+
+var _ Sink = (*NatsSink)(nil)
+
+// NatsSink publishes to NATS JetStream. The topics are subjects, which a stream must capture,
+// e.g. "rollytics.>"; the event id is the message id, so the stream drops redeliveries within
+// its duplicate window.
+type NatsSink struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+func NewNatsSink(cfg *config.EventSinkConfig, logger *slog.Logger) (*NatsSink, error) {
+	conn, err := nats.Connect(strings.Join(cfg.URLs, ","),
+		nats.Name("rollytics"),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn("disconnected from nats", slog.Any("error", err))
+			}
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &NatsSink{conn: conn, js: js}, nil
+}
+
+func (s *NatsSink) Name() string {
+	return config.EventSinkBrokerNats
+}
+
+func (s *NatsSink) Publish(ctx context.Context, messages []Message) error {
+	futures := make([]jetstream.PubAckFuture, 0, len(messages))
+	for _, m := range messages {
+		future, err := s.js.PublishMsgAsync(&nats.Msg{
+			Subject: m.Topic,
+			Data:    m.Value,
+		}, jetstream.WithMsgID(m.Id))
+		if err != nil {
+			return err
+		}
+		futures = append(futures, future)
+	}
+
+	for _, future := range futures {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-future.Ok():
+		case err := <-future.Err():
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NatsSink) Close() error {
+	return s.conn.Drain()
+}
@@ -0,0 +1,50 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/initia-labs/rollytics/config"
+)
+
+// Event types of the outbox, each published to its own topic
+const (
+	EventTypeBlock = "block"
+	EventTypeTx    = "tx"
+	EventTypeEvmTx = "evm_tx"
+	EventTypeNft   = "nft"
+)
+
+// Message is an encoded event ready to be published
+type Message struct {
+	Id    string // unique per event, lets the broker or the consumers drop redeliveries
+	Topic string
+	Key   string // events of the same key keep their order
+	Value []byte
+}
+
+// Sink publishes events to a broker. Publish returns once the broker acknowledged every message,
+// so the outbox only deletes the events which are durably published.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, messages []Message) error
+	Close() error
+}
+
+// New connects the sink of the configured broker
+func New(cfg *config.EventSinkConfig, logger *slog.Logger) (Sink, error) {
+	switch cfg.Broker {
+	case config.EventSinkBrokerKafka:
+		return NewKafkaSink(cfg), nil
+	case config.EventSinkBrokerNats:
+		return NewNatsSink(cfg, logger)
+	default:
+		return nil, fmt.Errorf("unsupported event sink broker: %s", cfg.Broker)
+	}
+}
+
+// Topic returns the topic of an event type
+func Topic(prefix, eventType string) string {
+	return prefix + "." + eventType
+}
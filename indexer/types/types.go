@@ -14,6 +14,11 @@ type Submodule interface {
 	Collect(block ScrapedBlock, tx *gorm.DB) error
 }
 
+// BatchSubmodule is a submodule which collects consecutive blocks, in height order, together
+type BatchSubmodule interface {
+	CollectBatch(blocks []ScrapedBlock, tx *gorm.DB) error
+}
+
 type ScrapedBlock struct {
 	ChainId    string
 	Height     int64
@@ -18,6 +18,8 @@ type DatabaseMetrics struct {
 	QueriesTotal      *prometheus.CounterVec
 	QueryDuration     *prometheus.HistogramVec
 	RowsAffected      *prometheus.HistogramVec
+	ReplicaLag        *prometheus.GaugeVec
+	ReadTransactions  *prometheus.CounterVec
 }
 
 // NewDatabaseMetrics creates and returns database metrics
@@ -56,6 +58,22 @@ func NewDatabaseMetrics() *DatabaseMetrics {
 			},
 			[]string{"operation"},
 		),
+		ReplicaLag: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "rollytics_db_replica_lag_blocks",
+				Help:        "Number of blocks a read replica trails the primary database by",
+				ConstLabels: constLabels(),
+			},
+			[]string{"replica"},
+		),
+		ReadTransactions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rollytics_db_read_transactions_total",
+				Help:        "Total number of read-only transactions by database they were routed to",
+				ConstLabels: constLabels(),
+			},
+			[]string{"target"},
+		),
 	}
 }
 
@@ -66,6 +84,8 @@ func (d *DatabaseMetrics) Register(reg *prometheus.Registry) {
 		d.QueriesTotal,
 		d.QueryDuration,
 		d.RowsAffected,
+		d.ReplicaLag,
+		d.ReadTransactions,
 	)
 }
 
@@ -23,6 +23,13 @@ type HTTPMetrics struct {
 	// Detailed metrics for troubleshooting (lower cardinality sampling)
 	SlowRequests *prometheus.CounterVec
 	TopEndpoints *prometheus.GaugeVec
+
+	// API key usage (key is the key name, or "anonymous")
+	APIKeyRequests *prometheus.CounterVec
+	APIKeyCost     *prometheus.CounterVec
+
+	// Response cache lookups by route pattern
+	CacheRequests *prometheus.CounterVec
 }
 
 // NewHTTPMetrics creates and returns HTTP metrics
@@ -76,6 +83,30 @@ func NewHTTPMetrics() *HTTPMetrics {
 			},
 			[]string{"path"}, // Only for top N slowest endpoints
 		),
+		APIKeyRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rollytics_http_api_key_requests_total",
+				Help:        "Total number of rate limited requests by API key",
+				ConstLabels: constLabels(),
+			},
+			[]string{"key", "result"}, // result: allowed, limited, unauthorized
+		),
+		APIKeyCost: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rollytics_http_api_key_cost_total",
+				Help:        "Total rate limit tokens consumed by API key",
+				ConstLabels: constLabels(),
+			},
+			[]string{"key"},
+		),
+		CacheRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rollytics_http_cache_requests_total",
+				Help:        "Total number of response cache lookups by route",
+				ConstLabels: constLabels(),
+			},
+			[]string{"route", "result"}, // result: hit, miss, unreachable
+		),
 	}
 }
 
@@ -88,6 +119,9 @@ func (h *HTTPMetrics) Register(reg *prometheus.Registry) {
 		h.ErrorsTotal,
 		h.SlowRequests,
 		h.TopEndpoints,
+		h.APIKeyRequests,
+		h.APIKeyCost,
+		h.CacheRequests,
 	)
 }
 
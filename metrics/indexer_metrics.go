@@ -16,9 +16,24 @@ type IndexerMetrics struct {
 	// Queue and throughput metrics
 	InflightBlocksCount prometheus.Gauge
 	ProcessingSpeed     prometheus.Gauge
+	ScrapeConcurrency   prometheus.Gauge
+	InflightWindow      prometheus.Gauge
 
 	// Error tracking
 	ProcessingErrors *prometheus.CounterVec
+
+	// Leader election
+	LeaderRole *prometheus.GaugeVec
+
+	// Extension supervision
+	ExtensionRestarts *prometheus.CounterVec
+	ExtensionDegraded *prometheus.GaugeVec
+
+	// Event sink
+	EventsPublished *prometheus.CounterVec
+
+	// Webhooks
+	WebhookDeliveries *prometheus.CounterVec
 }
 
 // NewIndexerMetrics creates and returns indexer metrics
@@ -61,6 +76,20 @@ func NewIndexerMetrics() *IndexerMetrics {
 				ConstLabels: constLabels(),
 			},
 		),
+		ScrapeConcurrency: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "rollytics_scrape_concurrency",
+				Help:        "Current limit of concurrent block scrapes during fast sync",
+				ConstLabels: constLabels(),
+			},
+		),
+		InflightWindow: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "rollytics_inflight_window",
+				Help:        "Current number of inflight blocks above which scraping pauses",
+				ConstLabels: constLabels(),
+			},
+		),
 		ProcessingErrors: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "rollytics_processing_errors_total",
@@ -69,6 +98,46 @@ func NewIndexerMetrics() *IndexerMetrics {
 			},
 			[]string{"stage", "error_type"}, // stage: scrape, prepare, collect
 		),
+		LeaderRole: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "rollytics_leader",
+				Help:        "Whether this replica leads the workload (1) or stands by (0)",
+				ConstLabels: constLabels(),
+			},
+			[]string{"workload"}, // workload: indexer, extensions
+		),
+		ExtensionRestarts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rollytics_extension_restarts_total",
+				Help:        "Total number of extension restarts after a failure",
+				ConstLabels: constLabels(),
+			},
+			[]string{"extension"},
+		),
+		ExtensionDegraded: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "rollytics_extension_degraded",
+				Help:        "Whether the extension stopped after exceeding its restarts (1) or not (0)",
+				ConstLabels: constLabels(),
+			},
+			[]string{"extension"},
+		),
+		EventsPublished: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rollytics_event_sink_published_total",
+				Help:        "Total number of outbox events published by the event sink",
+				ConstLabels: constLabels(),
+			},
+			[]string{"type"}, // type: block, tx, evm_tx, nft
+		),
+		WebhookDeliveries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rollytics_webhook_deliveries_total",
+				Help:        "Total number of webhook notifications by delivery result",
+				ConstLabels: constLabels(),
+			},
+			[]string{"event", "result"}, // result: delivered, dead_letter
+		),
 	}
 }
 
@@ -80,6 +149,13 @@ func (i *IndexerMetrics) Register(reg *prometheus.Registry) {
 		i.BlockProcessingTime,
 		i.InflightBlocksCount,
 		i.ProcessingSpeed,
+		i.ScrapeConcurrency,
+		i.InflightWindow,
 		i.ProcessingErrors,
+		i.LeaderRole,
+		i.ExtensionRestarts,
+		i.ExtensionDegraded,
+		i.EventsPublished,
+		i.WebhookDeliveries,
 	)
 }
@@ -0,0 +1,183 @@
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const manifestFile = "manifest.json"
+
+// Manifest lists the archived height ranges, in increasing order
+type Manifest struct {
+	Ranges []Range `json:"ranges"`
+}
+
+// Range is a range of heights exported to one Parquet file per table
+type Range struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+	// Files maps the table names to the file paths, relative to the archive directory
+	Files map[string]string `json:"files"`
+	Rows  map[string]int64  `json:"rows"`
+	// Indexes maps the tables looked up by hash to the sidecar index files of the hashes, relative to
+	// the archive directory. The ranges archived before the indexes have none.
+	Indexes map[string]string `json:"indexes,omitempty"`
+	// Dropped is set once the rows of the range are deleted from the database
+	Dropped bool `json:"dropped"`
+}
+
+// Store is an archive directory on a local or mounted volume. The manifest is written by the
+// indexer and reloaded by the API whenever it changes.
+type Store struct {
+	dir string
+
+	mu       sync.RWMutex
+	manifest Manifest
+	modTime  time.Time
+}
+
+// NewStore returns the archive stored under dir. Nothing is read until the archive is used.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Ranges returns the archived height ranges
+func (s *Store) Ranges() ([]Range, error) {
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.manifest.Ranges), nil
+}
+
+// Next returns the first height after the archived ranges, 0 if nothing is archived yet
+func (s *Store) Next() (int64, error) {
+	ranges, err := s.Ranges()
+	if err != nil || len(ranges) == 0 {
+		return 0, err
+	}
+	return ranges[len(ranges)-1].To + 1, nil
+}
+
+// DroppedHeight returns the highest height whose rows were deleted from the database after being
+// archived, below which lookups missing in the database fall back to the archive
+func (s *Store) DroppedHeight() (int64, error) {
+	ranges, err := s.Ranges()
+	if err != nil {
+		return 0, err
+	}
+	for i := len(ranges) - 1; i >= 0; i-- {
+		if ranges[i].Dropped {
+			return ranges[i].To, nil
+		}
+	}
+	return 0, nil
+}
+
+// MarkDropped records that the rows of the range starting at from are deleted from the database
+func (s *Store) MarkDropped(from int64) error {
+	return s.update(func(m *Manifest) error {
+		for i := range m.Ranges {
+			if m.Ranges[i].From == from {
+				m.Ranges[i].Dropped = true
+				return nil
+			}
+		}
+		return fmt.Errorf("no archived range starts at height %d", from)
+	})
+}
+
+func (s *Store) addRange(r Range) error {
+	return s.update(func(m *Manifest) error {
+		if n := len(m.Ranges); n > 0 && m.Ranges[n-1].To >= r.From {
+			return fmt.Errorf("range [%d-%d] overlaps the archived range [%d-%d]", r.From, r.To, m.Ranges[n-1].From, m.Ranges[n-1].To)
+		}
+		m.Ranges = append(m.Ranges, r)
+		return nil
+	})
+}
+
+// reload reads the manifest again if it was modified since the last read
+func (s *Store) reload() error {
+	path := filepath.Join(s.dir, manifestFile)
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat archive manifest: %w", err)
+	}
+
+	s.mu.RLock()
+	fresh := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read archive manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse archive manifest: %w", err)
+	}
+
+	s.mu.Lock()
+	s.manifest = manifest
+	s.modTime = info.ModTime()
+	s.mu.Unlock()
+	return nil
+}
+
+// update applies the change to the manifest and replaces the manifest file atomically
+func (s *Store) update(change func(m *Manifest) error) error {
+	if err := s.reload(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	manifest := Manifest{Ranges: slices.Clone(s.manifest.Ranges)}
+	if err := change(&manifest); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, manifestFile)
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	s.manifest = manifest
+	s.modTime = info.ModTime()
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,179 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// sqlite only parses the times of datetime columns
+	require.NoError(t, db.Exec(`CREATE TABLE block (
+		chain_id text, height integer, hash blob, timestamp datetime, block_time integer, proposer text,
+		gas_used integer, gas_wanted integer, tx_count integer, total_fee blob, PRIMARY KEY (chain_id, height))`).Error)
+	err = db.AutoMigrate(
+		&types.CollectedTx{},
+		&types.CollectedEvmTx{},
+		&types.CollectedEvmInternalTx{},
+	)
+	require.NoError(t, err)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for h := int64(1); h <= 20; h++ {
+		require.NoError(t, db.Create(&types.CollectedBlock{
+			ChainId:   "test-chain",
+			Height:    h,
+			Hash:      []byte{byte(h)},
+			Timestamp: base.Add(time.Duration(h) * time.Second),
+			TxCount:   1,
+			TotalFee:  json.RawMessage(`[]`),
+		}).Error)
+		require.NoError(t, db.Create(&types.CollectedTx{
+			Hash:     []byte{0xaa, byte(h)},
+			Height:   h,
+			Sequence: h,
+			Data:     json.RawMessage(`{"height":1}`),
+		}).Error)
+		require.NoError(t, db.Create(&types.CollectedEvmTx{
+			Hash:     []byte{0xbb, byte(h)},
+			Height:   h,
+			Sequence: h,
+			Data:     json.RawMessage(`{}`),
+		}).Error)
+	}
+	return db
+}
+
+func TestArchive(t *testing.T) {
+	db := setupTestDB(t)
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	next, err := store.Next()
+	require.NoError(t, err)
+	assert.Zero(t, next)
+
+	r, err := store.Archive(context.Background(), db, 1, 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), r.Rows["block"])
+	assert.Equal(t, int64(10), r.Rows["tx"])
+	assert.Equal(t, int64(10), r.Rows["evm_tx"])
+	assert.Equal(t, int64(0), r.Rows["evm_internal_tx"])
+	assert.FileExists(t, filepath.Join(dir, "tx", "000000000001-000000000010.parquet"))
+
+	next, err = store.Next()
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), next)
+
+	// overlapping ranges are rejected
+	_, err = store.Archive(context.Background(), db, 5, 15, true)
+	require.Error(t, err)
+
+	block, err := store.FindBlock(7)
+	require.NoError(t, err)
+	require.NotNil(t, block)
+	assert.Equal(t, []byte{7}, block.Hash)
+	assert.True(t, block.Timestamp.Equal(time.Date(2026, 1, 1, 0, 0, 7, 0, time.UTC)))
+	assert.JSONEq(t, `[]`, string(block.TotalFee))
+
+	block, err = store.FindBlock(11)
+	require.NoError(t, err)
+	assert.Nil(t, block)
+
+	tx, err := store.FindTx([]byte{0xaa, 3})
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	assert.Equal(t, int64(3), tx.Sequence)
+	assert.JSONEq(t, `{"height":1}`, string(tx.Data))
+
+	evmTx, err := store.FindEvmTx([]byte{0xbb, 4})
+	require.NoError(t, err)
+	require.NotNil(t, evmTx)
+	assert.Equal(t, int64(4), evmTx.Height)
+
+	tx, err = store.FindTx([]byte{0xaa, 15})
+	require.NoError(t, err)
+	assert.Nil(t, tx)
+}
+
+func TestStore_ReloadsManifest(t *testing.T) {
+	db := setupTestDB(t)
+	dir := t.TempDir()
+
+	writer := NewStore(dir)
+	reader := NewStore(dir)
+
+	dropped, err := reader.DroppedHeight()
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+
+	_, err = writer.Archive(context.Background(), db, 1, 10, false)
+	require.NoError(t, err)
+	// make the modification visible on file systems with a coarse mtime
+	require.NoError(t, os.Chtimes(filepath.Join(dir, manifestFile), time.Now(), time.Now().Add(time.Second)))
+	require.NoError(t, writer.MarkDropped(1))
+
+	dropped, err = reader.DroppedHeight()
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), dropped)
+
+	ranges, err := reader.Ranges()
+	require.NoError(t, err)
+	require.Len(t, ranges, 1)
+	_, ok := ranges[0].Files["evm_tx"]
+	assert.False(t, ok)
+
+	require.Error(t, writer.MarkDropped(11))
+}
+
+func TestFindTx_Index(t *testing.T) {
+	db := setupTestDB(t)
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	r, err := store.Archive(context.Background(), db, 1, 10, true)
+	require.NoError(t, err)
+	assert.Equal(t, "tx/000000000001-000000000010.parquet.idx", r.Indexes["tx"])
+	assert.Equal(t, "evm_tx/000000000001-000000000010.parquet.idx", r.Indexes["evm_tx"])
+	_, ok := r.Indexes["block"]
+	assert.False(t, ok)
+
+	for h := byte(1); h <= 10; h++ {
+		tx, err := store.FindTx([]byte{0xaa, h})
+		require.NoError(t, err)
+		require.NotNil(t, tx)
+		assert.Equal(t, int64(h), tx.Height)
+	}
+
+	// an unknown hash is answered by the index, without reading the Parquet file
+	require.NoError(t, os.Rename(filepath.Join(dir, r.Files["tx"]), filepath.Join(dir, r.Files["tx"]+".moved")))
+	tx, err := store.FindTx([]byte{0xaa, 15})
+	require.NoError(t, err)
+	assert.Nil(t, tx)
+	_, err = store.FindTx([]byte{0xaa, 3})
+	require.Error(t, err)
+	require.NoError(t, os.Rename(filepath.Join(dir, r.Files["tx"]+".moved"), filepath.Join(dir, r.Files["tx"])))
+
+	// the ranges archived before the indexes are scanned
+	require.NoError(t, store.update(func(m *Manifest) error {
+		m.Ranges[0].Indexes = nil
+		return nil
+	}))
+	evmTx, err := store.FindEvmTx([]byte{0xbb, 9})
+	require.NoError(t, err)
+	require.NotNil(t, evmTx)
+	assert.Equal(t, int64(9), evmTx.Sequence)
+}
@@ -0,0 +1,90 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+const (
+	// indexKeySize is the size of the hash keys of an index, shorter hashes are padded with zeros
+	indexKeySize = 32
+	// indexEntrySize is the size of an index entry, the key followed by the row number
+	indexEntrySize = indexKeySize + 8
+)
+
+// indexEntry locates the row of a hash in its Parquet file
+type indexEntry struct {
+	key [indexKeySize]byte
+	row int64
+}
+
+// indexKey returns the key of the hash, false when the hash is too long to be indexed
+func indexKey(hash []byte) ([indexKeySize]byte, bool) {
+	var key [indexKeySize]byte
+	if len(hash) > indexKeySize {
+		return key, false
+	}
+	copy(key[:], hash)
+	return key, true
+}
+
+// writeIndex writes the entries sorted by key to the sidecar index file at path, which only appears
+// once complete
+func writeIndex(path string, entries []indexEntry) error {
+	slices.SortFunc(entries, func(a, b indexEntry) int {
+		return bytes.Compare(a.key[:], b.key[:])
+	})
+
+	data := make([]byte, 0, len(entries)*indexEntrySize)
+	for _, entry := range entries {
+		data = append(data, entry.key[:]...)
+		data = binary.BigEndian.AppendUint64(data, uint64(entry.row)) //nolint:gosec
+	}
+	return writeFileAtomic(path, data)
+}
+
+// searchIndex binary searches the sidecar index file at path for the row of the hash, without
+// reading more than the entries it compares
+func searchIndex(path string, hash []byte) (row int64, found bool, err error) {
+	key, ok := indexKey(hash)
+	if !ok {
+		return 0, false, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to open archive index: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to stat archive index: %w", err)
+	}
+	if info.Size()%indexEntrySize != 0 {
+		return 0, false, fmt.Errorf("archive index %s is corrupted", path)
+	}
+
+	entry := make([]byte, indexEntrySize)
+	lo, hi := int64(0), info.Size()/indexEntrySize
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if _, err := f.ReadAt(entry, mid*indexEntrySize); err != nil && !errors.Is(err, io.EOF) {
+			return 0, false, fmt.Errorf("failed to read archive index %s: %w", path, err)
+		}
+		switch cmp := bytes.Compare(entry[:indexKeySize], key[:]); {
+		case cmp == 0:
+			return int64(binary.BigEndian.Uint64(entry[indexKeySize:])), true, nil //nolint:gosec
+		case cmp < 0:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return 0, false, nil
+}
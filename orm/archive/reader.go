@@ -0,0 +1,147 @@
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// readBatchSize is the number of rows read from an archive file at once
+const readBatchSize = 256
+
+// FindBlock returns the archived block of the height, nil if it is not archived
+func (s *Store) FindBlock(height int64) (*types.CollectedBlock, error) {
+	ranges, err := s.Ranges()
+	if err != nil {
+		return nil, err
+	}
+
+	table := types.CollectedBlock{}.TableName()
+	for _, r := range ranges {
+		if height < r.From || height > r.To {
+			continue
+		}
+		file, ok := r.Files[table]
+		if !ok {
+			return nil, nil
+		}
+		return findRow(filepath.Join(s.dir, file), func(block *types.CollectedBlock) bool {
+			return block.Height == height
+		})
+	}
+	return nil, nil
+}
+
+// FindTx returns the archived tx of the hash, nil if it is not archived.
+// The ranges are searched from the most recent one.
+func (s *Store) FindTx(hash []byte) (*types.CollectedTx, error) {
+	return findByHash(s, types.CollectedTx{}.TableName(), hash, func(tx *types.CollectedTx) bool {
+		return bytes.Equal(tx.Hash, hash)
+	})
+}
+
+// FindEvmTx returns the archived evm tx of the hash, nil if it is not archived
+func (s *Store) FindEvmTx(hash []byte) (*types.CollectedEvmTx, error) {
+	return findByHash(s, types.CollectedEvmTx{}.TableName(), hash, func(tx *types.CollectedEvmTx) bool {
+		return bytes.Equal(tx.Hash, hash)
+	})
+}
+
+// findByHash looks the hash up in the index of each range and reads the row from the one file
+// holding it, so an unknown hash reads no Parquet file. The ranges archived before the indexes are
+// scanned.
+func findByHash[T any](s *Store, table string, hash []byte, match func(*T) bool) (*T, error) {
+	ranges, err := s.Ranges()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(ranges) - 1; i >= 0; i-- {
+		file, ok := ranges[i].Files[table]
+		if !ok || ranges[i].Rows[table] == 0 {
+			continue
+		}
+
+		index, ok := ranges[i].Indexes[table]
+		if !ok {
+			row, err := findRow(filepath.Join(s.dir, file), match)
+			if err != nil || row != nil {
+				return row, err
+			}
+			continue
+		}
+
+		n, found, err := searchIndex(filepath.Join(s.dir, index), hash)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		row, err := readRow[T](filepath.Join(s.dir, file), n)
+		if err != nil {
+			return nil, err
+		}
+		if match(row) {
+			return row, nil
+		}
+	}
+	return nil, nil
+}
+
+// readRow reads the row of the given number from the Parquet file
+func readRow[T any](path string, n int64) (*T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer f.Close()
+
+	reader := parquet.NewGenericReader[T](f)
+	defer reader.Close()
+
+	if err := reader.SeekToRow(n); err != nil {
+		return nil, fmt.Errorf("failed to seek archive file %s: %w", path, err)
+	}
+	rows := make([]T, 1)
+	if _, err := reader.Read(rows); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read archive file %s: %w", path, err)
+	}
+	return &rows[0], nil
+}
+
+// findRow scans the Parquet file for the first row matching
+func findRow[T any](path string, match func(*T) bool) (*T, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer f.Close()
+
+	reader := parquet.NewGenericReader[T](f)
+	defer reader.Close()
+
+	rows := make([]T, readBatchSize)
+	for {
+		n, err := reader.Read(rows)
+		for i := range rows[:n] {
+			if match(&rows[i]) {
+				row := rows[i]
+				return &row, nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive file %s: %w", path, err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/parquet-go/parquet-go"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// writeBatchSize is the number of rows scanned from the database before they are written out
+const writeBatchSize = 1000
+
+// indexSuffix is appended to the path of a Parquet file for the path of its sidecar index
+const indexSuffix = ".idx"
+
+type archiveTable struct {
+	name  string
+	order string
+	// write exports the rows to the file, and returns the index entries of the tables looked up by hash
+	write func(ctx context.Context, query *gorm.DB, path string) (int64, []indexEntry, error)
+}
+
+// txHash keys the tx and evm tx rows in their index
+func txHash(tx *types.CollectedTx) []byte { return tx.Hash }
+
+func evmTxHash(tx *types.CollectedEvmTx) []byte { return tx.Hash }
+
+func writer[T any](key func(*T) []byte) func(ctx context.Context, query *gorm.DB, path string) (int64, []indexEntry, error) {
+	return func(ctx context.Context, query *gorm.DB, path string) (int64, []indexEntry, error) {
+		return writeTable(ctx, query, path, key)
+	}
+}
+
+// Archive exports the rows of the heights from..to of the block and tx tables, and of the evm tables
+// for EVM chains, to Parquet files, then records the range in the manifest.
+// The rows stay in the database, see Range.Dropped.
+func (s *Store) Archive(ctx context.Context, db *gorm.DB, from, to int64, evm bool) (Range, error) {
+	r := Range{
+		From:    from,
+		To:      to,
+		Files:   make(map[string]string),
+		Rows:    make(map[string]int64),
+		Indexes: make(map[string]string),
+	}
+
+	tables := []archiveTable{
+		{types.CollectedBlock{}.TableName(), "height", writer[types.CollectedBlock](nil)},
+		{types.CollectedTx{}.TableName(), "sequence", writer(txHash)},
+	}
+	if evm {
+		tables = append(tables,
+			archiveTable{types.CollectedEvmTx{}.TableName(), "sequence", writer(evmTxHash)},
+			archiveTable{types.CollectedEvmInternalTx{}.TableName(), "sequence", writer[types.CollectedEvmInternalTx](nil)},
+		)
+	}
+
+	for _, table := range tables {
+		file := filepath.Join(table.name, fmt.Sprintf("%012d-%012d.parquet", from, to))
+		query := db.WithContext(ctx).
+			Table(table.name).
+			Where("height >= ? AND height <= ?", from, to).
+			Order(table.order)
+
+		rows, entries, err := table.write(ctx, query, filepath.Join(s.dir, file))
+		if err != nil {
+			return Range{}, fmt.Errorf("failed to archive %s [%d-%d]: %w", table.name, from, to, err)
+		}
+		r.Files[table.name] = file
+		r.Rows[table.name] = rows
+
+		if entries != nil {
+			index := file + indexSuffix
+			if err := writeIndex(filepath.Join(s.dir, index), entries); err != nil {
+				return Range{}, fmt.Errorf("failed to index %s [%d-%d]: %w", table.name, from, to, err)
+			}
+			r.Indexes[table.name] = index
+		}
+	}
+
+	if err := s.addRange(r); err != nil {
+		return Range{}, err
+	}
+	return r, nil
+}
+
+// writeTable streams the rows of the query into a Parquet file, which only appears at path once complete.
+// With a key, it returns the index entries of the rows, empty but not nil without rows.
+func writeTable[T any](ctx context.Context, query *gorm.DB, path string, key func(*T) []byte) (count int64, entries []indexEntry, err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, nil, err
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	if key != nil {
+		entries = make([]indexEntry, 0)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	writer := parquet.NewGenericWriter[T](f, parquet.Compression(&parquet.Zstd))
+	batch := make([]T, 0, writeBatchSize)
+	for rows.Next() {
+		var row T
+		if err := query.ScanRows(rows, &row); err != nil {
+			return 0, nil, err
+		}
+		if key != nil {
+			k, ok := indexKey(key(&row))
+			if !ok {
+				return 0, nil, fmt.Errorf("hash of row %d is too long to be indexed", count+int64(len(batch)))
+			}
+			entries = append(entries, indexEntry{key: k, row: count + int64(len(batch))})
+		}
+		batch = append(batch, row)
+		if len(batch) == writeBatchSize {
+			if _, err := writer.Write(batch); err != nil {
+				return 0, nil, err
+			}
+			count += int64(len(batch))
+			batch = batch[:0]
+
+			if err := ctx.Err(); err != nil {
+				return 0, nil, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+	if _, err := writer.Write(batch); err != nil {
+		return 0, nil, err
+	}
+	count += int64(len(batch))
+
+	if err := writer.Close(); err != nil {
+		return 0, nil, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, nil, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, nil, err
+	}
+	return count, entries, os.Rename(tmp, path)
+}
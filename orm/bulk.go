@@ -0,0 +1,231 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// copyConnKey carries the connection of a bulk transaction in the context of its statements
+type copyConnKey struct{}
+
+// BulkTransaction runs fc in a transaction on a dedicated connection, so that CopyFrom can load
+// rows with COPY within the same transaction
+func (d *Database) BulkTransaction(ctx context.Context, fc func(tx *gorm.DB) error, opts *sql.TxOptions) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	db := d.DB.Session(&gorm.Session{Context: context.WithValue(ctx, copyConnKey{}, conn)})
+	db.Statement.ConnPool = conn
+	return db.Transaction(fc, opts)
+}
+
+// CopyEnabled reports whether the transaction was started by BulkTransaction
+func CopyEnabled(tx *gorm.DB) bool {
+	_, ok := tx.Statement.Context.Value(copyConnKey{}).(*sql.Conn)
+	return ok
+}
+
+// ErrCopyConflict is returned by CopyFrom when a row conflicts with an existing one, none of the rows
+// are loaded and the transaction remains usable
+var ErrCopyConflict = errors.New("copied rows conflict with existing rows")
+
+// copySavepoint is the savepoint CopyFrom rolls back to on a conflict
+const copySavepoint = "copy_from"
+
+// CopyFrom loads the rows into the table with COPY in the bulk transaction. COPY has no conflict
+// handling: on a unique violation, the copy is rolled back to a savepoint and ErrCopyConflict is
+// returned, so that the caller can insert the rows skipping the conflicts instead.
+func CopyFrom(tx *gorm.DB, table string, columns []string, rows [][]any) error {
+	conn, ok := tx.Statement.Context.Value(copyConnKey{}).(*sql.Conn)
+	if !ok {
+		return errors.New("copy is only available in a bulk transaction")
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	ctx := tx.Statement.Context
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("copy is not supported by the driver connection %T", driverConn)
+		}
+		pgConn := c.Conn()
+		if _, err := pgConn.Exec(ctx, "SAVEPOINT "+copySavepoint); err != nil {
+			return err
+		}
+		if _, err := pgConn.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows)); err != nil {
+			// 23505 is unique_violation
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+				return err
+			}
+			if _, err := pgConn.Exec(ctx, "ROLLBACK TO SAVEPOINT "+copySavepoint); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %s", ErrCopyConflict, pgErr.Message)
+		}
+		_, err := pgConn.Exec(ctx, "RELEASE SAVEPOINT "+copySavepoint)
+		return err
+	})
+}
+
+// DeferIndexes drops the secondary indexes of the tables to speed up the bulk load, recording their
+// definitions so that RestoreIndexes recreates them, also after a restart. Primary keys and unique
+// indexes are kept since they back the constraints.
+func (d *Database) DeferIndexes(ctx context.Context, tables []string, logger *slog.Logger) error {
+	var indexes []types.CollectedDeferredIndex
+	if err := d.WithContext(ctx).Raw(`
+		SELECT ic.relname AS name, tc.relname AS relation, pg_get_indexdef(i.indexrelid) AS definition
+		FROM pg_index i
+		JOIN pg_class ic ON ic.oid = i.indexrelid
+		JOIN pg_class tc ON tc.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = tc.relnamespace
+		WHERE n.nspname = current_schema() AND tc.relname IN ? AND NOT i.indisprimary AND NOT i.indisunique
+			AND NOT EXISTS (SELECT 1 FROM pg_inherits inh WHERE inh.inhrelid = i.indexrelid)
+		ORDER BY ic.relname`, tables).Scan(&indexes).Error; err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		if err := d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(DoNothingWhenConflict).Create(&index).Error; err != nil {
+				return err
+			}
+			return tx.Exec("DROP INDEX IF EXISTS " + pgx.Identifier{index.Name}.Sanitize()).Error
+		}); err != nil {
+			return fmt.Errorf("failed to defer index %s: %w", index.Name, err)
+		}
+		logger.Info("deferred index", slog.String("index", index.Name), slog.String("table", index.Relation))
+	}
+	return nil
+}
+
+// maxIdentifierLength is the length postgres truncates identifiers to
+const maxIdentifierLength = 63
+
+// RestoreIndexes recreates the indexes dropped by DeferIndexes with CREATE INDEX CONCURRENTLY, so that
+// the tables stay writable meanwhile. The indexes of partitioned tables are built on every partition,
+// then attached to the index of the table. An interrupted restore resumes where it stopped: the
+// indexes left invalid by an interrupted build are built again, and a deferred index is only
+// forgotten once restored.
+func (d *Database) RestoreIndexes(ctx context.Context, logger *slog.Logger) error {
+	var indexes []types.CollectedDeferredIndex
+	if err := d.WithContext(ctx).Order("name").Find(&indexes).Error; err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		logger.Info("restoring deferred index", slog.String("index", index.Name), slog.String("table", index.Relation))
+		if err := d.restoreIndex(ctx, index); err != nil {
+			return fmt.Errorf("failed to restore index %s: %w", index.Name, err)
+		}
+		if err := d.WithContext(ctx).Delete(&index).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Database) restoreIndex(ctx context.Context, index types.CollectedDeferredIndex) error {
+	// the definition from pg_get_indexdef is CREATE INDEX <name> ON [ONLY] <table> USING ..., only the
+	// part from USING is kept to build it on other relations
+	using := strings.Index(index.Definition, " USING ")
+	if using < 0 {
+		return fmt.Errorf("unexpected index definition %q", index.Definition)
+	}
+	method := index.Definition[using:]
+	name := pgx.Identifier{index.Name}.Sanitize()
+	relation := pgx.Identifier{index.Relation}.Sanitize()
+
+	var kinds []string
+	if err := d.WithContext(ctx).Raw("SELECT relkind::text FROM pg_class WHERE oid = to_regclass(?)", index.Relation).
+		Scan(&kinds).Error; err != nil {
+		return err
+	}
+	if len(kinds) == 0 {
+		return fmt.Errorf("table %s does not exist", index.Relation)
+	}
+	if kinds[0] != "p" {
+		return d.buildIndex(ctx, index.Name, "CREATE INDEX CONCURRENTLY "+name+" ON "+relation+method)
+	}
+
+	// CONCURRENTLY is not supported on partitioned tables, the index of the table is created invalid
+	// without building it, and becomes valid once an index is attached for every partition
+	if err := d.WithContext(ctx).Exec("CREATE INDEX IF NOT EXISTS " + name + " ON ONLY " + relation + method).Error; err != nil {
+		return err
+	}
+	var partitions []string
+	if err := d.WithContext(ctx).Raw(`
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = to_regclass(?)
+		ORDER BY c.relname`, index.Relation).Scan(&partitions).Error; err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		// a partition created meanwhile has its index created and attached along with it
+		var attached bool
+		if err := d.WithContext(ctx).Raw(`
+			SELECT EXISTS (
+				SELECT 1 FROM pg_inherits i
+				JOIN pg_index x ON x.indexrelid = i.inhrelid
+				WHERE i.inhparent = to_regclass(?) AND x.indrelid = to_regclass(?))`, index.Name, partition).
+			Scan(&attached).Error; err != nil {
+			return err
+		}
+		if attached {
+			continue
+		}
+
+		partitionIndex := partition + "_" + index.Name
+		if len(partitionIndex) > maxIdentifierLength {
+			partitionIndex = partitionIndex[:maxIdentifierLength]
+		}
+		if err := d.buildIndex(ctx, partitionIndex, "CREATE INDEX CONCURRENTLY "+pgx.Identifier{partitionIndex}.Sanitize()+
+			" ON "+pgx.Identifier{partition}.Sanitize()+method); err != nil {
+			return err
+		}
+		if err := d.WithContext(ctx).Exec("ALTER INDEX " + name + " ATTACH PARTITION " + pgx.Identifier{partitionIndex}.Sanitize()).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildIndex builds the index with the CREATE INDEX CONCURRENTLY statement unless it exists, an index
+// left invalid by an interrupted build is dropped first
+func (d *Database) buildIndex(ctx context.Context, name, stmt string) error {
+	var valid []bool
+	if err := d.WithContext(ctx).Raw("SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass(?)", name).
+		Scan(&valid).Error; err != nil {
+		return err
+	}
+	if len(valid) > 0 {
+		if valid[0] {
+			return nil
+		}
+		if err := d.WithContext(ctx).Exec("DROP INDEX CONCURRENTLY IF EXISTS " + pgx.Identifier{name}.Sanitize()).Error; err != nil {
+			return err
+		}
+	}
+	return d.WithContext(ctx).Exec(stmt).Error
+}
@@ -0,0 +1,107 @@
+package orm
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBulkTransaction_Copy(t *testing.T) {
+	db, mock := newMockGorm(t)
+	d := &Database{DB: db}
+
+	assert.False(t, CopyEnabled(db))
+	assert.Error(t, CopyFrom(db, "tx", []string{"hash"}, [][]any{{[]byte{1}}}))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err := d.BulkTransaction(context.Background(), func(tx *gorm.DB) error {
+		assert.True(t, CopyEnabled(tx))
+		// nothing to copy
+		require.NoError(t, CopyFrom(tx, "tx", []string{"hash"}, nil))
+		// the mock driver has no COPY support
+		return CopyFrom(tx, "tx", []string{"hash"}, [][]any{{[]byte{1}}})
+	}, nil)
+	assert.ErrorContains(t, err, "copy is not supported")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreIndexes(t *testing.T) {
+	db, mock := newMockGorm(t)
+	d := &Database{DB: db}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	relkind := func(table, kind string) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT relkind::text FROM pg_class WHERE oid = to_regclass($1)")).WithArgs(table).
+			WillReturnRows(sqlmock.NewRows([]string{"relkind"}).AddRow(kind))
+	}
+	indexValid := func(name string, valid ...bool) {
+		rows := sqlmock.NewRows([]string{"indisvalid"})
+		for _, v := range valid {
+			rows.AddRow(v)
+		}
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass($1)")).WithArgs(name).
+			WillReturnRows(rows)
+	}
+	forget := func(name string) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "deferred_index" WHERE "deferred_index"."name" = $1`)).
+			WithArgs(name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "deferred_index" ORDER BY name`)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "relation", "definition"}).
+			AddRow("evm_tx_height", "evm_tx", "CREATE INDEX evm_tx_height ON public.evm_tx USING btree (height)").
+			AddRow("evm_tx_signer_id", "evm_tx", "CREATE INDEX evm_tx_signer_id ON public.evm_tx USING btree (signer_id)").
+			AddRow("tx_height", "tx", "CREATE INDEX tx_height ON ONLY public.tx USING btree (height)").
+			AddRow("tx_signer_id", "tx", "CREATE INDEX tx_signer_id ON public.tx USING btree (signer_id)"))
+
+	// missing index is built concurrently
+	relkind("evm_tx", "r")
+	indexValid("evm_tx_height")
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE INDEX CONCURRENTLY "evm_tx_height" ON "evm_tx" USING btree (height)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	forget("evm_tx_height")
+
+	// index left invalid by an interrupted build is built again
+	relkind("evm_tx", "r")
+	indexValid("evm_tx_signer_id", false)
+	mock.ExpectExec(regexp.QuoteMeta(`DROP INDEX CONCURRENTLY IF EXISTS "evm_tx_signer_id"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE INDEX CONCURRENTLY "evm_tx_signer_id" ON "evm_tx" USING btree (signer_id)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	forget("evm_tx_signer_id")
+
+	// index of a partitioned table is built on the partitions not attached yet
+	relkind("tx", "p")
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE INDEX IF NOT EXISTS "tx_height" ON ONLY "tx" USING btree (height)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT c.relname FROM pg_inherits i")).WithArgs("tx").
+		WillReturnRows(sqlmock.NewRows([]string{"relname"}).AddRow("tx_p0").AddRow("tx_p1"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS")).WithArgs("tx_height", "tx_p0").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS")).WithArgs("tx_height", "tx_p1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	indexValid("tx_p1_tx_height")
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE INDEX CONCURRENTLY "tx_p1_tx_height" ON "tx_p1" USING btree (height)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER INDEX "tx_height" ATTACH PARTITION "tx_p1_tx_height"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	forget("tx_height")
+
+	// index restored before a restart is only forgotten
+	relkind("tx", "r")
+	indexValid("tx_signer_id", true)
+	forget("tx_signer_id")
+
+	require.NoError(t, d.RestoreIndexes(context.Background(), logger))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
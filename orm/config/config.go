@@ -1,6 +1,9 @@
 package config
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type Config struct {
 	DSN          string
@@ -9,6 +12,12 @@ type Config struct {
 	IdleConns    int
 	BatchSize    int
 	MigrationDir string
+
+	// ReadDSNs are the read replicas serving the read-only transactions
+	ReadDSNs []string
+	// ReadMaxLag is the number of blocks a replica may trail the primary by before reads fall back to the primary
+	ReadMaxLag           int64
+	ReadLagCheckInterval time.Duration
 }
 
 func (c Config) Validate() error {
@@ -27,6 +36,14 @@ func (c Config) Validate() error {
 	if c.MigrationDir == "" {
 		return errors.New("DB_MIGRATION_DIR is required")
 	}
+	if len(c.ReadDSNs) > 0 {
+		if c.ReadMaxLag < 0 {
+			return errors.New("DB_READ_MAX_LAG is invalid")
+		}
+		if c.ReadLagCheckInterval <= 0 {
+			return errors.New("DB_READ_LAG_CHECK_INTERVAL is invalid")
+		}
+	}
 	// no check AutoMigrate
 	return nil
 }
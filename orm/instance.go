@@ -33,9 +33,38 @@ var (
 type Database struct {
 	*gorm.DB
 	config *config.Config
+	reads  *readRouter // nil without read replicas
 }
 
 func OpenDB(config *config.Config, logger *slog.Logger) (*Database, error) {
+	instance, err := open(config, config.DSN, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &Database{DB: instance, config: config}
+	if len(config.ReadDSNs) == 0 {
+		return db, nil
+	}
+
+	replicas := make([]*gorm.DB, 0, len(config.ReadDSNs))
+	for _, dsn := range config.ReadDSNs {
+		replica, err := open(config, dsn, logger)
+		if err != nil {
+			for _, opened := range append(replicas, instance) {
+				closeDB(opened)
+			}
+			return nil, err
+		}
+		replicas = append(replicas, replica)
+	}
+
+	db.reads = newReadRouter(instance, replicas, config.ReadMaxLag, config.ReadLagCheckInterval, logger)
+	db.reads.start()
+	return db, nil
+}
+
+func open(config *config.Config, dsn string, logger *slog.Logger) (*gorm.DB, error) {
 	gormcfg := &gorm.Config{
 		NamingStrategy:  schema.NamingStrategy{SingularTable: true},
 		PrepareStmt:     true,
@@ -43,7 +72,7 @@ func OpenDB(config *config.Config, logger *slog.Logger) (*Database, error) {
 		Logger:          sloggorm.New(sloggorm.WithHandler(logger.Handler())),
 	}
 
-	instance, err := gorm.Open(postgres.Open(config.DSN), gormcfg)
+	instance, err := gorm.Open(postgres.Open(dsn), gormcfg)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +90,22 @@ func OpenDB(config *config.Config, logger *slog.Logger) (*Database, error) {
 		return nil, err
 	}
 
-	return &Database{DB: instance, config: config}, nil
+	return instance, nil
+}
+
+// Begin starts a transaction. With read replicas, read-only transactions go to a replica within
+// the lag threshold, and to the primary otherwise.
+func (d *Database) Begin(opts ...*sql.TxOptions) *gorm.DB {
+	if d.reads == nil || len(opts) == 0 || opts[0] == nil || !opts[0].ReadOnly {
+		return d.DB.Begin(opts...)
+	}
+
+	if replica := d.reads.pick(); replica != nil {
+		trackReadTransaction("replica")
+		return replica.Begin(opts...)
+	}
+	trackReadTransaction("primary")
+	return d.DB.Begin(opts...)
 }
 
 func (d Database) Migrate(ctx context.Context) error {
@@ -94,6 +138,13 @@ func (d Database) Migrate(ctx context.Context) error {
 }
 
 func (d Database) Close() error {
+	if d.reads != nil {
+		d.reads.stop()
+		for _, rep := range d.reads.replicas {
+			closeDB(rep.db)
+		}
+	}
+
 	sqlDB, err := d.DB.DB()
 	if err != nil {
 		return err
@@ -101,6 +152,12 @@ func (d Database) Close() error {
 	return sqlDB.Close()
 }
 
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
+
 func (d Database) GetBatchSize() int {
 	return d.config.BatchSize
 }
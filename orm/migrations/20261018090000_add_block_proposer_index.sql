@@ -0,0 +1,4 @@
+-- atlas:txmode none
+
+-- Create index on proposer used by proposer statistics queries.
+CREATE INDEX CONCURRENTLY "block_proposer" ON "public"."block" ("proposer");
@@ -0,0 +1,2 @@
+-- Modify "tx" table
+ALTER TABLE "public"."tx" ADD COLUMN "code" bigint NOT NULL DEFAULT 0, ADD COLUMN "codespace" text NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- atlas:txmode none
+
+-- Create partial indexes for failed transaction filters matching gorm tags in types/table.go
+
+-- (sequence DESC) WHERE code <> 0
+CREATE INDEX CONCURRENTLY "tx_failed_sequence_partial" ON "public"."tx" ("sequence" DESC) WHERE (code <> 0);
+-- (codespace, sequence DESC) WHERE code <> 0
+CREATE INDEX CONCURRENTLY "tx_codespace_sequence_partial" ON "public"."tx" ("codespace", "sequence" DESC) WHERE (code <> 0);
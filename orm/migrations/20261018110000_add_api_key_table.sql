@@ -0,0 +1,11 @@
+-- Create "api_key" table
+CREATE TABLE "public"."api_key" (
+  "key_hash" bytea NOT NULL,
+  "name" text NOT NULL,
+  "rate" double precision NOT NULL DEFAULT 0,
+  "burst" bigint NOT NULL DEFAULT 0,
+  "revoked" boolean NOT NULL DEFAULT false,
+  PRIMARY KEY ("key_hash")
+);
+-- Create index "api_key_name" to table: "api_key"
+CREATE UNIQUE INDEX "api_key_name" ON "public"."api_key" ("name");
@@ -0,0 +1,94 @@
+-- Range partitioning support for the tx, evm_tx, evm_internal_tx and tx edge tables.
+-- Tables stay regular heap tables until converted with `rollytics partition`, which validates
+-- the bound of the existing rows online and then calls rollytics_swap_to_partitioned.
+
+-- Create range partitions of part_size keys after the highest existing one until up_to is covered.
+-- Partitions are named <parent>_p<start / part_size>. Returns the number of created partitions.
+CREATE OR REPLACE FUNCTION rollytics_ensure_partitions(parent text, part_size bigint, up_to bigint)
+RETURNS integer
+LANGUAGE plpgsql
+AS $$
+DECLARE
+  upper_bound bigint;
+  start_key bigint;
+  created integer := 0;
+BEGIN
+  SELECT max((regexp_match(pg_get_expr(c.relpartbound, c.oid), 'TO \((-?\d+)\)'))[1]::bigint)
+    INTO upper_bound
+    FROM pg_inherits i
+    JOIN pg_class c ON c.oid = i.inhrelid
+   WHERE i.inhparent = parent::regclass;
+
+  IF upper_bound IS NULL THEN
+    RAISE EXCEPTION 'table % has no range partition', parent;
+  END IF;
+
+  WHILE upper_bound <= up_to LOOP
+    start_key := upper_bound;
+    upper_bound := (start_key / part_size + 1) * part_size;
+    EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%s) TO (%s)',
+      parent || '_p' || (start_key / part_size), parent, start_key, upper_bound);
+    created := created + 1;
+  END LOOP;
+
+  RETURN created;
+END;
+$$;
+
+-- Replace the regular table parent by a table partitioned by range of part_key, attaching the
+-- existing table as the partition <parent>_legacy of keys below bound. The caller must have
+-- validated the CHECK (part_key IS NOT NULL AND part_key < bound) constraint, so that neither
+-- NOT NULL nor the attachment scan the table, and built pkey_index, the unique index of the new
+-- primary key, when the current primary key does not contain part_key (empty otherwise).
+-- Only metadata changes: the existing indexes are renamed to <name>_legacy and attached to the
+-- indexes of the same definition created on the partitioned table.
+CREATE OR REPLACE FUNCTION rollytics_swap_to_partitioned(parent text, part_key text, pkey_index text, bound bigint)
+RETURNS void
+LANGUAGE plpgsql
+AS $$
+DECLARE
+  legacy text := parent || '_legacy';
+  pkey_name text;
+  pkey_def text;
+  idx record;
+  defs text[] := '{}';
+  def text;
+BEGIN
+  EXECUTE format('LOCK TABLE %I IN ACCESS EXCLUSIVE MODE', parent);
+
+  IF pkey_index <> '' THEN
+    SELECT conname INTO pkey_name FROM pg_constraint WHERE conrelid = parent::regclass AND contype = 'p';
+    IF pkey_name IS NOT NULL THEN
+      EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', parent, pkey_name);
+    END IF;
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN %I SET NOT NULL', parent, part_key);
+    EXECUTE format('ALTER TABLE %I ADD CONSTRAINT %I PRIMARY KEY USING INDEX %I', parent, parent || '_pkey', pkey_index);
+  END IF;
+
+  SELECT pg_get_constraintdef(oid) INTO pkey_def FROM pg_constraint WHERE conrelid = parent::regclass AND contype = 'p';
+
+  FOR idx IN
+    SELECT c.relname, pg_get_indexdef(i.indexrelid) AS indexdef, i.indisprimary
+      FROM pg_index i
+      JOIN pg_class c ON c.oid = i.indexrelid
+     WHERE i.indrelid = parent::regclass
+  LOOP
+    IF NOT idx.indisprimary THEN
+      defs := defs || idx.indexdef;
+    END IF;
+    EXECUTE format('ALTER INDEX %I RENAME TO %I', idx.relname, left(idx.relname, 56) || '_legacy');
+  END LOOP;
+
+  EXECUTE format('ALTER TABLE %I RENAME TO %I', parent, legacy);
+  EXECUTE format('CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS) PARTITION BY RANGE (%I)', parent, legacy, part_key);
+  IF pkey_def IS NOT NULL THEN
+    EXECUTE format('ALTER TABLE %I ADD CONSTRAINT %I %s', parent, parent || '_pkey', pkey_def);
+  END IF;
+  FOREACH def IN ARRAY defs LOOP
+    EXECUTE def;
+  END LOOP;
+
+  EXECUTE format('ALTER TABLE %I ATTACH PARTITION %I FOR VALUES FROM (MINVALUE) TO (%s)', parent, legacy, bound);
+  EXECUTE format('CREATE TABLE %I PARTITION OF %I DEFAULT', parent || '_default', parent);
+END;
+$$;
@@ -0,0 +1,5 @@
+-- Create "prune_status" table
+CREATE TABLE "public"."prune_status" (
+  "pruned_height" bigint NULL,
+  "deleted_records" bigint NULL
+);
@@ -0,0 +1,17 @@
+-- Create "event_outbox" table
+CREATE TABLE "public"."event_outbox" (
+  "id" bigserial NOT NULL,
+  "height" bigint NOT NULL,
+  "type" text NOT NULL,
+  "key" text NOT NULL,
+  "payload" jsonb NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "event_outbox_height" to table: "event_outbox"
+CREATE INDEX "event_outbox_height" ON "public"."event_outbox" ("height");
+-- Create "event_sink_status" table
+CREATE TABLE "public"."event_sink_status" (
+  "last_event_id" bigint NULL,
+  "last_height" bigint NULL,
+  "published_events" bigint NULL
+);
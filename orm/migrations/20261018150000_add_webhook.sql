@@ -0,0 +1,39 @@
+-- Create "webhook_subscription" table
+CREATE TABLE "public"."webhook_subscription" (
+  "id" bigserial NOT NULL,
+  "event" text NOT NULL,
+  "target" text NOT NULL,
+  "url" text NOT NULL,
+  "secret" text NOT NULL,
+  "state" jsonb NULL,
+  "created_at" timestamptz NOT NULL,
+  "delivered_count" bigint NOT NULL DEFAULT 0,
+  "failed_count" bigint NOT NULL DEFAULT 0,
+  "last_delivery_at" timestamptz NULL,
+  "last_status_code" integer NOT NULL DEFAULT 0,
+  "last_error" text NOT NULL DEFAULT '',
+  PRIMARY KEY ("id")
+);
+-- Create index "webhook_subscription_event" to table: "webhook_subscription"
+CREATE INDEX "webhook_subscription_event" ON "public"."webhook_subscription" ("event");
+-- Create "webhook_dead_letter" table
+CREATE TABLE "public"."webhook_dead_letter" (
+  "id" bigserial NOT NULL,
+  "subscription_id" bigint NOT NULL,
+  "delivery_id" text NOT NULL,
+  "payload" jsonb NULL,
+  "attempts" integer NOT NULL,
+  "status_code" integer NOT NULL DEFAULT 0,
+  "error" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "webhook_dead_letter_subscription_id" to table: "webhook_dead_letter"
+CREATE INDEX "webhook_dead_letter_subscription_id" ON "public"."webhook_dead_letter" ("subscription_id");
+-- Create "webhook_status" table
+CREATE TABLE "public"."webhook_status" (
+  "last_sequence" bigint NULL,
+  "last_rich_list_height" bigint NULL,
+  "delivered_events" bigint NULL,
+  "dead_letters" bigint NULL
+);
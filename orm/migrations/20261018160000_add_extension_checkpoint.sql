@@ -0,0 +1,9 @@
+-- Create "extension_checkpoint" table
+CREATE TABLE "public"."extension_checkpoint" (
+  "name" text NOT NULL,
+  "height" bigint NOT NULL DEFAULT 0,
+  "sequence" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL,
+  "meta" jsonb NULL,
+  PRIMARY KEY ("name")
+);
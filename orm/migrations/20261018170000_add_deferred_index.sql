@@ -0,0 +1,7 @@
+-- Create "deferred_index" table
+CREATE TABLE "public"."deferred_index" (
+  "name" text NOT NULL,
+  "relation" text NOT NULL,
+  "definition" text NOT NULL,
+  PRIMARY KEY ("name")
+);
@@ -0,0 +1,9 @@
+-- Create "backfill_shard" table
+CREATE TABLE "public"."backfill_shard" (
+  "id" integer NOT NULL,
+  "from_height" bigint NOT NULL,
+  "to_height" bigint NOT NULL,
+  "staged" boolean NOT NULL DEFAULT false,
+  "merged" boolean NOT NULL DEFAULT false,
+  PRIMARY KEY ("id")
+);
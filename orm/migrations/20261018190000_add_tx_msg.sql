@@ -0,0 +1,16 @@
+-- Create "tx_msg" table
+CREATE TABLE "public"."tx_msg" (
+  "sequence" bigint NOT NULL,
+  "msg_index" integer NOT NULL,
+  "msg_type_id" bigint NOT NULL,
+  "sender_id" bigint NOT NULL DEFAULT 0,
+  "contract" bytea NULL,
+  "module" text NOT NULL DEFAULT '',
+  "function" text NOT NULL DEFAULT '',
+  "funds" jsonb NULL,
+  PRIMARY KEY ("sequence", "msg_index")
+);
+-- Create index "tx_msg_contract_sequence_desc" to table: "tx_msg"
+CREATE INDEX "tx_msg_contract_sequence_desc" ON "public"."tx_msg" ("contract", "sequence" DESC) WHERE (contract IS NOT NULL);
+-- Create index "tx_msg_function_sequence_desc" to table: "tx_msg"
+CREATE INDEX "tx_msg_function_sequence_desc" ON "public"."tx_msg" ("function", "module", "sequence" DESC) WHERE (function <> ''::text);
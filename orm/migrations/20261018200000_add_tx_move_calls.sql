@@ -0,0 +1,16 @@
+-- Create "move_function_dict" table
+CREATE TABLE "public"."move_function_dict" (
+  "id" bigserial NOT NULL,
+  "function" text NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "move_function_dict_function" to table: "move_function_dict"
+CREATE UNIQUE INDEX "move_function_dict_function" ON "public"."move_function_dict" ("function");
+-- Create "tx_move_calls" table
+CREATE TABLE "public"."tx_move_calls" (
+  "function_id" bigint NOT NULL,
+  "sequence" bigint NOT NULL,
+  PRIMARY KEY ("function_id", "sequence")
+);
+-- Create index "idx_tx_move_calls_sequence" to table: "tx_move_calls"
+CREATE INDEX "idx_tx_move_calls_sequence" ON "public"."tx_move_calls" ("sequence");
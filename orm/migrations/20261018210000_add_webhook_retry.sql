@@ -0,0 +1,16 @@
+-- Create "webhook_retry" table
+CREATE TABLE "public"."webhook_retry" (
+  "id" bigserial NOT NULL,
+  "subscription_id" bigint NOT NULL,
+  "delivery_id" text NOT NULL,
+  "event" text NOT NULL,
+  "payload" jsonb NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL,
+  "last_status_code" integer NOT NULL DEFAULT 0,
+  "last_error" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "webhook_retry_subscription_id" to table: "webhook_retry"
+CREATE INDEX "webhook_retry_subscription_id" ON "public"."webhook_retry" ("subscription_id", "id");
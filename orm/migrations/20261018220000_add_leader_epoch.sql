@@ -0,0 +1,6 @@
+-- Create "leader_epoch" table
+CREATE TABLE "public"."leader_epoch" (
+  "lock_key" bigint NOT NULL,
+  "epoch" bigint NOT NULL,
+  PRIMARY KEY ("lock_key")
+);
@@ -0,0 +1,71 @@
+package partition
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/orm"
+)
+
+// Manager keeps partitions ready ahead of the head of the partitioned tables, so that the
+// indexer never writes into the default partition. Regular tables are left untouched.
+type Manager struct {
+	cfg    *config.PartitionConfig
+	logger *slog.Logger
+	db     *orm.Database
+}
+
+func NewManager(cfg *config.Config, logger *slog.Logger, db *orm.Database) *Manager {
+	return &Manager{
+		cfg:    cfg.GetPartitionConfig(),
+		logger: logger.With("module", "partition"),
+		db:     db,
+	}
+}
+
+// Run checks the partitions once per check interval until the context is done
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Maintain(ctx); err != nil {
+			m.logger.Error("failed to maintain partitions", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Maintain creates the partitions missing up to Ahead partitions past the head of each partitioned table
+func (m *Manager) Maintain(ctx context.Context) error {
+	for _, table := range Tables {
+		partitioned, err := IsPartitioned(ctx, m.db.DB, table.Name)
+		if err != nil {
+			return err
+		}
+		if !partitioned {
+			continue
+		}
+
+		head, err := Head(ctx, m.db.DB, table)
+		if err != nil {
+			return err
+		}
+
+		created, err := Ensure(ctx, m.db.DB, table.Name, m.cfg.Size, head+int64(m.cfg.Ahead)*m.cfg.Size)
+		if err != nil {
+			return err
+		}
+		if created > 0 {
+			m.logger.Info("created partitions", slog.String("table", table.Name), slog.Int64("head", head), slog.Int("created", created))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package partition
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// Key is the partition key of every partitioned table, assigned in increasing order by the indexer
+const Key = "sequence"
+
+// swapLockTimeout bounds the wait for the exclusive lock of the swap, which only changes metadata
+const swapLockTimeout = "10s"
+
+// Table describes a table which can be range partitioned by sequence
+type Table struct {
+	Name string
+	// PrimaryKey of the partitioned table, which must contain the partition key
+	PrimaryKey []string
+	// SeqInfo is the sequence counter of the table, whose value is the head of the partition key
+	SeqInfo types.SeqInfoName
+	// rebuildPrimaryKey is set when the current primary key lacks the partition key
+	rebuildPrimaryKey bool
+}
+
+// Tables lists the partitionable tables. The primary keys of tx, evm_tx and evm_internal_tx gain
+// the sequence, so their former primary keys are no longer enforced as unique on their own: the
+// collectors skip the blocks and the internal txs of the heights already indexed instead.
+var Tables = []Table{
+	{Name: types.CollectedTx{}.TableName(), PrimaryKey: []string{"hash", "height", Key}, SeqInfo: types.SeqInfoTx, rebuildPrimaryKey: true},
+	{Name: types.CollectedTxAccount{}.TableName(), PrimaryKey: []string{"account_id", Key}, SeqInfo: types.SeqInfoTx},
+	{Name: types.CollectedTxMsgType{}.TableName(), PrimaryKey: []string{"msg_type_id", Key}, SeqInfo: types.SeqInfoTx},
+	{Name: types.CollectedTxMsg{}.TableName(), PrimaryKey: []string{Key, "msg_index"}, SeqInfo: types.SeqInfoTx},
+	{Name: types.CollectedTxMoveCall{}.TableName(), PrimaryKey: []string{"function_id", Key}, SeqInfo: types.SeqInfoTx},
+	{Name: types.CollectedEvmTx{}.TableName(), PrimaryKey: []string{"hash", "height", Key}, SeqInfo: types.SeqInfoEvmTx, rebuildPrimaryKey: true},
+	{Name: types.CollectedEvmInternalTx{}.TableName(), PrimaryKey: []string{"height", "hash_id", "index", Key}, SeqInfo: types.SeqInfoEvmInternalTx, rebuildPrimaryKey: true},
+}
+
+// LookupTable returns the partitionable table of the given name
+func LookupTable(name string) (Table, bool) {
+	for _, table := range Tables {
+		if table.Name == name {
+			return table, true
+		}
+	}
+	return Table{}, false
+}
+
+func (t Table) boundConstraint() string {
+	return t.Name + "_partition_bound"
+}
+
+func (t Table) primaryKeyIndex() string {
+	if !t.rebuildPrimaryKey {
+		return ""
+	}
+	return t.Name + "_partition_pkey"
+}
+
+// IsPartitioned reports whether the table is already partitioned
+func IsPartitioned(ctx context.Context, db *gorm.DB, table string) (bool, error) {
+	var partitioned bool
+	if err := db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = to_regclass(?))", table).
+		Scan(&partitioned).Error; err != nil {
+		return false, types.NewDatabaseError("check partitioned table", err)
+	}
+	return partitioned, nil
+}
+
+// Head returns the latest sequence assigned to the rows of the table
+func Head(ctx context.Context, db *gorm.DB, table Table) (int64, error) {
+	var seqInfo types.CollectedSeqInfo
+	if err := db.WithContext(ctx).
+		Where("name = ?", table.SeqInfo).
+		Limit(1).
+		Find(&seqInfo).Error; err != nil {
+		return 0, types.NewDatabaseError("get sequence info", err)
+	}
+	return seqInfo.Sequence, nil
+}
+
+// Ensure creates the partitions of size sequences missing up to the given sequence, and returns how many were created
+func Ensure(ctx context.Context, db *gorm.DB, table string, size, upTo int64) (int, error) {
+	var created int
+	if err := db.WithContext(ctx).
+		Raw("SELECT rollytics_ensure_partitions(?, ?, ?)", table, size, upTo).
+		Scan(&created).Error; err != nil {
+		return 0, types.NewDatabaseError("ensure partitions", err)
+	}
+	return created, nil
+}
+
+// Bound returns the first sequence after the rows kept by the legacy partition of a table converted
+// at the given head: the end of the partition after the one holding the head. Rows indexed during
+// the conversion must stay below it, which leaves at least one partition of sequences of slack.
+func Bound(head, size int64) int64 {
+	return (head/size + 2) * size
+}
+
+// Convert turns the regular table into a table partitioned by sequence, without blocking the indexer
+// and the API for more than the final metadata swap. The existing rows are kept as the <table>_legacy
+// partition of the sequences below the bound, later rows go to partitions of size sequences.
+//
+// The slow steps, validating the bound of the existing rows and building the new primary key,
+// only take locks compatible with reads and writes. Interrupted conversions can be run again.
+func Convert(ctx context.Context, db *gorm.DB, table Table, size int64, ahead int, logger *slog.Logger) error {
+	// DDL must not be prepared, and CREATE INDEX CONCURRENTLY cannot run in a transaction
+	db = db.Session(&gorm.Session{PrepareStmt: false, Context: ctx})
+
+	partitioned, err := IsPartitioned(ctx, db, table.Name)
+	if err != nil {
+		return err
+	}
+	if partitioned {
+		logger.Info("table is already partitioned", slog.String("table", table.Name))
+		return nil
+	}
+
+	head, err := Head(ctx, db, table)
+	if err != nil {
+		return err
+	}
+	bound := Bound(head, size)
+
+	for _, step := range convertSteps(table, bound) {
+		logger.Info("converting table to partitions", slog.String("table", table.Name), slog.String("step", step.name))
+		if err := step.run(db); err != nil {
+			return types.NewDatabaseError(fmt.Sprintf("partition %s: %s", table.Name, step.name), err)
+		}
+	}
+
+	created, err := Ensure(ctx, db, table.Name, size, head+int64(ahead)*size)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("converted table to partitions",
+		slog.String("table", table.Name),
+		slog.Int64("legacy_bound", bound),
+		slog.Int("created", created))
+	return nil
+}
+
+type convertStep struct {
+	name string
+	run  func(db *gorm.DB) error
+}
+
+func convertSteps(table Table, bound int64) []convertStep {
+	name := quoteIdent(table.Name)
+	constraint := quoteIdent(table.boundConstraint())
+	steps := []convertStep{
+		{
+			name: "add bound constraint",
+			run: func(db *gorm.DB) error {
+				if err := db.Exec(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s", name, constraint)).Error; err != nil {
+					return err
+				}
+				return db.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s IS NOT NULL AND %s < %d) NOT VALID",
+					name, constraint, Key, Key, bound)).Error
+			},
+		},
+		{
+			name: "validate bound constraint",
+			run: func(db *gorm.DB) error {
+				return db.Exec(fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s", name, constraint)).Error
+			},
+		},
+	}
+
+	if index := table.primaryKeyIndex(); index != "" {
+		steps = append(steps, convertStep{
+			name: "build primary key",
+			run: func(db *gorm.DB) error {
+				// an interrupted concurrent build leaves an invalid index behind
+				var valid bool
+				if err := db.Raw("SELECT COALESCE((SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass(?)), false)", index).
+					Scan(&valid).Error; err != nil {
+					return err
+				}
+				if valid {
+					return nil
+				}
+				if err := db.Exec(fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", quoteIdent(index))).Error; err != nil {
+					return err
+				}
+				return db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX CONCURRENTLY %s ON %s (%s)",
+					quoteIdent(index), name, quoteIdents(table.PrimaryKey))).Error
+			},
+		})
+	}
+
+	return append(steps, convertStep{
+		name: "swap to partitioned table",
+		run: func(db *gorm.DB) error {
+			return db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec("SET LOCAL lock_timeout = '" + swapLockTimeout + "'").Error; err != nil {
+					return err
+				}
+				return tx.Exec("SELECT rollytics_swap_to_partitioned(?, ?, ?, ?)", table.Name, Key, table.primaryKeyIndex(), bound).Error
+			}, &sql.TxOptions{})
+		},
+	})
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,146 @@
+package partition
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/orm/testutil"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestBound(t *testing.T) {
+	assert.Equal(t, int64(200), Bound(0, 100))
+	assert.Equal(t, int64(200), Bound(99, 100))
+	assert.Equal(t, int64(300), Bound(100, 100))
+	assert.Equal(t, int64(20_000_000), Bound(1234, 10_000_000))
+}
+
+func expectPartitioned(mock sqlmock.Sqlmock, table string, partitioned bool) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = to_regclass($1))")).
+		WithArgs(table).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(partitioned))
+}
+
+func expectHead(mock sqlmock.Sqlmock, name string, head int64) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "seq_info" WHERE name = $1 LIMIT $2`)).
+		WithArgs(name, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "sequence"}).AddRow(name, head))
+}
+
+func TestConvert(t *testing.T) {
+	db, mock, err := testutil.NewMockDB(discardLogger)
+	require.NoError(t, err)
+
+	table, ok := LookupTable("tx")
+	require.True(t, ok)
+
+	expectPartitioned(mock, "tx", false)
+	expectHead(mock, "tx", 1234)
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "tx" DROP CONSTRAINT IF EXISTS "tx_partition_bound"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "tx" ADD CONSTRAINT "tx_partition_bound" CHECK (sequence IS NOT NULL AND sequence < 1400) NOT VALID`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "tx" VALIDATE CONSTRAINT "tx_partition_bound"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE((SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass($1)), false)")).
+		WithArgs("tx_partition_pkey").
+		WillReturnRows(sqlmock.NewRows([]string{"valid"}).AddRow(false))
+	mock.ExpectExec(regexp.QuoteMeta(`DROP INDEX CONCURRENTLY IF EXISTS "tx_partition_pkey"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE UNIQUE INDEX CONCURRENTLY "tx_partition_pkey" ON "tx" ("hash", "height", "sequence")`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SET LOCAL lock_timeout = '10s'")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT rollytics_swap_to_partitioned($1, $2, $3, $4)")).
+		WithArgs("tx", "sequence", "tx_partition_pkey", int64(1400)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT rollytics_ensure_partitions($1, $2, $3)")).
+		WithArgs("tx", int64(100), int64(1434)).
+		WillReturnRows(sqlmock.NewRows([]string{"created"}).AddRow(13))
+
+	require.NoError(t, Convert(context.Background(), db.DB, table, 100, 2, discardLogger))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConvert_KeepsPrimaryKeyWithSequence(t *testing.T) {
+	db, mock, err := testutil.NewMockDB(discardLogger)
+	require.NoError(t, err)
+
+	table, ok := LookupTable("tx_accounts")
+	require.True(t, ok)
+
+	expectPartitioned(mock, "tx_accounts", false)
+	expectHead(mock, "tx", 0)
+	mock.ExpectExec("DROP CONSTRAINT IF EXISTS").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ADD CONSTRAINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("VALIDATE CONSTRAINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL lock_timeout").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("rollytics_swap_to_partitioned").
+		WithArgs("tx_accounts", "sequence", "", int64(200)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery("rollytics_ensure_partitions").
+		WillReturnRows(sqlmock.NewRows([]string{"created"}).AddRow(1))
+
+	require.NoError(t, Convert(context.Background(), db.DB, table, 100, 2, discardLogger))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConvert_AlreadyPartitioned(t *testing.T) {
+	db, mock, err := testutil.NewMockDB(discardLogger)
+	require.NoError(t, err)
+
+	table, ok := LookupTable("evm_tx")
+	require.True(t, ok)
+
+	expectPartitioned(mock, "evm_tx", true)
+
+	require.NoError(t, Convert(context.Background(), db.DB, table, 100, 2, discardLogger))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMaintain(t *testing.T) {
+	db, mock, err := testutil.NewMockDB(discardLogger)
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	cfg.SetPartitionConfig(&config.PartitionConfig{Size: 1000, Ahead: 3, CheckInterval: time.Minute})
+	manager := NewManager(cfg, discardLogger, db)
+
+	// only tx is partitioned
+	for _, table := range Tables {
+		expectPartitioned(mock, table.Name, table.Name == "tx")
+		if table.Name == "tx" {
+			expectHead(mock, "tx", 5500)
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT rollytics_ensure_partitions($1, $2, $3)")).
+				WithArgs("tx", int64(1000), int64(8500)).
+				WillReturnRows(sqlmock.NewRows([]string{"created"}).AddRow(0))
+		}
+	}
+
+	require.NoError(t, manager.Maintain(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTables_PrimaryKeyHasPartitionKey(t *testing.T) {
+	for _, name := range []string{"tx_msg", "tx_move_calls"} {
+		_, ok := LookupTable(name)
+		assert.True(t, ok, name)
+	}
+	for _, table := range Tables {
+		assert.Contains(t, table.PrimaryKey, Key, table.Name)
+	}
+}
@@ -0,0 +1,132 @@
+package orm
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/metrics"
+)
+
+// heightQuery reads the latest indexed height, whose difference between the primary and a replica is the replica lag
+const heightQuery = "SELECT COALESCE(MAX(height), 0) FROM block"
+
+// replica is a read-only copy of the primary database
+type replica struct {
+	name    string
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// readRouter routes the read-only transactions to the replicas which trail the primary by at most
+// maxLag blocks, in turn. Without such a replica, reads go to the primary.
+type readRouter struct {
+	primary  *gorm.DB
+	replicas []*replica
+	maxLag   int64
+	interval time.Duration
+	logger   *slog.Logger
+
+	next     atomic.Uint64
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newReadRouter(primary *gorm.DB, replicas []*gorm.DB, maxLag int64, interval time.Duration, logger *slog.Logger) *readRouter {
+	r := &readRouter{
+		primary:  primary,
+		maxLag:   maxLag,
+		interval: interval,
+		logger:   logger.With("component", "read_router"),
+		done:     make(chan struct{}),
+	}
+	for i, db := range replicas {
+		r.replicas = append(r.replicas, &replica{name: strconv.Itoa(i), db: db})
+	}
+	return r
+}
+
+// pick returns the next replica within the lag threshold, nil if there is none
+func (r *readRouter) pick() *gorm.DB {
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := range n {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep.db
+		}
+	}
+	return nil
+}
+
+// start checks the replicas once, then in the background until stop
+func (r *readRouter) start() {
+	r.check()
+
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+				r.check()
+			}
+		}
+	}()
+}
+
+func (r *readRouter) stop() {
+	r.stopOnce.Do(func() { close(r.done) })
+}
+
+// check compares the height of every replica with the primary's and marks the replicas trailing by
+// more than maxLag, or unreachable, as unhealthy
+func (r *readRouter) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), max(r.interval, time.Second))
+	defer cancel()
+
+	var primaryHeight int64
+	if err := r.primary.WithContext(ctx).Raw(heightQuery).Scan(&primaryHeight).Error; err != nil {
+		// without the primary height, the lag is unknown
+		r.logger.Warn("failed to get primary height", slog.Any("error", err))
+		for _, rep := range r.replicas {
+			rep.healthy.Store(false)
+		}
+		return
+	}
+
+	for _, rep := range r.replicas {
+		var height int64
+		if err := rep.db.WithContext(ctx).Raw(heightQuery).Scan(&height).Error; err != nil {
+			if rep.healthy.Swap(false) {
+				r.logger.Warn("read replica is unreachable", slog.String("replica", rep.name), slog.Any("error", err))
+			}
+			continue
+		}
+
+		lag := max(primaryHeight-height, 0)
+		if m := metrics.GetMetrics(); m != nil {
+			m.DatabaseMetrics().ReplicaLag.WithLabelValues(rep.name).Set(float64(lag))
+		}
+
+		healthy := lag <= r.maxLag
+		if rep.healthy.Swap(healthy) != healthy {
+			r.logger.Info("read replica routing changed",
+				slog.String("replica", rep.name),
+				slog.Int64("lag", lag),
+				slog.Bool("healthy", healthy))
+		}
+	}
+}
+
+func trackReadTransaction(target string) {
+	if m := metrics.GetMetrics(); m != nil {
+		m.DatabaseMetrics().ReadTransactions.WithLabelValues(target).Inc()
+	}
+}
@@ -0,0 +1,103 @@
+package orm
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func newMockGorm(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+	})
+	require.NoError(t, err)
+	return db, mock
+}
+
+func expectHeight(mock sqlmock.Sqlmock, height int64) {
+	mock.ExpectQuery(regexp.QuoteMeta(heightQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(height))
+}
+
+func TestReadRouter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	primary, primaryMock := newMockGorm(t)
+	replica0, replica0Mock := newMockGorm(t)
+	replica1, replica1Mock := newMockGorm(t)
+
+	router := newReadRouter(primary, []*gorm.DB{replica0, replica1}, 10, time.Second, logger)
+	db := &Database{DB: primary, reads: router}
+
+	// no check yet: reads go to the primary
+	assert.Nil(t, router.pick())
+
+	// replica 1 trails by more than the threshold
+	expectHeight(primaryMock, 100)
+	expectHeight(replica0Mock, 95)
+	expectHeight(replica1Mock, 80)
+	router.check()
+
+	for range 3 {
+		assert.Same(t, replica0, router.pick())
+	}
+
+	replica0Mock.ExpectBegin()
+	replica0Mock.ExpectRollback()
+	tx := db.Begin(&sql.TxOptions{ReadOnly: true})
+	require.NoError(t, tx.Error)
+	tx.Rollback()
+
+	// writes always go to the primary
+	primaryMock.ExpectBegin()
+	primaryMock.ExpectRollback()
+	tx = db.Begin()
+	require.NoError(t, tx.Error)
+	tx.Rollback()
+
+	// both replicas caught up: reads alternate
+	expectHeight(primaryMock, 110)
+	expectHeight(replica0Mock, 110)
+	expectHeight(replica1Mock, 105)
+	router.check()
+
+	picked := map[*gorm.DB]int{}
+	for range 4 {
+		picked[router.pick()]++
+	}
+	assert.Equal(t, map[*gorm.DB]int{replica0: 2, replica1: 2}, picked)
+
+	// unreachable replica
+	expectHeight(primaryMock, 120)
+	replica0Mock.ExpectQuery(regexp.QuoteMeta(heightQuery)).WillReturnError(errors.New("connection refused"))
+	expectHeight(replica1Mock, 120)
+	router.check()
+	assert.Same(t, replica1, router.pick())
+
+	// unknown primary height
+	primaryMock.ExpectQuery(regexp.QuoteMeta(heightQuery)).WillReturnError(errors.New("connection refused"))
+	router.check()
+	assert.Nil(t, router.pick())
+
+	primaryMock.ExpectBegin()
+	tx = db.Begin(&sql.TxOptions{ReadOnly: true})
+	require.NoError(t, tx.Error)
+
+	require.NoError(t, primaryMock.ExpectationsWereMet())
+	require.NoError(t, replica0Mock.ExpectationsWereMet())
+	require.NoError(t, replica1Mock.ExpectationsWereMet())
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/initia-labs/rollytics/patcher/v1_0_12"
 	"github.com/initia-labs/rollytics/patcher/v1_0_14"
 	"github.com/initia-labs/rollytics/patcher/v1_0_15"
+	"github.com/initia-labs/rollytics/patcher/v1_0_16"
 	"github.com/initia-labs/rollytics/patcher/v1_0_2"
 	"github.com/initia-labs/rollytics/types"
 )
@@ -29,6 +30,7 @@ var patches = []PatchHandler{
 	{"v1.0.12", v1_0_12.Patch},
 	{"v1.0.14", v1_0_14.Patch},
 	{"v1.0.15", v1_0_15.Patch},
+	{"v1.0.16", v1_0_16.Patch},
 }
 
 // Patch applies data migration patches to fix or update existing data in the database.
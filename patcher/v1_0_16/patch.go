@@ -0,0 +1,25 @@
+package v1_0_16
+
+import (
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+)
+
+func Patch(tx *gorm.DB, cfg *config.Config, logger *slog.Logger) error {
+	logger.Info("backfilling tx code and codespace columns")
+
+	// Successful txs keep the column defaults, so only failed txs need to be updated
+	if err := tx.Exec(`
+		UPDATE tx
+		SET code = (data->>'code')::bigint, codespace = COALESCE(data->>'codespace', '')
+		WHERE COALESCE((data->>'code')::bigint, 0) <> 0`).Error; err != nil {
+		logger.Error("failed to backfill tx code", slog.Any("error", err))
+		return err
+	}
+
+	logger.Info("successfully backfilled tx code and codespace columns")
+	return nil
+}
@@ -18,6 +18,7 @@ const (
 	ErrTypeBadRequest   ErrorType = "BAD_REQUEST"
 	ErrTypeRateLimit    ErrorType = "RATE_LIMIT"
 	ErrTypeTimeout      ErrorType = "TIMEOUT"
+	ErrTypePruned       ErrorType = "PRUNED"
 )
 
 // StandardError provides consistent error formatting
@@ -113,6 +114,14 @@ func NewTimeoutError(operation string) error {
 	}
 }
 
+func NewPrunedError(resource string, floor int64) error {
+	return &StandardError{
+		Type:    ErrTypePruned,
+		Message: fmt.Sprintf("%s pruned: only heights from %d are retained", resource, floor),
+		Details: map[string]any{"resource": resource, "floor": floor},
+	}
+}
+
 func NewInternalError(msg string, cause error) error {
 	return &StandardError{
 		Type:    ErrTypeInternal,
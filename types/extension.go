@@ -0,0 +1,28 @@
+package types
+
+import "sync"
+
+// ExtensionHealth is the supervision state of an extension along with its recent failures, published
+// by the extension manager for the status endpoints
+type ExtensionHealth struct {
+	State     string
+	Restarts  int // consecutive restarts
+	LastError string
+}
+
+// extensionHealths holds the health of the extensions supervised by this process
+var extensionHealths sync.Map
+
+// SetExtensionHealth publishes the health of an extension supervised by this process
+func SetExtensionHealth(name string, health ExtensionHealth) {
+	extensionHealths.Store(name, health)
+}
+
+// ExtensionHealthOf returns the health of the extension, or false when the extension is not supervised
+// by this process, e.g. on the API server or a standby replica before it ever led
+func ExtensionHealthOf(name string) (ExtensionHealth, bool) {
+	if health, ok := extensionHealths.Load(name); ok {
+		return health.(ExtensionHealth), true
+	}
+	return ExtensionHealth{}, false
+}
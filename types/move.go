@@ -10,6 +10,16 @@ const (
 	MoveWithdrawOwnerEventTypeTag = "0x1::fungible_asset::WithdrawOwnerEvent"
 )
 
+const (
+	MoveMsgExecute     = "initia.move.v1.MsgExecute"
+	MoveMsgExecuteJSON = "initia.move.v1.MsgExecuteJSON"
+	MoveMsgScript      = "initia.move.v1.MsgScript"
+	MoveMsgScriptJSON  = "initia.move.v1.MsgScriptJSON"
+
+	// MoveScriptFunction is the function of the move calls of the scripts
+	MoveScriptFunction = "script"
+)
+
 type QueryMoveResourceResponse struct {
 	Resource struct {
 		Address      string `json:"address"`
@@ -43,7 +43,7 @@ type CollectedBlock struct {
 	Hash      []byte          `gorm:"type:bytea"`
 	Timestamp time.Time       `gorm:"type:timestamptz;index:block_timestamp_desc,sort:desc"`
 	BlockTime int64           `gorm:"type:bigint"`
-	Proposer  string          `gorm:"type:text"`
+	Proposer  string          `gorm:"type:text;index:block_proposer"`
 	GasUsed   int64           `gorm:"type:bigint"`
 	GasWanted int64           `gorm:"type:bigint"`
 	TxCount   int             `gorm:"type:smallint;index:block_tx_count"`
@@ -51,11 +51,13 @@ type CollectedBlock struct {
 }
 
 type CollectedTx struct {
-	Hash     []byte          `gorm:"type:bytea;primaryKey"`
-	Height   int64           `gorm:"type:bigint;primaryKey;autoIncrement:false;index:tx_height;index:tx_height_sequence_desc,priority:1"`
-	Sequence int64           `gorm:"type:bigint;index:tx_sequence_desc,sort:desc;index:tx_account_sequence_partial,sort:desc;index:tx_nft_sequence_partial,sort:desc;index:tx_msg_type_sequence_partial,sort:desc;index:tx_height_sequence_desc,priority:2,sort:desc"`
-	SignerId int64           `gorm:"type:bigint;index:tx_signer_id"`
-	Data     json.RawMessage `gorm:"type:jsonb"`
+	Hash      []byte          `gorm:"type:bytea;primaryKey"`
+	Height    int64           `gorm:"type:bigint;primaryKey;autoIncrement:false;index:tx_height;index:tx_height_sequence_desc,priority:1"`
+	Sequence  int64           `gorm:"type:bigint;index:tx_sequence_desc,sort:desc;index:tx_account_sequence_partial,sort:desc;index:tx_nft_sequence_partial,sort:desc;index:tx_msg_type_sequence_partial,sort:desc;index:tx_height_sequence_desc,priority:2,sort:desc;index:tx_failed_sequence_partial,sort:desc,where:code <> 0;index:tx_codespace_sequence_partial,priority:2,sort:desc,where:code <> 0"`
+	SignerId  int64           `gorm:"type:bigint;index:tx_signer_id"`
+	Code      uint32          `gorm:"type:bigint;not null;default:0"`
+	Codespace string          `gorm:"type:text;not null;default:'';index:tx_codespace_sequence_partial,priority:1,where:code <> 0"`
+	Data      json.RawMessage `gorm:"type:jsonb"`
 }
 
 type CollectedTxAccount struct {
@@ -79,6 +81,23 @@ type CollectedTxTypeTag struct {
 	Sequence  int64 `gorm:"type:bigint;primaryKey"`
 }
 
+type CollectedTxMoveCall struct {
+	FunctionId int64 `gorm:"type:bigint;primaryKey"`
+	Sequence   int64 `gorm:"type:bigint;primaryKey;index:idx_tx_move_calls_sequence"`
+}
+
+// CollectedTxMsg holds the fields of a msg of a tx decoded from the tx body, in the order of the msgs
+type CollectedTxMsg struct {
+	Sequence  int64           `gorm:"type:bigint;primaryKey;index:tx_msg_contract_sequence_desc,priority:2,sort:desc;index:tx_msg_function_sequence_desc,priority:3,sort:desc"`
+	MsgIndex  int32           `gorm:"type:integer;primaryKey;autoIncrement:false"`
+	MsgTypeId int64           `gorm:"type:bigint;not null"`
+	SenderId  int64           `gorm:"type:bigint;not null;default:0"`
+	Contract  []byte          `gorm:"type:bytea;index:tx_msg_contract_sequence_desc,priority:1,where:contract IS NOT NULL"`
+	Module    string          `gorm:"type:text;not null;default:'';index:tx_msg_function_sequence_desc,priority:2"`
+	Function  string          `gorm:"type:text;not null;default:'';index:tx_msg_function_sequence_desc,priority:1,where:function <> ''"`
+	Funds     json.RawMessage `gorm:"type:jsonb"`
+}
+
 type CollectedEvmTxAccount struct {
 	AccountId int64 `gorm:"type:bigint;primaryKey"`
 	Sequence  int64 `gorm:"type:bigint;primaryKey"`
@@ -145,6 +164,13 @@ type CollectedTypeTagDict struct {
 	TypeTag string `gorm:"type:text;uniqueIndex:type_tag_dict_type_tag"`
 }
 
+// CollectedMoveFunctionDict holds the move functions called by txs, <address>::<module>::<function>,
+// or script for the scripts
+type CollectedMoveFunctionDict struct {
+	Id       int64  `gorm:"type:bigint;primaryKey"`
+	Function string `gorm:"type:text;uniqueIndex:move_function_dict_function"`
+}
+
 // Extension: Table related to internal transaction
 type CollectedEvmInternalTx struct {
 	Height      int64  `gorm:"type:bigint;primaryKey;index:evm_internal_tx_height_sequence_desc,priority:1"`
@@ -188,6 +214,121 @@ type CollectedTxAccountCleanupStatus struct {
 	InsertedRecords     int64 `gorm:"type:bigint;column:inserted_records"`
 }
 
+// CollectedPruneStatus is the progress of the pruning mode: the txs of heights up to PrunedHeight are deleted.
+type CollectedPruneStatus struct {
+	PrunedHeight   int64 `gorm:"type:bigint;column:pruned_height"`
+	DeletedRecords int64 `gorm:"type:bigint;column:deleted_records"`
+}
+
+// CollectedApiKey is an API key of the API server. Only the SHA-256 hash of the key is stored.
+// A zero rate or burst falls back to the default key tier.
+type CollectedApiKey struct {
+	KeyHash []byte  `gorm:"type:bytea;primaryKey"`
+	Name    string  `gorm:"type:text;not null;uniqueIndex:api_key_name"`
+	Rate    float64 `gorm:"type:double precision;not null;default:0"`
+	Burst   int64   `gorm:"type:bigint;not null;default:0"`
+	Revoked bool    `gorm:"type:boolean;not null;default:false"`
+}
+
+// CollectedEventOutbox is an event of an indexed block waiting for the event sink. The events are
+// written in the transaction of their block and deleted once published.
+type CollectedEventOutbox struct {
+	Id      int64           `gorm:"type:bigint;primaryKey"`
+	Height  int64           `gorm:"type:bigint;not null;index:event_outbox_height"`
+	Type    string          `gorm:"type:text;not null"`
+	Key     string          `gorm:"type:text;not null"`
+	Payload json.RawMessage `gorm:"type:jsonb"`
+}
+
+// CollectedEventSinkStatus is the progress of the event sink: the outbox events up to LastEventId are published.
+type CollectedEventSinkStatus struct {
+	LastEventId     int64 `gorm:"type:bigint;column:last_event_id"`
+	LastHeight      int64 `gorm:"type:bigint;column:last_height"`
+	PublishedEvents int64 `gorm:"type:bigint;column:published_events"`
+}
+
+// CollectedWebhookSubscription is a webhook subscription: Url is notified of the Event on Target, an account,
+// a collection or a denom, with payloads signed by Secret. State keeps the last notified rich list top.
+type CollectedWebhookSubscription struct {
+	Id             int64           `gorm:"type:bigint;primaryKey"`
+	Event          string          `gorm:"type:text;not null;index:webhook_subscription_event"`
+	Target         string          `gorm:"type:text;not null"`
+	Url            string          `gorm:"type:text;not null"`
+	Secret         string          `gorm:"type:text;not null"`
+	State          json.RawMessage `gorm:"type:jsonb"`
+	CreatedAt      time.Time       `gorm:"type:timestamptz;not null"`
+	DeliveredCount int64           `gorm:"type:bigint;not null;default:0"`
+	FailedCount    int64           `gorm:"type:bigint;not null;default:0"`
+	LastDeliveryAt *time.Time      `gorm:"type:timestamptz"`
+	LastStatusCode int             `gorm:"type:integer;not null;default:0"`
+	LastError      string          `gorm:"type:text;not null;default:''"`
+}
+
+// CollectedWebhookDeadLetter is a notification which failed every delivery attempt
+type CollectedWebhookDeadLetter struct {
+	Id             int64           `gorm:"type:bigint;primaryKey"`
+	SubscriptionId int64           `gorm:"type:bigint;not null;index:webhook_dead_letter_subscription_id"`
+	DeliveryId     string          `gorm:"type:text;not null"`
+	Payload        json.RawMessage `gorm:"type:jsonb"`
+	Attempts       int             `gorm:"type:integer;not null"`
+	StatusCode     int             `gorm:"type:integer;not null;default:0"`
+	Error          string          `gorm:"type:text;not null;default:''"`
+	CreatedAt      time.Time       `gorm:"type:timestamptz;not null"`
+}
+
+// CollectedWebhookRetry is a notification waiting for redelivery after a failed attempt. The retries of a
+// subscription are redelivered in id order, so the notifications following a failed one wait behind it
+// with no attempt made.
+type CollectedWebhookRetry struct {
+	Id             int64           `gorm:"type:bigint;primaryKey;index:webhook_retry_subscription_id,priority:2"`
+	SubscriptionId int64           `gorm:"type:bigint;not null;index:webhook_retry_subscription_id,priority:1"`
+	DeliveryId     string          `gorm:"type:text;not null"`
+	Event          string          `gorm:"type:text;not null"`
+	Payload        json.RawMessage `gorm:"type:jsonb"`
+	Attempts       int             `gorm:"type:integer;not null;default:0"`
+	NextAttemptAt  time.Time       `gorm:"type:timestamptz;not null"`
+	LastStatusCode int             `gorm:"type:integer;not null;default:0"`
+	LastError      string          `gorm:"type:text;not null;default:''"`
+	CreatedAt      time.Time       `gorm:"type:timestamptz;not null"`
+}
+
+// CollectedWebhookStatus is the progress of the webhook dispatcher: the txs up to LastSequence and
+// the rich list up to LastRichListHeight are notified.
+type CollectedWebhookStatus struct {
+	LastSequence       int64 `gorm:"type:bigint;column:last_sequence"`
+	LastRichListHeight int64 `gorm:"type:bigint;column:last_rich_list_height"`
+	DeliveredEvents    int64 `gorm:"type:bigint;column:delivered_events"`
+	DeadLetters        int64 `gorm:"type:bigint;column:dead_letters"`
+}
+
+// CollectedExtensionCheckpoint is the progress of an extension: the heights up to Height, or the txs up to
+// Sequence for extensions following the tx sequence, are processed. Meta keeps extension specific details.
+type CollectedExtensionCheckpoint struct {
+	Name      string          `gorm:"type:text;primaryKey"`
+	Height    int64           `gorm:"type:bigint;not null;default:0"`
+	Sequence  int64           `gorm:"type:bigint;not null;default:0"`
+	UpdatedAt time.Time       `gorm:"type:timestamptz;not null"`
+	Meta      json.RawMessage `gorm:"type:jsonb"`
+}
+
+// CollectedDeferredIndex is a secondary index dropped for the initial sync, recreated from its
+// definition once the indexer is near the head
+type CollectedDeferredIndex struct {
+	Name       string `gorm:"type:text;primaryKey"`
+	Relation   string `gorm:"type:text;not null"`
+	Definition string `gorm:"type:text;not null"`
+}
+
+// CollectedBackfillShard is a height range of a backfill, indexed by a worker into its staging schema
+// and then merged into the public tables in the order of the shards
+type CollectedBackfillShard struct {
+	Id         int   `gorm:"type:integer;primaryKey;autoIncrement:false"`
+	FromHeight int64 `gorm:"type:bigint;not null"`
+	ToHeight   int64 `gorm:"type:bigint;not null"`
+	Staged     bool  `gorm:"type:boolean;not null;default:false"`
+	Merged     bool  `gorm:"type:boolean;not null;default:false"`
+}
+
 func (CollectedUpgradeHistory) TableName() string {
 	return "upgrade_history"
 }
@@ -220,6 +361,14 @@ func (CollectedTxTypeTag) TableName() string {
 	return "tx_type_tags"
 }
 
+func (CollectedTxMoveCall) TableName() string {
+	return "tx_move_calls"
+}
+
+func (CollectedTxMsg) TableName() string {
+	return "tx_msg"
+}
+
 func (CollectedEvmTxAccount) TableName() string {
 	return "evm_tx_accounts"
 }
@@ -264,6 +413,10 @@ func (CollectedTypeTagDict) TableName() string {
 	return "type_tag_dict"
 }
 
+func (CollectedMoveFunctionDict) TableName() string {
+	return "move_function_dict"
+}
+
 func (CollectedEvmTxHashDict) TableName() string {
 	return "evm_tx_hash_dict"
 }
@@ -284,6 +437,50 @@ func (CollectedTxAccountCleanupStatus) TableName() string {
 	return "tx_account_cleanup_status"
 }
 
+func (CollectedPruneStatus) TableName() string {
+	return "prune_status"
+}
+
+func (CollectedApiKey) TableName() string {
+	return "api_key"
+}
+
+func (CollectedEventOutbox) TableName() string {
+	return "event_outbox"
+}
+
+func (CollectedEventSinkStatus) TableName() string {
+	return "event_sink_status"
+}
+
+func (CollectedWebhookSubscription) TableName() string {
+	return "webhook_subscription"
+}
+
+func (CollectedWebhookDeadLetter) TableName() string {
+	return "webhook_dead_letter"
+}
+
+func (CollectedWebhookRetry) TableName() string {
+	return "webhook_retry"
+}
+
+func (CollectedWebhookStatus) TableName() string {
+	return "webhook_status"
+}
+
+func (CollectedExtensionCheckpoint) TableName() string {
+	return "extension_checkpoint"
+}
+
+func (CollectedDeferredIndex) TableName() string {
+	return "deferred_index"
+}
+
+func (CollectedBackfillShard) TableName() string {
+	return "backfill_shard"
+}
+
 // CursorRecord interface implementations
 
 // Sequence-based tables
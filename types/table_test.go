@@ -249,6 +249,12 @@ func TestTableNameMethods(t *testing.T) {
 		{"CollectedEvmTxHashDict", CollectedEvmTxHashDict{}, "evm_tx_hash_dict"},
 		{"CollectedRichListStatus", CollectedRichListStatus{}, "rich_list_status"},
 		{"CollectedRichList", CollectedRichList{}, "rich_list"},
+		{"CollectedExtensionCheckpoint", CollectedExtensionCheckpoint{}, "extension_checkpoint"},
+		{"CollectedDeferredIndex", CollectedDeferredIndex{}, "deferred_index"},
+		{"CollectedBackfillShard", CollectedBackfillShard{}, "backfill_shard"},
+		{"CollectedTxMsg", CollectedTxMsg{}, "tx_msg"},
+		{"CollectedMoveFunctionDict", CollectedMoveFunctionDict{}, "move_function_dict"},
+		{"CollectedTxMoveCall", CollectedTxMoveCall{}, "tx_move_calls"},
 	}
 
 	for _, tt := range tests {
@@ -4,6 +4,12 @@ type ValidatorResponse struct {
 	Validator Validator `json:"validator"`
 }
 
+// ValidatorsResponse is a page of the current validator set
+type ValidatorsResponse struct {
+	Validators []Validator `json:"validators"`
+	Pagination Pagination  `json:"pagination"`
+}
+
 type Validator struct {
 	Moniker         string          `json:"moniker"`
 	OperatorAddress string          `json:"operator_address"`
@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"gorm.io/gorm"
@@ -13,6 +15,9 @@ import (
 	"github.com/initia-labs/rollytics/util"
 )
 
+// ValidatorSetTTL bounds the age of the cached validator set
+const ValidatorSetTTL = 30 * time.Second
+
 type NftKey struct {
 	CollectionAddr string
 	TokenId        string
@@ -23,15 +28,48 @@ var (
 	nftCache              *cache.Cache[NftKey, int64]
 	msgTypeCache          *cache.Cache[string, int64]
 	typeTagCache          *cache.Cache[string, int64]
+	moveFunctionCache     *cache.Cache[string, int64]
 	moveDenomCache        *cache.Cache[string, string]
 	evmTxHashCache        *cache.Cache[string, int64]
 	evmDenomContractCache *cache.Cache[string, string]
 	validatorCache        *cache.Cache[string, *types.ValidatorResponse]
 
+	// validatorSet is the current validator set, fetched at validatorSetAt
+	validatorSet    []types.Validator
+	validatorSetAt  time.Time
+	validatorSetMtx sync.Mutex
+
 	// Singleton initialization
 	cacheInitOnce sync.Once
+
+	// dictionaryMtx serializes the dictionary lookups on a dictionary database
+	dictionaryMtx sync.Mutex
 )
 
+// dictionaryDBKey carries the dictionary database in the context of the statements of a transaction
+type dictionaryDBKey struct{}
+
+// WithDictionaryDB makes the dictionary lookups of the transactions started with the context read and
+// create the entries on db instead, outside the transaction and serialized within the process. The
+// cached ids are then committed ones, which concurrent collect transactions, like the backfill
+// workers, can share.
+func WithDictionaryDB(ctx context.Context, db *gorm.DB) context.Context {
+	return context.WithValue(ctx, dictionaryDBKey{}, db)
+}
+
+// dictionaryDB returns the database of the lookups on db, locked until the release is called
+func dictionaryDB(db *gorm.DB) (*gorm.DB, func()) {
+	if db.Statement == nil || db.Statement.Context == nil {
+		return db, func() {}
+	}
+	dictDB, ok := db.Statement.Context.Value(dictionaryDBKey{}).(*gorm.DB)
+	if !ok {
+		return db, func() {}
+	}
+	dictionaryMtx.Lock()
+	return dictDB.WithContext(db.Statement.Context), dictionaryMtx.Unlock
+}
+
 // InitializeCaches initializes all dictionary caches with the given configuration
 // This function is safe to call multiple times - it will only initialize once
 func InitializeCaches(cfg *config.CacheConfig) {
@@ -40,6 +78,7 @@ func InitializeCaches(cfg *config.CacheConfig) {
 		nftCache = cache.New[NftKey, int64](cfg.NftCacheSize)
 		msgTypeCache = cache.New[string, int64](cfg.MsgTypeCacheSize)
 		typeTagCache = cache.New[string, int64](cfg.TypeTagCacheSize)
+		moveFunctionCache = cache.New[string, int64](cfg.MoveFunctionCacheSize)
 		moveDenomCache = cache.New[string, string](cfg.MoveDenomCacheSize)
 		evmTxHashCache = cache.New[string, int64](cfg.EvmTxHashCacheSize)
 		evmDenomContractCache = cache.New[string, string](cfg.EvmDenomContractCacheSize)
@@ -47,6 +86,21 @@ func InitializeCaches(cfg *config.CacheConfig) {
 	})
 }
 
+// PurgeDictionaries drops the cached dictionary ids. The ids are cached as soon as they are created
+// within a collect transaction, so they are dropped when the transaction rolls back and the next
+// lookups read the committed ids back from the database.
+func PurgeDictionaries() {
+	if accountCache == nil {
+		return
+	}
+	accountCache.Purge()
+	nftCache.Purge()
+	msgTypeCache.Purge()
+	typeTagCache.Purge()
+	moveFunctionCache.Purge()
+	evmTxHashCache.Purge()
+}
+
 func normalizeAccountToBech32(account string) (string, error) {
 	accBytes, err := util.AccAddressFromString(account)
 	if err != nil {
@@ -158,6 +212,9 @@ func GetOrCreateAccountIds(db *gorm.DB, accounts []string, createNew bool) (idMa
 		return idMap, nil
 	}
 
+	db, release := dictionaryDB(db)
+	defer release()
+
 	// Fetch existing accounts from database
 	accountIdMap, err := fetchAccountsFromDB(db, uncached)
 	if err != nil {
@@ -274,6 +331,9 @@ func GetOrCreateNftIds(db *gorm.DB, keys []NftKey, createNew bool) (idMap map[Nf
 		return idMap, nil
 	}
 
+	db, release := dictionaryDB(db)
+	defer release()
+
 	// Fetch existing NFTs from database
 	nftIdMap, err := fetchNftsFromDB(db, uncached)
 	if err != nil {
@@ -312,6 +372,9 @@ func GetOrCreateMsgTypeIds(db *gorm.DB, msgTypes []string, createNew bool) (idMa
 		return idMap, nil
 	}
 
+	db, release := dictionaryDB(db)
+	defer release()
+
 	// fetch from db to create msgTypeIdMap
 	var entries []types.CollectedMsgTypeDict
 	if err := db.Where("msg_type IN ?", uncached).Find(&entries).Error; err != nil {
@@ -372,6 +435,9 @@ func GetOrCreateTypeTagIds(db *gorm.DB, typeTags []string, createNew bool) (idMa
 		return idMap, nil
 	}
 
+	db, release := dictionaryDB(db)
+	defer release()
+
 	// fetch from db to create typeTagIdMap
 	var entries []types.CollectedTypeTagDict
 	if err := db.Where("type_tag IN ?", uncached).Find(&entries).Error; err != nil {
@@ -413,6 +479,69 @@ func GetOrCreateTypeTagIds(db *gorm.DB, typeTags []string, createNew bool) (idMa
 	return idMap, nil
 }
 
+//nolint:dupl
+func GetOrCreateMoveFunctionIds(db *gorm.DB, functions []string, createNew bool) (idMap map[string]int64, err error) {
+	idMap = make(map[string]int64, len(functions))
+
+	// check cache and collect uncached
+	var uncached []string
+	for _, function := range functions {
+		id, ok := moveFunctionCache.Get(function)
+		if ok {
+			idMap[function] = id
+		} else {
+			uncached = append(uncached, function)
+		}
+	}
+
+	if len(uncached) == 0 {
+		return idMap, nil
+	}
+
+	db, release := dictionaryDB(db)
+	defer release()
+
+	// fetch from db to create functionIdMap
+	var entries []types.CollectedMoveFunctionDict
+	if err := db.Where("function IN ?", uncached).Find(&entries).Error; err != nil {
+		return idMap, err
+	}
+	functionIdMap := make(map[string]int64) // function -> id
+	for _, entry := range entries {
+		functionIdMap[entry.Function] = entry.Id
+	}
+
+	if createNew {
+		// create new entries if not in DB
+		var newEntries []types.CollectedMoveFunctionDict
+		for _, function := range uncached {
+			if _, ok := functionIdMap[function]; !ok {
+				newEntries = append(newEntries, types.CollectedMoveFunctionDict{Function: function})
+			}
+		}
+
+		if len(newEntries) > 0 {
+			if err := db.Clauses(orm.DoNothingWhenConflict).Create(&newEntries).Error; err != nil {
+				return idMap, err
+			}
+			// Add newly created entries to the map
+			for i, entry := range newEntries {
+				functionIdMap[entry.Function] = newEntries[i].Id
+			}
+		}
+	}
+
+	// set cache and add to result map
+	for _, function := range uncached {
+		if id, ok := functionIdMap[function]; ok {
+			moveFunctionCache.Set(function, id)
+			idMap[function] = id
+		}
+	}
+
+	return idMap, nil
+}
+
 func GetMoveDenomCache(metadataAddr string) (denom string, ok bool) {
 	if denom, ok := moveDenomCache.Get(metadataAddr); ok {
 		return denom, true
@@ -442,6 +571,9 @@ func GetOrCreateEvmTxHashIds(db *gorm.DB, hashes [][]byte, createNew bool) (idMa
 		return idMap, nil
 	}
 
+	db, release := dictionaryDB(db)
+	defer release()
+
 	// fetch from db to create hashIdMap
 	var entries []types.CollectedEvmTxHashDict
 	if err := db.Where("hash IN ?", uncached).Find(&entries).Error; err != nil {
@@ -526,3 +658,22 @@ func SetValidatorCache(validator *types.ValidatorResponse) {
 	}
 	validatorCache.Set(validator.Validator.OperatorAddress, validator)
 }
+
+// GetValidatorSetCache returns the cached validator set while it is not older than ValidatorSetTTL
+func GetValidatorSetCache() ([]types.Validator, bool) {
+	validatorSetMtx.Lock()
+	defer validatorSetMtx.Unlock()
+
+	if validatorSetAt.IsZero() || time.Since(validatorSetAt) > ValidatorSetTTL {
+		return nil, false
+	}
+	return validatorSet, true
+}
+
+func SetValidatorSetCache(validators []types.Validator) {
+	validatorSetMtx.Lock()
+	defer validatorSetMtx.Unlock()
+
+	validatorSet = validators
+	validatorSetAt = time.Now()
+}
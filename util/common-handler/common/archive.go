@@ -0,0 +1,42 @@
+package common
+
+import (
+	"github.com/initia-labs/rollytics/types"
+)
+
+// FindArchivedBlock returns the block of the height from the cold archive, if its rows were
+// dropped from the database. It returns nil when the block is not archived.
+func (h *BaseHandler) FindArchivedBlock(height int64) (*types.CollectedBlock, error) {
+	if h.archive == nil {
+		return nil, nil
+	}
+	droppedHeight, err := h.archive.DroppedHeight()
+	if err != nil || height > droppedHeight {
+		return nil, err
+	}
+	return h.archive.FindBlock(height)
+}
+
+// FindArchivedTx returns the tx of the hash from the cold archive, nil when it is not archived
+func (h *BaseHandler) FindArchivedTx(hash []byte) (*types.CollectedTx, error) {
+	if h.archive == nil {
+		return nil, nil
+	}
+	droppedHeight, err := h.archive.DroppedHeight()
+	if err != nil || droppedHeight == 0 {
+		return nil, err
+	}
+	return h.archive.FindTx(hash)
+}
+
+// FindArchivedEvmTx returns the evm tx of the hash from the cold archive, nil when it is not archived
+func (h *BaseHandler) FindArchivedEvmTx(hash []byte) (*types.CollectedEvmTx, error) {
+	if h.archive == nil {
+		return nil, nil
+	}
+	droppedHeight, err := h.archive.DroppedHeight()
+	if err != nil || droppedHeight == 0 {
+		return nil, err
+	}
+	return h.archive.FindEvmTx(hash)
+}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// MaxBatchSize is the maximum number of keys a single batch lookup request may hold
+const MaxBatchSize = 100
+
+// ParseBatchRequest decodes a JSON batch request body into req and validates that
+// the number of keys it holds, as reported by size, is within MaxBatchSize.
+func ParseBatchRequest(c *fiber.Ctx, req any, field string, size func() int) error {
+	if err := c.BodyParser(req); err != nil {
+		return types.NewBadRequestError(fmt.Sprintf("invalid request body: %v", err))
+	}
+
+	n := size()
+	if n == 0 {
+		return types.NewValidationError(field, "must not be empty")
+	}
+	if n > MaxBatchSize {
+		return types.NewValidationError(field, fmt.Sprintf("must not hold more than %d keys", MaxBatchSize))
+	}
+
+	return nil
+}
@@ -0,0 +1,296 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+
+	MIMETextCSV           = "text/csv"
+	MIMEApplicationNDJSON = "application/x-ndjson"
+)
+
+// ExportColumn is a named value extracted from an exported row
+type ExportColumn[T any] struct {
+	Name  string
+	Value func(row T) any
+}
+
+// ExportPageFunc fetches one page of rows. lastRecord is the raw record the next page starts after.
+type ExportPageFunc[T any] func(tx *gorm.DB, pagination *Pagination) (rows []T, lastRecord any, err error)
+
+// Export describes a streamed export of a list endpoint
+type Export[T any] struct {
+	// Name is the file name of the download, without extension
+	Name string
+	// Columns are the exportable columns in their default order
+	Columns []ExportColumn[T]
+	Fetch   ExportPageFunc[T]
+}
+
+// ParseExportFormat reads the export format from the format query param, falling back to the Accept header.
+// It returns an empty format for regular JSON responses.
+func ParseExportFormat(c *fiber.Ctx) (ExportFormat, error) {
+	switch format := c.Query("format"); format {
+	case "":
+	case "json":
+		return "", nil
+	case string(ExportFormatCSV), string(ExportFormatNDJSON):
+		return ExportFormat(format), nil
+	default:
+		return "", types.NewInvalidValueError("format", format, "must be one of json, csv, ndjson")
+	}
+
+	switch c.Accepts(fiber.MIMEApplicationJSON, MIMETextCSV, MIMEApplicationNDJSON) {
+	case MIMETextCSV:
+		return ExportFormatCSV, nil
+	case MIMEApplicationNDJSON:
+		return ExportFormatNDJSON, nil
+	default:
+		return "", nil
+	}
+}
+
+// IsExportRequest reports whether the request asks for a streamed export.
+// Streamed bodies must bypass the response cache, which would otherwise buffer them whole.
+func IsExportRequest(c *fiber.Ctx) bool {
+	format, err := ParseExportFormat(c)
+	return err == nil && format != ""
+}
+
+// ParseExportColumns picks the columns listed in the columns query param, keeping the requested order.
+// It returns nil when no column is requested.
+func ParseExportColumns[T any](c *fiber.Ctx, available []ExportColumn[T]) ([]ExportColumn[T], error) {
+	raw := strings.TrimSpace(c.Query("columns"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	byName := make(map[string]ExportColumn[T], len(available))
+	names := make([]string, 0, len(available))
+	for _, column := range available {
+		byName[column.Name] = column
+		names = append(names, column.Name)
+	}
+
+	var columns []ExportColumn[T]
+	for name := range strings.SplitSeq(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		column, ok := byName[name]
+		if !ok {
+			return nil, types.NewInvalidValueError("columns", name, "must be one of "+strings.Join(names, ", "))
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+// StreamExport streams every row matching the request in the given format, starting at the request's
+// pagination and following the cursor past MaxLimit, up to the configured maximum number of rows.
+// CSV rows hold the selected columns, or all of them by default. NDJSON lines hold the selected columns,
+// or the full record by default.
+//
+// Rows are read in a repeatable read transaction so that all pages see the same snapshot.
+// Errors after the first byte can no longer change the status, so they are logged and end the stream.
+func StreamExport[T any](c *fiber.Ctx, h *BaseHandler, format ExportFormat, pagination *Pagination, export Export[T]) error {
+	columns, err := ParseExportColumns(c, export.Columns)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if columns == nil && format == ExportFormatCSV {
+		columns = export.Columns
+	}
+
+	maxRows := h.GetConfig().GetExportConfig().GetMaxRows()
+	db := h.GetDatabase().DB
+	logger := h.GetLogger().With("export", export.Name, "format", string(format))
+
+	switch format {
+	case ExportFormatCSV:
+		c.Set(fiber.HeaderContentType, MIMETextCSV+"; charset=utf-8")
+	case ExportFormatNDJSON:
+		c.Set(fiber.HeaderContentType, MIMEApplicationNDJSON)
+	}
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, export.Name, format))
+	c.Set("X-Export-Max-Rows", strconv.Itoa(maxRows))
+
+	page := *pagination
+	page.CountTotal = false
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		tx := db.Begin(&sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+		if tx.Error != nil {
+			logger.Error("failed to begin export transaction", "error", tx.Error)
+			return
+		}
+		defer tx.Rollback()
+
+		enc := newExportEncoder(format, w, columns)
+		if err := enc.writeHeader(); err != nil {
+			return
+		}
+
+		for written := 0; written < maxRows; {
+			page.Limit = min(MaxLimit, maxRows-written)
+
+			rows, lastRecord, err := export.Fetch(tx, &page)
+			if err != nil {
+				logger.Error("failed to fetch export page", "error", err, "written", written)
+				return
+			}
+
+			for _, row := range rows {
+				if err := enc.writeRow(row); err != nil {
+					logger.Error("failed to encode export row", "error", err, "written", written)
+					return
+				}
+			}
+			written += len(rows)
+
+			// a flush error means the client went away
+			if err := enc.flush(); err != nil {
+				logger.Debug("export stream closed", "error", err, "written", written)
+				return
+			}
+
+			if len(rows) < page.Limit {
+				return
+			}
+			page.Advance(lastRecord)
+		}
+	})
+
+	return nil
+}
+
+// LastRecord returns the last record of a page as the cursor record of the next one
+func LastRecord[T any](records []T) any {
+	if len(records) == 0 {
+		return nil
+	}
+	return records[len(records)-1]
+}
+
+type exportEncoder[T any] struct {
+	format  ExportFormat
+	w       *bufio.Writer
+	csv     *csv.Writer
+	columns []ExportColumn[T]
+}
+
+func newExportEncoder[T any](format ExportFormat, w *bufio.Writer, columns []ExportColumn[T]) *exportEncoder[T] {
+	enc := &exportEncoder[T]{format: format, w: w, columns: columns}
+	if format == ExportFormatCSV {
+		enc.csv = csv.NewWriter(w)
+	}
+	return enc
+}
+
+func (e *exportEncoder[T]) writeHeader() error {
+	if e.csv == nil {
+		return nil
+	}
+	names := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		names[i] = column.Name
+	}
+	return e.csv.Write(names)
+}
+
+func (e *exportEncoder[T]) writeRow(row T) error {
+	if e.csv != nil {
+		record := make([]string, len(e.columns))
+		for i, column := range e.columns {
+			record[i] = formatExportValue(column.Value(row))
+		}
+		return e.csv.Write(record)
+	}
+
+	line, err := e.marshalLine(row)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(line); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+// marshalLine encodes the row as a JSON object holding the selected columns in order, or the full row
+func (e *exportEncoder[T]) marshalLine(row T) ([]byte, error) {
+	if e.columns == nil {
+		return json.Marshal(row)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range e.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(column.Value(row))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (e *exportEncoder[T]) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return e.w.Flush()
+}
+
+func formatExportValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case json.RawMessage:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,286 @@
+package common
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/config"
+	"github.com/initia-labs/rollytics/orm/testutil"
+	"github.com/initia-labs/rollytics/types"
+)
+
+type exportRow struct {
+	Sequence int64  `json:"sequence"`
+	Name     string `json:"name"`
+}
+
+var exportRowColumns = []ExportColumn[exportRow]{
+	{Name: "sequence", Value: func(row exportRow) any { return row.Sequence }},
+	{Name: "name", Value: func(row exportRow) any { return row.Name }},
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		accept    string
+		expected  ExportFormat
+		expectErr bool
+	}{
+		{name: "default", expected: ""},
+		{name: "json param", query: "?format=json", accept: MIMETextCSV, expected: ""},
+		{name: "csv param", query: "?format=csv", expected: ExportFormatCSV},
+		{name: "ndjson param", query: "?format=ndjson", expected: ExportFormatNDJSON},
+		{name: "csv accept", accept: MIMETextCSV, expected: ExportFormatCSV},
+		{name: "ndjson accept", accept: MIMEApplicationNDJSON, expected: ExportFormatNDJSON},
+		{name: "any accept", accept: "*/*", expected: ""},
+		{name: "invalid param", query: "?format=xml", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				format, err := ParseExportFormat(c)
+				if tt.expectErr {
+					assert.Error(t, err)
+					return nil
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, format)
+				assert.Equal(t, tt.expected != "", IsExportRequest(c))
+				return nil
+			})
+
+			req := httptest.NewRequest("GET", "/"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+			_, err := app.Test(req)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseExportColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		expected  []string
+		expectErr bool
+	}{
+		{name: "no columns", expected: nil},
+		{name: "requested order", query: "?columns=name,sequence", expected: []string{"name", "sequence"}},
+		{name: "spaces and empty entries", query: "?columns=name,%20,sequence%20", expected: []string{"name", "sequence"}},
+		{name: "unknown column", query: "?columns=name,height", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				columns, err := ParseExportColumns(c, exportRowColumns)
+				if tt.expectErr {
+					assert.Error(t, err)
+					return nil
+				}
+				assert.NoError(t, err)
+
+				var names []string
+				for _, column := range columns {
+					names = append(names, column.Name)
+				}
+				assert.Equal(t, tt.expected, names)
+				return nil
+			})
+
+			_, err := app.Test(httptest.NewRequest("GET", "/"+tt.query, nil))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPaginationAdvance(t *testing.T) {
+	t.Run("cursor", func(t *testing.T) {
+		p := &Pagination{Limit: 10, Offset: 20, Order: OrderDesc, CursorType: CursorTypeSequence}
+		p.Advance(types.CollectedTx{Sequence: 42})
+
+		assert.Equal(t, CursorTypeSequence, p.CursorType)
+		assert.Equal(t, int64(42), p.CursorValue["sequence"])
+		assert.Zero(t, p.Offset)
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		p := &Pagination{Limit: 10, Offset: 20, Order: OrderDesc, CursorType: CursorTypeOffset}
+		p.Advance(nil)
+
+		assert.Equal(t, 30, p.Offset)
+	})
+}
+
+func newExportTestHandler(t *testing.T, maxRows int) *BaseHandler {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db, mock, err := testutil.NewMockDB(logger)
+	require.NoError(t, err)
+	mock.MatchExpectationsInOrder(false)
+	for range 2 {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+
+	cfg := &config.Config{}
+	cfg.SetExportConfig(&config.ExportConfig{MaxRows: maxRows})
+	return NewBaseHandler(db, cfg, logger)
+}
+
+// exportRows serves a sequence cursor over rows with sequences total..1 in descending order
+func exportRows(total int64, limits *[]int) ExportPageFunc[exportRow] {
+	return func(_ *gorm.DB, p *Pagination) ([]exportRow, any, error) {
+		*limits = append(*limits, p.Limit)
+
+		next := total
+		if p.CursorType == CursorTypeSequence && p.CursorValue != nil {
+			sequence, err := p.safeGetInt64("sequence")
+			if err != nil {
+				return nil, nil, err
+			}
+			next = sequence - 1
+		}
+
+		var (
+			rows    []exportRow
+			records []types.CollectedTx
+		)
+		for seq := next; seq > 0 && len(rows) < p.Limit; seq-- {
+			rows = append(rows, exportRow{Sequence: seq, Name: "row"})
+			records = append(records, types.CollectedTx{Sequence: seq})
+		}
+		return rows, LastRecord(records), nil
+	}
+}
+
+func TestStreamExport(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		total          int64
+		maxRows        int
+		expectedRows   int
+		expectedLimits []int
+		expectedHeader string
+		expectedFirst  string
+	}{
+		{
+			name:           "csv follows the cursor past max limit",
+			query:          "?format=csv",
+			total:          2500,
+			maxRows:        10_000,
+			expectedRows:   2500,
+			expectedLimits: []int{MaxLimit, MaxLimit, MaxLimit},
+			expectedHeader: "sequence,name",
+			expectedFirst:  "2500,row",
+		},
+		{
+			name:           "csv stops at max rows",
+			query:          "?format=csv&columns=name,sequence",
+			total:          2500,
+			maxRows:        1200,
+			expectedRows:   1200,
+			expectedLimits: []int{MaxLimit, 200},
+			expectedHeader: "name,sequence",
+			expectedFirst:  "row,2500",
+		},
+		{
+			name:           "ndjson full records",
+			query:          "?format=ndjson",
+			total:          3,
+			maxRows:        10,
+			expectedRows:   3,
+			expectedLimits: []int{10},
+			expectedFirst:  `{"sequence":3,"name":"row"}`,
+		},
+		{
+			name:           "ndjson selected columns",
+			query:          "?format=ndjson&columns=name",
+			total:          3,
+			maxRows:        10,
+			expectedRows:   3,
+			expectedLimits: []int{10},
+			expectedFirst:  `{"name":"row"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newExportTestHandler(t, tt.maxRows)
+
+			var limits []int
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				format, err := ParseExportFormat(c)
+				require.NoError(t, err)
+				pagination, err := ParsePagination(c, CursorTypeSequence)
+				require.NoError(t, err)
+
+				return StreamExport(c, h, format, pagination, Export[exportRow]{
+					Name:    "rows",
+					Columns: exportRowColumns,
+					Fetch:   exportRows(tt.total, &limits),
+				})
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/"+tt.query, nil), -1)
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			var lines []string
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+			}
+			require.NoError(t, scanner.Err())
+
+			if tt.expectedHeader != "" {
+				assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), MIMETextCSV)
+				require.NotEmpty(t, lines)
+				assert.Equal(t, tt.expectedHeader, lines[0])
+				lines = lines[1:]
+			} else {
+				assert.Equal(t, MIMEApplicationNDJSON, resp.Header.Get(fiber.HeaderContentType))
+			}
+
+			assert.True(t, strings.HasPrefix(resp.Header.Get(fiber.HeaderContentDisposition), "attachment"))
+			assert.Len(t, lines, tt.expectedRows)
+			assert.Equal(t, tt.expectedFirst, lines[0])
+			assert.Equal(t, tt.expectedLimits, limits)
+		})
+	}
+}
+
+func TestStreamExport_InvalidColumns(t *testing.T) {
+	h := newExportTestHandler(t, 10)
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return StreamExport(c, h, ExportFormatCSV, &Pagination{Limit: DefaultLimit, Order: OrderDesc}, Export[exportRow]{
+			Name:    "rows",
+			Columns: exportRowColumns,
+			Fetch:   exportRows(1, new([]int)),
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/?columns=unknown", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
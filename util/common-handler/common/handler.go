@@ -7,6 +7,7 @@ import (
 
 	"github.com/initia-labs/rollytics/config"
 	"github.com/initia-labs/rollytics/orm"
+	"github.com/initia-labs/rollytics/orm/archive"
 	"github.com/initia-labs/rollytics/types"
 	"github.com/initia-labs/rollytics/util/cache"
 )
@@ -16,17 +17,22 @@ type HandlerRegistrar interface {
 }
 
 type BaseHandler struct {
-	db     *orm.Database
-	cfg    *config.Config
-	logger *slog.Logger
+	db      *orm.Database
+	cfg     *config.Config
+	logger  *slog.Logger
+	archive *archive.Store // nil when the cold archive is disabled
 }
 
 func NewBaseHandler(db *orm.Database, cfg *config.Config, logger *slog.Logger) *BaseHandler {
-	return &BaseHandler{
+	h := &BaseHandler{
 		db:     db,
 		cfg:    cfg,
 		logger: logger,
 	}
+	if cfg != nil && cfg.ArchiveEnabled() {
+		h.archive = archive.NewStore(cfg.GetArchiveConfig().Path)
+	}
+	return h
 }
 
 func (h *BaseHandler) GetDatabase() *orm.Database { return h.db }
@@ -74,6 +80,21 @@ func (h *BaseHandler) GetMsgTypeIds(msgs []string) ([]int64, error) {
 	return ids, nil
 }
 
+func (h *BaseHandler) GetMoveFunctionIds(functions []string) ([]int64, error) {
+	idMap, err := cache.GetOrCreateMoveFunctionIds(h.db.DB, functions, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, function := range functions {
+		if id, ok := idMap[function]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
 func (h *BaseHandler) GetNftIds(keys []cache.NftKey) ([]int64, error) {
 	idMap, err := cache.GetOrCreateNftIds(h.db.DB, keys, false)
 	if err != nil {
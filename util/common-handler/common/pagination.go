@@ -61,6 +61,8 @@ type Pagination struct {
 	CountTotal  bool
 	CursorType  CursorType
 	CursorValue map[string]any
+	// Range optionally bounds the cursor key on top of the cursor itself (e.g. from a time range)
+	Range *KeyRange
 }
 
 // UseCursor determines whether cursor-based pagination is enabled
@@ -256,6 +258,23 @@ func (p *Pagination) ToResponseWithLastRecord(total int64, hasMore bool, lastRec
 	return p.ToResponse(total, hasMore)
 }
 
+// Advance moves the pagination to the page right after lastRecord, the last record of the current page.
+// Cursor pagination continues from the record's cursor, offset pagination skips the current page.
+func (p *Pagination) Advance(lastRecord any) {
+	if p.UseCursor() {
+		if r, ok := lastRecord.(CursorRecord); ok {
+			if nextCursor := r.GetCursorData(); len(nextCursor) > 0 {
+				p.CursorValue = nextCursor
+				p.CursorType = detectCursorType(nextCursor)
+				p.Offset = 0
+				return
+			}
+		}
+	}
+
+	p.Offset += p.Limit
+}
+
 func (p *Pagination) ApplyToNftCollection(query *gorm.DB) *gorm.DB {
 	switch p.CursorType {
 	case CursorTypeHeight:
@@ -333,7 +352,32 @@ func (p *Pagination) ApplyToNft(query *gorm.DB, orderBy string) *gorm.DB {
 	}
 }
 
+// HasRange reports whether the cursor key is bounded by Range
+func (p *Pagination) HasRange() bool {
+	return p.Range != nil
+}
+
+// ApplyRange bounds the given column by Range, qualified by its table when the query joins several
+// tables having one. ApplyToBlock and ApplySequence already apply it, so it is only needed for queries
+// that are not paginated, such as count queries.
+func (p *Pagination) ApplyRange(query *gorm.DB, column string) *gorm.DB {
+	if p.Range == nil {
+		return query
+	}
+	if p.Range.Empty {
+		return query.Where("FALSE")
+	}
+	if p.Range.From != 0 {
+		query = query.Where(column+" >= ?", p.Range.From)
+	}
+	if p.Range.To != 0 {
+		query = query.Where(column+" <= ?", p.Range.To)
+	}
+	return query
+}
+
 func (p *Pagination) ApplyToBlock(query *gorm.DB) *gorm.DB {
+	query = p.ApplyRange(query, "height")
 	switch p.CursorType {
 	case CursorTypeHeight:
 		height, err := p.safeGetInt64("height")
@@ -357,22 +401,29 @@ func (p *Pagination) ApplyToBlock(query *gorm.DB) *gorm.DB {
 
 // ApplySequence applies sequence-based pagination to the given GORM query
 func (p *Pagination) ApplySequence(query *gorm.DB) *gorm.DB {
+	return p.ApplySequenceOf(query, "sequence")
+}
+
+// ApplySequenceOf applies sequence-based pagination on the given sequence column, qualified by its
+// table when the query joins several tables having one
+func (p *Pagination) ApplySequenceOf(query *gorm.DB, column string) *gorm.DB {
+	query = p.ApplyRange(query, column)
 	switch p.CursorType {
 	case CursorTypeSequence:
 		sequence, err := p.safeGetInt64("sequence")
 		if err != nil {
-			return query.Order(p.OrderBy("sequence")).Offset(p.Offset).Limit(p.Limit)
+			return query.Order(p.OrderBy(column)).Offset(p.Offset).Limit(p.Limit)
 		}
 		if p.Order == OrderDesc {
-			query = query.Where("sequence < ?", sequence)
+			query = query.Where(column+" < ?", sequence)
 		} else {
-			query = query.Where("sequence > ?", sequence)
+			query = query.Where(column+" > ?", sequence)
 		}
-		return query.Order(p.OrderBy("sequence")).Limit(p.Limit)
+		return query.Order(p.OrderBy(column)).Limit(p.Limit)
 
 	case CursorTypeOffset:
 		fallthrough
 	default:
-		return query.Order(p.OrderBy("sequence")).Offset(p.Offset).Limit(p.Limit)
+		return query.Order(p.OrderBy(column)).Offset(p.Offset).Limit(p.Limit)
 	}
 }
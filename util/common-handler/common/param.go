@@ -56,7 +56,7 @@ func GetCollectionAddrParam(c *fiber.Ctx, config *config.ChainConfig) ([]byte, e
 		return nil, err
 	}
 
-	return normalizeCollectionAddr(collectionAddr)
+	return NormalizeCollectionAddr(collectionAddr)
 }
 
 func GetMsgsQuery(c *fiber.Ctx) (msgs []string) {
@@ -73,5 +73,43 @@ func GetCollectionAddrQuery(c *fiber.Ctx, config *config.ChainConfig) ([]byte, e
 		return nil, nil
 	}
 
-	return normalizeCollectionAddr(collectionAddr)
+	return NormalizeCollectionAddr(collectionAddr)
+}
+
+// GetHeightQuery parses an optional height query parameter. It returns 0 when the parameter is absent.
+func GetHeightQuery(c *fiber.Ctx, key string) (int64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	intValue, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, types.NewInvalidValueError(key, value, "must be a valid integer")
+	}
+
+	if intValue < 1 {
+		return 0, types.NewInvalidValueError(key, value, "must be a positive integer")
+	}
+
+	return intValue, nil
+}
+
+// ResolveHeightRange fills in the defaults of an optional [fromHeight, toHeight] range and validates it.
+// toHeight must already be resolved to the latest height when it was not given; fromHeight defaults
+// to a window of defaultWindow blocks ending at toHeight.
+func ResolveHeightRange(fromHeight, toHeight, defaultWindow, maxWindow int64) (int64, int64, error) {
+	if toHeight == 0 {
+		return 0, 0, nil
+	}
+	if fromHeight == 0 {
+		fromHeight = max(1, toHeight-defaultWindow+1)
+	}
+	if fromHeight > toHeight {
+		return 0, 0, types.NewInvalidValueError("from_height", fmt.Sprintf("%d", fromHeight), "must not be greater than to_height")
+	}
+	if toHeight-fromHeight+1 > maxWindow {
+		return 0, 0, fmt.Errorf("height window must not exceed %d blocks", maxWindow)
+	}
+	return fromHeight, toHeight, nil
 }
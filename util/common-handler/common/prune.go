@@ -0,0 +1,61 @@
+package common
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// GetPrunedHeight returns the height up to which the txs were deleted by the pruning mode, 0 if none were
+func (h *BaseHandler) GetPrunedHeight(tx *gorm.DB) (int64, error) {
+	if !h.cfg.PruneEnabled() {
+		return 0, nil
+	}
+
+	var status types.CollectedPruneStatus
+	if err := tx.Model(&types.CollectedPruneStatus{}).First(&status).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return status.PrunedHeight, nil
+}
+
+// CheckNotPruned returns a 410 Gone error if the txs of the height were deleted by the pruning mode
+func (h *BaseHandler) CheckNotPruned(tx *gorm.DB, height int64, resource string) error {
+	prunedHeight, err := h.GetPrunedHeight(tx)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get prune status", err).Error())
+	}
+	if height <= prunedHeight {
+		return fiber.NewError(fiber.StatusGone, types.NewPrunedError(resource, prunedHeight+1).Error())
+	}
+	return nil
+}
+
+// CheckMissingNotPruned returns a 410 Gone error for a resource missing from the database once the
+// pruning mode deleted txs, since it may have been deleted with them
+func (h *BaseHandler) CheckMissingNotPruned(tx *gorm.DB, resource string) error {
+	prunedHeight, err := h.GetPrunedHeight(tx)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get prune status", err).Error())
+	}
+	if prunedHeight > 0 {
+		return fiber.NewError(fiber.StatusGone, types.NewPrunedError(resource, prunedHeight+1).Error())
+	}
+	return nil
+}
+
+// ListPrunedHeight returns the pruned height reported along a list, whose records of heights up to it
+// are missing, 0 if none were deleted
+func (h *BaseHandler) ListPrunedHeight(tx *gorm.DB) (int64, error) {
+	prunedHeight, err := h.GetPrunedHeight(tx)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusInternalServerError, types.NewDatabaseError("get prune status", err).Error())
+	}
+	return prunedHeight, nil
+}
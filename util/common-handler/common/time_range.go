@@ -0,0 +1,145 @@
+package common
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// TimeRange is an optional [from_time, to_time] filter. A zero bound is open.
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// KeyRange bounds the cursor key (sequence or height) of a paginated query, both ends inclusive.
+// A zero bound is open. Empty marks a range that matches no record at all.
+type KeyRange struct {
+	From  int64
+	To    int64
+	Empty bool
+}
+
+func ParseTimeRange(c *fiber.Ctx) (TimeRange, error) {
+	var tr TimeRange
+	for _, param := range []struct {
+		key    string
+		target *time.Time
+	}{
+		{"from_time", &tr.From},
+		{"to_time", &tr.To},
+	} {
+		value := c.Query(param.key)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return tr, types.NewInvalidValueError(param.key, value, "must be a RFC3339 timestamp")
+		}
+		*param.target = t
+	}
+
+	if !tr.From.IsZero() && !tr.To.IsZero() && tr.From.After(tr.To) {
+		return tr, types.NewInvalidValueError("from_time", tr.From.Format(time.RFC3339), "must not be after to_time")
+	}
+
+	return tr, nil
+}
+
+func (tr TimeRange) IsEmpty() bool {
+	return tr.From.IsZero() && tr.To.IsZero()
+}
+
+// GetHeightRangeByTime translates a time range into block height bounds. Both bounds are looked up
+// in a single round trip, each one being an index search on block_timestamp_desc.
+// It returns nil when the time range is empty.
+func GetHeightRangeByTime(tx *gorm.DB, chainId string, tr TimeRange) (*KeyRange, error) {
+	if tr.IsEmpty() {
+		return nil, nil
+	}
+
+	var (
+		selects []string
+		args    []any
+	)
+	if !tr.From.IsZero() {
+		selects = append(selects, "(SELECT height FROM block WHERE chain_id = ? AND timestamp >= ? ORDER BY timestamp ASC LIMIT 1) AS from_key")
+		args = append(args, chainId, tr.From)
+	}
+	if !tr.To.IsZero() {
+		selects = append(selects, "(SELECT height FROM block WHERE chain_id = ? AND timestamp <= ? ORDER BY timestamp DESC LIMIT 1) AS to_key")
+		args = append(args, chainId, tr.To)
+	}
+
+	return scanKeyRange(tx, "SELECT "+strings.Join(selects, ", "), args, tr.From.IsZero(), tr.To.IsZero())
+}
+
+// GetSequenceRangeByHeight translates block height bounds into the sequence bounds of the given table.
+// Sequences grow with heights, so the bounds are the first sequence of the first height at or after
+// the lower bound and the last sequence of the last height at or before the upper bound.
+// It returns nil when the height range is nil.
+func GetSequenceRangeByHeight(tx *gorm.DB, table string, hr *KeyRange) (*KeyRange, error) {
+	if hr == nil {
+		return nil, nil
+	}
+	if hr.Empty {
+		return hr, nil
+	}
+
+	var (
+		selects []string
+		args    []any
+	)
+	if hr.From != 0 {
+		selects = append(selects, fmt.Sprintf("(SELECT MIN(sequence) FROM %[1]s WHERE height = (SELECT MIN(height) FROM %[1]s WHERE height >= ?)) AS from_key", table))
+		args = append(args, hr.From)
+	}
+	if hr.To != 0 {
+		selects = append(selects, fmt.Sprintf("(SELECT MAX(sequence) FROM %[1]s WHERE height = (SELECT MAX(height) FROM %[1]s WHERE height <= ?)) AS to_key", table))
+		args = append(args, hr.To)
+	}
+
+	return scanKeyRange(tx, "SELECT "+strings.Join(selects, ", "), args, hr.From == 0, hr.To == 0)
+}
+
+func scanKeyRange(tx *gorm.DB, query string, args []any, openFrom, openTo bool) (*KeyRange, error) {
+	var res struct {
+		FromKey sql.NullInt64
+		ToKey   sql.NullInt64
+	}
+	if err := tx.Raw(query, args...).Scan(&res).Error; err != nil {
+		return nil, types.NewDatabaseError("resolve time range", err)
+	}
+
+	// a bound without any matching record leaves nothing in the range
+	if (!openFrom && !res.FromKey.Valid) || (!openTo && !res.ToKey.Valid) {
+		return &KeyRange{Empty: true}, nil
+	}
+
+	kr := &KeyRange{From: res.FromKey.Int64, To: res.ToKey.Int64}
+	if !openFrom && !openTo && kr.From > kr.To {
+		kr.Empty = true
+	}
+	return kr, nil
+}
+
+// GetHeightRange resolves a time range into block height bounds of the handler's chain
+func (h *BaseHandler) GetHeightRange(tx *gorm.DB, tr TimeRange) (*KeyRange, error) {
+	return GetHeightRangeByTime(tx, h.GetChainId(), tr)
+}
+
+// GetSequenceRange resolves a time range into the sequence bounds of the given table
+func (h *BaseHandler) GetSequenceRange(tx *gorm.DB, tr TimeRange, table string) (*KeyRange, error) {
+	hr, err := h.GetHeightRange(tx, tr)
+	if err != nil {
+		return nil, err
+	}
+	return GetSequenceRangeByHeight(tx, table, hr)
+}
@@ -0,0 +1,160 @@
+package common
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockGormDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+
+	return gormDB, mock
+}
+
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		expectErr bool
+		expected  TimeRange
+	}{
+		{name: "no params", query: ""},
+		{
+			name:     "both bounds",
+			query:    "?from_time=2025-01-01T00:00:00Z&to_time=2025-01-02T00:00:00Z",
+			expected: TimeRange{From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "lower bound only",
+			query:    "?from_time=2025-01-01T00:00:00Z",
+			expected: TimeRange{From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{name: "invalid format", query: "?to_time=2025-01-01", expectErr: true},
+		{name: "inverted range", query: "?from_time=2025-01-02T00:00:00Z&to_time=2025-01-01T00:00:00Z", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				tr, err := ParseTimeRange(c)
+				if tt.expectErr {
+					assert.Error(t, err)
+					return nil
+				}
+				assert.NoError(t, err)
+				assert.True(t, tt.expected.From.Equal(tr.From))
+				assert.True(t, tt.expected.To.Equal(tr.To))
+				return nil
+			})
+
+			_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/"+tt.query, nil))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGetHeightRangeByTime(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("empty time range skips the lookup", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		hr, err := GetHeightRangeByTime(db, "test-chain", TimeRange{})
+		assert.NoError(t, err)
+		assert.Nil(t, hr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("both bounds in a single query", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectQuery(`SELECT \(SELECT height FROM block WHERE chain_id = \$1 AND timestamp >= \$2 ORDER BY timestamp ASC LIMIT 1\) AS from_key, \(SELECT height FROM block WHERE chain_id = \$3 AND timestamp <= \$4 ORDER BY timestamp DESC LIMIT 1\) AS to_key`).
+			WithArgs("test-chain", from, "test-chain", to).
+			WillReturnRows(sqlmock.NewRows([]string{"from_key", "to_key"}).AddRow(100, 200))
+
+		hr, err := GetHeightRangeByTime(db, "test-chain", TimeRange{From: from, To: to})
+		assert.NoError(t, err)
+		assert.Equal(t, &KeyRange{From: 100, To: 200}, hr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no block after from_time", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectQuery(`SELECT \(SELECT height FROM block`).
+			WithArgs("test-chain", from).
+			WillReturnRows(sqlmock.NewRows([]string{"from_key"}).AddRow(nil))
+
+		hr, err := GetHeightRangeByTime(db, "test-chain", TimeRange{From: from})
+		assert.NoError(t, err)
+		assert.True(t, hr.Empty)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetSequenceRangeByHeight(t *testing.T) {
+	t.Run("empty height range", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		sr, err := GetSequenceRangeByHeight(db, "tx", &KeyRange{Empty: true})
+		assert.NoError(t, err)
+		assert.True(t, sr.Empty)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("upper bound only", func(t *testing.T) {
+		db, mock := newMockGormDB(t)
+		mock.ExpectQuery(`SELECT \(SELECT MAX\(sequence\) FROM tx WHERE height = \(SELECT MAX\(height\) FROM tx WHERE height <= \$1\)\) AS to_key`).
+			WithArgs(int64(200)).
+			WillReturnRows(sqlmock.NewRows([]string{"to_key"}).AddRow(5000))
+
+		sr, err := GetSequenceRangeByHeight(db, "tx", &KeyRange{To: 200})
+		assert.NoError(t, err)
+		assert.Equal(t, &KeyRange{To: 5000}, sr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPagination_ApplyRange(t *testing.T) {
+	db, _ := newMockGormDB(t)
+	dryRun := db.Session(&gorm.Session{DryRun: true})
+
+	tests := []struct {
+		name     string
+		keyRange *KeyRange
+		expected string
+	}{
+		{name: "no range", expected: `SELECT * FROM "tx" ORDER BY sequence DESC LIMIT $1`},
+		{name: "bounded range", keyRange: &KeyRange{From: 10, To: 20}, expected: `SELECT * FROM "tx" WHERE sequence >= $1 AND sequence <= $2 AND sequence < $3 ORDER BY sequence DESC LIMIT $4`},
+		{name: "empty range", keyRange: &KeyRange{Empty: true}, expected: `SELECT * FROM "tx" WHERE FALSE AND sequence < $1 ORDER BY sequence DESC LIMIT $2`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pagination := &Pagination{
+				Limit:      10,
+				Order:      OrderDesc,
+				CursorType: CursorTypeSequence,
+				Range:      tt.keyRange,
+			}
+			if tt.keyRange != nil {
+				pagination.CursorValue = map[string]any{"sequence": int64(15)}
+			}
+
+			stmt := pagination.ApplySequence(dryRun.Table("tx")).Find(&[]map[string]any{}).Statement
+			assert.Equal(t, tt.expected, stmt.SQL.String())
+		})
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"github.com/initia-labs/rollytics/util"
 )
 
-// normalizeCollectionAddr parses collection address (supports both hex and bech32)
-func normalizeCollectionAddr(collectionAddr string) ([]byte, error) {
+// NormalizeCollectionAddr parses collection address (supports both hex and bech32)
+func NormalizeCollectionAddr(collectionAddr string) ([]byte, error) {
 	accAddr, err := util.AccAddressFromString(collectionAddr)
 	if err != nil {
 		return nil, err
@@ -11,16 +11,22 @@ import (
 
 type StatusHandler struct {
 	*common.BaseHandler
+	extensions []string // enabled extensions listed with their checkpoints
+	cache      *cache.Cache
 }
 
 var _ common.HandlerRegistrar = (*StatusHandler)(nil)
 
-func NewStatusHandler(base *common.BaseHandler) *StatusHandler {
-	return &StatusHandler{BaseHandler: base}
+func NewStatusHandler(base *common.BaseHandler, apiCache *cache.Cache) *StatusHandler {
+	h := &StatusHandler{BaseHandler: base, cache: apiCache}
+	if cfg := base.GetConfig(); cfg != nil {
+		h.extensions = cfg.EnabledExtensions()
+	}
+	return h
 }
 
 func (h *StatusHandler) Register(router fiber.Router) {
 	status := router.Group("/status")
 
-	status.Get("/", cache.WithExpiration(time.Second), h.GetStatus)
+	status.Get("/", h.cache.WithExpiration(time.Second), h.GetStatus)
 }
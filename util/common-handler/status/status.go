@@ -4,11 +4,14 @@ import (
 	"database/sql"
 	"errors"
 	"sync/atomic"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 
 	"github.com/initia-labs/rollytics/config"
+	exttypes "github.com/initia-labs/rollytics/indexer/extension/types"
+	"github.com/initia-labs/rollytics/indexer/leader"
 	"github.com/initia-labs/rollytics/types"
 )
 
@@ -17,6 +20,8 @@ var (
 	lastRichListHeight         atomic.Int64
 	lastEvmRetCleanupHeight    atomic.Int64
 	lastTxAccountCleanupStatus atomic.Pointer[types.CollectedTxAccountCleanupStatus]
+	lastPrunedHeight           atomic.Int64
+	lastEventSinkHeight        atomic.Int64
 )
 
 // status handles GET /status
@@ -78,6 +83,29 @@ func (h *StatusHandler) GetStatus(c *fiber.Ctx) error {
 		txAccountCleanupStatus = *status
 	}
 
+	var pruneStatus types.CollectedPruneStatus
+	if h.GetConfig().PruneEnabled() {
+		status, err := h.getPruneStatus(tx)
+		if err != nil {
+			return err
+		}
+		pruneStatus = *status
+	}
+
+	var eventSinkHeight int64
+	if h.GetConfig().EventSinkEnabled() {
+		height, err := h.getEventSinkHeight(tx)
+		if err != nil {
+			return err
+		}
+		eventSinkHeight = height
+	}
+
+	extensions, err := h.getExtensionStatuses(tx)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(&StatusResponse{
 		Version:                  config.Version,
 		CommitHash:               config.CommitHash,
@@ -89,6 +117,12 @@ func (h *StatusHandler) GetStatus(c *fiber.Ctx) error {
 		TxAccountCleanupSequence: txAccountCleanupStatus.LastCleanedSequence,
 		TxAccountCleanupDeleted:  txAccountCleanupStatus.DeletedRecords,
 		TxAccountCleanupInserted: txAccountCleanupStatus.InsertedRecords,
+		PrunedHeight:             pruneStatus.PrunedHeight,
+		PruneDeleted:             pruneStatus.DeletedRecords,
+		IndexerRole:              string(leader.RoleOf(leader.WorkloadIndexer)),
+		ExtensionsRole:           string(leader.RoleOf(leader.WorkloadExtensions)),
+		EventSinkHeight:          eventSinkHeight,
+		Extensions:               extensions,
 	})
 }
 
@@ -205,3 +239,70 @@ func (h *StatusHandler) getEvmRetCleanupStatus(tx *gorm.DB) (int64, error) {
 
 	return height, nil
 }
+
+func (h *StatusHandler) getPruneStatus(tx *gorm.DB) (*types.CollectedPruneStatus, error) {
+	var pruneStatus types.CollectedPruneStatus
+	err := tx.Model(&types.CollectedPruneStatus{}).First(&pruneStatus).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	prunedHeight := lastPrunedHeight.Load()
+	if pruneStatus.PrunedHeight > prunedHeight {
+		lastPrunedHeight.CompareAndSwap(prunedHeight, pruneStatus.PrunedHeight)
+	} else {
+		pruneStatus.PrunedHeight = prunedHeight
+	}
+
+	return &pruneStatus, nil
+}
+
+func (h *StatusHandler) getEventSinkHeight(tx *gorm.DB) (int64, error) {
+	height := lastEventSinkHeight.Load()
+
+	var sinkStatus types.CollectedEventSinkStatus
+	err := tx.Model(&types.CollectedEventSinkStatus{}).First(&sinkStatus).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	if sinkStatus.LastHeight > height {
+		if lastEventSinkHeight.CompareAndSwap(height, sinkStatus.LastHeight) {
+			height = sinkStatus.LastHeight
+		} else {
+			height = lastEventSinkHeight.Load()
+		}
+	}
+
+	return height, nil
+}
+
+// getExtensionStatuses returns the checkpoints of the enabled extensions in their run order
+func (h *StatusHandler) getExtensionStatuses(tx *gorm.DB) ([]ExtensionStatus, error) {
+	if len(h.extensions) == 0 {
+		return nil, nil
+	}
+
+	checkpoints, err := exttypes.LoadCheckpoints(tx, h.extensions)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	statuses := make([]ExtensionStatus, 0, len(h.extensions))
+	for _, name := range h.extensions {
+		status := ExtensionStatus{Name: name}
+		if cp, ok := checkpoints[name]; ok {
+			status.Height = cp.Height
+			status.Sequence = cp.Sequence
+			status.UpdatedAt = cp.UpdatedAt.Format(time.RFC3339)
+			status.Meta = cp.Meta
+		}
+		if health, ok := types.ExtensionHealthOf(name); ok {
+			status.State = health.State
+			status.Restarts = health.Restarts
+			status.LastError = health.LastError
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -54,7 +54,7 @@ func setup(t *testing.T) (*StatusHandler, sqlmock.Sqlmock, *config.Config) {
 
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	baseHandler := common.NewBaseHandler(dbWrapper, cfg, logger)
-	statusHandler := NewStatusHandler(baseHandler)
+	statusHandler := NewStatusHandler(baseHandler, nil)
 
 	return statusHandler, mock, cfg
 }
@@ -199,7 +199,7 @@ func TestGetStatus(t *testing.T) {
 
 		app := fiber.New()
 		// Apply the cache middleware with sub-second expiration
-		app.Get("/status", cache.WithExpiration(250*time.Millisecond), h.GetStatus)
+		app.Get("/status", (*cache.Cache)(nil).WithExpiration(250*time.Millisecond), h.GetStatus)
 
 		// 1. First request - should be a cache miss and hit the DB
 		mock.ExpectBegin()
@@ -488,3 +488,42 @@ func TestGetStatus(t *testing.T) {
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestGetStatus_Extensions(t *testing.T) {
+	h, mock, cfg := setup(t)
+	defer lastRichListHeight.Store(0)
+
+	cfg.GetRichListConfig().Enabled = true
+	cfg.GetChainConfig().VmType = types.MoveVM
+	// the enabled extensions are listed once the handler is created
+	h = NewStatusHandler(h.BaseHandler, nil)
+	require.Equal(t, []string{"rich-list"}, h.extensions)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT .* FROM "block"`).WillReturnRows(sqlmock.NewRows([]string{"height"}).AddRow(100))
+	mock.ExpectQuery(`SELECT .* FROM "rich_list_status"`).WillReturnRows(sqlmock.NewRows([]string{"height"}).AddRow(95))
+	updatedAt := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT \* FROM "extension_checkpoint" WHERE name IN`).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "height", "sequence", "updated_at", "meta"}).
+			AddRow("rich-list", 95, 0, updatedAt, nil))
+	mock.ExpectRollback()
+
+	app := fiber.New()
+	app.Get("/status", h.GetStatus)
+
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", "/status", nil)
+	resp, err := app.Test(req)
+	defer closeBody(resp)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body StatusResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, []ExtensionStatus{{
+		Name:      "rich-list",
+		Height:    95,
+		UpdatedAt: "2026-10-18T00:00:00Z",
+	}}, body.Extensions)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
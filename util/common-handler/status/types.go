@@ -1,14 +1,35 @@
 package status
 
+import "encoding/json"
+
 type StatusResponse struct {
-	Version                  string `json:"version" extensions:"x-order:0"`
-	CommitHash               string `json:"commit_hash" extensions:"x-order:1"`
-	ChainId                  string `json:"chain_id" extensions:"x-order:2"`
-	Height                   int64  `json:"height" extensions:"x-order:3"`
-	InternalTxHeight         int64  `json:"internal_tx_height,omitempty" extensions:"x-order:4"`
-	RichListHeight           int64  `json:"rich_list_height,omitempty" extensions:"x-order:5"`
-	EvmRetCleanupHeight      int64  `json:"evm_ret_cleanup_height,omitempty" extensions:"x-order:6"`
-	TxAccountCleanupSequence int64  `json:"tx_account_cleanup_sequence,omitempty" extensions:"x-order:7"`
-	TxAccountCleanupDeleted  int64  `json:"tx_account_cleanup_deleted,omitempty" extensions:"x-order:8"`
-	TxAccountCleanupInserted int64  `json:"tx_account_cleanup_inserted,omitempty" extensions:"x-order:9"`
+	Version                  string            `json:"version" extensions:"x-order:0"`
+	CommitHash               string            `json:"commit_hash" extensions:"x-order:1"`
+	ChainId                  string            `json:"chain_id" extensions:"x-order:2"`
+	Height                   int64             `json:"height" extensions:"x-order:3"`
+	InternalTxHeight         int64             `json:"internal_tx_height,omitempty" extensions:"x-order:4"`
+	RichListHeight           int64             `json:"rich_list_height,omitempty" extensions:"x-order:5"`
+	EvmRetCleanupHeight      int64             `json:"evm_ret_cleanup_height,omitempty" extensions:"x-order:6"`
+	TxAccountCleanupSequence int64             `json:"tx_account_cleanup_sequence,omitempty" extensions:"x-order:7"`
+	TxAccountCleanupDeleted  int64             `json:"tx_account_cleanup_deleted,omitempty" extensions:"x-order:8"`
+	TxAccountCleanupInserted int64             `json:"tx_account_cleanup_inserted,omitempty" extensions:"x-order:9"`
+	PrunedHeight             int64             `json:"pruned_height,omitempty" extensions:"x-order:10"`
+	PruneDeleted             int64             `json:"prune_deleted,omitempty" extensions:"x-order:11"`
+	IndexerRole              string            `json:"indexer_role,omitempty" extensions:"x-order:12"`
+	ExtensionsRole           string            `json:"extensions_role,omitempty" extensions:"x-order:13"`
+	EventSinkHeight          int64             `json:"event_sink_height,omitempty" extensions:"x-order:14"`
+	Extensions               []ExtensionStatus `json:"extensions,omitempty" extensions:"x-order:15"`
+}
+
+// ExtensionStatus is the checkpoint of an enabled extension, empty until the extension saves one.
+// The supervision state is only reported by the indexer process running the extension.
+type ExtensionStatus struct {
+	Name      string          `json:"name" extensions:"x-order:0"`
+	Height    int64           `json:"height" extensions:"x-order:1"`
+	Sequence  int64           `json:"sequence,omitempty" extensions:"x-order:2"`
+	UpdatedAt string          `json:"updated_at,omitempty" extensions:"x-order:3"`
+	Meta      json.RawMessage `json:"meta,omitempty" swaggertype:"object" extensions:"x-order:4"`
+	State     string          `json:"state,omitempty" enums:"waiting,running,restarting,paused,degraded,completed,stopped" extensions:"x-order:5"`
+	Restarts  int             `json:"restarts,omitempty" extensions:"x-order:6"`
+	LastError string          `json:"last_error,omitempty" extensions:"x-order:7"`
 }
@@ -25,6 +25,7 @@ const (
 	cosmosBalancesPath       = "/cosmos/bank/v1beta1/balances/%s"
 	cosmosAccountsPath       = "/cosmos/auth/v1beta1/accounts"
 	cosmosNodeInfoPath       = "/cosmos/base/tendermint/v1beta1/node_info"
+	opchildValidatorsPath    = "/opinit/opchild/v1/validators"
 )
 
 // handlePaginationNextKey handles pagination logic for broken APIs that return null next_key prematurely.
@@ -167,6 +168,53 @@ func (q *Querier) GetValidator(ctx context.Context, validatorAddr string) (*type
 	return res, nil
 }
 
+func fetchValidators(useOffset bool, nextKey []byte) func(ctx context.Context, endpointURL string) (*types.ValidatorsResponse, error) {
+	return func(ctx context.Context, endpointURL string) (*types.ValidatorsResponse, error) {
+		params := map[string]string{"pagination.limit": paginationLimit}
+		if useOffset {
+			params["pagination.offset"] = "0"
+		} else if len(nextKey) > 0 {
+			params["pagination.key"] = base64.StdEncoding.EncodeToString(nextKey)
+		}
+		body, err := Get(ctx, endpointURL, opchildValidatorsPath, params, nil, queryTimeout)
+		if err != nil {
+			return nil, err
+		}
+		response, err := extractResponse[types.ValidatorsResponse](body)
+		if err != nil {
+			return nil, err
+		}
+		return &response, nil
+	}
+}
+
+// GetValidators returns the current validator set
+func (q *Querier) GetValidators(ctx context.Context) ([]types.Validator, error) {
+	var validators []types.Validator
+	var nextKey []byte
+	useOffset := false
+
+	for {
+		res, err := executeWithEndpointRotation(ctx, q.RestUrls, fetchValidators(useOffset, nextKey))
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, res.Validators...)
+
+		shouldContinue, shouldBreak := handlePaginationNextKey(res.Pagination, len(validators), len(res.Validators), &useOffset)
+		if shouldBreak {
+			break
+		}
+		if shouldContinue {
+			continue
+		}
+
+		nextKey = res.Pagination.NextKey
+	}
+
+	return validators, nil
+}
+
 func fetchMinterBurnerModuleAccounts() func(ctx context.Context, endpointURL string) (*types.QueryModuleAccountsResponse, error) {
 	return func(ctx context.Context, endpointURL string) (*types.QueryModuleAccountsResponse, error) {
 		body, err := Get(ctx, endpointURL, cosmosModuleAccountsPath, nil, nil, queryTimeout)
@@ -125,3 +125,14 @@ func findHealthyEndpoint(endpoints []string) int {
 	// If no healthy endpoints, start from beginning
 	return 0
 }
+
+// FailingEndpoints returns how many of the endpoints failed their last request
+func FailingEndpoints(endpoints []string) int {
+	failing := 0
+	for _, endpoint := range endpoints {
+		if getEndpointHealth(endpoint).load().failures > 0 {
+			failing++
+		}
+	}
+	return failing
+}
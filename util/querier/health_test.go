@@ -375,3 +375,24 @@ func TestSuccessResetsFailureCount(t *testing.T) {
 		t.Error("Should be healthy with 2 failures after reset")
 	}
 }
+
+func TestFailingEndpoints(t *testing.T) {
+	resetHealthTracker()
+
+	endpoints := []string{"https://endpoint1.com", "https://endpoint2.com", "https://endpoint3.com"}
+	if got := FailingEndpoints(endpoints); got != 0 {
+		t.Errorf("Expected no failing endpoints, got %d", got)
+	}
+
+	recordEndpointFailure(endpoints[0])
+	recordEndpointFailure(endpoints[2])
+	if got := FailingEndpoints(endpoints); got != 2 {
+		t.Errorf("Expected 2 failing endpoints, got %d", got)
+	}
+
+	// a success clears the failures
+	recordEndpointSuccess(endpoints[0])
+	if got := FailingEndpoints(endpoints); got != 1 {
+		t.Errorf("Expected 1 failing endpoint, got %d", got)
+	}
+}
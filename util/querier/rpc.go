@@ -39,15 +39,17 @@ func FetchRPCWithRotation(ctx context.Context, client *fiber.Client, rpcURLs []s
 		default:
 		}
 
-		baseURL := strings.TrimRight(rpcURLs[(start+i)%len(rpcURLs)], "/")
-		url := baseURL + path
+		endpoint := rpcURLs[(start+i)%len(rpcURLs)]
+		url := strings.TrimRight(endpoint, "/") + path
 		body, err := fetchFromRpc(client, timeout, url)
 		if err == nil {
+			recordEndpointSuccess(endpoint)
 			return body, nil
 		}
 		if isFutureHeightError(err) {
 			return nil, err
 		}
+		recordEndpointFailure(endpoint)
 		lastErr = err
 	}
 
@@ -56,3 +56,18 @@ func BytesToHexWithPrefixIfPresent(b []byte) string {
 	}
 	return "0x" + hex.EncodeToString(b)
 }
+
+// MoveModuleId returns the id <address>::<name> of a move module, with the address in the short hex
+// form of the type tags
+func MoveModuleId(addrStr, name string) (string, error) {
+	addr, err := AccAddressFromString(addrStr)
+	if err != nil {
+		return "", err
+	}
+
+	hexStr := strings.TrimLeft(hex.EncodeToString(addr), "0")
+	if hexStr == "" {
+		hexStr = "0"
+	}
+	return "0x" + hexStr + "::" + name, nil
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, internal to the provider like the private ranges
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ErrPrivateAddress is returned when a webhook URL resolves to an address which is not publicly routable
+var ErrPrivateAddress = errors.New("webhook address is not publicly routable")
+
+// IsPublicAddr reports whether the address is publicly routable: loopback, private, link-local,
+// multicast, unspecified and shared addresses are not
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// ValidateUrl accepts absolute http and https URLs. Unless allowPrivate is set, the host must only
+// resolve to publicly routable addresses, so that subscriptions cannot reach the internal network.
+func ValidateUrl(ctx context.Context, rawUrl string, allowPrivate bool) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return types.NewInvalidValueError("url", rawUrl, "must be an absolute http or https URL")
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return types.NewInvalidValueError("url", rawUrl, "must not point to a private address")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublicAddr(addr) {
+			return types.NewInvalidValueError("url", rawUrl, "must not point to a private address")
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return types.NewInvalidValueError("url", rawUrl, "host does not resolve")
+	}
+	for _, addr := range addrs {
+		if !IsPublicAddr(addr) {
+			return types.NewInvalidValueError("url", rawUrl, "must not point to a private address")
+		}
+	}
+	return nil
+}
+
+// NewClient returns the client delivering the notifications. It does not follow redirects, and unless
+// allowPrivate is set, it refuses to connect to addresses which are not publicly routable, checked on
+// the address dialed so that a host resolving differently than on subscription is still refused.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, address)
+			}
+			if !IsPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, address)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would dial the receiver on our behalf, past the address check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
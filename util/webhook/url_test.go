@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUrl(t *testing.T) {
+	ctx := context.Background()
+	for _, rawUrl := range []string{
+		"ftp://example.com",
+		"/relative",
+		"http://localhost:8080/hook",
+		"http://api.localhost/hook",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.8/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://[fd00::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	} {
+		assert.Error(t, ValidateUrl(ctx, rawUrl, false), rawUrl)
+	}
+
+	require.NoError(t, ValidateUrl(ctx, "https://8.8.8.8/hook", false))
+	require.NoError(t, ValidateUrl(ctx, "http://127.0.0.1:8080/hook", true))
+	assert.Error(t, ValidateUrl(ctx, "ftp://127.0.0.1/hook", true))
+}
+
+func TestNewClient(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	redirecting := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer redirecting.Close()
+
+	// the loopback receiver is refused at dial time
+	_, err := NewClient(time.Second, false).Get(target.URL)
+	require.ErrorIs(t, err, ErrPrivateAddress)
+
+	// redirects are returned instead of followed
+	resp, err := NewClient(time.Second, true).Get(redirecting.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+}
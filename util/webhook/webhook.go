@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/initia-labs/rollytics/types"
+	"github.com/initia-labs/rollytics/util"
+)
+
+// Events a subscription can watch
+const (
+	// EventAccountTx notifies every tx involving the target account
+	EventAccountTx = "account_tx"
+	// EventCollectionMint notifies every nft minted in the target collection
+	EventCollectionMint = "collection_mint"
+	// EventRichListTop notifies every change of the top holders of the target denom
+	EventRichListTop = "rich_list_top"
+)
+
+// Headers of a delivery
+const (
+	HeaderEvent     = "X-Rollytics-Event"
+	HeaderDelivery  = "X-Rollytics-Delivery"
+	HeaderTimestamp = "X-Rollytics-Timestamp"
+	HeaderSignature = "X-Rollytics-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// RichListTopSize is the number of top holders watched by rich list subscriptions
+const RichListTopSize = 10
+
+// secretSize is the number of random bytes of a generated secret
+const secretSize = 32
+
+// IsEvent reports whether the event can be subscribed to
+func IsEvent(event string) bool {
+	switch event {
+	case EventAccountTx, EventCollectionMint, EventRichListTop:
+		return true
+	}
+	return false
+}
+
+// NewSecret generates a random HMAC secret
+func NewSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature header value of a payload sent at the unix timestamp. The timestamp is
+// signed along with the body, so that receivers can reject replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature header value matches the payload
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NormalizeTarget returns the canonical form of a subscription target, the form the dispatcher
+// matches against: accounts are formatted like the API does, collections as hex addresses and
+// denoms in lower case. On EVM, rich list denoms are resolved to their contract by the caller.
+func NormalizeTarget(vmType types.VMType, event, target string) (string, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return "", types.NewValidationError("target", "required field is missing")
+	}
+
+	switch event {
+	case EventAccountTx:
+		addr, err := util.AccAddressFromString(target)
+		if err != nil {
+			return "", types.NewInvalidValueError("target", target, "invalid account address")
+		}
+		return FormatAccount(vmType, addr), nil
+	case EventCollectionMint:
+		addr, err := util.AccAddressFromString(target)
+		if err != nil {
+			return "", types.NewInvalidValueError("target", target, "invalid collection address")
+		}
+		return util.BytesToHexWithPrefix(addr), nil
+	case EventRichListTop:
+		return strings.ToLower(target), nil
+	default:
+		return "", types.NewInvalidValueError("event", event, "must be account_tx, collection_mint or rich_list_top")
+	}
+}
+
+// FormatAccount formats an account address like the API does
+func FormatAccount(vmType types.VMType, account []byte) string {
+	if vmType == types.EVM {
+		return util.BytesToHexWithPrefix(account)
+	}
+	return sdk.AccAddress(account).String()
+}
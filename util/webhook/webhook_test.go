@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/initia-labs/rollytics/types"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1/2"}`)
+	signature := Sign("secret", 100, body)
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, Verify("secret", 100, body, signature))
+	assert.False(t, Verify("other", 100, body, signature))
+	assert.False(t, Verify("secret", 101, body, signature))
+	assert.False(t, Verify("secret", 100, []byte(`{"id":"1/3"}`), signature))
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	require.NoError(t, err)
+	b, err := NewSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, a, 2*secretSize)
+	assert.NotEqual(t, a, b)
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	account := make([]byte, 20)
+	account[19] = 0x01
+
+	target, err := NormalizeTarget(types.WasmVM, EventAccountTx, "0x01")
+	require.NoError(t, err)
+	assert.Equal(t, sdk.AccAddress(account).String(), target)
+
+	target, err = NormalizeTarget(types.EVM, EventAccountTx, sdk.AccAddress(account).String())
+	require.NoError(t, err)
+	assert.Equal(t, "0x0000000000000000000000000000000000000001", target)
+
+	target, err = NormalizeTarget(types.WasmVM, EventCollectionMint, sdk.AccAddress(account).String())
+	require.NoError(t, err)
+	assert.Equal(t, "0x0000000000000000000000000000000000000001", target)
+
+	target, err = NormalizeTarget(types.MoveVM, EventRichListTop, " UINIT ")
+	require.NoError(t, err)
+	assert.Equal(t, "uinit", target)
+
+	_, err = NormalizeTarget(types.MoveVM, "unknown", "uinit")
+	assert.Error(t, err)
+	_, err = NormalizeTarget(types.MoveVM, EventAccountTx, "")
+	assert.Error(t, err)
+	_, err = NormalizeTarget(types.MoveVM, EventAccountTx, "not-an-address!")
+	assert.Error(t, err)
+}